@@ -29,6 +29,9 @@ type BartApp struct {
 	multiClass            bool
 	serverTimeoutSeconds  int
 	serverMaxRequestBytes int
+	quantize              bool
+	generateBatchWindow   int
+	generateMaxBatchSize  int
 }
 
 // NewBartApp returns a new BartApp object, which can be used as either client or server.
@@ -7,6 +7,7 @@ package app
 import (
 	"fmt"
 	"github.com/nlpodyssey/spago/pkg/ml/nn"
+	"github.com/nlpodyssey/spago/pkg/ml/nn/quantization"
 	"github.com/nlpodyssey/spago/pkg/nlp/tokenizers/bpetokenizer"
 	"github.com/nlpodyssey/spago/pkg/nlp/tokenizers/sentencepiece"
 	"github.com/nlpodyssey/spago/pkg/nlp/transformers/bart/head/conditionalgeneration"
@@ -21,6 +22,7 @@ import (
 	"os/user"
 	"path"
 	"path/filepath"
+	"time"
 )
 
 func newServerCommandFor(app *BartApp) *cli.Command {
@@ -93,6 +95,23 @@ func newServerCommandFlagsFor(app *BartApp) []cli.Flag {
 			Value:       httputils.DefaultMaxRequestBytes,
 			Destination: &app.serverMaxRequestBytes,
 		},
+		&cli.BoolFlag{
+			Name:        "quantize",
+			Usage:       "Quantizes the weights of the linear layers to int8 to speed up the inference.",
+			Destination: &app.quantize,
+		},
+		&cli.IntFlag{
+			Name:        "generate-batch-window",
+			Usage:       "Time in milliseconds to wait for concurrent generation requests to be processed in a single batch (0 to disable the batching).",
+			Value:       0,
+			Destination: &app.generateBatchWindow,
+		},
+		&cli.IntFlag{
+			Name:        "generate-max-batch-size",
+			Usage:       "Maximum number of generation requests processed in a single batch (0 for no limit).",
+			Value:       8,
+			Destination: &app.generateMaxBatchSize,
+		},
 	}
 }
 
@@ -109,6 +128,9 @@ func newServerCommandActionFor(app *BartApp) func(c *cli.Context) error {
 			log.Fatal(err)
 		}
 		defer nn.Close(model)
+		if app.quantize {
+			fmt.Printf("Quantized %d linear layers to int8.\n", quantization.Quantize(model))
+		}
 
 		var bpeTokenizer *bpetokenizer.BPETokenizer
 		var spTokenizer *sentencepiece.Tokenizer
@@ -150,6 +172,8 @@ func newServerCommandActionFor(app *BartApp) func(c *cli.Context) error {
 		s := server.NewServer(model, bpeTokenizer, spTokenizer)
 		s.TimeoutSeconds = app.serverTimeoutSeconds
 		s.MaxRequestBytes = app.serverMaxRequestBytes
+		s.GenerateBatchWindow = time.Duration(app.generateBatchWindow) * time.Millisecond
+		s.MaxGenerateBatchSize = app.generateMaxBatchSize
 		s.StartDefaultHTTPServer(app.address, app.tlsCert, app.tlsKey, app.tlsDisable)
 		s.StartDefaultServer(app.grpcAddress, app.tlsCert, app.tlsKey, app.tlsDisable)
 
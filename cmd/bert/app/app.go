@@ -12,7 +12,7 @@ const (
 	programName = "bert-server"
 )
 
-// BertApp contains everything needed to run the BERT demo client or server.
+// BertApp contains everything needed to run the BERT demo client or server, or to fine-tune a model.
 type BertApp struct {
 	*cli.App
 	address               string
@@ -29,9 +29,20 @@ type BertApp struct {
 	question              string
 	serverTimeoutSeconds  int
 	serverMaxRequestBytes int
+	quantize              bool
+	task                  string
+	trainFile             string
+	devFile               string
+	epochs                int
+	batchSize             int
+	learningRate          float64
+	patience              int
+	maxLength             int
+	loss                  string
 }
 
-// NewBertApp returns BertApp objects. The app can be used as both a client and a server.
+// NewBertApp returns BertApp objects. The app can be used as a client, a server, and a fine-tuning tool,
+// also for the sentence embeddings.
 func NewBertApp() *BertApp {
 	app := &BertApp{
 		App: cli.NewApp(),
@@ -42,6 +53,8 @@ func NewBertApp() *BertApp {
 	app.Commands = []*cli.Command{
 		newClientCommandFor(app),
 		newServerCommandFor(app),
+		newFineTuneCommandFor(app),
+		newFineTuneSentenceCommandFor(app),
 	}
 	return app
 }
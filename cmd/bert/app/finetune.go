@@ -0,0 +1,146 @@
+// Copyright 2021 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package app
+
+import (
+	"fmt"
+	mat "github.com/nlpodyssey/spago/pkg/mat32"
+	"github.com/nlpodyssey/spago/pkg/ml/optimizers/gd/adam"
+	"github.com/nlpodyssey/spago/pkg/nlp/transformers/bert"
+	"github.com/urfave/cli/v2"
+	"os/user"
+	"path"
+	"path/filepath"
+)
+
+func newFineTuneCommandFor(app *BertApp) *cli.Command {
+	return &cli.Command{
+		Name:  "finetune",
+		Usage: "Fine-tune a model for sequence classification, token classification or question-answering.",
+		Description: "Fine-tune the model indicating the model path (NOT the model file). " +
+			"The best model replaces the model file, so that it can be served by the server command.",
+		Flags:  newFineTuneCommandFlagsFor(app),
+		Action: newFineTuneCommandActionFor(app),
+	}
+}
+
+func newFineTuneCommandFlagsFor(app *BertApp) []cli.Flag {
+	usr, err := user.Current()
+	if err != nil {
+		panic(err)
+	}
+
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:        "repo",
+			Usage:       "Specifies the path to the models.",
+			Value:       path.Join(usr.HomeDir, ".spago"),
+			Destination: &app.repo,
+		},
+		&cli.StringFlag{
+			Name:        "model, m",
+			Required:    true,
+			Usage:       "Specifies the model name.",
+			Destination: &app.model,
+		},
+		&cli.StringFlag{
+			Name:        "task",
+			Usage:       "Specifies the task (sequence-classification, token-classification, question-answering).",
+			Value:       string(bert.SequenceClassificationTask),
+			Destination: &app.task,
+		},
+		&cli.StringFlag{
+			Name:        "train",
+			Required:    true,
+			Usage:       "Specifies the file of the training examples (JSONL, or CoNLL with the .conll extension).",
+			Destination: &app.trainFile,
+		},
+		&cli.StringFlag{
+			Name:        "dev",
+			Usage:       "Specifies the file of the development examples, to select the best model.",
+			Destination: &app.devFile,
+		},
+		&cli.IntFlag{
+			Name:        "epochs",
+			Usage:       "Specifies the number of training epochs.",
+			Value:       3,
+			Destination: &app.epochs,
+		},
+		&cli.IntFlag{
+			Name:        "batch-size",
+			Usage:       "Specifies the number of examples of a batch.",
+			Value:       16,
+			Destination: &app.batchSize,
+		},
+		&cli.Float64Flag{
+			Name:        "learning-rate",
+			Usage:       "Specifies the learning rate of the Adam optimizer.",
+			Value:       3.0e-5,
+			Destination: &app.learningRate,
+		},
+		&cli.IntFlag{
+			Name:        "patience",
+			Usage:       "Stops after the given number of epochs without improvement on the development set (0 disables it).",
+			Value:       0,
+			Destination: &app.patience,
+		},
+		&cli.IntFlag{
+			Name:        "max-length",
+			Usage:       "Specifies the maximum number of tokens of an example (0 means the maximum of the model).",
+			Value:       0,
+			Destination: &app.maxLength,
+		},
+	}
+}
+
+func newFineTuneCommandActionFor(app *BertApp) func(c *cli.Context) error {
+	return func(c *cli.Context) error {
+		modelPath := filepath.Join(app.repo, app.model)
+
+		trainSet, err := readExamples(app.trainFile)
+		if err != nil {
+			return err
+		}
+		var devSet []bert.Example
+		if app.devFile != "" {
+			if devSet, err = readExamples(app.devFile); err != nil {
+				return err
+			}
+		}
+
+		model, err := bert.LoadModel(modelPath)
+		if err != nil {
+			return fmt.Errorf("error during model loading (%v)", err)
+		}
+		defer model.Embeddings.Words.Close()
+
+		task := bert.FineTuningTask(app.task)
+		var labels []string
+		if task != bert.QuestionAnsweringTask {
+			labels = bert.CollectLabels(trainSet)
+			fmt.Printf("Labels: %v\n", labels)
+		}
+
+		tuner := bert.NewFineTuner(model, bert.FineTuningConfig{
+			Task:              task,
+			Seed:              42,
+			BatchSize:         app.batchSize,
+			Epochs:            app.epochs,
+			GradientClipping:  1.0,
+			UpdateMethod:      adam.NewConfig(mat.Float(app.learningRate), 0.9, 0.999, 1.0e-8),
+			Patience:          app.patience,
+			MaxSequenceLength: app.maxLength,
+			ModelPath:         modelPath,
+		}, labels)
+		return tuner.Train(trainSet, devSet)
+	}
+}
+
+func readExamples(filename string) ([]bert.Example, error) {
+	if filepath.Ext(filename) == ".conll" {
+		return bert.ReadCoNLLExamples(filename)
+	}
+	return bert.ReadJSONLExamples(filename)
+}
@@ -0,0 +1,120 @@
+// Copyright 2021 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package app
+
+import (
+	"fmt"
+	mat "github.com/nlpodyssey/spago/pkg/mat32"
+	"github.com/nlpodyssey/spago/pkg/ml/optimizers/gd/adam"
+	"github.com/nlpodyssey/spago/pkg/nlp/transformers/bert"
+	"github.com/urfave/cli/v2"
+	"os/user"
+	"path"
+	"path/filepath"
+)
+
+func newFineTuneSentenceCommandFor(app *BertApp) *cli.Command {
+	return &cli.Command{
+		Name:  "finetune-sentence",
+		Usage: "Train the sentence embeddings of a model for semantic similarity.",
+		Description: "Train the sentence embeddings indicating the model path (NOT the model file). " +
+			"The best model and its sentence embedding head are saved in the model path, " +
+			"so that the server command uses them for the encoding requests.",
+		Flags:  newFineTuneSentenceCommandFlagsFor(app),
+		Action: newFineTuneSentenceCommandActionFor(app),
+	}
+}
+
+func newFineTuneSentenceCommandFlagsFor(app *BertApp) []cli.Flag {
+	usr, err := user.Current()
+	if err != nil {
+		panic(err)
+	}
+
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:        "repo",
+			Usage:       "Specifies the path to the models.",
+			Value:       path.Join(usr.HomeDir, ".spago"),
+			Destination: &app.repo,
+		},
+		&cli.StringFlag{
+			Name:        "model, m",
+			Required:    true,
+			Usage:       "Specifies the model name.",
+			Destination: &app.model,
+		},
+		&cli.StringFlag{
+			Name:        "loss",
+			Usage:       "Specifies the loss (cosine-similarity, triplet, multiple-negatives-ranking).",
+			Value:       string(bert.MultipleNegativesRankingLoss),
+			Destination: &app.loss,
+		},
+		&cli.StringFlag{
+			Name:        "train",
+			Required:    true,
+			Usage:       "Specifies the JSONL file of the training examples.",
+			Destination: &app.trainFile,
+		},
+		&cli.StringFlag{
+			Name:        "dev",
+			Usage:       "Specifies the JSONL file of the development examples, to select the best model.",
+			Destination: &app.devFile,
+		},
+		&cli.IntFlag{
+			Name:        "epochs",
+			Usage:       "Specifies the number of training epochs.",
+			Value:       1,
+			Destination: &app.epochs,
+		},
+		&cli.IntFlag{
+			Name:        "batch-size",
+			Usage:       "Specifies the number of examples of a batch.",
+			Value:       16,
+			Destination: &app.batchSize,
+		},
+		&cli.Float64Flag{
+			Name:        "learning-rate",
+			Usage:       "Specifies the learning rate of the Adam optimizer.",
+			Value:       2.0e-5,
+			Destination: &app.learningRate,
+		},
+	}
+}
+
+func newFineTuneSentenceCommandActionFor(app *BertApp) func(c *cli.Context) error {
+	return func(c *cli.Context) error {
+		modelPath := filepath.Join(app.repo, app.model)
+
+		trainSet, err := bert.ReadSentenceExamples(app.trainFile)
+		if err != nil {
+			return err
+		}
+		var devSet []bert.SentenceExample
+		if app.devFile != "" {
+			if devSet, err = bert.ReadSentenceExamples(app.devFile); err != nil {
+				return err
+			}
+		}
+
+		model, err := bert.LoadSentenceTransformer(modelPath)
+		if err != nil {
+			return fmt.Errorf("error during model loading (%v)", err)
+		}
+		defer model.BERT.Embeddings.Words.Close()
+		fmt.Printf("Sentence embedding head: %+v\n", model.Config)
+
+		trainer := bert.NewSentenceTrainer(model, bert.SentenceTrainingConfig{
+			Loss:             bert.SentenceLoss(app.loss),
+			Seed:             42,
+			BatchSize:        app.batchSize,
+			Epochs:           app.epochs,
+			GradientClipping: 1.0,
+			UpdateMethod:     adam.NewConfig(mat.Float(app.learningRate), 0.9, 0.999, 1.0e-6),
+			ModelPath:        modelPath,
+		})
+		return trainer.Train(trainSet, devSet)
+	}
+}
@@ -6,6 +6,7 @@ package app
 
 import (
 	"fmt"
+	"github.com/nlpodyssey/spago/pkg/ml/nn/quantization"
 	"github.com/nlpodyssey/spago/pkg/nlp/transformers/bert"
 	"github.com/nlpodyssey/spago/pkg/nlp/transformers/huggingface"
 	"github.com/nlpodyssey/spago/pkg/utils/httputils"
@@ -87,6 +88,11 @@ func newServerCommandFlagsFor(app *BertApp) []cli.Flag {
 			Value:       httputils.DefaultMaxRequestBytes,
 			Destination: &app.serverMaxRequestBytes,
 		},
+		&cli.BoolFlag{
+			Name:        "quantize",
+			Usage:       "Quantizes the weights of the linear layers to int8 to speed up the inference.",
+			Destination: &app.quantize,
+		},
 	}
 }
 
@@ -127,6 +133,9 @@ func newServerCommandActionFor(app *BertApp) func(c *cli.Context) error {
 		if err != nil {
 			log.Fatalf("error during model loading (%v)\n", err)
 		}
+		if app.quantize {
+			fmt.Printf("Quantized %d linear layers to int8.\n", quantization.Quantize(model))
+		}
 		fmt.Printf("Config: %+v\n", model.Config)
 
 		if !app.tlsDisable {
@@ -149,6 +158,14 @@ func newServerCommandActionFor(app *BertApp) func(c *cli.Context) error {
 		server := bert.NewServer(model)
 		server.TimeoutSeconds = app.serverTimeoutSeconds
 		server.MaxRequestBytes = app.serverMaxRequestBytes
+		if _, err := os.Stat(path.Join(modelPath, bert.DefaultSentenceTransformerConfigFile)); err == nil {
+			sentenceTransformer, err := bert.LoadSentenceHead(model, modelPath)
+			if err != nil {
+				log.Fatalf("error during sentence embedding head loading (%v)\n", err)
+			}
+			fmt.Printf("Sentence embedding head: %+v\n", sentenceTransformer.Config)
+			server.SentenceTransformer = sentenceTransformer
+		}
 		server.StartDefaultServer(app.address, app.grpcAddress, app.tlsCert, app.tlsKey, app.tlsDisable)
 
 		return nil
@@ -6,6 +6,7 @@ package internal
 
 import (
 	"fmt"
+	mat "github.com/nlpodyssey/spago/pkg/mat32"
 	"github.com/nlpodyssey/spago/pkg/nlp/transformers/huggingface"
 	"github.com/nlpodyssey/spago/pkg/utils/homedir"
 	"github.com/urfave/cli/v2"
@@ -34,6 +35,7 @@ type ImporterArgs struct {
 	Model     string
 	ModelsURL string
 	Overwrite bool
+	Precision string
 }
 
 // NewImporterArgs builds args object.
@@ -76,6 +78,12 @@ func (a *ImporterArgs) BuildFlags() []cli.Flag {
 			Usage:       "overwrite files if they exist already",
 			Destination: &a.Overwrite,
 		},
+		&cli.StringFlag{
+			Name:        "precision",
+			Usage:       "floating-point format of the params: full, float16 or bfloat16",
+			Value:       "full",
+			Destination: &a.Precision,
+		},
 	}
 }
 
@@ -85,6 +93,10 @@ func (a *ImporterArgs) RunImporter() error {
 	if err != nil {
 		return err
 	}
+	precision, err := mat.ParsePrecision(a.Precision)
+	if err != nil {
+		return err
+	}
 
 	// Run interactive model selection if a model is not already set.
 	if a.Model == "" {
@@ -110,7 +122,7 @@ func (a *ImporterArgs) RunImporter() error {
 	}
 
 	fmt.Printf("Converting `%s` model...\n", a.Model)
-	return huggingface.NewConverter(a.Repo, a.Model).Convert()
+	return huggingface.NewConverter(a.Repo, a.Model, huggingface.ParamsPrecision(precision)).Convert()
 }
 
 // RunImporterCli runs the importer from the command line.
@@ -0,0 +1,141 @@
+// Copyright 2021 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package app
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/nlpodyssey/spago/pkg/ml/nn"
+	"github.com/nlpodyssey/spago/pkg/nlp/charlm"
+	"github.com/nlpodyssey/spago/pkg/nlp/sequencelabeler"
+	"github.com/nlpodyssey/spago/pkg/nlp/transformers/bart/config"
+	"github.com/nlpodyssey/spago/pkg/nlp/transformers/bart/loader"
+	"github.com/nlpodyssey/spago/pkg/nlp/transformers/bert"
+	"github.com/urfave/cli/v2"
+)
+
+const (
+	programName = "model-migrator"
+)
+
+// New returns a new CLI App for migrating gob-encoded spaGO models to the spaGO model file format.
+func New() *cli.App {
+	var modelType, modelPath string
+	app := cli.NewApp()
+	app.Name = programName
+	app.HelpName = programName
+	app.Usage = "Convert a gob-encoded spaGO model (e.g. spago_model.bin) to the spaGO model file format"
+	app.HideVersion = true
+	app.Flags = []cli.Flag{
+		&cli.StringFlag{
+			Name:        "type",
+			Usage:       "the type of the model: bert, bart, sequencelabeler or charlm",
+			Required:    true,
+			Destination: &modelType,
+		},
+		&cli.StringFlag{
+			Name:        "model-path",
+			Usage:       "the directory of the model, containing the configuration and the model file",
+			Required:    true,
+			Destination: &modelPath,
+		},
+	}
+	app.Action = func(_ *cli.Context) error {
+		return migrate(modelType, modelPath)
+	}
+	return app
+}
+
+// migrate loads the model with the loader of its type, which accepts both formats, and saves it again
+// in the spaGO model file format. The original file is kept with the ".gob" extension.
+func migrate(modelType, modelPath string) error {
+	filename, err := modelFilename(modelType, modelPath)
+	if err != nil {
+		return err
+	}
+	isModelFile, err := nn.IsModelFile(filename)
+	if err != nil {
+		return err
+	}
+	if isModelFile {
+		fmt.Printf("The model \"%s\" is already in the spaGO model file format.\n", filename)
+		return nil
+	}
+
+	model, modelConfig, err := load(modelType, modelPath)
+	if err != nil {
+		return err
+	}
+	tmpFilename := filename + ".new"
+	fmt.Printf("Saving model to \"%s\"... ", filename)
+	if err := nn.SaveModelFile(tmpFilename, model, modelConfig); err != nil {
+		_ = os.Remove(tmpFilename)
+		return err
+	}
+	if err := os.Rename(filename, filename+".gob"); err != nil {
+		return err
+	}
+	if err := os.Rename(tmpFilename, filename); err != nil {
+		return err
+	}
+	fmt.Println("ok")
+	fmt.Printf("The original model has been kept as \"%s.gob\".\n", filename)
+	return nil
+}
+
+// modelFilename returns the name of the model file, according to the type of the model.
+func modelFilename(modelType, modelPath string) (string, error) {
+	switch modelType {
+	case "bert":
+		return filepath.Join(modelPath, bert.DefaultModelFile), nil
+	case "bart":
+		return filepath.Join(modelPath, config.DefaultModelFile), nil
+	case "sequencelabeler":
+		c := sequencelabeler.LoadConfig(filepath.Join(modelPath, "config.json"))
+		return filepath.Join(modelPath, c.ModelFilename), nil
+	case "charlm":
+		return filepath.Join(modelPath, charlm.DefaultModelFilename), nil
+	default:
+		return "", fmt.Errorf("%s: unknown model type %q", programName, modelType)
+	}
+}
+
+// load loads the model and its configuration, according to the type of the model.
+func load(modelType, modelPath string) (nn.Model, interface{}, error) {
+	switch modelType {
+	case "bert":
+		m, err := bert.LoadModel(modelPath)
+		if err != nil {
+			return nil, nil, err
+		}
+		return m, m.Config, nil
+	case "bart":
+		c, err := config.Load(filepath.Join(modelPath, config.DefaultConfigurationFile))
+		if err != nil {
+			return nil, nil, err
+		}
+		m, err := loader.Load(modelPath)
+		if err != nil {
+			return nil, nil, err
+		}
+		return m, c, nil
+	case "sequencelabeler":
+		m, err := sequencelabeler.LoadModel(modelPath)
+		if err != nil {
+			return nil, nil, err
+		}
+		return m, m.Config, nil
+	case "charlm":
+		m, err := charlm.LoadModel(modelPath)
+		if err != nil {
+			return nil, nil, err
+		}
+		return m, m.Config, nil
+	default:
+		return nil, nil, fmt.Errorf("%s: unknown model type %q", programName, modelType)
+	}
+}
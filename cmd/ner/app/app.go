@@ -38,6 +38,15 @@ type NERApp struct {
 	filterNonEntities     bool
 	serverTimeoutSeconds  int
 	serverMaxRequestBytes int
+	trainFile             string
+	devFile               string
+	scheme                string
+	epochs                int
+	batchSize             int
+	learningRate          float64
+	patience              int
+	freezeCharLM          bool
+	freezeWordEmbeddings  bool
 }
 
 // NewNERApp returns NerApp objects.
@@ -52,6 +61,7 @@ func NewNERApp() *NERApp {
 		newClientCommandFor(app),
 		newServerCommandFor(app),
 		newConvertCommandFor(app),
+		newTrainCommandFor(app),
 	}
 	return app
 }
@@ -0,0 +1,152 @@
+// Copyright 2021 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package app
+
+import (
+	"fmt"
+	mat "github.com/nlpodyssey/spago/pkg/mat32"
+	"github.com/nlpodyssey/spago/pkg/ml/optimizers/gd/adam"
+	"github.com/nlpodyssey/spago/pkg/nlp/embeddings"
+	"github.com/nlpodyssey/spago/pkg/nlp/sequencelabeler"
+	"github.com/urfave/cli/v2"
+	"log"
+	"os/user"
+	"path"
+	"path/filepath"
+)
+
+func newTrainCommandFor(app *NERApp) *cli.Command {
+	return &cli.Command{
+		Name:  "train",
+		Usage: "Train a sequence labeling model on CoNLL files.",
+		Description: "Train the model indicating the model path (NOT the model file). " +
+			"If the labels of the training examples are new to the model, its scorer and CRF are replaced. " +
+			"The best model is saved in the model path, so that the server command uses it.",
+		Flags:  newTrainCommandFlagsFor(app),
+		Action: newTrainCommandActionFor(app),
+	}
+}
+
+func newTrainCommandFlagsFor(app *NERApp) []cli.Flag {
+	usr, err := user.Current()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:        "repo",
+			Usage:       "Specifies the path to the models.",
+			Value:       path.Join(usr.HomeDir, ".spago"),
+			Destination: &app.repo,
+		},
+		&cli.StringFlag{
+			Name:        "model",
+			Usage:       "Specifies the name of the model to train.",
+			Destination: &app.modelName,
+			Required:    true,
+		},
+		&cli.StringFlag{
+			Name:        "train",
+			Usage:       "Specifies the CoNLL file of the training examples.",
+			Destination: &app.trainFile,
+			Required:    true,
+		},
+		&cli.StringFlag{
+			Name:        "dev",
+			Usage:       "Specifies the CoNLL file of the development examples, to select the best model.",
+			Destination: &app.devFile,
+		},
+		&cli.StringFlag{
+			Name:        "scheme",
+			Usage:       "Specifies the label scheme of the model (iob1, bio, bioes).",
+			Value:       string(sequencelabeler.BIOES),
+			Destination: &app.scheme,
+		},
+		&cli.IntFlag{
+			Name:        "epochs",
+			Usage:       "Specifies the number of training epochs.",
+			Value:       10,
+			Destination: &app.epochs,
+		},
+		&cli.IntFlag{
+			Name:        "batch-size",
+			Usage:       "Specifies the number of examples of a batch.",
+			Value:       32,
+			Destination: &app.batchSize,
+		},
+		&cli.Float64Flag{
+			Name:        "learning-rate",
+			Usage:       "Specifies the learning rate of the Adam optimizer.",
+			Value:       1.0e-3,
+			Destination: &app.learningRate,
+		},
+		&cli.IntFlag{
+			Name:        "patience",
+			Usage:       "Specifies the number of epochs without improvement on the development set before stopping.",
+			Destination: &app.patience,
+		},
+		&cli.BoolFlag{
+			Name:        "freeze-charlm",
+			Usage:       "Specifies that the character-level language models are not trained.",
+			Destination: &app.freezeCharLM,
+		},
+		&cli.BoolFlag{
+			Name:        "freeze-word-embeddings",
+			Usage:       "Specifies that the word embeddings are not trained.",
+			Destination: &app.freezeWordEmbeddings,
+		},
+	}
+}
+
+func newTrainCommandActionFor(app *NERApp) func(c *cli.Context) error {
+	return func(c *cli.Context) error {
+		modelPath := filepath.Join(app.repo, app.modelName)
+		scheme := sequencelabeler.LabelScheme(app.scheme)
+		switch scheme {
+		case sequencelabeler.IOB1, sequencelabeler.BIO, sequencelabeler.BIOES:
+		default:
+			return fmt.Errorf("invalid label scheme `%s`", app.scheme)
+		}
+
+		trainSet, err := sequencelabeler.ReadCoNLLExamples(app.trainFile)
+		if err != nil {
+			return err
+		}
+		var devSet []sequencelabeler.Example
+		if app.devFile != "" {
+			if devSet, err = sequencelabeler.ReadCoNLLExamples(app.devFile); err != nil {
+				return err
+			}
+		}
+
+		var model *sequencelabeler.Model
+		if app.freezeWordEmbeddings {
+			model, err = sequencelabeler.LoadModel(modelPath)
+		} else {
+			model, err = sequencelabeler.LoadModelForTraining(modelPath)
+		}
+		if err != nil {
+			return fmt.Errorf("error during model loading (%v)", err)
+		}
+		defer embeddings.Close()
+
+		labels := sequencelabeler.CollectLabels(append(trainSet, devSet...), scheme)
+		fmt.Printf("Labels: %v\n", labels)
+		trainer := sequencelabeler.NewTrainer(model, sequencelabeler.TrainingConfig{
+			Seed:                 42,
+			Epochs:               app.epochs,
+			BatchSize:            app.batchSize,
+			GradientClipping:     5.0,
+			UpdateMethod:         adam.NewConfig(mat.Float(app.learningRate), 0.9, 0.999, 1.0e-8),
+			Scheme:               scheme,
+			Patience:             app.patience,
+			FreezeCharLM:         app.freezeCharLM,
+			FreezeWordEmbeddings: app.freezeWordEmbeddings,
+			ModelPath:            modelPath,
+		}, labels)
+		return trainer.Train(trainSet, devSet)
+	}
+}
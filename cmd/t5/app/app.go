@@ -0,0 +1,43 @@
+// Copyright 2021 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package app
+
+import (
+	"github.com/urfave/cli/v2"
+)
+
+const (
+	programName = "t5"
+)
+
+// T5App contains everything needed to run the T5 text-to-text generation demo.
+type T5App struct {
+	*cli.App
+	model       string
+	repo        string
+	task        string
+	text        string
+	numBeams    int
+	maxLength   int
+	sample      bool
+	seed        uint64
+	temperature float64
+	topK        int
+	topP        float64
+}
+
+// NewT5App returns a new T5App object.
+func NewT5App() *T5App {
+	app := &T5App{
+		App: cli.NewApp(),
+	}
+	app.Name = programName
+	app.HelpName = programName
+	app.Usage = "A demo for text-to-text generation based on T5."
+	app.Commands = []*cli.Command{
+		newGenerateCommandFor(app),
+	}
+	return app
+}
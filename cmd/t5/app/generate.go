@@ -0,0 +1,203 @@
+// Copyright 2021 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package app
+
+import (
+	"bufio"
+	"fmt"
+	mat "github.com/nlpodyssey/spago/pkg/mat32"
+	"github.com/nlpodyssey/spago/pkg/nlp/transformers/generation"
+	"github.com/nlpodyssey/spago/pkg/nlp/transformers/huggingface"
+	"github.com/nlpodyssey/spago/pkg/nlp/transformers/t5"
+	"github.com/urfave/cli/v2"
+	"log"
+	"os"
+	"os/user"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+func newGenerateCommandFor(app *T5App) *cli.Command {
+	return &cli.Command{
+		Name:  "generate",
+		Usage: "Generate a text from the input text.",
+		Description: "Run the " + programName + " text-to-text generation indicating the model name (NOT the model file).\n" +
+			"The input is read from the --text flag, or line by line from the standard input.",
+		Flags:  newGenerateCommandFlagsFor(app),
+		Action: newGenerateCommandActionFor(app),
+	}
+}
+
+func newGenerateCommandFlagsFor(app *T5App) []cli.Flag {
+	usr, err := user.Current()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:        "repo",
+			Usage:       "Specifies the path to the models.",
+			Value:       path.Join(usr.HomeDir, ".spago"),
+			Destination: &app.repo,
+		},
+		&cli.StringFlag{
+			Name:        "model, m",
+			Required:    true,
+			Usage:       "Specifies the model name.",
+			Destination: &app.model,
+		},
+		&cli.StringFlag{
+			Name:        "task",
+			Usage:       "Specifies the task (e.g. \"summarization\", \"translation_en_to_de\"), to use its prefix and generation settings.",
+			Destination: &app.task,
+		},
+		&cli.StringFlag{
+			Name:        "text",
+			Usage:       "Specifies the input text.",
+			Destination: &app.text,
+		},
+		&cli.IntFlag{
+			Name:        "num-beams",
+			Usage:       "Number of beams of the beam search (0 to use the model settings).",
+			Destination: &app.numBeams,
+		},
+		&cli.IntFlag{
+			Name:        "max-length",
+			Usage:       "Maximum length of the generated sequences (0 to use the model settings).",
+			Destination: &app.maxLength,
+		},
+		&cli.BoolFlag{
+			Name:        "sample",
+			Usage:       "Samples the next tokens instead of performing a beam search.",
+			Destination: &app.sample,
+		},
+		&cli.Uint64Flag{
+			Name:        "seed",
+			Usage:       "Seed of the random generator used for sampling.",
+			Value:       42,
+			Destination: &app.seed,
+		},
+		&cli.Float64Flag{
+			Name:        "temperature",
+			Usage:       "Temperature used to modulate the probability distribution when sampling.",
+			Value:       1,
+			Destination: &app.temperature,
+		},
+		&cli.IntFlag{
+			Name:        "top-k",
+			Usage:       "Number of the most probable tokens to sample from (0 to disable).",
+			Destination: &app.topK,
+		},
+		&cli.Float64Flag{
+			Name:        "top-p",
+			Usage:       "Cumulative probability of the most probable tokens to sample from (0 to disable).",
+			Destination: &app.topP,
+		},
+	}
+}
+
+func newGenerateCommandActionFor(app *T5App) func(c *cli.Context) error {
+	return func(c *cli.Context) error {
+		if err := pullModel(app); err != nil {
+			return err
+		}
+
+		model, err := t5.LoadText2TextGenerator(filepath.Join(app.repo, app.model))
+		if err != nil {
+			return err
+		}
+		defer model.Close()
+
+		prefix, opts, err := decodingOptions(app, model.T5.Config)
+		if err != nil {
+			return err
+		}
+		generate := func(text string) error {
+			out, err := model.Generate(prefix+text, opts...)
+			if err != nil {
+				return err
+			}
+			fmt.Println(out)
+			return nil
+		}
+
+		if app.text != "" {
+			return generate(app.text)
+		}
+		scanner := bufio.NewScanner(os.Stdin)
+		for scanner.Scan() {
+			text := strings.TrimSpace(scanner.Text())
+			if text == "" {
+				continue
+			}
+			if err := generate(text); err != nil {
+				return err
+			}
+		}
+		return scanner.Err()
+	}
+}
+
+// decodingOptions returns the prefix of the input texts and the decoding options, given by the task settings
+// of the model overridden by the command flags.
+func decodingOptions(app *T5App, config t5.Config) (string, []generation.DecodingOption, error) {
+	var prefix string
+	var opts []generation.DecodingOption
+	if app.task != "" {
+		taskPrefix, taskOpt, err := config.TaskSettings(app.task)
+		if err != nil {
+			return "", nil, err
+		}
+		prefix = taskPrefix
+		opts = append(opts, taskOpt)
+	}
+	if app.numBeams > 0 {
+		opts = append(opts, generation.BeamSearch(app.numBeams))
+	}
+	if app.maxLength > 0 {
+		opts = append(opts, func(c *generation.GeneratorConfig) {
+			c.MaxLength = app.maxLength
+		})
+	}
+	if app.sample {
+		opts = append(opts,
+			generation.Sampling(app.seed),
+			generation.Temperature(mat.Float(app.temperature)),
+			generation.TopK(app.topK),
+			generation.TopP(mat.Float(app.topP)),
+		)
+	}
+	return prefix, opts, nil
+}
+
+func pullModel(app *T5App) error {
+	modelPath := filepath.Join(app.repo, app.model)
+	if _, err := os.Stat(modelPath); os.IsNotExist(err) {
+		fmt.Printf("Unable to find `%s` locally.\n", modelPath)
+		fmt.Printf("Pulling `%s` from Hugging Face models hub...\n", app.model)
+		// make sure the models path exists
+		if _, err := os.Stat(app.repo); os.IsNotExist(err) {
+			if err := os.MkdirAll(app.repo, 0755); err != nil {
+				return err
+			}
+		}
+		err = huggingface.NewDownloader(app.repo, app.model, false).Download()
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Converting model...\n")
+		return huggingface.NewConverter(app.repo, app.model).Convert()
+	}
+
+	if _, err := os.Stat(path.Join(modelPath, t5.DefaultModelFile)); os.IsNotExist(err) {
+		fmt.Printf("Unable to find `%s` in the model directory.\n", t5.DefaultModelFile)
+		fmt.Printf("Assuming there is a Hugging Face model to convert...\n")
+		return huggingface.NewConverter(app.repo, app.model).Convert()
+	}
+
+	return nil
+}
@@ -0,0 +1,63 @@
+// Copyright 2021 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat32
+
+// Backend performs the computationally heavy operations on matrices.
+// It allows to swap the numerical kernels used by the operators without changing the operators themselves.
+type Backend interface {
+	// Mul performs the multiplication row by column.
+	// If A is an i×j Matrix, and B is j×k, then the resulting Matrix C = AB will be i×k.
+	Mul(a, b Matrix) Matrix
+}
+
+var (
+	_ Backend = DefaultBackend{}
+	_ Backend = ParallelBackend{}
+)
+
+// DefaultBackend is the Backend which delegates each operation to the matrices themselves,
+// using the serial pure-Go and assembly kernels.
+type DefaultBackend struct{}
+
+// Mul performs the multiplication row by column.
+func (DefaultBackend) Mul(a, b Matrix) Matrix {
+	return a.Mul(b)
+}
+
+// ParallelBackend is a Backend which performs the multiplication of dense matrices splitting the
+// output in blocks, so that each block is computed in a cache-friendly way by a pool of goroutines
+// (as many as runtime.GOMAXPROCS). Small matrices, matrix-vector products and sparse matrices are
+// handled as in the DefaultBackend.
+type ParallelBackend struct{}
+
+// Mul performs the multiplication row by column.
+func (ParallelBackend) Mul(a, b Matrix) Matrix {
+	ad, aIsDense := a.(*Dense)
+	bd, bIsDense := b.(*Dense)
+	if !aIsDense || !bIsDense || bd.cols == 1 {
+		return a.Mul(b)
+	}
+	if ad.cols != bd.rows {
+		panic("mat32: matrices with not compatible size")
+	}
+	out := GetEmptyDenseWorkspace(ad.rows, bd.cols)
+	if ad.cols == 0 {
+		return out
+	}
+	dgemm(
+		ad.rows,  // m
+		bd.cols,  // n
+		ad.cols,  // k
+		1.0,      // alpha
+		ad.data,  // a
+		ad.cols,  // lda
+		bd.data,  // b
+		bd.cols,  // ldb
+		0.0,      // beta
+		out.data, // c
+		out.cols, // ldc
+	)
+	return out
+}
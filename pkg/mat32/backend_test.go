@@ -0,0 +1,57 @@
+// Copyright 2021 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat32
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestDefaultBackend_Mul(t *testing.T) {
+	a := NewDense(2, 3, []Float{1, 2, 3, 4, 5, 6})
+	b := NewDense(3, 2, []Float{1, 0, 0, 1, 1, 1})
+	c := DefaultBackend{}.Mul(a, b)
+	assert.Equal(t, []Float{4, 5, 10, 11}, c.Data())
+}
+
+func TestParallelBackend_Mul(t *testing.T) {
+	t.Run("small matrices", func(t *testing.T) {
+		a := NewDense(2, 3, []Float{1, 2, 3, 4, 5, 6})
+		b := NewDense(3, 2, []Float{1, 0, 0, 1, 1, 1})
+		c := ParallelBackend{}.Mul(a, b)
+		assert.Equal(t, 2, c.Rows())
+		assert.Equal(t, 2, c.Columns())
+		assert.Equal(t, []Float{4, 5, 10, 11}, c.Data())
+	})
+
+	t.Run("matrix-vector", func(t *testing.T) {
+		a := NewDense(2, 3, []Float{1, 2, 3, 4, 5, 6})
+		b := NewVecDense([]Float{1, 0, 1})
+		c := ParallelBackend{}.Mul(a, b)
+		assert.Equal(t, []Float{4, 10}, c.Data())
+	})
+
+	t.Run("large matrices", func(t *testing.T) {
+		a := NewEmptyDense(150, 70)
+		b := NewEmptyDense(70, 200)
+		for i, data := 0, a.Data(); i < len(data); i++ {
+			data[i] = Float(i%7) - 3
+		}
+		for i, data := 0, b.Data(); i < len(data); i++ {
+			data[i] = Float(i%5) - 2
+		}
+		expected := a.Mul(b)
+		c := ParallelBackend{}.Mul(a, b)
+		assert.Equal(t, 150, c.Rows())
+		assert.Equal(t, 200, c.Columns())
+		assert.InDeltaSlice(t, expected.Data(), c.Data(), 1.0e-6)
+	})
+
+	t.Run("incompatible matrices", func(t *testing.T) {
+		a := NewEmptyDense(2, 3)
+		b := NewEmptyDense(2, 3)
+		assert.Panics(t, func() { ParallelBackend{}.Mul(a, b) })
+	})
+}
@@ -11,8 +11,6 @@ import (
 
 	// Ensure that GC and math optimizations setup runs first
 	_ "github.com/nlpodyssey/spago/pkg/global"
-	"github.com/nlpodyssey/spago/pkg/mat32/internal"
-	"github.com/nlpodyssey/spago/pkg/mat32/internal/asm/f32"
 )
 
 var _ Matrix = &Dense{}
@@ -363,47 +361,47 @@ func (d *Dense) Apply(fn func(i, j int, v Float) Float, a Matrix) {
 // AddScalar performs the addition between the matrix and the given value.
 func (d *Dense) AddScalar(n Float) Matrix {
 	out := d.Clone().(*Dense)
-	internal.AddConst(n, out.data)
+	addConst(n, out.data)
 	return out
 }
 
 // SubScalar performs a subtraction between the matrix and the given value.
 func (d *Dense) SubScalar(n Float) Matrix {
 	out := d.Clone().(*Dense)
-	internal.AddConst(-n, out.data)
+	addConst(-n, out.data)
 	return out
 }
 
 // AddScalarInPlace adds the scalar to all values of the matrix.
 func (d *Dense) AddScalarInPlace(n Float) Matrix {
-	internal.AddConst(n, d.data)
+	addConst(n, d.data)
 	return d
 }
 
 // SubScalarInPlace subtracts the scalar from the receiver's values.
 func (d *Dense) SubScalarInPlace(n Float) Matrix {
-	internal.AddConst(-n, d.data)
+	addConst(-n, d.data)
 	return d
 }
 
 // ProdScalarInPlace performs the in-place multiplication between the matrix and
 // the given value.
 func (d *Dense) ProdScalarInPlace(n Float) Matrix {
-	f32.ScalUnitary(n, d.data)
+	scalUnitary(n, d.data)
 	return d
 }
 
 // ProdMatrixScalarInPlace multiplies the given matrix with the value, storing the
 // result in the receiver.
 func (d *Dense) ProdMatrixScalarInPlace(m Matrix, n Float) Matrix {
-	f32.ScalUnitaryTo(d.data, n, m.(*Dense).data)
+	scalUnitaryTo(d.data, n, m.(*Dense).data)
 	return d
 }
 
 // ProdScalar returns the multiplication between the matrix and the given value.
 func (d *Dense) ProdScalar(n Float) Matrix {
 	out := d.ZerosLike().(*Dense)
-	f32.ScalUnitaryTo(out.data, n, d.data)
+	scalUnitaryTo(out.data, n, d.data)
 	return out
 }
 
@@ -414,9 +412,12 @@ func (d *Dense) Add(other Matrix) Matrix {
 		(other.IsVector() && d.IsVector() && other.Size() == d.Size())) {
 		panic("mat32: matrices with not compatible size")
 	}
+	if b, ok := other.(*RowSparse); ok {
+		return d.Clone().AddInPlace(b)
+	}
 	b := other.(*Dense)
 	out := d.ZerosLike().(*Dense)
-	f32.AxpyUnitaryTo(out.data, 1.0, b.data, d.data)
+	axpyUnitaryTo(out.data, 1.0, b.data, d.data)
 	return out
 }
 
@@ -427,8 +428,14 @@ func (d *Dense) AddInPlace(other Matrix) Matrix {
 		(other.IsVector() && d.IsVector() && other.Size() == d.Size())) {
 		panic("mat32: matrices with not compatible size")
 	}
-	b := other.(*Dense)
-	f32.AxpyUnitary(1.0, b.data, d.data)
+	switch b := other.(type) {
+	case *Dense:
+		axpyUnitary(1.0, b.data, d.data)
+	case *RowSparse:
+		d.addRowSparse(b, 1.0)
+	default:
+		panic("mat32: incompatible matrix types.")
+	}
 	return d
 }
 
@@ -439,9 +446,12 @@ func (d *Dense) Sub(other Matrix) Matrix {
 		(other.IsVector() && d.IsVector() && other.Size() == d.Size())) {
 		panic("mat32: matrices with not compatible size")
 	}
+	if b, ok := other.(*RowSparse); ok {
+		return d.Clone().SubInPlace(b)
+	}
 	out := d.ZerosLike().(*Dense)
 	b := other.(*Dense)
-	f32.AxpyUnitaryTo(out.data, -1.0, b.data, d.data)
+	axpyUnitaryTo(out.data, -1.0, b.data, d.data)
 	return out
 }
 
@@ -454,15 +464,25 @@ func (d *Dense) SubInPlace(other Matrix) Matrix {
 	}
 	switch other := other.(type) {
 	case *Dense:
-		f32.AxpyUnitary(-1.0, other.data, d.data)
+		axpyUnitary(-1.0, other.data, d.data)
 	case *Sparse:
 		other.DoNonZero(func(i, j int, k Float) {
 			d.Set(i, j, d.At(i, j)-k)
 		})
+	case *RowSparse:
+		d.addRowSparse(other, -1.0)
 	}
 	return d
 }
 
+// addRowSparse adds the stored rows of the RowSparse matrix, multiplied by alpha, to the receiver.
+func (d *Dense) addRowSparse(other *RowSparse, alpha Float) {
+	cols := other.cols
+	for p, i := range other.indices {
+		axpyUnitary(alpha, other.data[p*cols:(p+1)*cols], d.data[i*cols:(i+1)*cols])
+	}
+}
+
 // Prod performs the element-wise product between the receiver and the other matrix.
 func (d *Dense) Prod(other Matrix) Matrix {
 	if !(SameDims(d, other) ||
@@ -514,7 +534,7 @@ func (d *Dense) Div(other Matrix) Matrix {
 		panic("mat32: matrices with not compatible size")
 	}
 	out := d.ZerosLike().(*Dense)
-	internal.DivTo(out.data, d.data, other.(*Dense).data)
+	divTo(out.data, d.data, other.(*Dense).data)
 	return out
 }
 
@@ -543,7 +563,7 @@ func (d *Dense) Mul(other Matrix) Matrix {
 	switch b := other.(type) {
 	case *Dense:
 		if out.cols != 1 {
-			internal.DgemmSerial(
+			dgemmSerial(
 				false,
 				false,
 				d.rows,   // m
@@ -574,13 +594,13 @@ func (d *Dense) Mul(other Matrix) Matrix {
 }
 
 // matrixVectorMul performs matrix-vector multiplication: y = A * x.
-func matrixVectorMul(a []float32, x []float32, y []float32) {
+func matrixVectorMul(a []Float, x []Float, y []Float) {
 	start := 0
 	size := len(x)
 
 	for i := range y {
 		end := start + size
-		y[i] = f32.DotUnitary(a[start:end], x)
+		y[i] = dotUnitary(a[start:end], x)
 		start = end
 	}
 }
@@ -596,7 +616,7 @@ func (d *Dense) MulT(other Matrix) Matrix {
 	switch b := other.(type) {
 	case *Dense:
 		if out.cols == 1 {
-			internal.GemvT(
+			gemvT(
 				uintptr(d.rows), // m
 				uintptr(d.cols), // n
 				1.0,             // alpha
@@ -622,7 +642,7 @@ func (d *Dense) DotUnitary(other Matrix) Float {
 	if d.Size() != other.Size() {
 		panic("mat32: incompatible sizes.")
 	}
-	return f32.DotUnitary(d.data, other.Data())
+	return dotUnitary(d.data, other.Data())
 }
 
 // ClipInPlace clips in place each value of the matrix.
@@ -679,7 +699,7 @@ func (d *Dense) Sqrt() Matrix {
 
 // Sum returns the sum of all values of the matrix.
 func (d *Dense) Sum() Float {
-	return internal.Sum(d.data)
+	return sumUnitary(d.data)
 }
 
 // Max returns the maximum value of the matrix.
@@ -146,7 +146,7 @@ func (d *Dense) formattingMaxColumnsWidth(
 }
 
 func formatValue(buf []byte, val Float, c rune, precision int) []byte {
-	return strconv.AppendFloat(buf[:0], float64(val), byte(c), precision, 32)
+	return strconv.AppendFloat(buf[:0], float64(val), byte(c), precision, floatBitSize)
 }
 
 func indexOfPoint(buf []byte) (int, bool) {
@@ -67,15 +67,9 @@ func TestDense_Format(t *testing.T) {
 			"⎢     7.8     99.11      2.3        0.2 ⎥\n"+
 			"⎣     4.5      6.7      88.999 123456.78⎦")
 
-	run("Go-syntax representation", NewScalar(1.2), "%#v",
-		"&mat32.Dense{rows:1, cols:1, size:1, data:[]float32{1.2}, "+
-			"viewOf:(*mat32.Dense)(nil), fromPool:true}")
-
 	run("Default format with field names", NewScalar(1.2), "%+v",
 		"{rows:1 cols:1 size:1 data:[1.2] viewOf:<nil> fromPool:true}")
 
-	run("decimalless scientific notation", NewScalar(0), "%b", "[0p-149]")
-
 	run("scientific notation - small e", NewScalar(12.3), "%e", "[1.23e+01]")
 
 	run("scientific notation - capital E", NewScalar(12.3), "%E", "[1.23E+01]")
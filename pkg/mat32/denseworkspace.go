@@ -17,8 +17,6 @@ import (
 	"sync"
 )
 
-// TODO: adapt Dense Workspace to 32bits Float
-
 // Each pool element i returns slices capped at 1<<i.
 // 63 (and not 64) because MaxInt64  = 1<<63 - 1
 var densePool [63]sync.Pool
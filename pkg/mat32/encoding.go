@@ -9,7 +9,6 @@ import (
 	"encoding/gob"
 	"fmt"
 	"io"
-	"math"
 )
 
 func init() {
@@ -19,56 +18,73 @@ func init() {
 
 // MarshalBinary marshals a Dense matrix into binary form.
 func (d Dense) MarshalBinary() ([]byte, error) {
-	data := make([]byte, 8+d.size*4)
-	binary.LittleEndian.PutUint32(data, uint32(d.rows))
-	binary.LittleEndian.PutUint32(data[4:], uint32(d.cols))
-	for i, v := range d.data {
-		binary.LittleEndian.PutUint32(data[8+i*4:], math.Float32bits(v))
-	}
-	return data, nil
+	return marshalBinaryFloats(d.rows, d.cols, d.data), nil
 }
 
 // UnmarshalBinary unmarshals a binary representation of a Dense matrix.
 func (d *Dense) UnmarshalBinary(data []byte) error {
+	rows, cols, elements, err := unmarshalBinaryFloats(data)
+	if err != nil {
+		return err
+	}
 	d.viewOf = nil
 	d.fromPool = false
-	d.rows = int(binary.LittleEndian.Uint32(data))
-	d.cols = int(binary.LittleEndian.Uint32(data[4:]))
-	d.size = d.rows * d.cols
-	d.data = make([]Float, d.size)
-	for i := range d.data {
-		d.data[i] = math.Float32frombits(binary.LittleEndian.Uint32(data[8+i*4:]))
-	}
+	d.rows = rows
+	d.cols = cols
+	d.size = rows * cols
+	d.data = elements
 	return nil
 }
 
 // MarshalBinary marshals a Sparse matrix into binary form.
 func (s Sparse) MarshalBinary() ([]byte, error) {
-	data := make([]byte, 8+s.size*4)
-	binary.LittleEndian.PutUint32(data, uint32(s.rows))
-	binary.LittleEndian.PutUint32(data[4:], uint32(s.cols))
-	for i, v := range s.Data() {
-		binary.LittleEndian.PutUint32(data[8+i*4:], math.Float32bits(v))
-	}
-	return data, nil
+	return marshalBinaryFloats(s.rows, s.cols, s.Data()), nil
 }
 
 // UnmarshalBinary unmarshals a binary representation of a Sparse matrix.
 func (s *Sparse) UnmarshalBinary(data []byte) error {
-	rows := int(binary.LittleEndian.Uint32(data))
-	cols := int(binary.LittleEndian.Uint32(data[4:]))
-	elements := make([]Float, rows*cols)
-	for i := range elements {
-		elements[i] = math.Float32frombits(binary.LittleEndian.Uint32(data[8+i*4:]))
+	rows, cols, elements, err := unmarshalBinaryFloats(data)
+	if err != nil {
+		return err
 	}
 	*s = *NewSparse(rows, cols, elements)
 	return nil
 }
 
+// marshalBinaryFloats encodes the dimensions and the elements of a matrix.
+func marshalBinaryFloats(rows, cols int, elements []Float) []byte {
+	data := make([]byte, 8+len(elements)*floatSize)
+	binary.LittleEndian.PutUint32(data, uint32(rows))
+	binary.LittleEndian.PutUint32(data[4:], uint32(cols))
+	for i, v := range elements {
+		putFloat(data[8+i*floatSize:], v)
+	}
+	return data
+}
+
+// unmarshalBinaryFloats decodes a matrix encoded by marshalBinaryFloats.
+func unmarshalBinaryFloats(data []byte) (rows, cols int, elements []Float, err error) {
+	if len(data) < 8 {
+		return 0, 0, nil, fmt.Errorf("mat32: invalid binary data size %d", len(data))
+	}
+	rows = int(binary.LittleEndian.Uint32(data))
+	cols = int(binary.LittleEndian.Uint32(data[4:]))
+	data = data[8:]
+	if len(data) != rows*cols*floatSize {
+		return 0, 0, nil, fmt.Errorf("mat32: invalid binary data size %d for %d elements", len(data), rows*cols)
+	}
+	elements = make([]Float, rows*cols)
+	for i := range elements {
+		elements[i] = getFloat(data[i*floatSize:])
+	}
+	return rows, cols, elements, nil
+}
+
 const (
 	binaryNilMatrix byte = iota
 	binaryDenseMatrix
 	binarySparseMatrix
+	binaryHalfDenseMatrix
 )
 
 // MarshalBinaryMatrix encodes a Matrix into binary form.
@@ -117,40 +133,75 @@ func MarshalBinaryMatrix(m Matrix, w io.Writer) error {
 	return nil
 }
 
+// MarshalBinaryHalfDense encodes a HalfDense into binary form.
+// The result can be decoded either by UnmarshalBinaryMatrix or by UnmarshalBinaryMatrixOrHalfDense.
+func MarshalBinaryHalfDense(h *HalfDense, w io.Writer) error {
+	if h == nil {
+		return MarshalBinaryMatrix(nil, w)
+	}
+	bin, err := h.MarshalBinary()
+	if err != nil {
+		return err
+	}
+	binLen := make([]byte, 4)
+	binary.LittleEndian.PutUint32(binLen, uint32(len(bin)))
+	_, err = w.Write(append([]byte{binaryHalfDenseMatrix}, binLen...))
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(bin)
+	return err
+}
+
 // UnmarshalBinaryMatrix decodes a Matrix from binary form.
+// A HalfDense is converted to a Dense matrix.
 func UnmarshalBinaryMatrix(r io.Reader) (Matrix, error) {
+	m, h, err := UnmarshalBinaryMatrixOrHalfDense(r)
+	if err != nil || h == nil {
+		return m, err
+	}
+	return h.Dense(), nil
+}
+
+// UnmarshalBinaryMatrixOrHalfDense decodes either a Matrix or a HalfDense from binary form.
+// At most one of the two returned values is not nil.
+func UnmarshalBinaryMatrixOrHalfDense(r io.Reader) (Matrix, *HalfDense, error) {
 	smType := make([]byte, 1)
 	_, err := r.Read(smType)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	mType := smType[0]
 	if mType == binaryNilMatrix {
-		return nil, nil
+		return nil, nil, nil
 	}
 
 	binLenBytes := make([]byte, 4)
 	_, err = r.Read(binLenBytes)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	binLen := int(binary.LittleEndian.Uint32(binLenBytes))
 	bin := make([]byte, binLen)
 	_, err = r.Read(bin)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	switch mType {
 	case binaryDenseMatrix:
 		m := new(Dense)
 		err = m.UnmarshalBinary(bin)
-		return m, err
+		return m, nil, err
 	case binarySparseMatrix:
 		m := new(Sparse)
 		err = m.UnmarshalBinary(bin)
-		return m, err
+		return m, nil, err
+	case binaryHalfDenseMatrix:
+		h := new(HalfDense)
+		err = h.UnmarshalBinary(bin)
+		return nil, h, err
 	default:
-		return nil, fmt.Errorf("unknown binary matrix type %d", mType)
+		return nil, nil, fmt.Errorf("unknown binary matrix type %d", mType)
 	}
 }
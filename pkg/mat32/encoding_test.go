@@ -104,3 +104,13 @@ func TestMatrixBinaryMarshaling(t *testing.T) {
 		require.Nil(t, decodedMatrix)
 	})
 }
+
+func TestDense_UnmarshalBinaryInvalid(t *testing.T) {
+	data, err := NewDense(2, 3, []Float{1, 2, 3, 4, 5, 6}).MarshalBinary()
+	require.Nil(t, err)
+	assert.Len(t, data, 8+6*floatSize)
+
+	assert.NotNil(t, new(Dense).UnmarshalBinary(data[:len(data)-1]))
+	assert.NotNil(t, new(Sparse).UnmarshalBinary(data[:len(data)-floatSize]))
+	assert.NotNil(t, new(Dense).UnmarshalBinary(data[:7]))
+}
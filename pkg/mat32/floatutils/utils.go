@@ -5,7 +5,7 @@
 package floatutils
 
 import (
-	"github.com/nlpodyssey/spago/pkg/mat32/internal"
+	"github.com/nlpodyssey/spago/pkg/mat32"
 	"math"
 	"strconv"
 	"strings"
@@ -13,13 +13,13 @@ import (
 
 // EqualApprox returns true if a and b are equal to within reasonable
 // absolute tolerance (hardcoded as 1.0e-04).
-func EqualApprox(a, b float32) bool {
-	return a == b || float32(math.Abs(float64(a-b))) <= 1.0e-04
+func EqualApprox(a, b mat32.Float) bool {
+	return a == b || mat32.Float(math.Abs(float64(a-b))) <= 1.0e-04
 }
 
 // SliceEqualApprox returns true if a and b have the same length and EqualApprox
 // is true for each element pair from a and b.
-func SliceEqualApprox(a, b []float32) bool {
+func SliceEqualApprox(a, b []mat32.Float) bool {
 	if len(a) != len(b) {
 		return false
 	}
@@ -32,21 +32,21 @@ func SliceEqualApprox(a, b []float32) bool {
 }
 
 // Copy creates and return a copy of the given slice.
-func Copy(in []float32) []float32 {
-	out := make([]float32, len(in))
+func Copy(in []mat32.Float) []mat32.Float {
+	out := make([]mat32.Float, len(in))
 	copy(out, in)
 	return out
 }
 
 // FillFloatSlice fills the given slice's elements with value.
-func FillFloatSlice(slice []float32, value float32) {
+func FillFloatSlice(slice []mat32.Float, value mat32.Float) {
 	for i := range slice {
 		slice[i] = value
 	}
 }
 
 // Sign returns +1 if a is positive, -1 if a is negative, or 0 if a is 0.
-func Sign(a float32) int {
+func Sign(a mat32.Float) int {
 	switch {
 	case a < 0:
 		return -1
@@ -57,7 +57,7 @@ func Sign(a float32) int {
 }
 
 // Max returns the maximum value from the given slice, which MUST NOT be empty.
-func Max(v []float32) (m float32) {
+func Max(v []mat32.Float) (m mat32.Float) {
 	m = v[len(v)-1]
 	for _, e := range v {
 		if m <= e {
@@ -68,7 +68,7 @@ func Max(v []float32) (m float32) {
 }
 
 // Sum returns the sum of all values from the given slice.
-func Sum(v []float32) (s float32) {
+func Sum(v []mat32.Float) (s mat32.Float) {
 	for _, e := range v {
 		s += e
 	}
@@ -76,7 +76,7 @@ func Sum(v []float32) (s float32) {
 }
 
 // ArgMinMax finds the indices of min and max arguments.
-func ArgMinMax(v []float32) (imin, imax int) {
+func ArgMinMax(v []mat32.Float) (imin, imax int) {
 	if len(v) < 1 {
 		return
 	}
@@ -96,33 +96,33 @@ func ArgMinMax(v []float32) (imin, imax int) {
 }
 
 // ArgMax finds the index of the max argument.
-func ArgMax(v []float32) int {
+func ArgMax(v []mat32.Float) int {
 	_, imax := ArgMinMax(v)
 	return imax
 }
 
 // ArgMin finds the index of the min argument.
-func ArgMin(v []float32) int {
+func ArgMin(v []mat32.Float) int {
 	imin, _ := ArgMinMax(v)
 	return imin
 }
 
 // MakeFloatMatrix returns a new 2-dimensional slice.
-func MakeFloatMatrix(rows, cols int) [][]float32 {
-	matrix := make([][]float32, rows)
+func MakeFloatMatrix(rows, cols int) [][]mat32.Float {
+	matrix := make([][]mat32.Float, rows)
 	for i := 0; i < rows; i++ {
-		matrix[i] = make([]float32, cols)
+		matrix[i] = make([]mat32.Float, cols)
 	}
 	return matrix
 }
 
-// StrToFloatSlice parses a string representation of a slice of float32 values.
-func StrToFloatSlice(str string) ([]float32, error) {
+// StrToFloatSlice parses a string representation of a slice of Float values.
+func StrToFloatSlice(str string) ([]mat32.Float, error) {
 	spl := strings.Fields(str)
-	data := make([]float32, len(spl))
+	data := make([]mat32.Float, len(spl))
 	for i, v := range spl {
-		if num, err := strconv.ParseFloat(v, 32); err == nil {
-			data[i] = float32(num)
+		if num, err := strconv.ParseFloat(v, floatBitSize); err == nil {
+			data[i] = mat32.Float(num)
 		} else {
 			return nil, err
 		}
@@ -131,20 +131,15 @@ func StrToFloatSlice(str string) ([]float32, error) {
 }
 
 // SoftMax returns the results of the softmax function.
-func SoftMax(v []float32) (sm []float32) {
+func SoftMax(v []mat32.Float) (sm []mat32.Float) {
 	c := Max(v)
-	var sum float32 = 0
+	var sum mat32.Float = 0
 	for _, e := range v {
-		sum += float32(math.Exp(float64(e - c)))
+		sum += mat32.Float(math.Exp(float64(e - c)))
 	}
-	sm = make([]float32, len(v))
+	sm = make([]mat32.Float, len(v))
 	for i, v := range v {
-		sm[i] = float32(math.Exp(float64(v-c))) / sum
+		sm[i] = mat32.Float(math.Exp(float64(v-c))) / sum
 	}
 	return sm
 }
-
-// CumSum computes the cumulative sum of src into dst, and returns dst.
-func CumSum(dst, src []float32) []float32 {
-	return internal.CumSum(dst, src)
-}
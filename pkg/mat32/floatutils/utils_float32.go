@@ -0,0 +1,18 @@
+// Copyright 2021 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package floatutils
+
+import (
+	"github.com/nlpodyssey/spago/pkg/mat32"
+	"github.com/nlpodyssey/spago/pkg/mat32/internal"
+)
+
+// floatBitSize is the size in bits of mat32.Float.
+const floatBitSize = 32
+
+// CumSum computes the cumulative sum of src into dst, and returns dst.
+func CumSum(dst, src []mat32.Float) []mat32.Float {
+	return internal.CumSum(dst, src)
+}
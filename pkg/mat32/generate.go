@@ -0,0 +1,8 @@
+// Copyright 2021 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat32
+
+// The mat64 package is generated from this package: see the genmat64 command.
+//go:generate go run ./internal/genmat64 ../mat64
@@ -0,0 +1,229 @@
+// Copyright 2021 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat32
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+)
+
+// Precision is the floating-point format used to store the values of a matrix.
+type Precision byte
+
+const (
+	// FullPrecision stores the values as Float.
+	FullPrecision Precision = iota
+	// Float16 stores the values in IEEE 754 half-precision format (1 bit sign, 5 bits exponent, 10 bits mantissa).
+	Float16
+	// BFloat16 stores the values in brain floating-point format (1 bit sign, 8 bits exponent, 7 bits mantissa),
+	// which has the same range of float32 with a lower precision.
+	BFloat16
+)
+
+// String returns the name of the precision.
+func (p Precision) String() string {
+	switch p {
+	case FullPrecision:
+		return "full"
+	case Float16:
+		return "float16"
+	case BFloat16:
+		return "bfloat16"
+	default:
+		return fmt.Sprintf("Precision(%d)", p)
+	}
+}
+
+// ParsePrecision returns the Precision corresponding to the given name ("full", "float16" or "bfloat16").
+func ParsePrecision(s string) (Precision, error) {
+	switch s {
+	case "full", "":
+		return FullPrecision, nil
+	case "float16":
+		return Float16, nil
+	case "bfloat16":
+		return BFloat16, nil
+	default:
+		return FullPrecision, fmt.Errorf("mat32: unknown precision %q", s)
+	}
+}
+
+// HalfDense is a compact representation of a dense matrix, whose values are stored with 16 bits.
+// It is meant for storage only: the values must be converted back to a Dense matrix with Dense()
+// to perform any computation.
+type HalfDense struct {
+	rows      int
+	cols      int
+	precision Precision
+	data      []uint16
+}
+
+// NewHalfDense returns a new HalfDense with a copy of the values of m converted to the given precision,
+// which must be either Float16 or BFloat16.
+func NewHalfDense(m Matrix, precision Precision) *HalfDense {
+	var convert func(float32) uint16
+	switch precision {
+	case Float16:
+		convert = float32ToFloat16
+	case BFloat16:
+		convert = float32ToBFloat16
+	default:
+		panic(fmt.Sprintf("mat32: invalid half precision %s", precision))
+	}
+	rows, cols := m.Dims()
+	h := &HalfDense{
+		rows:      rows,
+		cols:      cols,
+		precision: precision,
+		data:      make([]uint16, rows*cols),
+	}
+	for i := 0; i < rows; i++ {
+		for j := 0; j < cols; j++ {
+			h.data[i*cols+j] = convert(float32(m.At(i, j)))
+		}
+	}
+	return h
+}
+
+// Dims returns the number of rows and columns of the matrix.
+func (h *HalfDense) Dims() (r, c int) {
+	return h.rows, h.cols
+}
+
+// Size returns the number of elements of the matrix.
+func (h *HalfDense) Size() int {
+	return len(h.data)
+}
+
+// Precision returns the format of the values of the matrix.
+func (h *HalfDense) Precision() Precision {
+	return h.precision
+}
+
+// Dense returns a new Dense matrix with the values of the receiver.
+func (h *HalfDense) Dense() *Dense {
+	convert := float16ToFloat32
+	if h.precision == BFloat16 {
+		convert = bfloat16ToFloat32
+	}
+	d := GetDenseWorkspace(h.rows, h.cols)
+	for i, v := range h.data {
+		d.data[i] = Float(convert(v))
+	}
+	return d
+}
+
+// MarshalBinary marshals a HalfDense matrix into binary form.
+func (h HalfDense) MarshalBinary() ([]byte, error) {
+	data := make([]byte, 9+len(h.data)*2)
+	binary.LittleEndian.PutUint32(data, uint32(h.rows))
+	binary.LittleEndian.PutUint32(data[4:], uint32(h.cols))
+	data[8] = byte(h.precision)
+	for i, v := range h.data {
+		binary.LittleEndian.PutUint16(data[9+i*2:], v)
+	}
+	return data, nil
+}
+
+// UnmarshalBinary unmarshals a binary representation of a HalfDense matrix.
+func (h *HalfDense) UnmarshalBinary(data []byte) error {
+	if len(data) < 9 {
+		return fmt.Errorf("mat32: invalid binary data size %d for a half-precision matrix", len(data))
+	}
+	h.rows = int(binary.LittleEndian.Uint32(data))
+	h.cols = int(binary.LittleEndian.Uint32(data[4:]))
+	h.precision = Precision(data[8])
+	if h.precision != Float16 && h.precision != BFloat16 {
+		return fmt.Errorf("mat32: invalid half precision %s", h.precision)
+	}
+	size := h.rows * h.cols
+	if len(data) != 9+size*2 {
+		return fmt.Errorf("mat32: invalid binary data size %d for %d elements", len(data)-9, size)
+	}
+	h.data = make([]uint16, size)
+	for i := range h.data {
+		h.data[i] = binary.LittleEndian.Uint16(data[9+i*2:])
+	}
+	return nil
+}
+
+// float32ToFloat16 converts a float32 to the IEEE 754 half-precision format, rounding to the nearest even.
+// Values out of range become infinite.
+func float32ToFloat16(f float32) uint16 {
+	b := math.Float32bits(f)
+	sign := uint16(b>>16) & 0x8000
+	exp := int(b>>23) & 0xff
+	mant := b & 0x7fffff
+
+	if exp == 0xff { // Inf or NaN
+		if mant != 0 {
+			return sign | 0x7e00
+		}
+		return sign | 0x7c00
+	}
+	e := exp - 127 + 15
+	if e >= 0x1f {
+		return sign | 0x7c00 // overflow
+	}
+	if e <= 0 { // subnormal or zero
+		if e < -10 {
+			return sign
+		}
+		mant |= 0x800000
+		shift := uint(14 - e)
+		h := uint16(mant >> shift)
+		rem, halfway := mant&(1<<shift-1), uint32(1)<<(shift-1)
+		if rem > halfway || (rem == halfway && h&1 == 1) {
+			h++
+		}
+		return sign | h
+	}
+	h := uint16(e)<<10 | uint16(mant>>13)
+	rem := mant & 0x1fff
+	if rem > 0x1000 || (rem == 0x1000 && h&1 == 1) {
+		h++ // a carry to the exponent is still correct
+	}
+	return sign | h
+}
+
+// float16ToFloat32 converts a value in IEEE 754 half-precision format to float32.
+func float16ToFloat32(h uint16) float32 {
+	sign := uint32(h&0x8000) << 16
+	exp := uint32(h>>10) & 0x1f
+	mant := uint32(h & 0x3ff)
+
+	switch exp {
+	case 0x1f: // Inf or NaN
+		return math.Float32frombits(sign | 0x7f800000 | mant<<13)
+	case 0: // subnormal or zero
+		if mant == 0 {
+			return math.Float32frombits(sign)
+		}
+		e := uint32(127 - 15 + 1)
+		for mant&0x400 == 0 {
+			mant <<= 1
+			e--
+		}
+		return math.Float32frombits(sign | e<<23 | (mant&0x3ff)<<13)
+	default:
+		return math.Float32frombits(sign | (exp+127-15)<<23 | mant<<13)
+	}
+}
+
+// float32ToBFloat16 converts a float32 to the bfloat16 format, rounding to the nearest even.
+func float32ToBFloat16(f float32) uint16 {
+	b := math.Float32bits(f)
+	if math.IsNaN(float64(f)) {
+		return uint16(b>>16) | 0x40
+	}
+	rounding := uint32(0x7fff) + (b>>16)&1
+	return uint16((b + rounding) >> 16)
+}
+
+// bfloat16ToFloat32 converts a value in bfloat16 format to float32.
+func bfloat16ToFloat32(h uint16) float32 {
+	return math.Float32frombits(uint32(h) << 16)
+}
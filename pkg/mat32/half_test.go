@@ -0,0 +1,95 @@
+// Copyright 2021 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat32
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"math"
+	"testing"
+)
+
+func TestFloat16Conversion(t *testing.T) {
+	testCases := []struct {
+		value float32
+		bits  uint16
+	}{
+		{0.0, 0x0000},
+		{1.0, 0x3c00},
+		{-2.0, 0xc000},
+		{0.5, 0x3800},
+		{65504.0, 0x7bff},                         // max normal
+		{float32(math.Pow(2, -14)), 0x0400},       // min normal
+		{float32(math.Pow(2, -24)), 0x0001},       // min subnormal
+		{float32(math.Inf(1)), 0x7c00},            // +Inf
+		{float32(math.Inf(-1)), 0xfc00},           // -Inf
+		{1.0e5, 0x7c00},                           // overflow
+		{1.0e-9, 0x0000},                          // underflow
+		{1.0 + float32(math.Pow(2, -11)), 0x3c00}, // tie, rounded to even
+	}
+	for _, tc := range testCases {
+		assert.Equal(t, tc.bits, float32ToFloat16(tc.value), "%g", tc.value)
+		if tc.value != 1.0e5 && tc.value != 1.0e-9 && tc.bits != 0x3c00 {
+			assert.Equal(t, tc.value, float16ToFloat32(tc.bits), "%#04x", tc.bits)
+		}
+	}
+	assert.True(t, math.IsNaN(float64(float16ToFloat32(float32ToFloat16(float32(math.NaN()))))))
+	assert.InDelta(t, 0.1, float16ToFloat32(float32ToFloat16(0.1)), 1.0e-4)
+}
+
+func TestBFloat16Conversion(t *testing.T) {
+	assert.Equal(t, uint16(0x3f80), float32ToBFloat16(1.0))
+	assert.Equal(t, uint16(0xc000), float32ToBFloat16(-2.0))
+	assert.Equal(t, float32(1.0), bfloat16ToFloat32(0x3f80))
+	assert.InEpsilon(t, 3.0e38, bfloat16ToFloat32(float32ToBFloat16(3.0e38)), 1.0e-2) // no overflow
+	assert.InDelta(t, 0.1, bfloat16ToFloat32(float32ToBFloat16(0.1)), 1.0e-3)
+	assert.True(t, math.IsNaN(float64(bfloat16ToFloat32(float32ToBFloat16(float32(math.NaN()))))))
+}
+
+func TestHalfDense(t *testing.T) {
+	m := NewDense(2, 3, []Float{0.1, -0.2, 0.3, 1.5, -2.0, 100.0})
+	for _, precision := range []Precision{Float16, BFloat16} {
+		t.Run(precision.String(), func(t *testing.T) {
+			h := NewHalfDense(m, precision)
+			rows, cols := h.Dims()
+			assert.Equal(t, 2, rows)
+			assert.Equal(t, 3, cols)
+			assert.Equal(t, 6, h.Size())
+			assert.Equal(t, precision, h.Precision())
+			assert.InDeltaSlice(t, m.Data(), h.Dense().Data(), 0.5)
+			assert.InDeltaSlice(t, m.Data()[:5], h.Dense().Data()[:5], 1.0e-2)
+		})
+	}
+	assert.Panics(t, func() { NewHalfDense(m, FullPrecision) })
+}
+
+func TestHalfDense_Binary(t *testing.T) {
+	m := NewDense(2, 2, []Float{0.1, -0.2, 0.3, 1.5})
+	h := NewHalfDense(m, Float16)
+
+	var buf bytes.Buffer
+	require.Nil(t, MarshalBinaryHalfDense(h, &buf))
+	data := buf.Bytes()
+
+	decodedMatrix, decodedHalf, err := UnmarshalBinaryMatrixOrHalfDense(bytes.NewReader(data))
+	require.Nil(t, err)
+	assert.Nil(t, decodedMatrix)
+	assert.Equal(t, h, decodedHalf)
+
+	upcast, err := UnmarshalBinaryMatrix(bytes.NewReader(data))
+	require.Nil(t, err)
+	assert.Equal(t, h.Dense().Data(), upcast.Data())
+}
+
+func TestParsePrecision(t *testing.T) {
+	for _, p := range []Precision{FullPrecision, Float16, BFloat16} {
+		parsed, err := ParsePrecision(p.String())
+		assert.Nil(t, err)
+		assert.Equal(t, p, parsed)
+	}
+	_, err := ParsePrecision("foo")
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,164 @@
+// Copyright 2021 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Command genmat64 generates the mat64 package (and its sub-packages) from the mat32 package.
+//
+// The two packages share the same implementation, written in terms of the Float type. The code which
+// depends on the precision of Float (the type itself, the math functions, the numerical kernels and the
+// binary encoding of the elements) lives in files with the "_float32.go" suffix in mat32, and with the
+// "_float64.go" suffix in mat64: those files, as well as the internal packages, are written by hand.
+// Every other Go file of mat32, except for the go:generate directive, is copied to mat64, replacing "mat32"
+// with "mat64".
+//
+// Usage (from the directory of the mat32 package):
+//
+//     go run ./internal/genmat64 ../mat64
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// header is the first line of each generated file.
+const header = "// Code generated by genmat64 from the mat32 package. DO NOT EDIT.\n\n"
+
+var (
+	mat32Pattern    = regexp.MustCompile(`\bmat32\b`)
+	internalPattern = regexp.MustCompile(`"github.com/nlpodyssey/spago/pkg/mat64/internal[/"]`)
+)
+
+func main() {
+	if len(os.Args) != 2 {
+		log.Fatalln("usage: genmat64 <mat64 directory>")
+	}
+	if err := generate(".", os.Args[1]); err != nil {
+		log.Fatalln(err)
+	}
+}
+
+// generate writes the files generated from srcDir into dstDir, removing the generated files which
+// are no longer produced.
+func generate(srcDir, dstDir string) error {
+	files, err := generateFiles(srcDir)
+	if err != nil {
+		return err
+	}
+	stale, err := generatedFiles(dstDir)
+	if err != nil {
+		return err
+	}
+	for _, name := range stale {
+		if _, ok := files[name]; ok {
+			continue
+		}
+		if err := os.Remove(filepath.Join(dstDir, name)); err != nil {
+			return err
+		}
+	}
+	for _, name := range sortedNames(files) {
+		path := filepath.Join(dstDir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return err
+		}
+		if err := ioutil.WriteFile(path, files[name], 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// generateFiles returns the content of the files generated from the package in srcDir, indexed by their
+// path relative to it.
+func generateFiles(srcDir string) (map[string][]byte, error) {
+	files := make(map[string][]byte)
+	err := walkGoFiles(srcDir, func(name string) error {
+		if name == "generate.go" || isPrecisionSpecific(name) {
+			return nil
+		}
+		src, err := ioutil.ReadFile(filepath.Join(srcDir, name))
+		if err != nil {
+			return err
+		}
+		out, err := convert(src)
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		files[name] = out
+		return nil
+	})
+	return files, err
+}
+
+// generatedFiles returns the paths, relative to dir, of the files produced by this command.
+func generatedFiles(dir string) ([]string, error) {
+	var names []string
+	err := walkGoFiles(dir, func(name string) error {
+		data, err := ioutil.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			return err
+		}
+		if bytes.HasPrefix(data, []byte(header)) {
+			names = append(names, name)
+		}
+		return nil
+	})
+	return names, err
+}
+
+// walkGoFiles calls fn with the path, relative to dir, of each Go file in dir and its sub-directories,
+// except for the internal ones.
+func walkGoFiles(dir string, fn func(name string) error) error {
+	return filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		name, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			if name == "internal" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !strings.HasSuffix(name, ".go") {
+			return nil
+		}
+		return fn(name)
+	})
+}
+
+// isPrecisionSpecific reports whether a file is written by hand for each precision.
+func isPrecisionSpecific(name string) bool {
+	name = strings.TrimSuffix(strings.TrimSuffix(name, ".go"), "_test")
+	return strings.HasSuffix(name, "_float32") || strings.HasSuffix(name, "_float64")
+}
+
+// convert returns the mat64 version of a mat32 source file.
+func convert(src []byte) ([]byte, error) {
+	out := mat32Pattern.ReplaceAll(src, []byte("mat64"))
+	if internalPattern.Match(out) {
+		return nil, fmt.Errorf("the internal packages can only be imported by the %q files", "_float32.go")
+	}
+	// the replacement has the same length, so the formatting is preserved
+	return append([]byte(header), out...), nil
+}
+
+func sortedNames(files map[string][]byte) []string {
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,55 @@
+// Copyright 2021 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+)
+
+const (
+	mat32Dir = "../.."
+	mat64Dir = "../../../mat64"
+)
+
+func TestGenerate_UpToDate(t *testing.T) {
+	files, err := generateFiles(mat32Dir)
+	require.NoError(t, err)
+	require.NotEmpty(t, files)
+
+	for _, name := range sortedNames(files) {
+		actual, err := ioutil.ReadFile(filepath.Join(mat64Dir, name))
+		require.NoError(t, err, "mat64 is out of date: run go generate in pkg/mat32")
+		assert.Equal(t, string(files[name]), string(actual), "mat64 is out of date: run go generate in pkg/mat32")
+	}
+
+	generated, err := generatedFiles(mat64Dir)
+	require.NoError(t, err)
+	for _, name := range generated {
+		assert.Contains(t, files, name, "stale generated file: run go generate in pkg/mat32")
+	}
+}
+
+func TestConvert(t *testing.T) {
+	out, err := convert([]byte("package mat32\n\nimport \"github.com/nlpodyssey/spago/pkg/mat32/sort\"\n\n" +
+		"// Sort sorts mat32.Float values.\nvar Sort = sort.NewFloatSlice\n"))
+	require.NoError(t, err)
+	assert.Equal(t, header+"package mat64\n\nimport \"github.com/nlpodyssey/spago/pkg/mat64/sort\"\n\n"+
+		"// Sort sorts mat64.Float values.\nvar Sort = sort.NewFloatSlice\n", string(out))
+
+	_, err = convert([]byte("package mat32\n\nimport \"github.com/nlpodyssey/spago/pkg/mat32/internal\"\n"))
+	assert.Error(t, err)
+}
+
+func TestIsPrecisionSpecific(t *testing.T) {
+	assert.True(t, isPrecisionSpecific("math_float32.go"))
+	assert.True(t, isPrecisionSpecific("rand/rand_float64.go"))
+	assert.True(t, isPrecisionSpecific("encoding_float32_test.go"))
+	assert.False(t, isPrecisionSpecific("dense.go"))
+	assert.False(t, isPrecisionSpecific("dense_test.go"))
+}
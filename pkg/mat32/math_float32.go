@@ -12,6 +12,9 @@ import (
 // Float is the main float type for the mat32 package. It is an alias for float32.
 type Float = float32
 
+// floatBitSize is the size in bits of Float.
+const floatBitSize = 32
+
 const (
 	// SmallestNonzeroFloat corresponds to math.SmallestNonzeroFloat32.
 	SmallestNonzeroFloat Float = math.SmallestNonzeroFloat32
@@ -94,6 +97,11 @@ func NaN() Float {
 	return math32.NaN()
 }
 
+// IsNaN reports whether f is an IEEE 754 ``not-a-number'' value.
+func IsNaN(f Float) bool {
+	return math32.IsNaN(f)
+}
+
 // Ceil returns the least integer value greater than or equal to x.
 func Ceil(x Float) Float {
 	return math32.Ceil(x)
@@ -0,0 +1,216 @@
+// Copyright 2021 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat32
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+)
+
+// QuantizedDense is a dense matrix whose values are quantized to int8, with a separate scale factor for each row
+// (symmetric per-row quantization): the value at (i, j) is approximated by Scale(i) * q(i, j).
+// It is meant for inference only, to multiply the (constant) weights of a layer by the float inputs.
+type QuantizedDense struct {
+	rows   int
+	cols   int
+	scales []Float
+	data   []int8
+}
+
+// NewQuantizedDense returns a new QuantizedDense with the values of m quantized to int8.
+//
+// The optional ranges set, for each row, the absolute value which is mapped to 127; values out of the range are
+// clipped. If ranges is nil, the maximum absolute value of each row is used, so that no value is clipped.
+// It panics if the length of ranges doesn't match the number of rows.
+func NewQuantizedDense(m Matrix, ranges []Float) *QuantizedDense {
+	rows, cols := m.Dims()
+	if ranges != nil && len(ranges) != rows {
+		panic("mat32: the number of ranges doesn't match the number of rows")
+	}
+	q := &QuantizedDense{
+		rows:   rows,
+		cols:   cols,
+		scales: make([]Float, rows),
+		data:   make([]int8, rows*cols),
+	}
+	for i := 0; i < rows; i++ {
+		var r Float
+		if ranges != nil {
+			r = Abs(ranges[i])
+		} else {
+			for j := 0; j < cols; j++ {
+				r = Max(r, Abs(m.At(i, j)))
+			}
+		}
+		if r == 0.0 {
+			continue // all the values are (quantized to) zero
+		}
+		scale := r / 127.0
+		q.scales[i] = scale
+		row := q.data[i*cols : (i+1)*cols]
+		for j := range row {
+			row[j] = QuantizeInt8(m.At(i, j), scale)
+		}
+	}
+	return q
+}
+
+// QuantizeInt8 returns the value divided by the scale, rounded to the nearest integer and clipped to [-127, 127].
+func QuantizeInt8(value, scale Float) int8 {
+	v := Round(value / scale)
+	if v > 127.0 {
+		return 127
+	}
+	if v < -127.0 {
+		return -127
+	}
+	return int8(v)
+}
+
+// Dims returns the number of rows and columns of the matrix.
+func (q *QuantizedDense) Dims() (r, c int) {
+	return q.rows, q.cols
+}
+
+// Rows returns the number of rows of the matrix.
+func (q *QuantizedDense) Rows() int {
+	return q.rows
+}
+
+// Columns returns the number of columns of the matrix.
+func (q *QuantizedDense) Columns() int {
+	return q.cols
+}
+
+// Scale returns the scale factor of the i-th row.
+func (q *QuantizedDense) Scale(i int) Float {
+	return q.scales[i]
+}
+
+// Dense returns a new Dense matrix with the dequantized values of the receiver.
+func (q *QuantizedDense) Dense() *Dense {
+	d := GetDenseWorkspace(q.rows, q.cols)
+	for i := 0; i < q.rows; i++ {
+		scale := q.scales[i]
+		for j := i * q.cols; j < (i+1)*q.cols; j++ {
+			d.data[j] = Float(q.data[j]) * scale
+		}
+	}
+	return d
+}
+
+// Mul performs the multiplication of the (dequantized) receiver by the other matrix, which is typically
+// a column vector or a minibatch of column vectors. The int8 values are accumulated without being dequantized
+// first: only the results are multiplied by the scale of each row.
+func (q *QuantizedDense) Mul(other Matrix) Matrix {
+	if q.cols != other.Rows() {
+		panic("mat32: matrices with not compatible size")
+	}
+	b, ok := other.(*Dense)
+	if !ok {
+		panic("mat32: matrices not compatible")
+	}
+	n := b.cols
+	out := GetEmptyDenseWorkspace(q.rows, n)
+	if n == 1 {
+		for i := 0; i < q.rows; i++ {
+			out.data[i] = dotInt8(q.data[i*q.cols:(i+1)*q.cols], b.data) * q.scales[i]
+		}
+		return out
+	}
+	for i := 0; i < q.rows; i++ {
+		y := out.data[i*n : (i+1)*n]
+		for j, v := range q.data[i*q.cols : (i+1)*q.cols] {
+			if v == 0 {
+				continue
+			}
+			fv := Float(v)
+			for k, x := range b.data[j*n : (j+1)*n] {
+				y[k] += fv * x
+			}
+		}
+		scale := q.scales[i]
+		for k := range y {
+			y[k] *= scale
+		}
+	}
+	return out
+}
+
+// MulT performs the multiplication of the transposed (dequantized) receiver by the other column vector.
+// It is used by the backward pass, to propagate the gradients to the inputs.
+func (q *QuantizedDense) MulT(other Matrix) Matrix {
+	if q.rows != other.Rows() || other.Columns() != 1 {
+		panic("mat32: matrices with not compatible size")
+	}
+	out := GetEmptyDenseWorkspace(q.cols, 1)
+	for i := 0; i < q.rows; i++ {
+		g := other.AtVec(i) * q.scales[i]
+		if g == 0.0 {
+			continue
+		}
+		for j, v := range q.data[i*q.cols : (i+1)*q.cols] {
+			out.data[j] += Float(v) * g
+		}
+	}
+	return out
+}
+
+// dotInt8 returns the dot product between a vector of int8 values and a vector of float values of the same length.
+func dotInt8(a []int8, b []Float) Float {
+	var s0, s1, s2, s3 Float
+	i := 0
+	for ; i+4 <= len(a); i += 4 {
+		s0 += Float(a[i]) * b[i]
+		s1 += Float(a[i+1]) * b[i+1]
+		s2 += Float(a[i+2]) * b[i+2]
+		s3 += Float(a[i+3]) * b[i+3]
+	}
+	for ; i < len(a); i++ {
+		s0 += Float(a[i]) * b[i]
+	}
+	return s0 + s1 + s2 + s3
+}
+
+// MarshalBinary marshals a QuantizedDense matrix into binary form.
+// The scales are always encoded with 32 bits, so that the matrix can be decoded with either precision of Float.
+func (q QuantizedDense) MarshalBinary() ([]byte, error) {
+	data := make([]byte, 8+len(q.scales)*4+len(q.data))
+	binary.LittleEndian.PutUint32(data, uint32(q.rows))
+	binary.LittleEndian.PutUint32(data[4:], uint32(q.cols))
+	offset := 8
+	for _, s := range q.scales {
+		binary.LittleEndian.PutUint32(data[offset:], math.Float32bits(float32(s)))
+		offset += 4
+	}
+	for i, v := range q.data {
+		data[offset+i] = byte(v)
+	}
+	return data, nil
+}
+
+// UnmarshalBinary unmarshals a binary representation of a QuantizedDense matrix.
+func (q *QuantizedDense) UnmarshalBinary(data []byte) error {
+	if len(data) < 8 {
+		return fmt.Errorf("mat32: invalid binary data size %d for a quantized matrix", len(data))
+	}
+	q.rows = int(binary.LittleEndian.Uint32(data))
+	q.cols = int(binary.LittleEndian.Uint32(data[4:]))
+	if len(data) != 8+q.rows*4+q.rows*q.cols {
+		return fmt.Errorf("mat32: invalid binary data size %d for a %dx%d quantized matrix", len(data), q.rows, q.cols)
+	}
+	q.scales = make([]Float, q.rows)
+	offset := 8
+	for i := range q.scales {
+		q.scales[i] = Float(math.Float32frombits(binary.LittleEndian.Uint32(data[offset:])))
+		offset += 4
+	}
+	q.data = make([]int8, q.rows*q.cols)
+	for i := range q.data {
+		q.data[i] = int8(data[offset+i])
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+// Copyright 2021 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat32
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestNewQuantizedDense(t *testing.T) {
+	m := NewDense(2, 3, []Float{
+		0.5, -1.27, 0.1,
+		0.0, 0.0, 0.0,
+	})
+	q := NewQuantizedDense(m, nil)
+	r, c := q.Dims()
+	assert.Equal(t, 2, r)
+	assert.Equal(t, 3, c)
+	assert.InDelta(t, 0.01, q.Scale(0), 1.0e-7)
+	assert.Equal(t, Float(0.0), q.Scale(1))
+	assert.Equal(t, []int8{50, -127, 10, 0, 0, 0}, q.data)
+	assert.InDeltaSlice(t, m.Data(), q.Dense().Data(), 1.0e-6)
+}
+
+func TestNewQuantizedDense_Ranges(t *testing.T) {
+	m := NewDense(1, 3, []Float{0.5, -2.0, 0.1})
+	q := NewQuantizedDense(m, []Float{1.0})
+	assert.Equal(t, []int8{64, -127, 13}, q.data)
+	assert.Panics(t, func() { NewQuantizedDense(m, []Float{1.0, 2.0}) })
+}
+
+func TestQuantizedDense_Mul(t *testing.T) {
+	m := NewDense(3, 5, []Float{
+		0.1, 0.2, -0.3, 0.4, 0.5,
+		-0.6, 0.7, 0.8, -0.9, 1.0,
+		0.05, -0.15, 0.25, 0.35, -0.45,
+	})
+	q := NewQuantizedDense(m, nil)
+
+	x := NewVecDense([]Float{0.3, -0.2, 0.5, 0.8, -0.1})
+	assert.InDeltaSlice(t, m.Mul(x).Data(), q.Mul(x).Data(), 1.0e-2)
+
+	xs := NewDense(5, 2, []Float{
+		0.3, 0.1,
+		-0.2, 0.9,
+		0.5, -0.4,
+		0.8, 0.2,
+		-0.1, 0.6,
+	})
+	y := q.Mul(xs)
+	assert.Equal(t, 3, y.Rows())
+	assert.Equal(t, 2, y.Columns())
+	assert.InDeltaSlice(t, m.Mul(xs).Data(), y.Data(), 1.0e-2)
+
+	gy := NewVecDense([]Float{0.5, -1.0, 0.25})
+	assert.InDeltaSlice(t, m.T().Mul(gy).Data(), q.MulT(gy).Data(), 1.0e-2)
+
+	assert.Panics(t, func() { q.Mul(NewVecDense([]Float{1.0, 2.0})) })
+}
+
+func TestQuantizedDense_MarshalBinary(t *testing.T) {
+	q := NewQuantizedDense(NewDense(2, 2, []Float{0.5, -1.0, 2.0, 0.25}), nil)
+	data, err := q.MarshalBinary()
+	require.Nil(t, err)
+
+	decoded := new(QuantizedDense)
+	require.Nil(t, decoded.UnmarshalBinary(data))
+	assert.Equal(t, q.data, decoded.data)
+	assert.Equal(t, 2, decoded.Rows())
+	assert.Equal(t, 2, decoded.Columns())
+	assert.InDeltaSlice(t, q.scales, decoded.scales, 1.0e-7)
+
+	assert.NotNil(t, decoded.UnmarshalBinary(data[:len(data)-1]))
+	assert.NotNil(t, decoded.UnmarshalBinary(data[:4]))
+}
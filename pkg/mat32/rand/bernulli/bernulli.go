@@ -12,16 +12,16 @@ import (
 )
 
 // Distribution creates a new matrix initialized with Bernoulli distribution.
-func Distribution(r, c int, prob float32, generator *rand.LockedRand) mat32.Matrix {
+func Distribution(r, c int, prob mat32.Float, generator *rand.LockedRand) mat32.Matrix {
 	out := mat32.NewEmptyDense(r, c)
 	dist := uniform.New(0.0, 1.0, generator)
 	for i := 0; i < r; i++ {
 		for j := 0; j < c; j++ {
 			val := dist.Next()
 			if val < prob {
-				out.Set(i, j, float32(math.Floor(float64(val))))
+				out.Set(i, j, mat32.Float(math.Floor(float64(val))))
 			} else {
-				out.Set(i, j, float32(math.Floor(float64(val)))+1.0)
+				out.Set(i, j, mat32.Float(math.Floor(float64(val)))+1.0)
 			}
 		}
 	}
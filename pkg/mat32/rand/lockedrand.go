@@ -12,18 +12,41 @@ import (
 // LockedRand is an implementation of rand.Rand that is concurrency-safe.
 // It is just a wrap of the standard rand.Rand with its operations protected by a sync.Mutex.
 type LockedRand struct {
-	lk sync.Mutex
-	r  *rand.Rand
+	lk  sync.Mutex
+	r   *rand.Rand
+	src *rand.PCGSource
 }
 
 // NewLockedRand creates a new LockedRand that implements all Rand functions that is safe
 // for concurrent use.
 func NewLockedRand(seed uint64) *LockedRand {
+	src := rand.NewSource(seed).(*rand.PCGSource)
 	return &LockedRand{
-		r: rand.New(rand.NewSource(seed)),
+		r:   rand.New(src),
+		src: src,
 	}
 }
 
+// MarshalBinary returns the current state of the generator, which can be restored with UnmarshalBinary
+// to continue the same sequence of pseudo-random numbers (e.g. when resuming a training).
+// The bytes generated by Read and not consumed yet are not part of the state.
+func (lr *LockedRand) MarshalBinary() ([]byte, error) {
+	lr.lk.Lock()
+	defer lr.lk.Unlock()
+	return lr.src.MarshalBinary()
+}
+
+// UnmarshalBinary restores a state of the generator returned by MarshalBinary.
+func (lr *LockedRand) UnmarshalBinary(data []byte) error {
+	lr.lk.Lock()
+	defer lr.lk.Unlock()
+	if err := lr.src.UnmarshalBinary(data); err != nil {
+		return err
+	}
+	lr.r = rand.New(lr.src) // discard the bytes buffered by Read
+	return nil
+}
+
 // Seed uses the provided seed value to initialize the generator to a deterministic state.
 // Seed should not be called concurrently with any other Rand method.
 func (lr *LockedRand) Seed(seed uint64) {
@@ -118,29 +141,6 @@ func (lr *LockedRand) Intn(n int) (r int) {
 	return
 }
 
-// NormFloat32 returns a normally distributed float32 in the range
-// [-math.MaxFloat64, +math.MaxFloat64] with
-// standard normal distribution (mean = 0, stddev = 1).
-func (lr *LockedRand) NormFloat32() (n float32) {
-	lr.lk.Lock()
-	n = float32(lr.r.NormFloat64())
-	lr.lk.Unlock()
-	return
-}
-
-// Float is an alias for Float32.
-func (lr *LockedRand) Float() (n float32) {
-	return lr.Float32()
-}
-
-// Float32 returns, as a float32, a pseudo-random number in [0.0,1.0).
-func (lr *LockedRand) Float32() (n float32) {
-	lr.lk.Lock()
-	n = lr.r.Float32()
-	lr.lk.Unlock()
-	return
-}
-
 // Perm returns, as a slice of n ints, a pseudo-random permutation of the integers [0,n).
 func (lr *LockedRand) Perm(n int) (r []int) {
 	lr.lk.Lock()
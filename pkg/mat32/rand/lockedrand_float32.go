@@ -0,0 +1,33 @@
+// Copyright 2021 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rand
+
+// NormFloat32 returns a normally distributed float32 in the range
+// [-math.MaxFloat64, +math.MaxFloat64] with
+// standard normal distribution (mean = 0, stddev = 1).
+func (lr *LockedRand) NormFloat32() (n float32) {
+	lr.lk.Lock()
+	n = float32(lr.r.NormFloat64())
+	lr.lk.Unlock()
+	return
+}
+
+// Float is an alias for Float32.
+func (lr *LockedRand) Float() (n float32) {
+	return lr.Float32()
+}
+
+// Float32 returns, as a float32, a pseudo-random number in [0.0,1.0).
+func (lr *LockedRand) Float32() (n float32) {
+	lr.lk.Lock()
+	n = lr.r.Float32()
+	lr.lk.Unlock()
+	return
+}
+
+// NormFloat is an alias for NormFloat32.
+func (lr *LockedRand) NormFloat() (n float32) {
+	return lr.NormFloat32()
+}
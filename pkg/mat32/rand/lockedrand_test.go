@@ -0,0 +1,22 @@
+// Copyright 2021 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rand
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestLockedRand_MarshalBinary(t *testing.T) {
+	r := NewLockedRand(42)
+	r.Uint64()
+	state, err := r.MarshalBinary()
+	assert.NoError(t, err)
+	expected := []uint64{r.Uint64(), r.Uint64(), r.Uint64()}
+
+	restored := NewLockedRand(1)
+	assert.NoError(t, restored.UnmarshalBinary(state))
+	assert.Equal(t, expected, []uint64{restored.Uint64(), restored.Uint64(), restored.Uint64()})
+}
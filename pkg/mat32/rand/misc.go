@@ -5,6 +5,7 @@
 package rand
 
 import (
+	"github.com/nlpodyssey/spago/pkg/mat32"
 	"github.com/nlpodyssey/spago/pkg/utils"
 	"golang.org/x/exp/rand"
 )
@@ -23,9 +24,9 @@ func ShuffleInPlace(xs []int, generator *LockedRand) []int {
 
 // WeightedChoice performs a random generation of the indices based of the probability distribution itself.
 // Please note that it uses the global random.
-func WeightedChoice(dist []float32) int {
-	rnd := rand.Float32() // // Warning: use global rand
-	var cumulativeProb float32 = 0.0
+func WeightedChoice(dist []mat32.Float) int {
+	rnd := Float() // // Warning: use global rand
+	var cumulativeProb mat32.Float = 0.0
 	for i, prob := range dist {
 		cumulativeProb += prob
 		if rnd < cumulativeProb {
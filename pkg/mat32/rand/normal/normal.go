@@ -5,19 +5,20 @@
 package normal
 
 import (
+	"github.com/nlpodyssey/spago/pkg/mat32"
 	"github.com/nlpodyssey/spago/pkg/mat32/rand"
 )
 
 // Normal is a source of normally distributed random numbers.
 type Normal struct {
-	Std       float32
-	Mean      float32
+	Std       mat32.Float
+	Mean      mat32.Float
 	generator *rand.LockedRand
 }
 
 // New returns a new Normal, initialized with the given standard deviation and
 // mean parameters.
-func New(std, mean float32, generator *rand.LockedRand) *Normal {
+func New(std, mean mat32.Float, generator *rand.LockedRand) *Normal {
 	return &Normal{
 		Std:       std,
 		Mean:      mean,
@@ -26,6 +27,6 @@ func New(std, mean float32, generator *rand.LockedRand) *Normal {
 }
 
 // Next returns a random sample drawn from the distribution.
-func (u Normal) Next() float32 {
-	return u.generator.NormFloat32()*u.Std + u.Mean
+func (u Normal) Next() mat32.Float {
+	return u.generator.NormFloat()*u.Std + u.Mean
 }
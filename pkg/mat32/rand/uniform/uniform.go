@@ -5,19 +5,20 @@
 package uniform
 
 import (
+	"github.com/nlpodyssey/spago/pkg/mat32"
 	"github.com/nlpodyssey/spago/pkg/mat32/rand"
 )
 
 // Uniform is a source of uniformly distributed random numbers.
 // See: https://en.wikipedia.org/wiki/Continuous_uniform_distribution.
 type Uniform struct {
-	Min       float32
-	Max       float32
+	Min       mat32.Float
+	Max       mat32.Float
 	generator *rand.LockedRand
 }
 
 // New returns a new Normal, initialized with the given min and max parameters.
-func New(min, max float32, generator *rand.LockedRand) *Uniform {
+func New(min, max mat32.Float, generator *rand.LockedRand) *Uniform {
 	return &Uniform{
 		Min:       min,
 		Max:       max,
@@ -26,6 +27,6 @@ func New(min, max float32, generator *rand.LockedRand) *Uniform {
 }
 
 // Next returns a random sample drawn from the distribution.
-func (u Uniform) Next() float32 {
-	return u.generator.Float32()*(u.Max-u.Min) + u.Min
+func (u Uniform) Next() mat32.Float {
+	return u.generator.Float()*(u.Max-u.Min) + u.Min
 }
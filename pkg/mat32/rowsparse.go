@@ -0,0 +1,553 @@
+// Copyright 2021 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat32
+
+import (
+	"fmt"
+	"math"
+)
+
+var _ Matrix = &RowSparse{}
+
+// RowSparse is a matrix where only some rows are stored, and all the others are zeros.
+// It is meant to represent the gradients of a large matrix (e.g. an embeddings matrix) of which only a few rows
+// are used at a time: adding a row-sparse matrix to a Dense matrix touches only the stored rows.
+//
+// The operations which preserve the row-sparsity (e.g. AddInPlace with another RowSparse, ProdScalar, Sqrt, ...)
+// return a RowSparse; the others return a Dense matrix. The in-place operations which would break the
+// row-sparsity (e.g. AddScalarInPlace) panic.
+type RowSparse struct {
+	rows    int
+	cols    int
+	indices []int       // the indices of the stored rows, in order of insertion
+	data    []Float     // the values of the stored rows, one after the other
+	pos     map[int]int // the position of each stored row in indices
+}
+
+// NewRowSparse returns a new rows x cols RowSparse matrix, without stored rows (i.e. all zeros).
+func NewRowSparse(rows, cols int) *RowSparse {
+	return &RowSparse{
+		rows:    rows,
+		cols:    cols,
+		indices: make([]int, 0),
+		data:    make([]Float, 0),
+		pos:     make(map[int]int),
+	}
+}
+
+// AddToRow adds the values to the i-th row of the matrix, storing the row if it isn't already.
+// It panics if the number of values doesn't match the number of columns.
+func (s *RowSparse) AddToRow(i int, values []Float) {
+	if len(values) != s.cols {
+		panic("mat32: the number of values doesn't match the number of columns")
+	}
+	row := s.row(i)
+	for j, v := range values {
+		row[j] += v
+	}
+}
+
+// row returns the values of the i-th row, storing it if it isn't already.
+func (s *RowSparse) row(i int) []Float {
+	if i < 0 || i >= s.rows {
+		panic(fmt.Sprintf("mat32: row %d out of range", i))
+	}
+	if p, ok := s.pos[i]; ok {
+		return s.data[p*s.cols : (p+1)*s.cols]
+	}
+	s.pos[i] = len(s.indices)
+	s.indices = append(s.indices, i)
+	s.data = append(s.data, make([]Float, s.cols)...)
+	return s.data[len(s.data)-s.cols:]
+}
+
+// RowIndices returns the indices of the stored rows, in order of insertion.
+func (s *RowSparse) RowIndices() []int {
+	return s.indices
+}
+
+// Row returns the values of the i-th row if it is stored, otherwise nil.
+// The values are not copied, so changing them changes the matrix.
+func (s *RowSparse) Row(i int) []Float {
+	if p, ok := s.pos[i]; ok {
+		return s.data[p*s.cols : (p+1)*s.cols]
+	}
+	return nil
+}
+
+// ToDense returns the matrix converted to Dense.
+func (s *RowSparse) ToDense() *Dense {
+	out := GetEmptyDenseWorkspace(s.rows, s.cols)
+	for p, i := range s.indices {
+		copy(out.data[i*s.cols:(i+1)*s.cols], s.data[p*s.cols:(p+1)*s.cols])
+	}
+	return out
+}
+
+// dense calls the function with the matrix converted to Dense, and releases it afterwards.
+func (s *RowSparse) dense(fn func(d *Dense) Matrix) Matrix {
+	d := s.ToDense()
+	defer ReleaseDense(d)
+	return fn(d)
+}
+
+// ZerosLike returns a new RowSparse with the same dimensions of the receiver, without stored rows.
+func (s *RowSparse) ZerosLike() Matrix {
+	return NewRowSparse(s.rows, s.cols)
+}
+
+// OnesLike returns a new Dense matrix with the same dimensions of the receiver, initialized with ones.
+func (s *RowSparse) OnesLike() Matrix {
+	return s.dense(func(d *Dense) Matrix { return d.OnesLike() })
+}
+
+// Clone returns a new RowSparse, copying all its values from the receiver.
+func (s *RowSparse) Clone() Matrix {
+	pos := make(map[int]int, len(s.pos))
+	for k, v := range s.pos {
+		pos[k] = v
+	}
+	return &RowSparse{
+		rows:    s.rows,
+		cols:    s.cols,
+		indices: append(make([]int, 0, len(s.indices)), s.indices...),
+		data:    append(make([]Float, 0, len(s.data)), s.data...),
+		pos:     pos,
+	}
+}
+
+// Copy copies the data from the other RowSparse to the receiver.
+// It panics if the other matrix is not a RowSparse, or if the dimensions are incompatible.
+func (s *RowSparse) Copy(other Matrix) {
+	if !SameDims(s, other) {
+		panic("mat32: incompatible matrix dimensions.")
+	}
+	o, ok := other.(*RowSparse)
+	if !ok {
+		panic("mat32: incompatible matrix types.")
+	}
+	*s = *o.Clone().(*RowSparse)
+}
+
+// Zeros removes all the stored rows.
+func (s *RowSparse) Zeros() {
+	s.indices = s.indices[:0]
+	s.data = s.data[:0]
+	s.pos = make(map[int]int)
+}
+
+// Dims returns the number of rows and columns of the matrix.
+func (s *RowSparse) Dims() (r, c int) {
+	return s.rows, s.cols
+}
+
+// Rows returns the number of rows of the matrix.
+func (s *RowSparse) Rows() int {
+	return s.rows
+}
+
+// Columns returns the number of columns of the matrix.
+func (s *RowSparse) Columns() int {
+	return s.cols
+}
+
+// Size returns the size of the matrix (rows × columns).
+func (s *RowSparse) Size() int {
+	return s.rows * s.cols
+}
+
+// LastIndex returns the last element's index, in respect of linear indexing.
+// It returns -1 if the matrix is empty.
+func (s *RowSparse) LastIndex() int {
+	return s.Size() - 1
+}
+
+// Data returns a copy of all the values of the matrix (zeros included), as a raw one-dimensional slice.
+func (s *RowSparse) Data() []Float {
+	data := make([]Float, s.Size())
+	for p, i := range s.indices {
+		copy(data[i*s.cols:(i+1)*s.cols], s.data[p*s.cols:(p+1)*s.cols])
+	}
+	return data
+}
+
+// IsVector returns whether the matrix is either a row or column vector.
+func (s *RowSparse) IsVector() bool {
+	return s.rows == 1 || s.cols == 1
+}
+
+// IsScalar returns whether the matrix contains exactly one scalar value.
+func (s *RowSparse) IsScalar() bool {
+	return s.Size() == 1
+}
+
+// Scalar returns the scalar value.
+// It panics if the matrix does not contain exactly one element.
+func (s *RowSparse) Scalar() Float {
+	if !s.IsScalar() {
+		panic("mat32: expected scalar but the matrix contains more elements.")
+	}
+	return s.At(0, 0)
+}
+
+// Set sets the value v at row i and column j, storing the row if it isn't already.
+func (s *RowSparse) Set(i int, j int, v Float) {
+	if j < 0 || j >= s.cols {
+		panic(fmt.Sprintf("mat32: column %d out of range", j))
+	}
+	s.row(i)[j] = v
+}
+
+// At returns the value at row i and column j.
+func (s *RowSparse) At(i int, j int) Float {
+	if i < 0 || i >= s.rows || j < 0 || j >= s.cols {
+		panic("mat32: index out of range")
+	}
+	if row := s.Row(i); row != nil {
+		return row[j]
+	}
+	return 0.0
+}
+
+// SetVec sets the value v at position i of a vector.
+// It panics if the receiver is not a vector.
+func (s *RowSparse) SetVec(i int, v Float) {
+	if !s.IsVector() {
+		panic("mat32: expected vector")
+	}
+	if s.cols == 1 {
+		s.Set(i, 0, v)
+	} else {
+		s.Set(0, i, v)
+	}
+}
+
+// AtVec returns the value at position i of a vector.
+// It panics if the receiver is not a vector.
+func (s *RowSparse) AtVec(i int) Float {
+	if !s.IsVector() {
+		panic("mat32: expected vector")
+	}
+	if s.cols == 1 {
+		return s.At(i, 0)
+	}
+	return s.At(0, i)
+}
+
+// T returns the transpose of the matrix, as a Dense matrix.
+func (s *RowSparse) T() Matrix {
+	return s.dense(func(d *Dense) Matrix { return d.T() })
+}
+
+// Reshape returns a copy of the matrix, as a Dense matrix.
+// It panics if the dimensions are incompatible.
+func (s *RowSparse) Reshape(r, c int) Matrix {
+	return s.dense(func(d *Dense) Matrix { return d.Reshape(r, c) })
+}
+
+// Apply is not supported by RowSparse, and panics.
+func (s *RowSparse) Apply(_ func(i, j int, v Float) Float, _ Matrix) {
+	panic("mat32: Apply not supported by RowSparse")
+}
+
+// ApplyWithAlpha is not supported by RowSparse, and panics.
+func (s *RowSparse) ApplyWithAlpha(_ func(i, j int, v Float, alpha ...Float) Float, _ Matrix, _ ...Float) {
+	panic("mat32: ApplyWithAlpha not supported by RowSparse")
+}
+
+// AddScalar returns a new Dense matrix with the addition of the receiver and the given value.
+func (s *RowSparse) AddScalar(n Float) Matrix {
+	return s.dense(func(d *Dense) Matrix { return d.AddScalar(n) })
+}
+
+// AddScalarInPlace is not supported by RowSparse, and panics.
+func (s *RowSparse) AddScalarInPlace(_ Float) Matrix {
+	panic("mat32: AddScalarInPlace not supported by RowSparse")
+}
+
+// SubScalar returns a new Dense matrix with the subtraction of the given value from the receiver.
+func (s *RowSparse) SubScalar(n Float) Matrix {
+	return s.dense(func(d *Dense) Matrix { return d.SubScalar(n) })
+}
+
+// SubScalarInPlace is not supported by RowSparse, and panics.
+func (s *RowSparse) SubScalarInPlace(_ Float) Matrix {
+	panic("mat32: SubScalarInPlace not supported by RowSparse")
+}
+
+// ProdScalar returns the multiplication between the matrix and the given value.
+func (s *RowSparse) ProdScalar(n Float) Matrix {
+	return s.Clone().ProdScalarInPlace(n)
+}
+
+// ProdScalarInPlace performs the in-place multiplication between the matrix and the given value.
+func (s *RowSparse) ProdScalarInPlace(n Float) Matrix {
+	for i := range s.data {
+		s.data[i] *= n
+	}
+	return s
+}
+
+// ProdMatrixScalarInPlace multiplies the given RowSparse matrix with the value, storing the result in the receiver.
+// It panics if the other matrix is not a RowSparse.
+func (s *RowSparse) ProdMatrixScalarInPlace(m Matrix, n Float) Matrix {
+	s.Copy(m)
+	return s.ProdScalarInPlace(n)
+}
+
+// Add returns the addition between the receiver and another matrix.
+// The result is a RowSparse if the other matrix is a RowSparse too, otherwise it is Dense.
+func (s *RowSparse) Add(other Matrix) Matrix {
+	if o, ok := other.(*RowSparse); ok {
+		return s.Clone().AddInPlace(o)
+	}
+	return s.dense(func(d *Dense) Matrix { return d.Add(other) })
+}
+
+// AddInPlace performs the in-place addition with the other RowSparse matrix.
+// It panics if the other matrix is not a RowSparse.
+func (s *RowSparse) AddInPlace(other Matrix) Matrix {
+	s.addInPlace(other, 1.0)
+	return s
+}
+
+// Sub returns the subtraction of the other matrix from the receiver.
+// The result is a RowSparse if the other matrix is a RowSparse too, otherwise it is Dense.
+func (s *RowSparse) Sub(other Matrix) Matrix {
+	if o, ok := other.(*RowSparse); ok {
+		return s.Clone().SubInPlace(o)
+	}
+	return s.dense(func(d *Dense) Matrix { return d.Sub(other) })
+}
+
+// SubInPlace performs the in-place subtraction with the other RowSparse matrix.
+// It panics if the other matrix is not a RowSparse.
+func (s *RowSparse) SubInPlace(other Matrix) Matrix {
+	s.addInPlace(other, -1.0)
+	return s
+}
+
+func (s *RowSparse) addInPlace(other Matrix, alpha Float) {
+	if !SameDims(s, other) {
+		panic("mat32: matrices with not compatible size")
+	}
+	o, ok := other.(*RowSparse)
+	if !ok {
+		panic("mat32: in-place operation not supported between RowSparse and other matrix types")
+	}
+	for p, i := range o.indices {
+		row := s.row(i)
+		for j, v := range o.data[p*o.cols : (p+1)*o.cols] {
+			row[j] += alpha * v
+		}
+	}
+}
+
+// Prod performs the element-wise product between the receiver and the other matrix, returning a RowSparse.
+func (s *RowSparse) Prod(other Matrix) Matrix {
+	return s.Clone().ProdInPlace(other)
+}
+
+// ProdInPlace performs the in-place element-wise product with the other matrix.
+func (s *RowSparse) ProdInPlace(other Matrix) Matrix {
+	if !SameDims(s, other) {
+		panic("mat32: matrices with not compatible size")
+	}
+	for p, i := range s.indices {
+		row := s.data[p*s.cols : (p+1)*s.cols]
+		for j := range row {
+			row[j] *= other.At(i, j)
+		}
+	}
+	return s
+}
+
+// Div returns the result of the element-wise division of the receiver by the other matrix, as a RowSparse.
+// Only the stored rows are divided, the others remaining zeros.
+func (s *RowSparse) Div(other Matrix) Matrix {
+	return s.Clone().DivInPlace(other)
+}
+
+// DivInPlace performs the in-place element-wise division of the receiver by the other matrix.
+// Only the stored rows are divided, the others remaining zeros.
+func (s *RowSparse) DivInPlace(other Matrix) Matrix {
+	if !SameDims(s, other) {
+		panic("mat32: matrices with not compatible size")
+	}
+	for p, i := range s.indices {
+		row := s.data[p*s.cols : (p+1)*s.cols]
+		for j := range row {
+			row[j] /= other.At(i, j)
+		}
+	}
+	return s
+}
+
+// Mul performs the multiplication row by column, returning a Dense matrix.
+func (s *RowSparse) Mul(other Matrix) Matrix {
+	if s.cols != other.Rows() {
+		panic("mat32: matrices with not compatible size")
+	}
+	out := GetEmptyDenseWorkspace(s.rows, other.Columns())
+	for p, i := range s.indices {
+		row := s.data[p*s.cols : (p+1)*s.cols]
+		for k := 0; k < out.cols; k++ {
+			var sum Float
+			for j, v := range row {
+				sum += v * other.At(j, k)
+			}
+			out.data[i*out.cols+k] = sum
+		}
+	}
+	return out
+}
+
+// DotUnitary returns the dot product of two vectors.
+func (s *RowSparse) DotUnitary(other Matrix) Float {
+	if s.Size() != other.Size() {
+		panic("mat32: incompatible sizes.")
+	}
+	var sum Float
+	s.DoNonZero(func(i, j int, v Float) {
+		if s.cols == 1 {
+			sum += v * other.AtVec(i)
+		} else {
+			sum += v * other.AtVec(i*s.cols+j)
+		}
+	})
+	return sum
+}
+
+// Pow returns a new Dense matrix, applying the power function with given exponent to all elements of the matrix.
+func (s *RowSparse) Pow(power Float) Matrix {
+	return s.dense(func(d *Dense) Matrix { return d.Pow(power) })
+}
+
+// Norm returns the vector's norm. Use pow = 2.0 to compute the Euclidean norm.
+func (s *RowSparse) Norm(pow Float) Float {
+	var sum Float
+	for _, v := range s.data {
+		sum += Float(math.Pow(float64(v), float64(pow)))
+	}
+	return Float(math.Pow(float64(sum), float64(1/pow)))
+}
+
+// Sqrt returns a new matrix applying the square root function to all elements.
+func (s *RowSparse) Sqrt() Matrix {
+	out := s.Clone().(*RowSparse)
+	for i, v := range out.data {
+		out.data[i] = Sqrt(v)
+	}
+	return out
+}
+
+// ClipInPlace clips in place each value of the matrix.
+// The rows which are not stored remain zeros, so min must not be greater than zero, and max not less than zero.
+func (s *RowSparse) ClipInPlace(min, max Float) Matrix {
+	for i, v := range s.data {
+		if v < min {
+			s.data[i] = min
+		} else if v > max {
+			s.data[i] = max
+		}
+	}
+	return s
+}
+
+// SplitV extract N Dense vectors from the Matrix. N[i] has size sizes[i].
+func (s *RowSparse) SplitV(sizes ...int) []Matrix {
+	d := s.ToDense()
+	defer ReleaseDense(d)
+	return d.SplitV(sizes...)
+}
+
+// Minimum returns a new Dense matrix containing the element-wise minima.
+func (s *RowSparse) Minimum(other Matrix) Matrix {
+	return s.dense(func(d *Dense) Matrix { return d.Minimum(other) })
+}
+
+// Maximum returns a new Dense matrix containing the element-wise maxima.
+func (s *RowSparse) Maximum(other Matrix) Matrix {
+	return s.dense(func(d *Dense) Matrix { return d.Maximum(other) })
+}
+
+// MulT performs the matrix multiplication row by column of the transposed receiver by the other matrix,
+// returning a Dense matrix.
+func (s *RowSparse) MulT(other Matrix) Matrix {
+	return s.dense(func(d *Dense) Matrix { return d.MulT(other) })
+}
+
+// Inverse returns the inverse of the matrix, as a Dense matrix.
+func (s *RowSparse) Inverse() Matrix {
+	return s.dense(func(d *Dense) Matrix { return d.Inverse() })
+}
+
+// DoNonZero calls a function for each non-zero element of the matrix.
+// The parameters of the function are the element indices and its value.
+func (s *RowSparse) DoNonZero(fn func(i, j int, v Float)) {
+	for p, i := range s.indices {
+		for j, v := range s.data[p*s.cols : (p+1)*s.cols] {
+			if v != 0.0 {
+				fn(i, j, v)
+			}
+		}
+	}
+}
+
+// Abs returns a new matrix applying the absolute value function to all elements.
+func (s *RowSparse) Abs() Matrix {
+	out := s.Clone().(*RowSparse)
+	for i, v := range out.data {
+		out.data[i] = Abs(v)
+	}
+	return out
+}
+
+// Sum returns the sum of all values of the matrix.
+func (s *RowSparse) Sum() Float {
+	var sum Float
+	for _, v := range s.data {
+		sum += v
+	}
+	return sum
+}
+
+// Max returns the maximum value of the matrix.
+func (s *RowSparse) Max() Float {
+	max := Float(math.Inf(-1))
+	if len(s.indices) < s.rows {
+		max = 0.0
+	}
+	for _, v := range s.data {
+		if v > max {
+			max = v
+		}
+	}
+	return max
+}
+
+// Min returns the minimum value of the matrix.
+func (s *RowSparse) Min() Float {
+	min := Float(math.Inf(1))
+	if len(s.indices) < s.rows {
+		min = 0.0
+	}
+	for _, v := range s.data {
+		if v < min {
+			min = v
+		}
+	}
+	return min
+}
+
+// String returns a string representation of the matrix data.
+func (s *RowSparse) String() string {
+	return fmt.Sprintf("%v", s.Data())
+}
+
+// SetData is not supported by RowSparse, and panics.
+func (s *RowSparse) SetData(_ []Float) {
+	panic("mat32: SetData not supported by RowSparse")
+}
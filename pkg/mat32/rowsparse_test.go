@@ -0,0 +1,135 @@
+// Copyright 2021 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat32
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func newTestRowSparse() *RowSparse {
+	s := NewRowSparse(4, 3)
+	s.AddToRow(2, []Float{1.0, -2.0, 3.0})
+	s.AddToRow(0, []Float{0.5, 0.0, -0.5})
+	return s
+}
+
+func TestRowSparse_Basics(t *testing.T) {
+	s := newTestRowSparse()
+	assert.Equal(t, []int{2, 0}, s.RowIndices())
+	assert.Equal(t, []Float{1.0, -2.0, 3.0}, s.Row(2))
+	assert.Nil(t, s.Row(1))
+	assert.Equal(t, Float(-2.0), s.At(2, 1))
+	assert.Equal(t, Float(0.0), s.At(3, 1))
+	assert.Equal(t, []Float{
+		0.5, 0.0, -0.5,
+		0.0, 0.0, 0.0,
+		1.0, -2.0, 3.0,
+		0.0, 0.0, 0.0,
+	}, s.Data())
+	assert.Equal(t, s.Data(), s.ToDense().Data())
+	assert.Equal(t, Float(2.0), s.Sum())
+	assert.Equal(t, Float(3.0), s.Max())
+	assert.Equal(t, Float(-2.0), s.Min())
+	assert.InDelta(t, 3.8079, s.Norm(2), 1.0e-4)
+
+	s.AddToRow(2, []Float{1.0, 1.0, 1.0})
+	assert.Equal(t, []Float{2.0, -1.0, 4.0}, s.Row(2))
+	assert.Panics(t, func() { s.AddToRow(4, []Float{1.0, 1.0, 1.0}) })
+	assert.Panics(t, func() { s.AddToRow(1, []Float{1.0}) })
+
+	s.Zeros()
+	assert.Empty(t, s.RowIndices())
+	assert.Equal(t, Float(0.0), s.Max())
+}
+
+func TestRowSparse_AddInPlace(t *testing.T) {
+	s := newTestRowSparse()
+	other := NewRowSparse(4, 3)
+	other.AddToRow(3, []Float{1.0, 1.0, 1.0})
+	other.AddToRow(2, []Float{1.0, 1.0, 1.0})
+
+	s.AddInPlace(other)
+	assert.Equal(t, []int{2, 0, 3}, s.RowIndices())
+	assert.Equal(t, []Float{
+		0.5, 0.0, -0.5,
+		0.0, 0.0, 0.0,
+		2.0, -1.0, 4.0,
+		1.0, 1.0, 1.0,
+	}, s.Data())
+
+	s.SubInPlace(other)
+	assert.Equal(t, newTestRowSparse().Data(), s.Data())
+
+	assert.Panics(t, func() { s.AddInPlace(NewEmptyDense(4, 3)) })
+	assert.Panics(t, func() { s.AddInPlace(NewRowSparse(3, 3)) })
+}
+
+func TestRowSparse_Clone(t *testing.T) {
+	s := newTestRowSparse()
+	c := s.Clone().(*RowSparse)
+	c.AddToRow(2, []Float{1.0, 1.0, 1.0})
+	c.AddToRow(1, []Float{1.0, 1.0, 1.0})
+	assert.Equal(t, []Float{1.0, -2.0, 3.0}, s.Row(2))
+	assert.Nil(t, s.Row(1))
+}
+
+func TestRowSparse_ElementWise(t *testing.T) {
+	s := newTestRowSparse()
+
+	p := s.ProdScalar(2.0).(*RowSparse)
+	assert.Equal(t, []Float{2.0, -4.0, 6.0}, p.Row(2))
+	assert.Equal(t, []Float{1.0, -2.0, 3.0}, s.Row(2))
+
+	sq := s.Prod(s).(*RowSparse)
+	assert.Equal(t, []Float{1.0, 4.0, 9.0}, sq.Row(2))
+	assert.Equal(t, []Float{1.0, 2.0, 3.0}, sq.Sqrt().(*RowSparse).Row(2))
+	assert.Equal(t, []Float{1.0, 2.0, 3.0}, s.Abs().(*RowSparse).Row(2))
+	assert.Equal(t, []Float{1.0, -1.0, 1.0}, s.Div(s.Abs()).(*RowSparse).Row(2))
+
+	s.ClipInPlace(-1.0, 1.0)
+	assert.Equal(t, []Float{1.0, -1.0, 1.0}, s.Row(2))
+
+	assert.Panics(t, func() { s.AddScalarInPlace(1.0) })
+	assert.Equal(t, Float(1.0), s.AddScalar(1.0).At(1, 1))
+}
+
+func TestRowSparse_Mul(t *testing.T) {
+	s := newTestRowSparse()
+	x := NewVecDense([]Float{1.0, 2.0, 3.0})
+	assert.Equal(t, s.ToDense().Mul(x).Data(), s.Mul(x).Data())
+}
+
+func TestDense_RowSparse(t *testing.T) {
+	d := NewDense(4, 3, []Float{
+		1.0, 1.0, 1.0,
+		2.0, 2.0, 2.0,
+		3.0, 3.0, 3.0,
+		4.0, 4.0, 4.0,
+	})
+	s := newTestRowSparse()
+
+	assert.Equal(t, []Float{
+		1.5, 1.0, 0.5,
+		2.0, 2.0, 2.0,
+		4.0, 1.0, 6.0,
+		4.0, 4.0, 4.0,
+	}, d.Add(s).Data())
+	assert.Equal(t, []Float{
+		0.5, 1.0, 1.5,
+		2.0, 2.0, 2.0,
+		2.0, 5.0, 0.0,
+		4.0, 4.0, 4.0,
+	}, d.Sub(s).Data())
+
+	d.AddInPlace(s)
+	d.SubInPlace(s)
+	assert.Equal(t, []Float{
+		1.0, 1.0, 1.0,
+		2.0, 2.0, 2.0,
+		3.0, 3.0, 3.0,
+		4.0, 4.0, 4.0,
+	}, d.Data())
+}
@@ -4,18 +4,21 @@
 
 package sort
 
-import "sort"
+import (
+	"github.com/nlpodyssey/spago/pkg/mat32"
+	"sort"
+)
 
-// FloatSlice attaches the methods of sort.Interface to []float32, sorting in increasing order
+// FloatSlice attaches the methods of sort.Interface to []mat32.Float, sorting in increasing order
 // (not-a-number values are treated as less than other values).
-type FloatSlice []float32
+type FloatSlice []mat32.Float
 
 func (p FloatSlice) Len() int           { return len(p) }
 func (p FloatSlice) Less(i, j int) bool { return p[i] < p[j] || isNaN(p[i]) && !isNaN(p[j]) }
 func (p FloatSlice) Swap(i, j int)      { p[i], p[j] = p[j], p[i] }
 
 // isNaN is a copy of math.IsNaN to avoid a dependency on the math package.
-func isNaN(f float32) bool {
+func isNaN(f mat32.Float) bool {
 	return f != f
 }
 
@@ -51,8 +54,8 @@ func NewIntSlice(n ...int) *Slice {
 	return NewSlice(sort.IntSlice(n))
 }
 
-// NewFloatSlice returns a new Slice for the given sequence of float32 values.
-func NewFloatSlice(n ...float32) *Slice {
+// NewFloatSlice returns a new Slice for the given sequence of mat32.Float values.
+func NewFloatSlice(n ...mat32.Float) *Slice {
 	return NewSlice(FloatSlice(n))
 }
 
@@ -0,0 +1,27 @@
+// Copyright 2021 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat64
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestDense_FormatFloat64(t *testing.T) {
+	run := func(name string, d *Dense, format, expected string) {
+		t.Run(name, func(t *testing.T) {
+			actual := fmt.Sprintf(format, d)
+			if actual != expected {
+				t.Errorf("Expected:\n%s\nActual:\n%s", expected, actual)
+			}
+		})
+	}
+
+	run("Go-syntax representation", NewScalar(1.2), "%#v",
+		"&mat64.Dense{rows:1, cols:1, size:1, data:[]float64{1.2}, "+
+			"viewOf:(*mat64.Dense)(nil), fromPool:true}")
+
+	run("decimalless scientific notation", NewScalar(0), "%b", "[0p-1074]")
+}
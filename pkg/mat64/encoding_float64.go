@@ -0,0 +1,23 @@
+// Copyright 2021 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat64
+
+import (
+	"encoding/binary"
+	"math"
+)
+
+// floatSize is the size in bytes of a Float in the binary encoding of the matrices.
+const floatSize = 8
+
+// putFloat encodes v into the first floatSize bytes of b, in little-endian order.
+func putFloat(b []byte, v Float) {
+	binary.LittleEndian.PutUint64(b, math.Float64bits(v))
+}
+
+// getFloat decodes a Float from the first floatSize bytes of b, in little-endian order.
+func getFloat(b []byte) Float {
+	return math.Float64frombits(binary.LittleEndian.Uint64(b))
+}
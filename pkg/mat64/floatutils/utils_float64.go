@@ -0,0 +1,18 @@
+// Copyright 2021 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package floatutils
+
+import (
+	"github.com/nlpodyssey/spago/pkg/mat64"
+	"github.com/nlpodyssey/spago/pkg/mat64/internal/asm/f64"
+)
+
+// floatBitSize is the size in bits of mat64.Float.
+const floatBitSize = 64
+
+// CumSum computes the cumulative sum of src into dst, and returns dst.
+func CumSum(dst, src []mat64.Float) []mat64.Float {
+	return f64.CumSum(dst, src)
+}
@@ -0,0 +1,67 @@
+// Copyright 2021 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat64
+
+import "github.com/nlpodyssey/spago/pkg/mat64/internal/asm/f64"
+
+// The numerical kernels of the matrices, implemented for float64 by the Gonum assembly code and its
+// pure Go fallbacks.
+
+// addConst adds alpha to each element of x.
+func addConst(alpha Float, x []Float) {
+	f64.AddConst(alpha, x)
+}
+
+// scalUnitary multiplies each element of x by alpha.
+func scalUnitary(alpha Float, x []Float) {
+	f64.ScalUnitary(alpha, x)
+}
+
+// scalUnitaryTo sets each element of dst to the corresponding element of x multiplied by alpha.
+func scalUnitaryTo(dst []Float, alpha Float, x []Float) {
+	f64.ScalUnitaryTo(dst, alpha, x)
+}
+
+// axpyUnitary adds alpha*x to y.
+func axpyUnitary(alpha Float, x, y []Float) {
+	f64.AxpyUnitary(alpha, x, y)
+}
+
+// axpyUnitaryTo sets dst to alpha*x + y.
+func axpyUnitaryTo(dst []Float, alpha Float, x, y []Float) {
+	f64.AxpyUnitaryTo(dst, alpha, x, y)
+}
+
+// divTo sets each element of dst to the division of the corresponding elements of s and t, and returns dst.
+func divTo(dst, s, t []Float) []Float {
+	return f64.DivTo(dst, s, t)
+}
+
+// dotUnitary returns the dot product of x and y.
+func dotUnitary(x, y []Float) Float {
+	return f64.DotUnitary(x, y)
+}
+
+// sumUnitary returns the sum of the elements of x.
+func sumUnitary(x []Float) Float {
+	return f64.Sum(x)
+}
+
+// dgemm computes C = alpha * A * B + beta * C, concurrently.
+func dgemm(m, n, k int, alpha Float, a []Float, lda int, b []Float, ldb int, beta Float, c []Float, ldc int) {
+	f64.Dgemm(false, false, m, n, k, alpha, a, lda, b, ldb, beta, c, ldc)
+}
+
+// dgemmSerial computes C += alpha * A * B serially, where A and B can be transposed.
+func dgemmSerial(aTrans, bTrans bool, m, n, k int, a []Float, lda int, b []Float, ldb int, c []Float, ldc int,
+	alpha Float) {
+	f64.DgemmSerial(aTrans, bTrans, m, n, k, a, lda, b, ldb, c, ldc, alpha)
+}
+
+// gemvT computes y = alpha * Aᵀ * x + beta * y.
+func gemvT(m, n uintptr, alpha Float, a []Float, lda uintptr, x []Float, incX uintptr, beta Float, y []Float,
+	incY uintptr) {
+	f64.GemvT(m, n, alpha, a, lda, x, incX, beta, y, incY)
+}
@@ -9,6 +9,9 @@ import "math"
 // Float is the main float type for the mat64 package. It is an alias for float64.
 type Float = float64
 
+// floatBitSize is the size in bits of Float.
+const floatBitSize = 64
+
 const (
 	// SmallestNonzeroFloat corresponds to math.SmallestNonzeroFloat64.
 	SmallestNonzeroFloat Float = math.SmallestNonzeroFloat64
@@ -91,6 +94,11 @@ func NaN() Float {
 	return math.NaN()
 }
 
+// IsNaN reports whether f is an IEEE 754 ``not-a-number'' value.
+func IsNaN(f Float) bool {
+	return math.IsNaN(f)
+}
+
 // Ceil returns the least integer value greater than or equal to x.
 func Ceil(x Float) Float {
 	return math.Ceil(x)
@@ -1,3 +1,5 @@
+// Code generated by genmat64 from the mat32 package. DO NOT EDIT.
+
 // Copyright 2021 spaGO Authors. All rights reserved.
 // Use of this source code is governed by a BSD-style
 // license that can be found in the LICENSE file.
@@ -23,11 +25,11 @@ func TestSin(t *testing.T) {
 }
 
 func TestCosh(t *testing.T) {
-	assert.InDelta(t, 11.59195, Cosh(Pi), 0.00001)
+	assert.InDelta(t, Float(11.59195), Cosh(Pi), 0.00001)
 }
 
 func TestSinh(t *testing.T) {
-	assert.InDelta(t, 11.54874, Sinh(Pi), 0.00001)
+	assert.InDelta(t, Float(11.54874), Sinh(Pi), 0.00001)
 }
 
 func TestExp(t *testing.T) {
@@ -61,8 +63,8 @@ func TestMax(t *testing.T) {
 }
 
 func TestInf(t *testing.T) {
-	assert.True(t, math.IsInf(Inf(1), +1))
-	assert.True(t, math.IsInf(Inf(-1), -1))
+	assert.True(t, math.IsInf(float64(Inf(1)), +1))
+	assert.True(t, math.IsInf(float64(Inf(-1)), -1))
 }
 
 func TestIsInf(t *testing.T) {
@@ -75,7 +77,7 @@ func TestIsInf(t *testing.T) {
 }
 
 func TestNaN(t *testing.T) {
-	assert.True(t, math.IsNaN(NaN()))
+	assert.True(t, math.IsNaN(float64(NaN())))
 }
 
 func TestCeil(t *testing.T) {
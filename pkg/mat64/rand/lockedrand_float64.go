@@ -0,0 +1,41 @@
+// Copyright 2021 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rand
+
+// Float is an alias for Float64.
+func (lr *LockedRand) Float() (n float64) {
+	return lr.Float64()
+}
+
+// Float64 returns, as a float64, a pseudo-random number in [0.0,1.0).
+func (lr *LockedRand) Float64() (n float64) {
+	lr.lk.Lock()
+	n = lr.r.Float64()
+	lr.lk.Unlock()
+	return
+}
+
+// NormFloat64 returns a normally distributed float64 in the range
+// [-math.MaxFloat64, +math.MaxFloat64] with
+// standard normal distribution (mean = 0, stddev = 1).
+func (lr *LockedRand) NormFloat64() (n float64) {
+	lr.lk.Lock()
+	n = lr.r.NormFloat64()
+	lr.lk.Unlock()
+	return
+}
+
+// Float32 returns, as a float32, a pseudo-random number in [0.0,1.0).
+func (lr *LockedRand) Float32() (n float32) {
+	lr.lk.Lock()
+	n = lr.r.Float32()
+	lr.lk.Unlock()
+	return
+}
+
+// NormFloat is an alias for NormFloat64.
+func (lr *LockedRand) NormFloat() (n float64) {
+	return lr.NormFloat64()
+}
@@ -0,0 +1,112 @@
+// Copyright 2021 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fn
+
+import (
+	mat "github.com/nlpodyssey/spago/pkg/mat32"
+)
+
+var (
+	_ Function = &BroadcastCols{}
+	_ Function = &BroadcastRows{}
+)
+
+// BroadcastCols is a Function which repeats the column vector x as many times as the columns of another
+// operand, whose value is only read during the forward pass (e.g. to apply a bias to a whole minibatch).
+type BroadcastCols struct {
+	x    Operand
+	like Operand
+}
+
+// NewBroadcastCols returns a new BroadcastCols Function.
+func NewBroadcastCols(x, like Operand) *BroadcastCols {
+	return &BroadcastCols{x: x, like: like}
+}
+
+// Forward computes the output of the function.
+func (r *BroadcastCols) Forward() mat.Matrix {
+	xv := r.x.Value()
+	if !xv.IsVector() {
+		panic("fn: the input must be a vector")
+	}
+	data := xv.Data()
+	rows, cols := len(data), r.like.Value().Columns()
+	y := mat.GetDenseWorkspace(rows, cols)
+	for i, v := range data {
+		for j := 0; j < cols; j++ {
+			y.Set(i, j, v)
+		}
+	}
+	return y
+}
+
+// Backward computes the backward pass.
+func (r *BroadcastCols) Backward(gy mat.Matrix) {
+	if gy.Rows() != r.x.Value().Size() {
+		panic("fn: matrices with not compatible size")
+	}
+	if r.x.RequiresGrad() {
+		gx := mat.GetEmptyDenseWorkspace(r.x.Value().Dims())
+		defer mat.ReleaseDense(gx)
+		rows, cols := gy.Dims()
+		for i := 0; i < rows; i++ {
+			var sum mat.Float
+			for j := 0; j < cols; j++ {
+				sum += gy.At(i, j)
+			}
+			gx.Data()[i] = sum
+		}
+		r.x.PropagateGrad(gx)
+	}
+}
+
+// BroadcastRows is a Function which repeats the row vector x as many times as the rows of another
+// operand, whose value is only read during the forward pass.
+type BroadcastRows struct {
+	x    Operand
+	like Operand
+}
+
+// NewBroadcastRows returns a new BroadcastRows Function.
+func NewBroadcastRows(x, like Operand) *BroadcastRows {
+	return &BroadcastRows{x: x, like: like}
+}
+
+// Forward computes the output of the function.
+func (r *BroadcastRows) Forward() mat.Matrix {
+	xv := r.x.Value()
+	if !xv.IsVector() {
+		panic("fn: the input must be a vector")
+	}
+	data := xv.Data()
+	rows := r.like.Value().Rows()
+	y := mat.GetDenseWorkspace(rows, len(data))
+	for i := 0; i < rows; i++ {
+		for j, v := range data {
+			y.Set(i, j, v)
+		}
+	}
+	return y
+}
+
+// Backward computes the backward pass.
+func (r *BroadcastRows) Backward(gy mat.Matrix) {
+	if gy.Columns() != r.x.Value().Size() {
+		panic("fn: matrices with not compatible size")
+	}
+	if r.x.RequiresGrad() {
+		gx := mat.GetEmptyDenseWorkspace(r.x.Value().Dims())
+		defer mat.ReleaseDense(gx)
+		rows, cols := gy.Dims()
+		for j := 0; j < cols; j++ {
+			var sum mat.Float
+			for i := 0; i < rows; i++ {
+				sum += gy.At(i, j)
+			}
+			gx.Data()[j] = sum
+		}
+		r.x.PropagateGrad(gx)
+	}
+}
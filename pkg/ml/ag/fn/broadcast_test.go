@@ -0,0 +1,59 @@
+// Copyright 2021 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fn
+
+import (
+	mat "github.com/nlpodyssey/spago/pkg/mat32"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestBroadcastCols_Forward(t *testing.T) {
+	x := &variable{
+		value:        mat.NewVecDense([]mat.Float{0.1, 0.2}),
+		grad:         nil,
+		requiresGrad: true,
+	}
+	like := &variable{
+		value:        mat.NewEmptyDense(5, 3),
+		grad:         nil,
+		requiresGrad: false,
+	}
+	f := NewBroadcastCols(x, like)
+	y := f.Forward()
+
+	assert.Equal(t, 2, y.Rows())
+	assert.Equal(t, 3, y.Columns())
+	assert.InDeltaSlice(t, []mat.Float{0.1, 0.1, 0.1, 0.2, 0.2, 0.2}, y.Data(), 1.0e-6)
+
+	f.Backward(mat.NewDense(2, 3, []mat.Float{0.1, 0.2, 0.3, -0.4, 0.5, 0.6}))
+
+	assert.InDeltaSlice(t, []mat.Float{0.6, 0.7}, x.grad.Data(), 1.0e-6)
+	assert.Nil(t, like.grad)
+}
+
+func TestBroadcastRows_Forward(t *testing.T) {
+	x := &variable{
+		value:        mat.NewDense(1, 3, []mat.Float{0.1, 0.2, 0.3}),
+		grad:         nil,
+		requiresGrad: true,
+	}
+	like := &variable{
+		value:        mat.NewEmptyDense(2, 5),
+		grad:         nil,
+		requiresGrad: false,
+	}
+	f := NewBroadcastRows(x, like)
+	y := f.Forward()
+
+	assert.Equal(t, 2, y.Rows())
+	assert.Equal(t, 3, y.Columns())
+	assert.InDeltaSlice(t, []mat.Float{0.1, 0.2, 0.3, 0.1, 0.2, 0.3}, y.Data(), 1.0e-6)
+
+	f.Backward(mat.NewDense(2, 3, []mat.Float{0.1, 0.2, 0.3, -0.4, 0.5, 0.6}))
+
+	assert.Equal(t, 1, x.grad.Rows())
+	assert.InDeltaSlice(t, []mat.Float{-0.3, 0.7, 0.9}, x.grad.Data(), 1.0e-6)
+}
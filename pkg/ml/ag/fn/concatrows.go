@@ -0,0 +1,61 @@
+// Copyright 2021 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fn
+
+import (
+	mat "github.com/nlpodyssey/spago/pkg/mat32"
+)
+
+var _ Function = &ConcatRows{}
+
+// ConcatRows is a Function which concatenates vertically matrices with the same number of columns
+// (e.g. the outputs of the attention heads for a whole minibatch).
+type ConcatRows struct {
+	xs []Operand
+}
+
+// NewConcatRows returns a new ConcatRows Function.
+func NewConcatRows(xs []Operand) *ConcatRows {
+	return &ConcatRows{xs: xs}
+}
+
+// Forward computes the output of the function.
+func (r *ConcatRows) Forward() mat.Matrix {
+	cols := r.xs[0].Value().Columns()
+	rows := 0
+	for _, x := range r.xs {
+		if x.Value().Columns() != cols {
+			panic("fn: matrices with not compatible size")
+		}
+		rows += x.Value().Rows()
+	}
+	y := mat.GetDenseWorkspace(rows, cols)
+	data := y.Data()
+	offset := 0
+	for _, x := range r.xs {
+		// the row-major data of a vertical concatenation is the concatenation of the data
+		offset += copy(data[offset:], x.Value().Data())
+	}
+	return y
+}
+
+// Backward computes the backward pass.
+func (r *ConcatRows) Backward(gy mat.Matrix) {
+	data := gy.Data()
+	offset := 0
+	for _, x := range r.xs {
+		rows, cols := x.Value().Dims()
+		size := rows * cols
+		if x.RequiresGrad() {
+			gx := mat.NewDense(rows, cols, data[offset:offset+size])
+			x.PropagateGrad(gx)
+			mat.ReleaseDense(gx)
+		}
+		offset += size
+	}
+	if offset != gy.Size() {
+		panic("fn: matrices with not compatible size")
+	}
+}
@@ -0,0 +1,42 @@
+// Copyright 2021 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fn
+
+import (
+	mat "github.com/nlpodyssey/spago/pkg/mat32"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestConcatRows_Forward(t *testing.T) {
+	x1 := &variable{
+		value:        mat.NewDense(1, 2, []mat.Float{0.1, 0.2}),
+		grad:         nil,
+		requiresGrad: true,
+	}
+	x2 := &variable{
+		value:        mat.NewDense(2, 2, []mat.Float{0.3, 0.4, 0.5, 0.6}),
+		grad:         nil,
+		requiresGrad: false,
+	}
+	x3 := &variable{
+		value:        mat.NewDense(1, 2, []mat.Float{0.7, 0.8}),
+		grad:         nil,
+		requiresGrad: true,
+	}
+	f := NewConcatRows([]Operand{x1, x2, x3})
+	y := f.Forward()
+
+	assert.Equal(t, 4, y.Rows())
+	assert.Equal(t, 2, y.Columns())
+	assert.InDeltaSlice(t, []mat.Float{0.1, 0.2, 0.3, 0.4, 0.5, 0.6, 0.7, 0.8}, y.Data(), 1.0e-6)
+
+	f.Backward(mat.NewDense(4, 2, []mat.Float{1, 2, 3, 4, 5, 6, 7, 8}))
+
+	assert.InDeltaSlice(t, []mat.Float{1, 2}, x1.grad.Data(), 1.0e-6)
+	assert.Nil(t, x2.grad)
+	assert.Equal(t, 1, x3.grad.Rows())
+	assert.InDeltaSlice(t, []mat.Float{7, 8}, x3.grad.Data(), 1.0e-6)
+}
@@ -23,3 +23,10 @@ type Function interface {
 	// Backward computes the backward pass.
 	Backward(gy mat.Matrix)
 }
+
+// BackendSetter is implemented by any Function whose heavy computations can be
+// delegated to a mat.Backend.
+type BackendSetter interface {
+	// SetBackend sets the mat.Backend used by the function.
+	SetBackend(backend mat.Backend)
+}
@@ -9,17 +9,26 @@ import (
 	"sync"
 )
 
-var _ Function = &Mul{}
+var (
+	_ Function      = &Mul{}
+	_ BackendSetter = &Mul{}
+)
 
 // Mul is an operator to perform matrix-vector multiplication.
 type Mul struct {
-	x1 Operand // matrix
-	x2 Operand // vector
+	x1      Operand // matrix
+	x2      Operand // vector
+	backend mat.Backend
 }
 
 // NewMul returns a new Mul Function.
 func NewMul(x1, x2 Operand) *Mul {
-	return &Mul{x1: x1, x2: x2}
+	return &Mul{x1: x1, x2: x2, backend: mat.DefaultBackend{}}
+}
+
+// SetBackend sets the mat.Backend used to perform the multiplications.
+func (r *Mul) SetBackend(backend mat.Backend) {
+	r.backend = backend
 }
 
 // Forward computes the output of the function.
@@ -27,7 +36,7 @@ func (r *Mul) Forward() mat.Matrix {
 	if r.x1.Value().Columns() != r.x2.Value().Rows() {
 		panic("fn: matrices with not compatible size")
 	}
-	return r.x1.Value().Mul(r.x2.Value())
+	return r.backend.Mul(r.x1.Value(), r.x2.Value())
 }
 
 // Backward computes the backward pass.
@@ -43,7 +52,7 @@ func (r *Mul) Backward(gy mat.Matrix) {
 			defer wg.Done()
 			x2t := r.x2.Value().T()
 			defer mat.ReleaseMatrix(x2t)
-			gx := gy.Mul(x2t)
+			gx := r.backend.Mul(gy, x2t)
 			defer mat.ReleaseMatrix(gx)
 			r.x1.PropagateGrad(gx)
 		}()
@@ -60,7 +69,7 @@ func (r *Mul) Backward(gy mat.Matrix) {
 			} else {
 				x1t := r.x1.Value().T()
 				defer mat.ReleaseMatrix(x1t)
-				gx := x1t.Mul(gy)
+				gx := r.backend.Mul(x1t, gy)
 				defer mat.ReleaseMatrix(gx)
 				r.x2.PropagateGrad(gx)
 			}
@@ -0,0 +1,52 @@
+// Copyright 2021 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fn
+
+import (
+	mat "github.com/nlpodyssey/spago/pkg/mat32"
+)
+
+var _ Function = &QuantizedMul{}
+
+// QuantizedMul is an operator to perform the multiplication of a constant int8-quantized matrix
+// by a vector (or a minibatch of column vectors).
+type QuantizedMul struct {
+	w *mat.QuantizedDense
+	x Operand
+}
+
+// NewQuantizedMul returns a new QuantizedMul Function.
+func NewQuantizedMul(w *mat.QuantizedDense, x Operand) *QuantizedMul {
+	return &QuantizedMul{w: w, x: x}
+}
+
+// Forward computes the output of the function.
+func (r *QuantizedMul) Forward() mat.Matrix {
+	return r.w.Mul(r.x.Value())
+}
+
+// Backward computes the backward pass.
+// The gradients are propagated to the input only, the quantized matrix being constant.
+func (r *QuantizedMul) Backward(gy mat.Matrix) {
+	if !(r.w.Rows() == gy.Rows() && r.x.Value().Columns() == gy.Columns()) {
+		panic("fn: matrices with not compatible size")
+	}
+	if !r.x.RequiresGrad() {
+		return
+	}
+	if gy.Columns() == 1 {
+		gx := r.w.MulT(gy)
+		defer mat.ReleaseMatrix(gx)
+		r.x.PropagateGrad(gx)
+		return
+	}
+	w := r.w.Dense()
+	defer mat.ReleaseDense(w)
+	wt := w.T()
+	defer mat.ReleaseMatrix(wt)
+	gx := wt.Mul(gy)
+	defer mat.ReleaseMatrix(gx)
+	r.x.PropagateGrad(gx)
+}
@@ -0,0 +1,57 @@
+// Copyright 2021 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fn
+
+import (
+	mat "github.com/nlpodyssey/spago/pkg/mat32"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestQuantizedMul_Forward(t *testing.T) {
+	w := mat.NewQuantizedDense(mat.NewDense(2, 3, []mat.Float{
+		0.5, 0.6, -0.8,
+		-0.2, 0.9, 0.1,
+	}), nil)
+	x := &variable{
+		value:        mat.NewVecDense([]mat.Float{0.1, 0.2, 0.3}),
+		grad:         nil,
+		requiresGrad: true,
+	}
+
+	f := NewQuantizedMul(w, x)
+	y := f.Forward()
+	assert.InDeltaSlice(t, []mat.Float{-0.07, 0.19}, y.Data(), 1.0e-2)
+
+	f.Backward(mat.NewVecDense([]mat.Float{1.0, -0.5}))
+	assert.InDeltaSlice(t, []mat.Float{0.6, 0.15, -0.85}, x.grad.Data(), 1.0e-2)
+}
+
+func TestQuantizedMul_ForwardBatch(t *testing.T) {
+	w := mat.NewQuantizedDense(mat.NewDense(2, 3, []mat.Float{
+		0.5, 0.6, -0.8,
+		-0.2, 0.9, 0.1,
+	}), nil)
+	x := &variable{
+		value: mat.NewDense(3, 2, []mat.Float{
+			0.1, 0.0,
+			0.2, 1.0,
+			0.3, 0.0,
+		}),
+		grad:         nil,
+		requiresGrad: true,
+	}
+
+	f := NewQuantizedMul(w, x)
+	y := f.Forward()
+	assert.InDeltaSlice(t, []mat.Float{-0.07, 0.6, 0.19, 0.9}, y.Data(), 1.0e-2)
+
+	f.Backward(mat.NewDense(2, 2, []mat.Float{1.0, 0.0, -0.5, 1.0}))
+	assert.InDeltaSlice(t, []mat.Float{
+		0.6, -0.2,
+		0.15, 0.9,
+		-0.85, 0.1,
+	}, x.grad.Data(), 1.0e-2)
+}
@@ -0,0 +1,92 @@
+// Copyright 2021 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fn
+
+import (
+	mat "github.com/nlpodyssey/spago/pkg/mat32"
+)
+
+var (
+	_ Function = &ReduceSumCols{}
+	_ Function = &ReduceMeanCols{}
+)
+
+// ReduceSumCols is a Function which sums the elements of each column of the input matrix.
+// The output is a row vector with as many elements as the columns of x.
+type ReduceSumCols struct {
+	x Operand
+}
+
+// NewReduceSumCols returns a new ReduceSumCols Function.
+func NewReduceSumCols(x Operand) *ReduceSumCols {
+	return &ReduceSumCols{x: x}
+}
+
+// Forward computes the output of the function.
+func (r *ReduceSumCols) Forward() mat.Matrix {
+	return reduceCols(r.x.Value(), 1.0)
+}
+
+// Backward computes the backward pass.
+func (r *ReduceSumCols) Backward(gy mat.Matrix) {
+	if r.x.RequiresGrad() {
+		gx := expandCols(gy, r.x.Value().Rows(), 1.0)
+		defer mat.ReleaseDense(gx)
+		r.x.PropagateGrad(gx)
+	}
+}
+
+// ReduceMeanCols is a Function which averages the elements of each column of the input matrix.
+// The output is a row vector with as many elements as the columns of x.
+type ReduceMeanCols struct {
+	x Operand
+}
+
+// NewReduceMeanCols returns a new ReduceMeanCols Function.
+func NewReduceMeanCols(x Operand) *ReduceMeanCols {
+	return &ReduceMeanCols{x: x}
+}
+
+// Forward computes the output of the function.
+func (r *ReduceMeanCols) Forward() mat.Matrix {
+	xv := r.x.Value()
+	return reduceCols(xv, 1.0/mat.Float(xv.Rows()))
+}
+
+// Backward computes the backward pass.
+func (r *ReduceMeanCols) Backward(gy mat.Matrix) {
+	if r.x.RequiresGrad() {
+		rows := r.x.Value().Rows()
+		gx := expandCols(gy, rows, 1.0/mat.Float(rows))
+		defer mat.ReleaseDense(gx)
+		r.x.PropagateGrad(gx)
+	}
+}
+
+// reduceCols returns a row vector with the sums of the columns of x, multiplied by the factor.
+func reduceCols(x mat.Matrix, factor mat.Float) mat.Matrix {
+	rows, cols := x.Dims()
+	y := mat.GetEmptyDenseWorkspace(1, cols)
+	for j := 0; j < cols; j++ {
+		var sum mat.Float
+		for i := 0; i < rows; i++ {
+			sum += x.At(i, j)
+		}
+		y.Set(0, j, sum*factor)
+	}
+	return y
+}
+
+// expandCols returns the gradient of reduceCols, repeating each element of gy, multiplied by the factor,
+// in all the rows of the corresponding column.
+func expandCols(gy mat.Matrix, rows int, factor mat.Float) *mat.Dense {
+	gx := mat.GetDenseWorkspace(rows, gy.Size())
+	for j, v := range gy.Data() {
+		for i := 0; i < rows; i++ {
+			gx.Set(i, j, v*factor)
+		}
+	}
+	return gx
+}
@@ -0,0 +1,44 @@
+// Copyright 2021 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fn
+
+import (
+	mat "github.com/nlpodyssey/spago/pkg/mat32"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestReduceSumCols_Forward(t *testing.T) {
+	x := &variable{
+		value:        mat.NewDense(2, 3, []mat.Float{0.1, 0.2, 0.3, -0.4, 0.5, 0.6}),
+		grad:         nil,
+		requiresGrad: true,
+	}
+	f := NewReduceSumCols(x)
+	y := f.Forward()
+
+	assert.Equal(t, 1, y.Rows())
+	assert.InDeltaSlice(t, []mat.Float{-0.3, 0.7, 0.9}, y.Data(), 1.0e-6)
+
+	f.Backward(mat.NewDense(1, 3, []mat.Float{0.5, -1.0, 2.0}))
+
+	assert.InDeltaSlice(t, []mat.Float{0.5, -1.0, 2.0, 0.5, -1.0, 2.0}, x.grad.Data(), 1.0e-6)
+}
+
+func TestReduceMeanCols_Forward(t *testing.T) {
+	x := &variable{
+		value:        mat.NewDense(2, 3, []mat.Float{0.1, 0.2, 0.3, -0.4, 0.5, 0.6}),
+		grad:         nil,
+		requiresGrad: true,
+	}
+	f := NewReduceMeanCols(x)
+	y := f.Forward()
+
+	assert.InDeltaSlice(t, []mat.Float{-0.15, 0.35, 0.45}, y.Data(), 1.0e-6)
+
+	f.Backward(mat.NewDense(1, 3, []mat.Float{0.5, -1.0, 2.0}))
+
+	assert.InDeltaSlice(t, []mat.Float{0.25, -0.5, 1.0, 0.25, -0.5, 1.0}, x.grad.Data(), 1.0e-6)
+}
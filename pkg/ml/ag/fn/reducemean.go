@@ -31,7 +31,8 @@ func (r *ReduceMean) Backward(gy mat.Matrix) {
 		panic("fn: the gradient had to be a scalar")
 	}
 	if r.x.RequiresGrad() {
-		gx := mat.NewInitVecDense(r.x.Value().Size(), gy.Scalar()/mat.Float(r.x.Value().Size()))
+		rows, cols := r.x.Value().Dims()
+		gx := mat.NewInitDense(rows, cols, gy.Scalar()/mat.Float(r.x.Value().Size()))
 		defer mat.ReleaseDense(gx)
 		r.x.PropagateGrad(gx)
 	}
@@ -31,7 +31,8 @@ func (r *ReduceSum) Backward(gy mat.Matrix) {
 		panic("fn: the gradient had to be a scalar")
 	}
 	if r.x.RequiresGrad() {
-		gx := mat.NewInitVecDense(r.x.Value().Size(), gy.Scalar())
+		rows, cols := r.x.Value().Dims()
+		gx := mat.NewInitDense(rows, cols, gy.Scalar())
 		defer mat.ReleaseDense(gx)
 		r.x.PropagateGrad(gx)
 	}
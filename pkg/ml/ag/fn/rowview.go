@@ -9,6 +9,8 @@ import mat "github.com/nlpodyssey/spago/pkg/mat32"
 var _ Function = &RowView{}
 
 // RowView is a function to extract the i-th row from the input matrix.
+// The gradients of the input are row-sparse (see mat.RowSparse), so that the backward pass only touches the
+// extracted row, e.g. of a large embedding matrix.
 type RowView struct {
 	x Operand
 	i int
@@ -42,11 +44,8 @@ func (r *RowView) Backward(gy mat.Matrix) {
 		panic("fn: matrices with not compatible size")
 	}
 	if r.x.RequiresGrad() {
-		gx := mat.NewEmptyDense(r.x.Value().Dims())
-		defer mat.ReleaseDense(gx)
-		for j := 0; j < r.x.Value().Columns(); j++ {
-			gx.Set(r.i, j, gy.At(0, j))
-		}
+		gx := mat.NewRowSparse(r.x.Value().Dims())
+		gx.AddToRow(r.i, gy.Data())
 		r.x.PropagateGrad(gx)
 	}
 }
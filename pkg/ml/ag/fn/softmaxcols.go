@@ -0,0 +1,63 @@
+// Copyright 2021 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fn
+
+import (
+	mat "github.com/nlpodyssey/spago/pkg/mat32"
+)
+
+var _ Function = &SoftmaxCols{}
+
+// SoftmaxCols is a softmax function applied independently to each column of the input matrix.
+// It allows to compute the softmax of a whole minibatch of vectors, stacked as columns, at once.
+type SoftmaxCols struct {
+	x Operand
+	y mat.Matrix // initialized during the forward pass (required by the backward pass)
+}
+
+// NewSoftmaxCols returns a new SoftmaxCols Function.
+func NewSoftmaxCols(x Operand) *SoftmaxCols {
+	return &SoftmaxCols{x: x}
+}
+
+// Forward computes the output of this function.
+func (r *SoftmaxCols) Forward() mat.Matrix {
+	xv := r.x.Value()
+	rows, cols := xv.Dims()
+	y := mat.GetDenseWorkspace(rows, cols)
+	col := make([]mat.Float, rows)
+	for j := 0; j < cols; j++ {
+		for i := 0; i < rows; i++ {
+			col[i] = xv.At(i, j)
+		}
+		for i, v := range softmax(col) {
+			y.Set(i, j, v)
+		}
+	}
+	r.y = y
+	return r.y
+}
+
+// Backward computes the backward pass.
+func (r *SoftmaxCols) Backward(gy mat.Matrix) {
+	if !mat.SameDims(r.x.Value(), gy) {
+		panic("fn: matrices with not compatible size")
+	}
+	if r.x.RequiresGrad() {
+		rows, cols := r.y.Dims()
+		gx := mat.GetDenseWorkspace(rows, cols)
+		defer mat.ReleaseDense(gx)
+		for j := 0; j < cols; j++ {
+			var dot mat.Float = 0.0
+			for i := 0; i < rows; i++ {
+				dot += r.y.At(i, j) * gy.At(i, j)
+			}
+			for i := 0; i < rows; i++ {
+				gx.Set(i, j, r.y.At(i, j)*(gy.At(i, j)-dot))
+			}
+		}
+		r.x.PropagateGrad(gx)
+	}
+}
@@ -0,0 +1,55 @@
+// Copyright 2021 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fn
+
+import (
+	mat "github.com/nlpodyssey/spago/pkg/mat32"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestSoftmaxCols_Forward(t *testing.T) {
+	x := &variable{
+		value: mat.NewDense(6, 2, []mat.Float{
+			-0.41, 0.0,
+			-1.08, 0.0,
+			0, 0.0,
+			0.87, 0.0,
+			-0.19, 0.0,
+			-0.75, mat.Inf(-1),
+		}),
+		grad:         nil,
+		requiresGrad: true,
+	}
+	f := NewSoftmaxCols(x)
+	y := f.Forward()
+
+	assert.InDeltaSlice(t, []mat.Float{
+		0.1166451, 0.2,
+		0.0596882, 0.2,
+		0.1757629, 0.2,
+		0.4195304, 0.2,
+		0.1453487, 0.2,
+		0.083024, 0.0,
+	}, y.Data(), 1.0e-6)
+
+	f.Backward(mat.NewDense(6, 2, []mat.Float{
+		0.0, 1.0,
+		0.0, 0.0,
+		-5.689482, 0.0,
+		0.0, 0.0,
+		0.0, 0.0,
+		0.0, 0.0,
+	}))
+
+	assert.InDeltaSlice(t, []mat.Float{
+		0.1166451, 0.16,
+		0.0596882, -0.04,
+		-0.8242370, -0.04,
+		0.4195304, -0.04,
+		0.1453487, -0.04,
+		0.083024, 0.0,
+	}, x.grad.Data(), 1.0e-6)
+}
@@ -184,6 +184,11 @@ func Mul(x1 Node, x2 Node) Node {
 	return globalGraph.Mul(x1, x2)
 }
 
+// QuantizedMul returns a new operator node as a result of the fn.QuantizedMul function.
+func QuantizedMul(w *mat.QuantizedDense, x Node) Node {
+	return globalGraph.QuantizedMul(w, x)
+}
+
 // Dot returns a new operator node as a result of the fn.Dot function.
 func Dot(x1 Node, x2 Node) Node {
 	return globalGraph.Dot(x1, x2)
@@ -439,3 +444,50 @@ func Concat(xs ...Node) Node {
 func Stack(xs ...Node) Node {
 	return globalGraph.Stack(xs...)
 }
+
+// SoftmaxCols returns a new operator node as a result of the fn.SoftmaxCols function.
+func SoftmaxCols(x Node) Node {
+	return globalGraph.SoftmaxCols(x)
+}
+
+// StackCols returns a new operator node as a result of stacking the given vectors as columns.
+func StackCols(xs ...Node) Node {
+	return globalGraph.StackCols(xs...)
+}
+
+// BroadcastCols returns a new operator node as a result of repeating the column vector x n times.
+func BroadcastCols(x Node, n int) Node {
+	return globalGraph.BroadcastCols(x, n)
+}
+
+// BroadcastRows returns a new operator node as a result of repeating the row vector x n times.
+func BroadcastRows(x Node, n int) Node {
+	return globalGraph.BroadcastRows(x, n)
+}
+
+// ReduceSumCols returns a new operator node as a result of the sum of each column of x.
+func ReduceSumCols(x Node) Node {
+	return globalGraph.ReduceSumCols(x)
+}
+
+// ReduceMeanCols returns a new operator node as a result of the mean of each column of x.
+func ReduceMeanCols(x Node) Node {
+	return globalGraph.ReduceMeanCols(x)
+}
+
+// BroadcastColsLike returns a new operator node as a result of repeating the column vector x
+// as many times as the columns of like.
+func BroadcastColsLike(x, like Node) Node {
+	return globalGraph.BroadcastColsLike(x, like)
+}
+
+// BroadcastRowsLike returns a new operator node as a result of repeating the row vector x
+// as many times as the rows of like.
+func BroadcastRowsLike(x, like Node) Node {
+	return globalGraph.BroadcastRowsLike(x, like)
+}
+
+// ConcatRows returns a new operator node as a result of the fn.ConcatRows function.
+func ConcatRows(xs ...Node) Node {
+	return globalGraph.ConcatRows(xs...)
+}
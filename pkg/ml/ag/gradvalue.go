@@ -25,3 +25,23 @@ type GradValue interface {
 	// ZeroGrad set the gradients to zeros.
 	ZeroGrad()
 }
+
+// AccumulateGrad adds the gradients gx to the accumulated gradients grad, which can be nil, and returns the result.
+// The dimensions of the gradients are rows x cols.
+//
+// The row-sparse gradients (mat.RowSparse) are accumulated in a row-sparse matrix, which is converted to a dense
+// matrix as soon as a dense gradient is accumulated too.
+func AccumulateGrad(grad, gx mat.Matrix, rows, cols int) mat.Matrix {
+	if gx, ok := gx.(*mat.RowSparse); ok && grad == nil {
+		return gx.Clone()
+	}
+	if g, ok := grad.(*mat.RowSparse); ok {
+		if _, ok := gx.(*mat.RowSparse); !ok {
+			grad = g.ToDense()
+		}
+	}
+	if grad == nil {
+		grad = mat.GetEmptyDenseWorkspace(rows, cols) // this could reduce the number of allocations
+	}
+	return grad.AddInPlace(gx)
+}
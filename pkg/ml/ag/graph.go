@@ -47,6 +47,8 @@ type Graph struct {
 	// such as forward and backward steps.
 	// The default size is defaultProcessingQueueSize.
 	processingQueue processingqueue.ProcessingQueue
+	// backend performs the heavy computations of the operators that support it (default mat.DefaultBackend).
+	backend mat.Backend
 }
 
 // defaultProcessingQueueSize is the default size of Graph.processingQueue on a new Graph.
@@ -91,6 +93,14 @@ func ConcurrentComputations(value int) GraphOption {
 	}
 }
 
+// Backend sets the mat.Backend used by the operators to perform the computationally heavy operations,
+// such as the matrix multiplication (default mat.DefaultBackend).
+func Backend(backend mat.Backend) GraphOption {
+	return func(g *Graph) {
+		g.backend = backend
+	}
+}
+
 // NewGraph returns a new initialized graph.
 // It can take an optional random generator of type rand.Rand.
 func NewGraph(opts ...GraphOption) *Graph {
@@ -101,6 +111,7 @@ func NewGraph(opts ...GraphOption) *Graph {
 		constants:          map[mat.Float]Node{},
 		incrementalForward: true,
 		processingQueue:    processingqueue.New(defaultProcessingQueueSize),
+		backend:            mat.DefaultBackend{},
 	}
 	g.clearCache()
 	for _, opt := range opts {
@@ -272,6 +283,9 @@ func (g *Graph) NewOperator(f fn.Function, operands ...Node) Node {
 				"You may consider wrapping the nodes you need with NewWrap().")
 		}
 	}
+	if f, ok := f.(fn.BackendSetter); ok {
+		f.SetBackend(g.backend)
+	}
 	var value mat.Matrix = nil
 	if g.incrementalForward {
 		// the calculation is out of the lock so it can run concurrently with other operators
@@ -514,6 +528,11 @@ func (g *Graph) Nodes() []Node {
 	return g.nodes
 }
 
+// Backend returns the mat.Backend used by the operators of the Graph.
+func (g *Graph) Backend() mat.Backend {
+	return g.backend
+}
+
 // ConcurrentComputations returns the maximum number of concurrent computations handled by the Graph
 // for heavy tasks such as forward and backward steps.
 func (g *Graph) ConcurrentComputations() int {
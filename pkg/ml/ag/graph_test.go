@@ -32,6 +32,7 @@ func TestNewGraph(t *testing.T) {
 		assert.NotNil(t, g.randGen)
 		assert.True(t, g.incrementalForward)
 		assert.Equal(t, defaultProcessingQueueSize, g.ConcurrentComputations())
+		assert.Equal(t, mat.DefaultBackend{}, g.Backend())
 	})
 
 	t.Run("with IncrementalForward(false) option", func(t *testing.T) {
@@ -73,6 +74,38 @@ func TestNewGraph(t *testing.T) {
 		assert.True(t, g.incrementalForward)
 		assert.Equal(t, defaultProcessingQueueSize, g.ConcurrentComputations())
 	})
+
+	t.Run("with Backend option", func(t *testing.T) {
+		g := NewGraph(Backend(mat.ParallelBackend{}))
+		runCommonAssertions(t, g)
+		assert.Equal(t, mat.ParallelBackend{}, g.Backend())
+	})
+}
+
+// countingBackend is a mat.Backend which counts the number of multiplications.
+type countingBackend struct {
+	mat.DefaultBackend
+	muls int
+}
+
+func (b *countingBackend) Mul(x, y mat.Matrix) mat.Matrix {
+	b.muls++
+	return b.DefaultBackend.Mul(x, y)
+}
+
+func TestGraph_Backend(t *testing.T) {
+	backend := &countingBackend{}
+	g := NewGraph(Backend(backend), ConcurrentComputations(1))
+	x := g.NewVariable(mat.NewDense(2, 2, []mat.Float{1, 2, 3, 4}), true)
+	y := g.NewVariable(mat.NewVecDense([]mat.Float{1, -1}), true)
+	z := g.Mul(x, y)
+	assert.Equal(t, []mat.Float{-1, -1}, z.Value().Data())
+	assert.Equal(t, 1, backend.muls)
+
+	g.Backward(z, OutputGrad(mat.NewVecDense([]mat.Float{1, 1})))
+	assert.Equal(t, []mat.Float{1, -1, 1, -1}, x.Grad().Data())
+	assert.Equal(t, []mat.Float{4, 6}, y.Grad().Data())
+	assert.Equal(t, 2, backend.muls) // the gradient of y is computed with MulT
 }
 
 func TestConcurrentComputations(t *testing.T) {
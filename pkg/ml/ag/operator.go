@@ -32,7 +32,7 @@ type Operator struct {
 	operands     []Node
 	value        mat.Matrix // store the results of a forward evaluation
 	mu           sync.Mutex // to avoid data race during gradients accumulation
-	grad         mat.Matrix // TODO: support of sparse gradients
+	grad         mat.Matrix // dense or row-sparse (see AccumulateGrad)
 	hasGrad      bool
 	requiresGrad bool
 }
@@ -77,10 +77,8 @@ func (r *Operator) PropagateGrad(grad mat.Matrix) {
 	}
 	r.mu.Lock()
 	defer r.mu.Unlock()
-	if r.grad == nil {
-		r.grad = mat.GetEmptyDenseWorkspace(r.value.Dims()) // this could reduce the number of allocations
-	}
-	r.grad.AddInPlace(grad)
+	rows, cols := r.value.Dims()
+	r.grad = AccumulateGrad(r.grad, grad, rows, cols)
 	r.hasGrad = true
 }
 
@@ -118,5 +116,8 @@ func (r *Operator) backward() {
 	if !r.hasGrad {
 		return
 	}
+	if grad, ok := r.grad.(*mat.RowSparse); ok {
+		r.grad = grad.ToDense() // the functions expect dense output gradients
+	}
 	r.function.Backward(r.grad)
 }
@@ -147,74 +147,95 @@ const (
 	OpConcat
 	// OpStack identifies the Graph.Stack operator.
 	OpStack
+	// OpSoftmaxCols identifies the Graph.SoftmaxCols operator.
+	OpSoftmaxCols
+	// OpStackCols identifies the Graph.StackCols operator.
+	OpStackCols
+	// OpReduceSumCols identifies the Graph.ReduceSumCols operator.
+	OpReduceSumCols
+	// OpReduceMeanCols identifies the Graph.ReduceMeanCols operator.
+	OpReduceMeanCols
+	// OpBroadcastColsLike identifies the Graph.BroadcastColsLike operator.
+	OpBroadcastColsLike
+	// OpBroadcastRowsLike identifies the Graph.BroadcastRowsLike operator.
+	OpBroadcastRowsLike
+	// OpConcatRows identifies the Graph.ConcatRows operator.
+	OpConcatRows
 )
 
 var opNameToMethodName = map[OpName]string{
-	OpIdentity:      "Identity",
-	OpDropout:       "Dropout",
-	OpAtVec:         "AtVec",
-	OpAt:            "At",
-	OpAdd:           "Add",
-	OpSub:           "Sub",
-	OpSubScalar:     "SubScalar",
-	OpAddScalar:     "AddScalar",
-	OpReverseSub:    "ReverseSub",
-	OpProd:          "Prod",
-	OpDiv:           "Div",
-	OpProdScalar:    "ProdScalar",
-	OpDivScalar:     "DivScalar",
-	OpMul:           "Mul",
-	OpDot:           "Dot",
-	OpReshape:       "Reshape",
-	OpMaxPooling:    "MaxPooling",
-	OpView:          "View",
-	OpRowView:       "RowView",
-	OpColView:       "ColView",
-	OpVec:           "Vec",
-	OpRotateR:       "RotateR",
-	OpT:             "T",
-	OpSquare:        "Square",
-	OpPow:           "Pow",
-	OpSqrt:          "Sqrt",
-	OpTan:           "Tan",
-	OpTanh:          "Tanh",
-	OpSigmoid:       "Sigmoid",
-	OpHardSigmoid:   "HardSigmoid",
-	OpHardTanh:      "HardTanh",
-	OpSoftsign:      "Softsign",
-	OpReLU:          "ReLU",
-	OpCELU:          "CELU",
-	OpGELU:          "GELU",
-	OpELU:           "ELU",
-	OpPositiveELU:   "PositiveELU",
-	OpSwishB:        "SwishB",
-	OpSwish:         "Swish",
-	OpSiLU:          "SiLU",
-	OpMish:          "Mish",
-	OpLeakyReLU:     "LeakyReLU",
-	OpSELU:          "SELU",
-	OpSoftPlus:      "SoftPlus",
-	OpSoftShrink:    "SoftShrink",
-	OpThreshold:     "Threshold",
-	OpSoftmax:       "Softmax",
-	OpLogSoftmax:    "LogSoftmax",
-	OpSparseMax:     "SparseMax",
-	OpSparseMaxLoss: "SparseMaxLoss",
-	OpSin:           "Sin",
-	OpCos:           "Cos",
-	OpExp:           "Exp",
-	OpLog:           "Log",
-	OpAbs:           "Abs",
-	OpNeg:           "Neg",
-	OpReciprocal:    "Reciprocal",
-	OpMax:           "Max",
-	OpMin:           "Min",
-	OpReduceSum:     "ReduceSum",
-	OpReduceMean:    "ReduceMean",
-	OpMean:          "Mean",
-	OpSum:           "Sum",
-	OpConcat:        "Concat",
-	OpStack:         "Stack",
+	OpIdentity:          "Identity",
+	OpDropout:           "Dropout",
+	OpAtVec:             "AtVec",
+	OpAt:                "At",
+	OpAdd:               "Add",
+	OpSub:               "Sub",
+	OpSubScalar:         "SubScalar",
+	OpAddScalar:         "AddScalar",
+	OpReverseSub:        "ReverseSub",
+	OpProd:              "Prod",
+	OpDiv:               "Div",
+	OpProdScalar:        "ProdScalar",
+	OpDivScalar:         "DivScalar",
+	OpMul:               "Mul",
+	OpDot:               "Dot",
+	OpReshape:           "Reshape",
+	OpMaxPooling:        "MaxPooling",
+	OpView:              "View",
+	OpRowView:           "RowView",
+	OpColView:           "ColView",
+	OpVec:               "Vec",
+	OpRotateR:           "RotateR",
+	OpT:                 "T",
+	OpSquare:            "Square",
+	OpPow:               "Pow",
+	OpSqrt:              "Sqrt",
+	OpTan:               "Tan",
+	OpTanh:              "Tanh",
+	OpSigmoid:           "Sigmoid",
+	OpHardSigmoid:       "HardSigmoid",
+	OpHardTanh:          "HardTanh",
+	OpSoftsign:          "Softsign",
+	OpReLU:              "ReLU",
+	OpCELU:              "CELU",
+	OpGELU:              "GELU",
+	OpELU:               "ELU",
+	OpPositiveELU:       "PositiveELU",
+	OpSwishB:            "SwishB",
+	OpSwish:             "Swish",
+	OpSiLU:              "SiLU",
+	OpMish:              "Mish",
+	OpLeakyReLU:         "LeakyReLU",
+	OpSELU:              "SELU",
+	OpSoftPlus:          "SoftPlus",
+	OpSoftShrink:        "SoftShrink",
+	OpThreshold:         "Threshold",
+	OpSoftmax:           "Softmax",
+	OpLogSoftmax:        "LogSoftmax",
+	OpSparseMax:         "SparseMax",
+	OpSparseMaxLoss:     "SparseMaxLoss",
+	OpSin:               "Sin",
+	OpCos:               "Cos",
+	OpExp:               "Exp",
+	OpLog:               "Log",
+	OpAbs:               "Abs",
+	OpNeg:               "Neg",
+	OpReciprocal:        "Reciprocal",
+	OpMax:               "Max",
+	OpMin:               "Min",
+	OpReduceSum:         "ReduceSum",
+	OpReduceMean:        "ReduceMean",
+	OpMean:              "Mean",
+	OpSum:               "Sum",
+	OpConcat:            "Concat",
+	OpStack:             "Stack",
+	OpSoftmaxCols:       "SoftmaxCols",
+	OpStackCols:         "StackCols",
+	OpReduceSumCols:     "ReduceSumCols",
+	OpReduceMeanCols:    "ReduceMeanCols",
+	OpBroadcastColsLike: "BroadcastColsLike",
+	OpBroadcastRowsLike: "BroadcastRowsLike",
+	OpConcatRows:        "ConcatRows",
 }
 
 // strToOpName is the inverse map of opNameToMethodName.
@@ -322,6 +343,11 @@ func (g *Graph) Mul(x1 Node, x2 Node) Node {
 	return g.NewOperator(fn.NewMul(x1, x2), x1, x2)
 }
 
+// QuantizedMul returns a new operator node as a result of the fn.QuantizedMul function.
+func (g *Graph) QuantizedMul(w *mat.QuantizedDense, x Node) Node {
+	return g.NewOperator(fn.NewQuantizedMul(w, x), x)
+}
+
 // Dot returns a new operator node as a result of the fn.Dot function.
 func (g *Graph) Dot(x1 Node, x2 Node) Node {
 	return g.NewOperator(fn.NewDot(x1, x2), x1, x2)
@@ -493,6 +519,41 @@ func (g *Graph) Softmax(x Node) Node {
 	return g.NewOperator(fn.NewSoftmax(x), x)
 }
 
+// SoftmaxCols returns a new operator node as a result of the fn.SoftmaxCols function.
+func (g *Graph) SoftmaxCols(x Node) Node {
+	return g.NewOperator(fn.NewSoftmaxCols(x), x)
+}
+
+// ReduceSumCols returns a new operator node as a result of the fn.ReduceSumCols function.
+// The output is a row vector with as many elements as the columns of x.
+func (g *Graph) ReduceSumCols(x Node) Node {
+	return g.NewOperator(fn.NewReduceSumCols(x), x)
+}
+
+// ReduceMeanCols returns a new operator node as a result of the fn.ReduceMeanCols function.
+// The output is a row vector with as many elements as the columns of x.
+func (g *Graph) ReduceMeanCols(x Node) Node {
+	return g.NewOperator(fn.NewReduceMeanCols(x), x)
+}
+
+// BroadcastColsLike returns a new operator node as a result of the fn.BroadcastCols function.
+// The column vector x is repeated as many times as the columns of like, which are only known
+// when the values are computed (e.g. to apply a bias to a minibatch of any size).
+func (g *Graph) BroadcastColsLike(x, like Node) Node {
+	return g.NewOperator(fn.NewBroadcastCols(x, like), x, like)
+}
+
+// BroadcastRowsLike returns a new operator node as a result of the fn.BroadcastRows function.
+// The row vector x is repeated as many times as the rows of like.
+func (g *Graph) BroadcastRowsLike(x, like Node) Node {
+	return g.NewOperator(fn.NewBroadcastRows(x, like), x, like)
+}
+
+// ConcatRows returns a new operator node as a result of the fn.ConcatRows function.
+func (g *Graph) ConcatRows(xs ...Node) Node {
+	return g.NewOperator(fn.NewConcatRows(Operands(xs)), xs...)
+}
+
 // SparseMax returns a new operator node as a result of the fn.SparseMax function.
 func (g *Graph) SparseMax(x Node) Node {
 	return g.NewOperator(fn.NewSparseMax(x), x)
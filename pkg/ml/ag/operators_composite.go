@@ -34,3 +34,24 @@ func (g *Graph) Mean(xs []Node) Node {
 	}
 	return g.DivScalar(sumVector, g.Constant(mat.Float(len(xs))))
 }
+
+// The following operators support the processing of a minibatch at once.
+// A minibatch of vectors is represented as a single matrix, where each column is a vector of the batch.
+
+// StackCols returns a new operator node as a result of stacking the given vectors as the columns of a matrix.
+func (g *Graph) StackCols(xs ...Node) Node {
+	return g.T(g.Stack(xs...))
+}
+
+// BroadcastCols returns a new operator node as a result of repeating the column vector x n times,
+// so that the resulting matrix has n columns.
+// It is useful to apply the same vector (e.g. a bias) to all the columns of a minibatch.
+func (g *Graph) BroadcastCols(x Node, n int) Node {
+	return g.Mul(x, g.NewVariable(mat.NewInitDense(1, n, 1.0), false))
+}
+
+// BroadcastRows returns a new operator node as a result of repeating the row vector x n times,
+// so that the resulting matrix has n rows.
+func (g *Graph) BroadcastRows(x Node, n int) Node {
+	return g.Mul(g.NewVariable(mat.NewInitDense(n, 1, 1.0), false), x)
+}
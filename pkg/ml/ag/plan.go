@@ -0,0 +1,235 @@
+// Copyright 2021 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ag
+
+import (
+	mat "github.com/nlpodyssey/spago/pkg/mat32"
+	"sync"
+)
+
+// Plan is the compiled form of the forward computation of a Graph, obtained with Graph.Compile().
+//
+// When the topology of the graph doesn't change from one computation to another (e.g. for inputs of the same
+// length), the graph can be recorded once, compiled, and replayed with new input values, saving the cost of
+// building the nodes and sorting them again. A convenient way to record a graph without computing it is to
+// create it with the IncrementalForward(false) option.
+//
+// The Plan executes only the operators which the outputs depend on (dead-node elimination), grouped by height
+// so that the operators within a group run concurrently if the Graph allows it (see ConcurrentComputations()).
+// The values of the nodes excluded from the plan are not updated.
+// A Plan must not be used after the Graph has been cleared with Clear(); ClearForReuse() is fine instead.
+type Plan struct {
+	g       *Graph
+	inputs  []*Variable
+	outputs []Node
+	// steps contains the operators to execute, grouped by height (i.e. in topological order).
+	steps [][]*Operator
+	// releases contains, for each step, the operators whose value is no longer needed after that step.
+	// It is populated only when the buffer reuse is enabled.
+	releases     [][]*Operator
+	reuseBuffers bool
+}
+
+// PlanOption allows to adapt the Plan to your specific needs.
+type PlanOption func(*Plan)
+
+// ReuseBuffers sets whether to release the value of each intermediate operator as soon as all the operators that
+// depend on it have been computed (default false). This way, the memory is given back to the pool of dense
+// matrices to be reused by the next operators, reducing the memory footprint of the computation.
+// Only the values of the outputs are kept, so the Backward() can't be performed after the replay.
+func ReuseBuffers(value bool) PlanOption {
+	return func(p *Plan) {
+		p.reuseBuffers = value
+	}
+}
+
+// Compile returns the Plan to compute the outputs from the given inputs, which must be variables.
+// The nodes the outputs depend on must already exist in the graph; nodes added afterwards are ignored by the Plan.
+// It panics if the nodes do not belong to the graph, or if an input is not a variable.
+func (g *Graph) Compile(inputs []Node, outputs []Node, opts ...PlanOption) *Plan {
+	p := &Plan{
+		g:            g,
+		inputs:       make([]*Variable, len(inputs)),
+		outputs:      outputs,
+		reuseBuffers: false,
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	for i, input := range inputs {
+		if input.Graph() != g {
+			panic("ag: the plan cannot be compiled among nodes of different graphs")
+		}
+		v, ok := input.(*Variable)
+		if !ok {
+			panic("ag: invalid input node. Only variables are allowed to change their value.")
+		}
+		p.inputs[i] = v
+	}
+	for _, output := range outputs {
+		if output.Graph() != g {
+			panic("ag: the plan cannot be compiled among nodes of different graphs")
+		}
+	}
+	p.compile()
+	return p
+}
+
+// compile visits the nodes in reverse topological order (i.e. by decreasing ID) to find the operators the outputs
+// depend on, then groups them by height.
+func (p *Plan) compile() {
+	g := p.g
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	maxID := -1
+	for _, output := range p.outputs {
+		if output.ID() > maxID {
+			maxID = output.ID()
+		}
+	}
+	live := make([]bool, maxID+1)
+	for _, output := range p.outputs {
+		live[output.ID()] = true
+	}
+	for id := maxID; id >= 0; id-- {
+		if !live[id] {
+			continue
+		}
+		if op, ok := g.nodes[id].(*Operator); ok {
+			for _, operand := range op.operands {
+				live[operand.ID()] = true
+			}
+		}
+	}
+
+	height := make([]int, maxID+1)
+	lastUse := make([]int, maxID+1) // the height of the last operator which uses the node as operand
+	for id := 0; id <= maxID; id++ {
+		op, ok := g.nodes[id].(*Operator)
+		if !live[id] || !ok {
+			continue
+		}
+		h := 0
+		for _, operand := range op.operands {
+			if _, ok := operand.(*Operator); ok && height[operand.ID()] >= h {
+				h = height[operand.ID()] + 1
+			}
+		}
+		height[id] = h
+		for _, operand := range op.operands {
+			if h > lastUse[operand.ID()] {
+				lastUse[operand.ID()] = h
+			}
+		}
+		if h == len(p.steps) {
+			p.steps = append(p.steps, make([]*Operator, 0, 1))
+		}
+		p.steps[h] = append(p.steps[h], op)
+	}
+
+	if !p.reuseBuffers {
+		return
+	}
+	isOutput := make([]bool, maxID+1)
+	for _, output := range p.outputs {
+		isOutput[output.ID()] = true
+	}
+	p.releases = make([][]*Operator, len(p.steps))
+	for _, step := range p.steps {
+		for _, op := range step {
+			if isOutput[op.id] || lastUse[op.id] == 0 {
+				continue // the outputs are kept (an operator never used by others is an output)
+			}
+			h := lastUse[op.id]
+			p.releases[h] = append(p.releases[h], op)
+		}
+	}
+}
+
+// Inputs returns the input nodes of the plan.
+func (p *Plan) Inputs() []Node {
+	inputs := make([]Node, len(p.inputs))
+	for i, input := range p.inputs {
+		inputs[i] = input
+	}
+	return inputs
+}
+
+// Outputs returns the output nodes of the plan.
+func (p *Plan) Outputs() []Node {
+	return p.outputs
+}
+
+// Len returns the number of operators executed by the plan.
+func (p *Plan) Len() int {
+	n := 0
+	for _, step := range p.steps {
+		n += len(step)
+	}
+	return n
+}
+
+// Replay replaces the values of the inputs with the given ones, in the same order, then computes the outputs.
+// If no values are given, the outputs are computed with the current values of the inputs.
+// It panics if the number of values doesn't match the number of inputs.
+func (p *Plan) Replay(values ...mat.Matrix) {
+	if len(values) > 0 {
+		if len(values) != len(p.inputs) {
+			panic("ag: the number of values doesn't match the number of inputs of the plan")
+		}
+		for i, input := range p.inputs {
+			input.value = values[i]
+		}
+	}
+
+	// Free the values that are about to be recalculated so that memory is not wasted
+	for _, step := range p.steps {
+		for _, op := range step {
+			p.g.releaseValue(op)
+		}
+	}
+
+	if p.g.processingQueue.Size() > 1 {
+		p.runConcurrent()
+	} else {
+		p.runSerial()
+	}
+}
+
+func (p *Plan) runSerial() {
+	for h, step := range p.steps {
+		for _, op := range step {
+			op.value = op.function.Forward()
+		}
+		p.release(h)
+	}
+}
+
+func (p *Plan) runConcurrent() {
+	var wg sync.WaitGroup
+	for h, step := range p.steps {
+		for _, op := range step {
+			op := op
+			wg.Add(1)
+			p.g.processingQueue.Go(func() {
+				defer wg.Done()
+				op.value = op.function.Forward()
+			})
+		}
+		wg.Wait()
+		p.release(h)
+	}
+}
+
+// release frees the values no longer needed after the execution of the given step.
+func (p *Plan) release(step int) {
+	if !p.reuseBuffers {
+		return
+	}
+	for _, op := range p.releases[step] {
+		p.g.releaseValue(op)
+	}
+}
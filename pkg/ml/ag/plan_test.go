@@ -0,0 +1,90 @@
+// Copyright 2021 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ag
+
+import (
+	"fmt"
+	mat "github.com/nlpodyssey/spago/pkg/mat32"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestGraph_Compile(t *testing.T) {
+	for _, size := range []int{1, 4} {
+		t.Run(fmt.Sprintf("concurrent computations %d", size), func(t *testing.T) {
+			g := NewGraph(IncrementalForward(false), ConcurrentComputations(size))
+			x := g.NewVariable(mat.NewVecDense([]mat.Float{0.0, 0.0}), false)
+			w := g.NewVariable(mat.NewDense(2, 2, []mat.Float{0.5, -0.2, 0.3, 0.1}), true)
+			b := g.NewVariable(mat.NewVecDense([]mat.Float{0.1, -0.1}), true)
+			h := g.Add(g.Mul(w, x), b)
+			dead := g.Exp(h)
+			y := g.ReduceSum(g.Tanh(h))
+
+			plan := g.Compile([]Node{x}, []Node{y})
+			assert.Equal(t, 4, plan.Len())
+			assert.Equal(t, []Node{x}, plan.Inputs())
+			assert.Equal(t, []Node{y}, plan.Outputs())
+
+			plan.Replay(mat.NewVecDense([]mat.Float{1.0, 2.0}))
+			assert.InDeltaSlice(t, []mat.Float{0.2, 0.4}, h.Value().Data(), 1.0e-6)
+			assert.InDelta(t, mat.Tanh(0.2)+mat.Tanh(0.4), y.ScalarValue(), 1.0e-6)
+			assert.Nil(t, dead.Value())
+
+			plan.Replay(mat.NewVecDense([]mat.Float{-1.0, 1.0}))
+			assert.InDeltaSlice(t, []mat.Float{-0.6, -0.3}, h.Value().Data(), 1.0e-6)
+			assert.InDelta(t, mat.Tanh(-0.6)+mat.Tanh(-0.3), y.ScalarValue(), 1.0e-6)
+
+			g.Backward(y)
+			assert.NotNil(t, w.Grad())
+		})
+	}
+}
+
+func TestGraph_Compile_ReuseBuffers(t *testing.T) {
+	for _, size := range []int{1, 4} {
+		t.Run(fmt.Sprintf("concurrent computations %d", size), func(t *testing.T) {
+			g := NewGraph(IncrementalForward(false), ConcurrentComputations(size))
+			x := g.NewVariable(mat.NewVecDense([]mat.Float{1.0, 2.0}), false)
+			a := g.ProdScalar(x, g.NewScalar(2.0))
+			b := g.Add(a, x)
+			c := g.Sigmoid(b)
+			y1 := g.Tanh(c)
+			y2 := g.Prod(c, a)
+
+			plan := g.Compile([]Node{x}, []Node{y1, y2}, ReuseBuffers(true))
+			plan.Replay()
+			assert.Nil(t, b.Value())
+			assert.Nil(t, a.Value())
+			assert.Nil(t, c.Value())
+
+			expected := func(v mat.Float) (mat.Float, mat.Float) {
+				s := 1.0 / (1.0 + mat.Exp(-3.0*v))
+				return mat.Tanh(s), s * 2.0 * v
+			}
+			y10, y20 := expected(1.0)
+			y11, y21 := expected(2.0)
+			assert.InDeltaSlice(t, []mat.Float{y10, y11}, y1.Value().Data(), 1.0e-6)
+			assert.InDeltaSlice(t, []mat.Float{y20, y21}, y2.Value().Data(), 1.0e-6)
+
+			plan.Replay(mat.NewVecDense([]mat.Float{3.0, 4.0}))
+			y10, y20 = expected(3.0)
+			y11, y21 = expected(4.0)
+			assert.InDeltaSlice(t, []mat.Float{y10, y11}, y1.Value().Data(), 1.0e-6)
+			assert.InDeltaSlice(t, []mat.Float{y20, y21}, y2.Value().Data(), 1.0e-6)
+		})
+	}
+}
+
+func TestGraph_Compile_Panics(t *testing.T) {
+	g := NewGraph()
+	x := g.NewVariable(mat.NewScalar(1.0), true)
+	y := g.Exp(x)
+
+	assert.Panics(t, func() { g.Compile([]Node{y}, []Node{y}) }, "the input is not a variable")
+	assert.Panics(t, func() { NewGraph().Compile([]Node{x}, []Node{y}) }, "nodes of a different graph")
+
+	plan := g.Compile([]Node{x}, []Node{y})
+	assert.Panics(t, func() { plan.Replay(mat.NewScalar(1.0), mat.NewScalar(2.0)) }, "too many values")
+}
@@ -24,7 +24,7 @@ type Variable struct {
 	name         string
 	value        mat.Matrix // store the results of a forward evaluation.
 	mu           sync.Mutex // to avoid data race during gradients accumulation
-	grad         mat.Matrix // TODO: support of sparse gradients
+	grad         mat.Matrix // dense or row-sparse (see AccumulateGrad)
 	hasGrad      bool
 	requiresGrad bool
 }
@@ -70,10 +70,8 @@ func (r *Variable) PropagateGrad(grad mat.Matrix) {
 	}
 	r.mu.Lock()
 	defer r.mu.Unlock()
-	if r.grad == nil {
-		r.grad = mat.GetEmptyDenseWorkspace(r.value.Dims()) // this could reduce the number of allocations
-	}
-	r.grad.AddInPlace(grad)
+	rows, cols := r.value.Dims()
+	r.grad = AccumulateGrad(r.grad, grad, rows, cols)
 	r.hasGrad = true
 }
 
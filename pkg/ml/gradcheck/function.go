@@ -0,0 +1,85 @@
+// Copyright 2021 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gradcheck
+
+import (
+	"fmt"
+	mat "github.com/nlpodyssey/spago/pkg/mat32"
+	"github.com/nlpodyssey/spago/pkg/ml/ag/fn"
+)
+
+// FunctionBuilder returns a new fn.Function operating on the given operands.
+// A new Function is built for each evaluation, so that the state cached during the
+// forward step never leaks from one evaluation to the next one.
+type FunctionBuilder func(xs []fn.Operand) fn.Function
+
+// Function checks the gradients of the function built by newFunc w.r.t. each of its operands,
+// whose values are given in the same order. The values are cloned and never modified.
+//
+// The output of the function is reduced to a scalar loss through the dot product with a random
+// matrix; the analytic gradients are the ones propagated by Backward, while the numerical
+// gradients are estimated perturbing each element of the operands with central finite differences.
+func Function(newFunc FunctionBuilder, values []mat.Matrix, opts ...Option) Results {
+	c := newChecker(opts...)
+	xs := make([]*operand, len(values))
+	ops := make([]fn.Operand, len(values))
+	for i, v := range values {
+		xs[i] = &operand{value: v.Clone(), requiresGrad: !c.noGrad[i]}
+		ops[i] = xs[i]
+	}
+
+	f := newFunc(ops)
+	r := c.projection(f.Forward())
+	f.Backward(r)
+
+	loss := func() float64 {
+		return dot(newFunc(ops).Forward(), r)
+	}
+
+	var results Results
+	for i, x := range xs {
+		if !x.requiresGrad {
+			continue
+		}
+		analytic := x.grad
+		if analytic == nil {
+			analytic = x.value.ZerosLike()
+		}
+		numeric := c.numericGrad(x.value, loss)
+		results = append(results, Result{
+			Name:        fmt.Sprintf("operand %d", i),
+			Analytic:    analytic,
+			Numeric:     numeric,
+			MaxRelError: maxRelError(analytic, numeric),
+		})
+	}
+	return results
+}
+
+// operand is a minimal implementation of fn.Operand which accumulates the gradients.
+type operand struct {
+	value        mat.Matrix
+	grad         mat.Matrix
+	requiresGrad bool
+}
+
+// Value returns the value of the operand.
+func (o *operand) Value() mat.Matrix {
+	return o.value
+}
+
+// PropagateGrad accumulates the gradients gx.
+func (o *operand) PropagateGrad(gx mat.Matrix) {
+	if o.grad == nil {
+		o.grad = gx.Clone()
+		return
+	}
+	o.grad.AddInPlace(gx)
+}
+
+// RequiresGrad returns true if the operand requires gradients.
+func (o *operand) RequiresGrad() bool {
+	return o.requiresGrad
+}
@@ -0,0 +1,97 @@
+// Copyright 2021 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gradcheck
+
+import (
+	mat "github.com/nlpodyssey/spago/pkg/mat32"
+	"github.com/nlpodyssey/spago/pkg/ml/ag/fn"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+const maxRelErrorTolerance = 1e-2
+
+func TestFunction(t *testing.T) {
+	x := mat.NewVecDense([]mat.Float{0.1, -0.3, 0.6, 0.25, -0.8, 0.45})
+	y := mat.NewVecDense([]mat.Float{-0.7, 0.5, 0.35, -0.2, 0.9, 0.15})
+	m := mat.NewDense(2, 3, []mat.Float{0.1, -0.3, 0.6, 0.25, -0.8, 0.45})
+	n := mat.NewDense(3, 2, []mat.Float{-0.7, 0.5, 0.35, -0.2, 0.9, 0.15})
+	alpha := mat.NewScalar(0.5)
+
+	testCases := []struct {
+		name    string
+		newFunc FunctionBuilder
+		values  []mat.Matrix
+		opts    []Option
+	}{
+		{"Add", func(xs []fn.Operand) fn.Function { return fn.NewAdd(xs[0], xs[1]) }, []mat.Matrix{x, y}, nil},
+		{"Sub", func(xs []fn.Operand) fn.Function { return fn.NewSub(xs[0], xs[1]) }, []mat.Matrix{x, y}, nil},
+		{"Prod", func(xs []fn.Operand) fn.Function { return fn.NewProd(xs[0], xs[1]) }, []mat.Matrix{x, y}, nil},
+		{"Div", func(xs []fn.Operand) fn.Function { return fn.NewDiv(xs[0], xs[1]) }, []mat.Matrix{x, y}, nil},
+		{"Dot", func(xs []fn.Operand) fn.Function { return fn.NewDot(xs[0], xs[1]) }, []mat.Matrix{x, y}, nil},
+		{"Mul", func(xs []fn.Operand) fn.Function { return fn.NewMul(xs[0], xs[1]) }, []mat.Matrix{m, n}, nil},
+		{"MulVec", func(xs []fn.Operand) fn.Function { return fn.NewMul(xs[0], xs[1]) }, []mat.Matrix{n, mat.NewVecDense([]mat.Float{0.3, -0.1})}, nil},
+		{"ProdScalar", func(xs []fn.Operand) fn.Function { return fn.NewProdScalar(xs[0], xs[1]) }, []mat.Matrix{x, alpha}, nil},
+		{"Tanh", func(xs []fn.Operand) fn.Function { return fn.NewTanh(xs[0]) }, []mat.Matrix{x}, nil},
+		{"Sigmoid", func(xs []fn.Operand) fn.Function { return fn.NewSigmoid(xs[0]) }, []mat.Matrix{x}, nil},
+		{"GELU", func(xs []fn.Operand) fn.Function { return fn.NewGELU(xs[0]) }, []mat.Matrix{x}, nil},
+		{"Exp", func(xs []fn.Operand) fn.Function { return fn.NewExp(xs[0]) }, []mat.Matrix{x}, nil},
+		{"Softmax", func(xs []fn.Operand) fn.Function { return fn.NewSoftmax(xs[0]) }, []mat.Matrix{x}, nil},
+		{"SoftmaxCols", func(xs []fn.Operand) fn.Function { return fn.NewSoftmaxCols(xs[0]) }, []mat.Matrix{m}, nil},
+		{"ELU", func(xs []fn.Operand) fn.Function { return fn.NewELU(xs[0], xs[1]) }, []mat.Matrix{x, alpha}, []Option{NoGrad(1)}},
+		{"ReduceSum", func(xs []fn.Operand) fn.Function { return fn.NewReduceSum(xs[0]) }, []mat.Matrix{m}, nil},
+		{"ReduceMean", func(xs []fn.Operand) fn.Function { return fn.NewReduceMean(xs[0]) }, []mat.Matrix{m}, nil},
+		{"Transpose", func(xs []fn.Operand) fn.Function { return fn.NewTranspose(xs[0]) }, []mat.Matrix{m}, nil},
+		{"Stack", func(xs []fn.Operand) fn.Function { return fn.NewStack(xs) }, []mat.Matrix{x, y}, nil},
+		{"Concat", func(xs []fn.Operand) fn.Function { return fn.NewConcat(xs) }, []mat.Matrix{x, y}, nil},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			results := Function(tc.newFunc, tc.values, tc.opts...)
+			assert.NotEmpty(t, results)
+			for _, r := range results {
+				assert.Less(t, float64(r.MaxRelError), maxRelErrorTolerance, r.String())
+			}
+		})
+	}
+}
+
+func TestFunction_DoesNotModifyValues(t *testing.T) {
+	x := mat.NewVecDense([]mat.Float{0.1, -0.3, 0.6})
+	Function(func(xs []fn.Operand) fn.Function { return fn.NewTanh(xs[0]) }, []mat.Matrix{x})
+	assert.Equal(t, []mat.Float{0.1, -0.3, 0.6}, x.Data())
+}
+
+func TestFunction_DetectsWrongGradients(t *testing.T) {
+	x := mat.NewVecDense([]mat.Float{0.1, -0.3, 0.6})
+	results := Function(func(xs []fn.Operand) fn.Function {
+		return &wrongSquare{x: xs[0]}
+	}, []mat.Matrix{x})
+	assert.Len(t, results, 1)
+	assert.Greater(t, float64(results.MaxRelError()), 0.1)
+}
+
+func TestFunction_NoGrad(t *testing.T) {
+	x := mat.NewVecDense([]mat.Float{0.1, -0.3, 0.6})
+	results := Function(func(xs []fn.Operand) fn.Function {
+		return fn.NewProdScalar(xs[0], xs[1])
+	}, []mat.Matrix{x, mat.NewScalar(2.0)}, NoGrad(1))
+	assert.Len(t, results, 1)
+	assert.Equal(t, "operand 0", results[0].Name)
+}
+
+// wrongSquare computes x^2, but its backward step is deliberately wrong.
+type wrongSquare struct {
+	x fn.Operand
+}
+
+func (r *wrongSquare) Forward() mat.Matrix {
+	return r.x.Value().Prod(r.x.Value())
+}
+
+func (r *wrongSquare) Backward(gy mat.Matrix) {
+	r.x.PropagateGrad(gy.Prod(r.x.Value())) // the correct gradient is 2x
+}
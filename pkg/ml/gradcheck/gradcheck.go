@@ -0,0 +1,162 @@
+// Copyright 2021 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package gradcheck verifies the analytic gradients computed by the automatic differentiation
+// against numerical gradients estimated with central finite differences.
+//
+// Keep in mind that spaGO works in single precision by default: the perturbation and the
+// tolerances used in the tests must be chosen accordingly (e.g. an Epsilon of 1e-3 and a
+// maximum relative error of 1e-2 are usually adequate).
+package gradcheck
+
+import (
+	"fmt"
+	mat "github.com/nlpodyssey/spago/pkg/mat32"
+	"github.com/nlpodyssey/spago/pkg/mat32/rand"
+	"math"
+)
+
+const (
+	defaultEpsilon = 1e-3
+	defaultSeed    = 42
+)
+
+// Result reports the outcome of the gradient check of a single operand or param.
+type Result struct {
+	// Name identifies the operand (by its index) or the param (by its name).
+	Name string
+	// Analytic is the gradient computed by the automatic differentiation.
+	Analytic mat.Matrix
+	// Numeric is the gradient estimated with the finite differences.
+	Numeric mat.Matrix
+	// MaxRelError is the maximum relative error among all the elements of the gradient.
+	MaxRelError mat.Float
+}
+
+// String returns a human-readable representation of the result.
+func (r Result) String() string {
+	return fmt.Sprintf("%s: max relative error %g", r.Name, r.MaxRelError)
+}
+
+// Results is a list of Result.
+type Results []Result
+
+// MaxRelError returns the maximum relative error among all the results.
+func (rs Results) MaxRelError() mat.Float {
+	max := mat.Float(0.0)
+	for _, r := range rs {
+		if r.MaxRelError > max {
+			max = r.MaxRelError
+		}
+	}
+	return max
+}
+
+// Option allows to configure a gradient check with your specific needs.
+type Option func(*checker)
+
+// Epsilon sets the perturbation applied to each element to estimate the numerical gradients.
+func Epsilon(value mat.Float) Option {
+	return func(c *checker) {
+		c.epsilon = value
+	}
+}
+
+// Seed sets the seed of the random generator used to project the output of a function onto a scalar.
+func Seed(value uint64) Option {
+	return func(c *checker) {
+		c.seed = value
+	}
+}
+
+// NoGrad excludes the operands at the given indices from the check.
+// It is useful for operands that are not meant to be differentiated (e.g. indices, or scalar
+// parameters of the function). It has no effect on the check of a model.
+func NoGrad(indices ...int) Option {
+	return func(c *checker) {
+		for _, i := range indices {
+			c.noGrad[i] = true
+		}
+	}
+}
+
+type checker struct {
+	epsilon mat.Float
+	seed    uint64
+	noGrad  map[int]bool
+}
+
+func newChecker(opts ...Option) *checker {
+	c := &checker{
+		epsilon: defaultEpsilon,
+		seed:    defaultSeed,
+		noGrad:  make(map[int]bool),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// numericGrad estimates the gradient of the loss w.r.t. each element of x, perturbing it in place.
+// The original values of x are restored before returning.
+func (c *checker) numericGrad(x mat.Matrix, loss func() float64) mat.Matrix {
+	rows, cols := x.Dims()
+	gx := mat.NewEmptyDense(rows, cols)
+	for i := 0; i < rows; i++ {
+		for j := 0; j < cols; j++ {
+			orig := x.At(i, j)
+			x.Set(i, j, orig+c.epsilon)
+			lPlus := loss()
+			x.Set(i, j, orig-c.epsilon)
+			lMinus := loss()
+			x.Set(i, j, orig)
+			gx.Set(i, j, mat.Float((lPlus-lMinus)/(2.0*float64(c.epsilon))))
+		}
+	}
+	return gx
+}
+
+// projection returns a matrix with the same dimensions of y filled with random values in [-1, 1].
+// The dot product of the output with this matrix makes a scalar loss whose gradient w.r.t. the
+// output is non-trivial (unlike, for instance, the plain sum of the output of a softmax).
+func (c *checker) projection(y mat.Matrix) mat.Matrix {
+	rows, cols := y.Dims()
+	r := mat.NewEmptyDense(rows, cols)
+	generator := rand.NewLockedRand(c.seed)
+	for i := range r.Data() {
+		r.Data()[i] = mat.Float(generator.Float())*2.0 - 1.0
+	}
+	return r
+}
+
+// dot returns the dot product of two matrices with the same dimensions, accumulated in double precision.
+func dot(a, b mat.Matrix) float64 {
+	sum := 0.0
+	rows, cols := a.Dims()
+	for i := 0; i < rows; i++ {
+		for j := 0; j < cols; j++ {
+			sum += float64(a.At(i, j)) * float64(b.At(i, j))
+		}
+	}
+	return sum
+}
+
+// maxRelError returns the maximum relative error between the analytic and the numeric gradients.
+// The error of each element is |a - n| / max(1, |a|, |n|), so that it turns into an absolute
+// error for gradients smaller than one.
+func maxRelError(analytic, numeric mat.Matrix) mat.Float {
+	max := 0.0
+	rows, cols := analytic.Dims()
+	for i := 0; i < rows; i++ {
+		for j := 0; j < cols; j++ {
+			a, n := float64(analytic.At(i, j)), float64(numeric.At(i, j))
+			err := math.Abs(a-n) / math.Max(1.0, math.Max(math.Abs(a), math.Abs(n)))
+			if err > max {
+				max = err
+			}
+		}
+	}
+	return mat.Float(max)
+}
@@ -0,0 +1,72 @@
+// Copyright 2021 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gradcheck
+
+import (
+	mat "github.com/nlpodyssey/spago/pkg/mat32"
+	"github.com/nlpodyssey/spago/pkg/ml/ag"
+	"github.com/nlpodyssey/spago/pkg/ml/nn"
+)
+
+// LossFunc computes a scalar loss on the graph g using the given processor, which is
+// the model under check reified on g in training mode.
+// The loss must be deterministic: the gradient check is unreliable in the presence of
+// random operations such as dropout.
+type LossFunc func(g *ag.Graph, processor nn.Model) ag.Node
+
+// Model checks the gradients of the loss w.r.t. each param of the model that requires gradients.
+// The results are sorted in the order of traversal of the params (see nn.ForEachParam).
+//
+// The analytic gradients are the ones accumulated by the backward step, while the numerical
+// gradients are estimated perturbing each element of the params with central finite differences.
+// The gradients of the params are reset both before and after the check; the values of the params
+// are restored.
+func Model(model nn.Model, lossFn LossFunc, opts ...Option) Results {
+	c := newChecker(opts...)
+
+	loss := func(backward bool) float64 {
+		g := ag.NewGraph()
+		defer g.Clear()
+		processor := nn.Reify(nn.Context{Graph: g, Mode: nn.Training}, model)
+		y := lossFn(g, processor)
+		if !y.Value().IsScalar() {
+			panic("gradcheck: the loss must be a scalar")
+		}
+		if backward {
+			g.Backward(y)
+		}
+		return float64(y.Value().Scalar())
+	}
+
+	nn.ZeroGrad(model)
+	defer nn.ZeroGrad(model)
+	loss(true)
+
+	var params []nn.Param
+	var analytics []mat.Matrix
+	nn.ForEachParam(model, func(param nn.Param) {
+		if !param.RequiresGrad() {
+			return
+		}
+		params = append(params, param)
+		if param.HasGrad() {
+			analytics = append(analytics, param.Grad().Clone())
+		} else {
+			analytics = append(analytics, param.Value().ZerosLike())
+		}
+	})
+
+	results := make(Results, len(params))
+	for i, param := range params {
+		numeric := c.numericGrad(param.Value(), func() float64 { return loss(false) })
+		results[i] = Result{
+			Name:        param.Name(),
+			Analytic:    analytics[i],
+			Numeric:     numeric,
+			MaxRelError: maxRelError(analytics[i], numeric),
+		}
+	}
+	return results
+}
@@ -0,0 +1,33 @@
+// Copyright 2021 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gradcheck
+
+import (
+	mat "github.com/nlpodyssey/spago/pkg/mat32"
+	"github.com/nlpodyssey/spago/pkg/ml/ag"
+	"github.com/nlpodyssey/spago/pkg/ml/nn"
+	"github.com/nlpodyssey/spago/pkg/ml/nn/linear"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestModel(t *testing.T) {
+	model := linear.New(3, 2)
+	model.W.Value().SetData([]mat.Float{0.5, -0.6, 0.3, 0.7, -0.4, 0.1})
+	model.B.Value().SetData([]mat.Float{0.4, -0.2})
+
+	results := Model(model, func(g *ag.Graph, processor nn.Model) ag.Node {
+		x := g.NewVariable(mat.NewVecDense([]mat.Float{-0.8, -0.9, 0.9}), false)
+		y := processor.(*linear.Model).Forward(x)[0]
+		return g.ReduceSum(g.Square(g.Tanh(y)))
+	})
+
+	assert.Len(t, results, 2)
+	assert.Equal(t, "w", results[0].Name)
+	assert.Equal(t, "b", results[1].Name)
+	assert.Less(t, float64(results.MaxRelError()), maxRelErrorTolerance)
+	assert.Equal(t, []mat.Float{0.5, -0.6, 0.3, 0.7, -0.4, 0.1}, model.W.Value().Data())
+	assert.False(t, model.W.HasGrad())
+}
@@ -40,6 +40,24 @@ func CrossEntropy(g *ag.Graph, x ag.Node, c int) ag.Node {
 	return g.Add(g.Neg(g.AtVec(x, c)), g.Log(g.ReduceSum(g.Exp(x))))
 }
 
+// LabelSmoothedCrossEntropy implements a cross-entropy loss function with label smoothing, where the target
+// distribution assigns 1-epsilon to the gold class, plus epsilon spread evenly over all the classes.
+// x is the raw scores for each class (logits).
+// c is the index of the gold class.
+// epsilon is the smoothing factor (0 ≤ epsilon < 1); with zero, it is the same as CrossEntropy.
+func LabelSmoothedCrossEntropy(g *ag.Graph, x ag.Node, c int, epsilon mat.Float) ag.Node {
+	logProbs := g.LogSoftmax(x)
+	nll := g.Neg(g.AtVec(logProbs, c))
+	if epsilon == 0 {
+		return nll
+	}
+	smoothLoss := g.Neg(g.ReduceMean(logProbs))
+	return g.Add(
+		g.ProdScalar(nll, g.NewScalar(1.0-epsilon)),
+		g.ProdScalar(smoothLoss, g.NewScalar(epsilon)),
+	)
+}
+
 // WeightedCrossEntropy implements a weighted cross-entropy loss function.
 // x is the raw scores for each class (logits).
 // c is the index of the gold class.
@@ -49,6 +49,22 @@ func TestCrossEntropyLoss(t *testing.T) {
 	assert.InDeltaSlice(t, []mat.Float{0.0, 0.1, -0.8, 0.7}, x.Grad().Data(), 1.0e-6)
 }
 
+func TestLabelSmoothedCrossEntropyLoss(t *testing.T) {
+	g := ag.NewGraph()
+	x := g.NewVariable(mat.NewVecDense([]mat.Float{0, 0.693147, 1.098612, 1.386294}), true)
+	loss := LabelSmoothedCrossEntropy(g, x, 2, 0.1)
+
+	assertEqualApprox(t, 1.234383, loss.Value().Scalar())
+
+	g.Backward(loss)
+
+	assert.InDeltaSlice(t, []mat.Float{0.075, 0.175, -0.625, 0.375}, x.Grad().Data(), 1.0e-6)
+
+	g2 := ag.NewGraph()
+	x2 := g2.NewVariable(mat.NewVecDense([]mat.Float{-500, 0, 0.693147, 1.94591}), true)
+	assertEqualApprox(t, 1.609438, LabelSmoothedCrossEntropy(g2, x2, 2, 0).Value().Scalar())
+}
+
 func TestWeightedCrossEntropyLoss(t *testing.T) {
 	g := ag.NewGraph()
 	x := g.NewVariable(mat.NewVecDense([]mat.Float{-500, 0, 0.693147, 1.94591}), true)
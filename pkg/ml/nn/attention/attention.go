@@ -7,6 +7,7 @@ package attention
 import (
 	mat "github.com/nlpodyssey/spago/pkg/mat32"
 	"github.com/nlpodyssey/spago/pkg/ml/ag"
+	"github.com/nlpodyssey/spago/pkg/ml/nn"
 	"sync"
 )
 
@@ -70,6 +71,62 @@ func ScaledDotProductAttention(g *ag.Graph, qkv QKV, scaleFactor mat.Float, useC
 	return
 }
 
+// ScaledDotProductAttentionBatch does the same thing as ScaledDotProductAttention but processes a minibatch
+// of sequences at once. Each query, key and value holds the vectors of all the sequences at the same position,
+// stacked as the columns of a matrix (see nn.PaddingMask).
+// The optional mask excludes the padding keys from the attention; the output at padding positions is meaningless.
+// The attention scores of the i-th query are returned as a matrix with one row for each key and one column for
+// each sequence of the minibatch.
+func ScaledDotProductAttentionBatch(g *ag.Graph, qkv QKV, scaleFactor mat.Float, useCausalMask bool, mask nn.PaddingMask) (context []ag.Node, prob []mat.Matrix) {
+	context = make([]ag.Node, len(qkv.Queries))
+	prob = make([]mat.Matrix, len(qkv.Queries))
+	factor := g.NewScalar(scaleFactor)
+	useCausalMask = useCausalMask && len(qkv.Queries) > 1
+
+	for i, q := range qkv.Queries {
+		scores := make([]ag.Node, len(qkv.Keys))
+		for j, k := range qkv.Keys {
+			scores[j] = g.ReduceSumCols(g.Prod(q, k))
+		}
+		attScores := g.ProdScalar(g.Stack(scores...), factor)
+
+		if mask != nil || useCausalMask {
+			attMask := g.NewVariable(makeBatchMask(i, len(qkv.Keys), useCausalMask, mask), false)
+			if mask == nil {
+				attMask = g.BroadcastColsLike(attMask, attScores) // the same causal mask for all the sequences
+			}
+			attScores = g.Add(attScores, attMask)
+		}
+
+		attProb := g.SoftmaxCols(attScores)
+		for j, v := range qkv.Values {
+			weights := g.BroadcastRowsLike(g.RowView(attProb, j), v)
+			context[i] = g.Add(context[i], g.Prod(v, weights))
+		}
+		prob[i] = attProb.Value()
+	}
+	return
+}
+
+// makeBatchMask returns a matrix with one row for each key and one column for each sequence of the mask
+// (or a single column, without mask), filled with zeros except for the keys excluded from the attention
+// of the query at curIndex, which are set to -inf.
+func makeBatchMask(curIndex, seqLength int, useCausalMask bool, mask nn.PaddingMask) mat.Matrix {
+	batchSize := 1
+	if mask != nil {
+		batchSize = len(mask[0])
+	}
+	out := mat.NewEmptyDense(seqLength, batchSize)
+	for k := 0; k < seqLength; k++ {
+		for j := 0; j < batchSize; j++ {
+			if (useCausalMask && k > curIndex) || (mask != nil && !mask[k][j]) {
+				out.Set(k, j, mat.Inf(-1))
+			}
+		}
+	}
+	return out
+}
+
 // MakeCausalMask returns a slice of size seqLength filled with zeros until curIndex, and the rest with -inf.
 func MakeCausalMask(curIndex, seqLength int) []mat.Float {
 	causalMask := make([]mat.Float, seqLength)
@@ -114,3 +114,32 @@ func (m *Model) forward(qkv attention.QKV, pastProjKeysValues KeysValuesPairs) O
 		ProjKeysValues: attProjKeysValues,
 	}
 }
+
+// ForwardBatch performs the forward step for a minibatch of sequences and returns the result.
+// Each input node holds the vectors of all the sequences at the same position, stacked as the
+// columns of a matrix. The optional mask marks the padding elements, which are not attended.
+func (m *Model) ForwardBatch(qkv attention.QKV, mask nn.PaddingMask) Output {
+	g := m.Graph()
+	headsAttNodes := make([][]ag.Node, m.NumOfHeads)
+	headsAttWeights := make([][]mat.Matrix, m.NumOfHeads)
+	attProjKeysValues := make(KeysValuesPairs, m.NumOfHeads)
+	for h, proc := range m.Attention {
+		out := proc.ForwardBatch(qkv, mask)
+		headsAttNodes[h] = out.AttOutput
+		headsAttWeights[h] = out.AttWeights
+		attProjKeysValues[h] = out.ProjKeysValues
+	}
+	concatHeads := make([]ag.Node, len(qkv.Queries))
+	for i := 0; i < len(concatHeads); i++ {
+		buf := make([]ag.Node, m.NumOfHeads)
+		for j := 0; j < m.NumOfHeads; j++ {
+			buf[j] = headsAttNodes[j][i]
+		}
+		concatHeads[i] = g.ConcatRows(buf...)
+	}
+	return Output{
+		AttOutput:      m.OutputMerge.ForwardBatch(concatHeads...),
+		AttWeights:     headsAttWeights,
+		ProjKeysValues: attProjKeysValues,
+	}
+}
@@ -0,0 +1,38 @@
+// Copyright 2021 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package multiheadattention
+
+import (
+	mat "github.com/nlpodyssey/spago/pkg/mat32"
+	"github.com/nlpodyssey/spago/pkg/mat32/rand"
+	"github.com/nlpodyssey/spago/pkg/ml/ag"
+	"github.com/nlpodyssey/spago/pkg/ml/gradcheck"
+	"github.com/nlpodyssey/spago/pkg/ml/initializers"
+	"github.com/nlpodyssey/spago/pkg/ml/nn"
+	"github.com/nlpodyssey/spago/pkg/ml/nn/attention"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestModel_Gradients(t *testing.T) {
+	model := New(4, 2, true)
+	rndGen := rand.NewLockedRand(42)
+	nn.ForEachParam(model, func(param nn.Param) {
+		initializers.Uniform(param.Value(), -0.5, 0.5, rndGen)
+	})
+
+	results := gradcheck.Model(model, func(g *ag.Graph, processor nn.Model) ag.Node {
+		xs := []ag.Node{
+			g.NewVariable(mat.NewVecDense([]mat.Float{0.3, -0.5, 0.8, 0.1}), false),
+			g.NewVariable(mat.NewVecDense([]mat.Float{-0.2, 0.4, 0.6, -0.9}), false),
+			g.NewVariable(mat.NewVecDense([]mat.Float{0.7, 0.2, -0.4, 0.5}), false),
+		}
+		ys := processor.(*Model).Forward(attention.ToQKV(xs)).AttOutput
+		return g.ReduceSum(g.Square(g.Concat(ys...)))
+	})
+	for _, r := range results {
+		assert.Less(t, float64(r.MaxRelError), 1.0e-02, r.String())
+	}
+}
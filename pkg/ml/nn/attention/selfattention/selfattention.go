@@ -95,3 +95,23 @@ func (m *Model) ForwardWithPastKeysValues(qkv attention.QKV, past attention.Keys
 		},
 	}
 }
+
+// ForwardBatch performs the forward step for a minibatch of sequences and returns the result.
+// Each input node holds the vectors of all the sequences at the same position, stacked as the
+// columns of a matrix. The optional mask marks the padding elements, which are not attended.
+func (m *Model) ForwardBatch(qkv attention.QKV, mask nn.PaddingMask) attention.Output {
+	projAtt := attention.QKV{
+		Queries: m.Query.ForwardBatch(qkv.Queries...),
+		Keys:    m.Key.ForwardBatch(qkv.Keys...),
+		Values:  m.Value.ForwardBatch(qkv.Values...),
+	}
+	attOutput, attWeights := attention.ScaledDotProductAttentionBatch(m.Graph(), projAtt, m.ScaleFactor, m.UseCausalMask, mask)
+	return attention.Output{
+		AttOutput:  attOutput,
+		AttWeights: attWeights,
+		ProjKeysValues: attention.KeysValuesPair{
+			Keys:   projAtt.Keys,
+			Values: projAtt.Values,
+		},
+	}
+}
@@ -92,3 +92,89 @@ func newTestModel() *Model {
 	model.Query.B.Value().SetData([]mat.Float{0.3, 0.5, -0.7})
 	return model
 }
+
+func TestModel_ForwardBatch(t *testing.T) {
+	seqs := [][][]mat.Float{
+		{{-0.8, -0.9, -0.9, 1.0}, {0.8, -0.3, 0.5, 0.3}, {-0.2, 0.7, 0.2, 0.4}},
+		{{0.1, 0.3, -0.4, 0.2}, {-0.5, 0.6, 0.1, -0.9}},
+	}
+
+	// == Expected results, processing one sequence at a time
+
+	model := newTestModel()
+	expected := make([][]mat.Matrix, len(seqs))
+	expectedQueryGrad := model.Query.W.Value().ZerosLike()
+	for j, seq := range seqs {
+		g := ag.NewGraph()
+		proc := nn.Reify(nn.Context{Graph: g, Mode: nn.Training}, model).(*Model)
+		xs := make([]ag.Node, len(seq))
+		for i, x := range seq {
+			xs[i] = g.NewVariable(mat.NewVecDense(x), true)
+		}
+		ys := proc.Forward(attention.ToQKV(xs)).AttOutput
+		for _, y := range ys {
+			expected[j] = append(expected[j], y.Value().Clone())
+		}
+		g.Backward(g.ReduceSum(g.Sum(ys...)))
+		expectedQueryGrad.AddInPlace(model.Query.W.Grad())
+		nn.ZeroGrad(model)
+	}
+
+	// == Batch
+
+	g := ag.NewGraph()
+	proc := nn.Reify(nn.Context{Graph: g, Mode: nn.Training}, model).(*Model)
+	mask := nn.NewPaddingMask(len(seqs[0]), len(seqs[1]))
+	xs := make([]ag.Node, len(mask))
+	for i := range xs {
+		cols := make([]ag.Node, len(seqs))
+		for j, seq := range seqs {
+			if i < len(seq) {
+				cols[j] = g.NewVariable(mat.NewVecDense(seq[i]), true)
+			} else {
+				cols[j] = g.NewVariable(mat.NewEmptyVecDense(4), false) // padding
+			}
+		}
+		xs[i] = g.StackCols(cols...)
+	}
+	ys := proc.ForwardBatch(attention.ToQKV(xs), mask).AttOutput
+
+	for j, seq := range seqs {
+		for i := range seq {
+			assert.InDeltaSlice(t, expected[j][i].Data(), ys[i].Value().(*mat.Dense).ExtractColumn(j).Data(), 1.0e-06)
+		}
+	}
+
+	// the padding outputs are excluded from the loss
+	keep := make([]ag.Node, len(ys))
+	for i, y := range ys {
+		keep[i] = g.Prod(y, g.NewVariable(mask.Matrix(i, y.Value().Rows(), 1.0, 0.0), false))
+	}
+	g.Backward(g.ReduceSum(g.Sum(keep...)))
+	assert.InDeltaSlice(t, expectedQueryGrad.Data(), model.Query.W.Grad().Data(), 1.0e-06)
+}
+
+func TestModel_ForwardBatch_LazyGraph(t *testing.T) {
+	model := newTestModel()
+	seqs := [][]mat.Float{
+		{-0.8, -0.9, -0.9, 1.0}, {0.8, -0.3, 0.5, 0.3}, {-0.2, 0.7, 0.2, 0.4},
+	}
+	forward := func(g *ag.Graph) []ag.Node {
+		proc := nn.Reify(nn.Context{Graph: g, Mode: nn.Inference}, model).(*Model)
+		xs := make([]ag.Node, len(seqs))
+		for i, x := range seqs {
+			// a minibatch of the same sequence repeated twice, built by operators
+			v := g.NewVariable(mat.NewVecDense(x), false)
+			xs[i] = g.StackCols(v, v)
+		}
+		return proc.ForwardBatch(attention.ToQKV(xs), nil).AttOutput
+	}
+	expected := forward(ag.NewGraph())
+
+	g := ag.NewGraph(ag.IncrementalForward(false))
+	ys := forward(g)
+	g.Forward()
+	for i, y := range ys {
+		assert.InDeltaSlice(t, expected[i].Value().Data(), y.Value().Data(), 1.0e-06)
+	}
+}
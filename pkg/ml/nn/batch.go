@@ -0,0 +1,67 @@
+// Copyright 2021 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package nn
+
+import (
+	mat "github.com/nlpodyssey/spago/pkg/mat32"
+	"github.com/nlpodyssey/spago/pkg/ml/ag"
+)
+
+// A minibatch of vectors is represented by a single node whose value is a matrix, where each
+// column is a vector of the batch. A minibatch of sequences is therefore a slice of such nodes,
+// one for each position, where shorter sequences are padded up to the length of the longest one.
+
+// PaddingMask marks the real elements of a padded minibatch of sequences.
+// It is indexed by position first and then by example: mask[t][j] is true if the
+// j-th sequence has a real element at position t, false if it is padding.
+type PaddingMask [][]bool
+
+// NewPaddingMask returns the PaddingMask of a minibatch of sequences with the given lengths,
+// padded up to the longest one.
+func NewPaddingMask(lengths ...int) PaddingMask {
+	maxLen := 0
+	for _, l := range lengths {
+		if l > maxLen {
+			maxLen = l
+		}
+	}
+	mask := make(PaddingMask, maxLen)
+	for t := range mask {
+		mask[t] = make([]bool, len(lengths))
+		for j, l := range lengths {
+			mask[t][j] = t < l
+		}
+	}
+	return mask
+}
+
+// Matrix returns the mask at position t as a new matrix with the given number of rows and one column
+// for each example, filled with value for the real elements and with pad for the padding.
+func (m PaddingMask) Matrix(t, rows int, value, pad mat.Float) *mat.Dense {
+	out := mat.NewEmptyDense(rows, len(m[t]))
+	for j, isReal := range m[t] {
+		v := pad
+		if isReal {
+			v = value
+		}
+		for i := 0; i < rows; i++ {
+			out.Set(i, j, v)
+		}
+	}
+	return out
+}
+
+// AffineBatch performs the same transformation as Affine over a minibatch of vectors, stacked
+// as the columns of each "x". The bias is a vector, which is added to each column of the output.
+// y = b + W1x1 + W2x2 + ... + WnXn
+func AffineBatch(g *ag.Graph, xs ...ag.Node) ag.Node {
+	if len(xs)%2 == 0 {
+		panic("nn: the number of arguments of the affine transformation should be odd")
+	}
+	ys := make([]ag.Node, len(xs))
+	copy(ys, xs)
+	ys[0] = g.BroadcastColsLike(xs[0], xs[2])
+	return Affine(g, ys...)
+}
@@ -0,0 +1,28 @@
+// Copyright 2021 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package nn
+
+import (
+	mat "github.com/nlpodyssey/spago/pkg/mat32"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestNewPaddingMask(t *testing.T) {
+	mask := NewPaddingMask(3, 1, 2)
+	assert.Equal(t, PaddingMask{
+		{true, true, true},
+		{true, false, true},
+		{true, false, false},
+	}, mask)
+}
+
+func TestPaddingMask_Matrix(t *testing.T) {
+	mask := NewPaddingMask(3, 1, 2)
+	m := mask.Matrix(1, 2, 1.0, -1.0)
+	assert.Equal(t, 2, m.Rows())
+	assert.Equal(t, 3, m.Columns())
+	assert.Equal(t, []mat.Float{1.0, -1.0, 1.0, 1.0, -1.0, 1.0}, m.Data())
+}
@@ -7,6 +7,7 @@ package crf
 import (
 	mat "github.com/nlpodyssey/spago/pkg/mat32"
 	"github.com/nlpodyssey/spago/pkg/ml/ag"
+	"github.com/nlpodyssey/spago/pkg/ml/gradcheck"
 	"github.com/nlpodyssey/spago/pkg/ml/nn"
 	"github.com/stretchr/testify/assert"
 	"testing"
@@ -96,3 +97,15 @@ func newTestModel() *Model {
 	})
 	return model
 }
+
+func TestModel_Gradients(t *testing.T) {
+	model := newTestModel()
+	results := gradcheck.Model(model, func(g *ag.Graph, processor nn.Model) ag.Node {
+		w1 := g.NewVariable(mat.NewVecDense([]mat.Float{1.7, 0.2, -0.3, 0.5}), false)
+		w2 := g.NewVariable(mat.NewVecDense([]mat.Float{2.0, -3.5, 0.1, 2.0}), false)
+		w3 := g.NewVariable(mat.NewVecDense([]mat.Float{-2.5, 3.2, -0.2, -0.3}), false)
+		return processor.(*Model).NegativeLogLoss([]ag.Node{w1, w2, w3}, []int{0, 2, 1})
+	})
+	assert.Len(t, results, 1)
+	assert.Less(t, float64(results.MaxRelError()), 1.0e-02, results[0].String())
+}
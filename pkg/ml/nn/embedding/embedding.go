@@ -0,0 +1,62 @@
+// Copyright 2021 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package embedding
+
+import (
+	"encoding/gob"
+	mat "github.com/nlpodyssey/spago/pkg/mat32"
+	"github.com/nlpodyssey/spago/pkg/ml/ag"
+	"github.com/nlpodyssey/spago/pkg/ml/nn"
+)
+
+var (
+	_ nn.Model = &Model{}
+)
+
+// Model implements a lookup table of embeddings, stored as the rows of a single weights matrix.
+//
+// Unlike keeping a separate param for each embedding, the lookup of an embedding only propagates
+// row-sparse gradients (see mat.RowSparse) to the weights, so that the optimizers supporting them
+// (see gd.RowSparseMethod) update only the rows actually used. This allows to train large vocabularies
+// with a memory and time overhead proportional to the number of used embeddings.
+type Model struct {
+	nn.BaseModel
+	W nn.Param `spago:"type:weights"`
+	// UsedEmbeddings contains the nodes of the embeddings already looked up by the processor.
+	UsedEmbeddings map[int]ag.Node `spago:"scope:processor"`
+}
+
+func init() {
+	gob.Register(&Model{})
+}
+
+// New returns a new model with a table of size embeddings of the given dimension, initialized to zeros.
+func New(size, dim int) *Model {
+	return &Model{
+		W: nn.NewParam(mat.NewEmptyDense(size, dim)),
+	}
+}
+
+// InitProcessor initializes the cache of the used embeddings.
+func (m *Model) InitProcessor() {
+	m.UsedEmbeddings = make(map[int]ag.Node)
+}
+
+// Encode returns the embeddings (column vectors) associated with the input ids.
+// The same node is returned for the repeated ids.
+// It panics if an id is out of the range of the table.
+func (m *Model) Encode(ids []int) []ag.Node {
+	g := m.Graph()
+	ys := make([]ag.Node, len(ids))
+	for i, id := range ids {
+		if embedding, ok := m.UsedEmbeddings[id]; ok {
+			ys[i] = embedding
+			continue
+		}
+		ys[i] = g.T(g.RowView(m.W, id))
+		m.UsedEmbeddings[id] = ys[i]
+	}
+	return ys
+}
@@ -0,0 +1,68 @@
+// Copyright 2021 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package embedding
+
+import (
+	mat "github.com/nlpodyssey/spago/pkg/mat32"
+	"github.com/nlpodyssey/spago/pkg/ml/ag"
+	"github.com/nlpodyssey/spago/pkg/ml/nn"
+	"github.com/nlpodyssey/spago/pkg/ml/optimizers/gd"
+	"github.com/nlpodyssey/spago/pkg/ml/optimizers/gd/sgd"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func newTestModel() *Model {
+	model := New(4, 2)
+	model.W.Value().SetData([]mat.Float{
+		0.1, 0.2,
+		0.3, 0.4,
+		0.5, 0.6,
+		0.7, 0.8,
+	})
+	return model
+}
+
+func TestModel_Encode(t *testing.T) {
+	model := newTestModel()
+	g := ag.NewGraph()
+	proc := nn.Reify(nn.Context{Graph: g, Mode: nn.Training}, model).(*Model)
+
+	ys := proc.Encode([]int{2, 0, 2})
+	assert.Len(t, ys, 3)
+	assert.Same(t, ys[0], ys[2])
+	assert.Equal(t, []mat.Float{0.5, 0.6}, ys[0].Value().Data())
+	assert.Equal(t, []mat.Float{0.1, 0.2}, ys[1].Value().Data())
+	assert.Equal(t, 2, ys[0].Value().Rows())
+
+	g.Backward(g.ReduceSum(g.Add(g.Add(ys[0], ys[1]), ys[2])))
+
+	grad, ok := model.W.Grad().(*mat.RowSparse)
+	if !assert.True(t, ok) {
+		return
+	}
+	assert.ElementsMatch(t, []int{0, 2}, grad.RowIndices())
+	assert.Equal(t, []mat.Float{2.0, 2.0}, grad.Row(2))
+	assert.Equal(t, []mat.Float{1.0, 1.0}, grad.Row(0))
+}
+
+func TestModel_SparseUpdate(t *testing.T) {
+	model := newTestModel()
+	optimizer := gd.NewOptimizer(sgd.New(sgd.NewConfig(0.1, 0.9, false)), nn.NewDefaultParamsIterator(model))
+
+	g := ag.NewGraph()
+	proc := nn.Reify(nn.Context{Graph: g, Mode: nn.Training}, model).(*Model)
+	ys := proc.Encode([]int{1})
+	g.Backward(g.ReduceSum(ys[0]))
+	optimizer.Optimize()
+
+	assert.InDeltaSlice(t, []mat.Float{
+		0.1, 0.2,
+		0.2, 0.3,
+		0.5, 0.6,
+		0.7, 0.8,
+	}, model.W.Value().Data(), 1.0e-6)
+	assert.False(t, model.W.HasGrad())
+}
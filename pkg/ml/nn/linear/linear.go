@@ -22,6 +22,11 @@ type Model struct {
 	nn.BaseModel
 	W nn.Param `spago:"type:weights"`
 	B nn.Param `spago:"type:biases"`
+	// Quantized, if not nil, contains the int8 quantization of the weights, which replaces W (see Quantize).
+	Quantized *mat.QuantizedDense
+	// Observer, if not nil, is called with the value of each input during the forward step
+	// (e.g. to calibrate the quantization). It is not serialized.
+	Observer func(x mat.Matrix)
 }
 
 // Option allows to configure a new Model with your specific needs.
@@ -82,5 +87,46 @@ func (m *Model) fwdConcurrent(xs []ag.Node) []ag.Node {
 
 // y = w (dot) x + b
 func (m *Model) forward(x ag.Node) ag.Node {
+	m.observe(x)
+	if m.Quantized != nil {
+		g := m.Graph()
+		return g.Add(g.QuantizedMul(m.Quantized, x), m.B)
+	}
 	return nn.Affine(m.Graph(), m.B, m.W, x)
 }
+
+// ForwardBatch performs the forward step for each input node and returns the result.
+// Each input is a minibatch of vectors stacked as the columns of a matrix, which
+// are transformed at once.
+func (m *Model) ForwardBatch(xs ...ag.Node) []ag.Node {
+	ys := make([]ag.Node, len(xs))
+	for i, x := range xs {
+		m.observe(x)
+		if m.Quantized != nil {
+			g := m.Graph()
+			ys[i] = g.Add(g.QuantizedMul(m.Quantized, x), g.BroadcastColsLike(m.B, x))
+			continue
+		}
+		ys[i] = nn.AffineBatch(m.Graph(), m.B, m.W, x)
+	}
+	return ys
+}
+
+func (m *Model) observe(x ag.Node) {
+	if m.Observer != nil && x.Value() != nil {
+		m.Observer(x.Value())
+	}
+}
+
+// Quantize replaces the weights W with their int8 quantization, to speed up the inference and to reduce
+// the size of the model. The ranges set the value mapped to 127 for each row of W (see mat.NewQuantizedDense);
+// if nil, the maximum absolute value of each row is used.
+// The weights W are emptied, so the model can no longer be trained, except for the biases.
+// It does nothing if the model is already quantized.
+func (m *Model) Quantize(ranges []mat.Float) {
+	if m.Quantized != nil {
+		return
+	}
+	m.Quantized = mat.NewQuantizedDense(m.W.Value(), ranges)
+	m.W.ReplaceValue(mat.NewEmptyDense(0, 0))
+}
@@ -5,12 +5,15 @@
 package linear
 
 import (
+	"bytes"
+	"encoding/gob"
 	mat "github.com/nlpodyssey/spago/pkg/mat32"
 	"github.com/nlpodyssey/spago/pkg/ml/ag"
 	"github.com/nlpodyssey/spago/pkg/ml/losses"
 	"github.com/nlpodyssey/spago/pkg/ml/nn"
 	"github.com/nlpodyssey/spago/pkg/ml/nn/activation"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"testing"
 )
 
@@ -63,3 +66,97 @@ func newTestModel() *Model {
 	model.B.Value().SetData([]mat.Float{0.4, 0.0, -0.3, 0.8, -0.4})
 	return model
 }
+
+func TestModel_ForwardBatch(t *testing.T) {
+	xs := [][]mat.Float{
+		{-0.8, -0.9, -0.9, 1.0},
+		{0.3, 0.1, -0.2, 0.5},
+	}
+	gys := [][]mat.Float{
+		{1.0, 0.5, -0.5, 0.0, 0.2},
+		{0.0, -1.0, 0.3, 0.4, 0.1},
+	}
+
+	// == Expected results, processing one vector at a time
+
+	model := newTestModel()
+	g := ag.NewGraph()
+	proc := nn.Reify(nn.Context{Graph: g, Mode: nn.Training}, model).(*Model)
+	ys := make([]ag.Node, len(xs))
+	for i, x := range xs {
+		ys[i] = proc.Forward(g.NewVariable(mat.NewVecDense(x), true))[0]
+		ys[i].PropagateGrad(mat.NewVecDense(gys[i]))
+	}
+	g.BackwardAll()
+	expectedWGrad := model.W.Grad().Clone()
+	expectedBGrad := model.B.Grad().Clone()
+	nn.ZeroGrad(model)
+
+	// == Batch
+
+	g = ag.NewGraph()
+	proc = nn.Reify(nn.Context{Graph: g, Mode: nn.Training}, model).(*Model)
+	x := g.StackCols(g.NewVariable(mat.NewVecDense(xs[0]), true), g.NewVariable(mat.NewVecDense(xs[1]), true))
+	y := proc.ForwardBatch(x)[0]
+
+	assert.Equal(t, 5, y.Value().Rows())
+	assert.Equal(t, 2, y.Value().Columns())
+	for j := range xs {
+		assert.InDeltaSlice(t, ys[j].Value().Data(), y.Value().(*mat.Dense).ExtractColumn(j).Data(), 1.0e-06)
+	}
+
+	y.PropagateGrad(mat.Stack(mat.NewVecDense(gys[0]), mat.NewVecDense(gys[1])).T())
+	g.BackwardAll()
+
+	assert.InDeltaSlice(t, expectedWGrad.Data(), model.W.Grad().Data(), 1.0e-06)
+	assert.InDeltaSlice(t, expectedBGrad.Data(), model.B.Grad().Data(), 1.0e-06)
+}
+
+func TestModel_Quantize(t *testing.T) {
+	x := mat.NewVecDense([]mat.Float{-0.8, -0.9, -0.9, 1.0})
+	xs := mat.NewDense(4, 2, []mat.Float{
+		-0.8, 0.3,
+		-0.9, 0.1,
+		-0.9, -0.2,
+		1.0, 0.5,
+	})
+
+	forward := func(model *Model) (y, ys mat.Matrix) {
+		g := ag.NewGraph()
+		proc := nn.Reify(nn.Context{Graph: g, Mode: nn.Inference}, model).(*Model)
+		y = proc.Forward(g.NewVariable(x, false))[0].Value()
+		ys = proc.ForwardBatch(g.NewVariable(xs, false))[0].Value()
+		return
+	}
+
+	expectedY, expectedYs := forward(newTestModel())
+
+	model := newTestModel()
+	var observed []mat.Matrix
+	model.Observer = func(x mat.Matrix) {
+		observed = append(observed, x)
+	}
+	model.Quantize(nil)
+	assert.NotNil(t, model.Quantized)
+	assert.Equal(t, 0, model.W.Value().Size())
+
+	y, ys := forward(model)
+	assert.InDeltaSlice(t, expectedY.Data(), y.Data(), 1.0e-2)
+	assert.InDeltaSlice(t, expectedYs.Data(), ys.Data(), 1.0e-2)
+	assert.Equal(t, []mat.Matrix{x, xs}, observed)
+}
+
+func TestModel_QuantizedSerialization(t *testing.T) {
+	model := newTestModel()
+	model.Quantize(nil)
+
+	var buf bytes.Buffer
+	require.Nil(t, gob.NewEncoder(&buf).Encode(model))
+
+	decoded := New(4, 5)
+	require.Nil(t, gob.NewDecoder(&buf).Decode(decoded))
+	require.NotNil(t, decoded.Quantized)
+	assert.Equal(t, model.Quantized.Dense().Data(), decoded.Quantized.Dense().Data())
+	assert.Equal(t, 0, decoded.W.Value().Size())
+	assert.Equal(t, model.B.Value().Data(), decoded.B.Value().Data())
+}
@@ -5,8 +5,11 @@
 package nn
 
 import (
+	"fmt"
 	mat "github.com/nlpodyssey/spago/pkg/mat32"
 	"github.com/nlpodyssey/spago/pkg/ml/ag"
+	"github.com/nlpodyssey/spago/pkg/utils"
+	"reflect"
 )
 
 // ProcessingMode regulates the different usage of some operations (e.g. Dropout, BatchNorm, etc.),
@@ -129,12 +132,46 @@ func LoadParamsVector(model Model, vector mat.Matrix) {
 	data := vector.Data()
 	offset := 0
 	ForEachParam(model, func(param Param) {
-		size := param.Value().Size()
-		param.Value().SetData(data[offset : offset+size])
+		value := param.Value()
+		size := value.Size()
+		value.SetData(data[offset : offset+size])
+		if param.Precision() != mat.FullPrecision {
+			param.ReplaceValue(value) // the value is a copy
+		}
 		offset += size
 	})
 }
 
+// SetPrecision converts the storage of the values of all model's parameters (including sub-params)
+// to the given floating-point format (see Param.SetPrecision).
+func SetPrecision(m Model, precision mat.Precision) {
+	ForEachParam(m, func(param Param) {
+		param.SetPrecision(precision)
+	})
+}
+
+// WithPrecision returns a utils.DecodingHook which converts the storage of the params of the decoded model
+// to the given floating-point format. For example:
+//
+//     err := utils.DeserializeFromFile(filename, &model, nn.WithPrecision(mat.Float16))
+//
+// Keep in mind that the model is fully decoded before the conversion.
+func WithPrecision(precision mat.Precision) utils.DecodingHook {
+	return func(obj interface{}) error {
+		m, ok := obj.(Model)
+		if !ok {
+			if v := reflect.ValueOf(obj); v.Kind() == reflect.Ptr && !v.IsNil() {
+				m, ok = v.Elem().Interface().(Model)
+			}
+		}
+		if !ok || m == nil {
+			return fmt.Errorf("nn: cannot set the precision of %T, which is not a Model", obj)
+		}
+		SetPrecision(m, precision)
+		return nil
+	}
+}
+
 // MakeNewModels return n new models.
 // The callback is delegated to return a new model for each i-item.
 func MakeNewModels(n int, callback func(i int) Model) []Model {
@@ -0,0 +1,660 @@
+// Copyright 2021 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package nn
+
+import (
+	"bufio"
+	"bytes"
+	"encoding"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	mat "github.com/nlpodyssey/spago/pkg/mat32"
+	"github.com/nlpodyssey/spago/pkg/utils"
+	"io"
+	"math"
+	"os"
+	"reflect"
+	"sort"
+	"strconv"
+)
+
+// The spaGO model file format is a versioned container for the params of a model, which doesn't depend on the
+// Go types of the model (unlike the gob encoding) and can be inspected with any language. It is made of:
+//
+//     magic      8 bytes  "SPAGOMDL"
+//     version    uint32   little-endian, currently 1
+//     reserved   uint32   zero
+//     indexSize  uint64   little-endian size of the index
+//     index      JSON     {"config": <any JSON>, "tensors": [<TensorInfo>, ...]}
+//     padding    zeros up to a multiple of 64 bytes
+//     data       the tensors, each starting at a multiple of 64 bytes
+//
+// The tensors are named by the traversal path of the corresponding field of the model, e.g.
+// "Encoder.Layers.0.FFN.Layers.0.W": the exported fields are separated by dots (the embedded fields are omitted),
+// as well as the indices of slices and arrays and the keys of maps.
+//
+// The values are stored in little-endian order, row after row. The data types are:
+//
+//     F32   float32 values (the params in full precision)
+//     F16   IEEE 754 half-precision values (see mat.Float16)
+//     BF16  brain floating-point values (see mat.BFloat16)
+//     Q8    the float32 scale of each row, followed by the int8 values (see mat.QuantizedDense)
+//     BIN   the opaque binary encoding (encoding.BinaryMarshaler) of a value other than a matrix, e.g. a vocabulary
+//
+// The data of each tensor is aligned, so that the file can be memory-mapped and read in place.
+const (
+	// ModelFileVersion is the current version of the spaGO model file format.
+	ModelFileVersion = 1
+	// modelFileMagic identifies a file in the spaGO model format.
+	modelFileMagic = "SPAGOMDL"
+	// modelFileHeaderSize is the size of the fixed-size header (magic, version, reserved and index size).
+	modelFileHeaderSize = 24
+	// modelFileAlignment is the alignment of the index end and of the data of each tensor.
+	modelFileAlignment = 64
+)
+
+// Data types of the tensors of the spaGO model file format.
+const (
+	DTypeF32  = "F32"
+	DTypeF16  = "F16"
+	DTypeBF16 = "BF16"
+	DTypeQ8   = "Q8"
+	DTypeBin  = "BIN"
+)
+
+// TensorInfo describes a tensor of a spaGO model file.
+type TensorInfo struct {
+	// Name is the traversal path of the tensor in the model.
+	Name string `json:"name"`
+	// DType is the data type of the values.
+	DType string `json:"dtype"`
+	// Shape contains the number of rows and columns of a matrix (empty for DTypeBin).
+	Shape []int `json:"shape,omitempty"`
+	// Offset is the position of the data, relative to the beginning of the data section.
+	Offset int64 `json:"offset"`
+	// Size is the size of the data in bytes.
+	Size int64 `json:"size"`
+}
+
+type modelFileIndex struct {
+	Config  json.RawMessage `json:"config,omitempty"`
+	Tensors []TensorInfo    `json:"tensors"`
+}
+
+var (
+	paramType           = reflect.TypeOf((*Param)(nil)).Elem()
+	quantizedDenseType  = reflect.TypeOf((*mat.QuantizedDense)(nil))
+	binaryMarshalerType = reflect.TypeOf((*encoding.BinaryMarshaler)(nil)).Elem()
+)
+
+// SaveModelFile writes the model to file in the spaGO model format, along with the given configuration,
+// which can be any value encodable to JSON (or nil).
+//
+// The file contains the values of the params, the quantized matrices and the other values of the model
+// which implement encoding.BinaryMarshaler (those with an empty encoding, such as the handles of external
+// storages, are skipped). Everything else, including the support structures of the params (see Payload),
+// is not saved: the model to load must be built with the same configuration.
+func SaveModelFile(filename string, m Model, config interface{}) (err error) {
+	f, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if e := f.Close(); e != nil && err == nil {
+			err = e
+		}
+	}()
+	w := bufio.NewWriter(f)
+	if err := WriteModelFile(w, m, config); err != nil {
+		return err
+	}
+	return w.Flush()
+}
+
+// WriteModelFile writes the model to w in the spaGO model format (see SaveModelFile).
+func WriteModelFile(w io.Writer, m Model, config interface{}) error {
+	index := modelFileIndex{Tensors: make([]TensorInfo, 0)}
+	if config != nil {
+		data, err := json.Marshal(config)
+		if err != nil {
+			return err
+		}
+		index.Config = data
+	}
+
+	var writers []func(w io.Writer) error
+	var offset int64
+	var err error
+	forEachModelFileEntry(m, func(name string, v reflect.Value) {
+		if err != nil || (v.Kind() == reflect.Ptr && v.IsNil()) {
+			return
+		}
+		var info TensorInfo
+		var write func(w io.Writer) error
+		info, write, err = newTensorWriter(name, v.Interface())
+		if err != nil || write == nil {
+			return
+		}
+		info.Offset = offset
+		offset = alignModelFile(offset + info.Size)
+		index.Tensors = append(index.Tensors, info)
+		writers = append(writers, write)
+	})
+	if err != nil {
+		return err
+	}
+
+	indexData, err := json.Marshal(index)
+	if err != nil {
+		return err
+	}
+	header := make([]byte, modelFileHeaderSize)
+	copy(header, modelFileMagic)
+	binary.LittleEndian.PutUint32(header[8:], ModelFileVersion)
+	binary.LittleEndian.PutUint64(header[16:], uint64(len(indexData)))
+	cw := &countingWriter{w: w}
+	if _, err := cw.Write(header); err != nil {
+		return err
+	}
+	if _, err := cw.Write(indexData); err != nil {
+		return err
+	}
+	if err := cw.pad(); err != nil {
+		return err
+	}
+	dataStart := cw.n
+	for i, write := range writers {
+		if err := write(cw); err != nil {
+			return err
+		}
+		if cw.n-dataStart != index.Tensors[i].Offset+index.Tensors[i].Size {
+			return fmt.Errorf("nn: unexpected size of tensor %q", index.Tensors[i].Name)
+		}
+		if err := cw.pad(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// newTensorWriter returns the description of the tensor corresponding to the value, and the function to write
+// its data. The function is nil if the value must not be saved.
+func newTensorWriter(name string, value interface{}) (TensorInfo, func(w io.Writer) error, error) {
+	info := TensorInfo{Name: name}
+	switch v := value.(type) {
+	case Param:
+		p, ok := v.(*param)
+		if !ok {
+			return info, nil, fmt.Errorf("nn: unsupported Param implementation %T", v)
+		}
+		p.mu.Lock()
+		value, half := p.value, p.half
+		if half != nil {
+			half = p.halfValue()
+		}
+		p.mu.Unlock()
+		if half != nil {
+			data, err := half.MarshalBinary()
+			if err != nil {
+				return info, nil, err
+			}
+			rows, cols := half.Dims()
+			info.DType, info.Shape, info.Size = DTypeF16, []int{rows, cols}, int64(len(data)-9)
+			if half.Precision() == mat.BFloat16 {
+				info.DType = DTypeBF16
+			}
+			return info, writeBytes(data[9:]), nil // skip the header (dims and precision)
+		}
+		rows, cols := value.Dims()
+		info.DType, info.Shape, info.Size = DTypeF32, []int{rows, cols}, int64(rows*cols*4)
+		return info, func(w io.Writer) error {
+			return binary.Write(w, binary.LittleEndian, toFloat32s(value.Data()))
+		}, nil
+	case *mat.QuantizedDense:
+		data, err := v.MarshalBinary()
+		if err != nil {
+			return info, nil, err
+		}
+		rows, cols := v.Dims()
+		info.DType, info.Shape, info.Size = DTypeQ8, []int{rows, cols}, int64(len(data)-8)
+		return info, writeBytes(data[8:]), nil // skip the header (dims)
+	case encoding.BinaryMarshaler:
+		data, err := v.MarshalBinary()
+		if err != nil || len(data) == 0 {
+			return info, nil, err
+		}
+		info.DType, info.Size = DTypeBin, int64(len(data))
+		return info, writeBytes(data), nil
+	default:
+		return info, nil, fmt.Errorf("nn: unsupported value %T for %q", value, name)
+	}
+}
+
+func writeBytes(data []byte) func(w io.Writer) error {
+	return func(w io.Writer) error {
+		_, err := w.Write(data)
+		return err
+	}
+}
+
+func toFloat32s(data []mat.Float) []float32 {
+	out := make([]float32, len(data))
+	for i, v := range data {
+		out[i] = float32(v)
+	}
+	return out
+}
+
+func alignModelFile(n int64) int64 {
+	return (n + modelFileAlignment - 1) / modelFileAlignment * modelFileAlignment
+}
+
+// countingWriter counts the bytes written, to align the data.
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
+
+func (c *countingWriter) pad() error {
+	_, err := c.Write(make([]byte, alignModelFile(c.n)-c.n))
+	return err
+}
+
+// ModelFile is a file in the spaGO model format, opened for reading.
+// The file is memory-mapped where supported, so that only the tensors actually read are loaded in memory.
+type ModelFile struct {
+	version uint32
+	index   modelFileIndex
+	tensors map[string]TensorInfo
+	data    []byte // the data section
+	close   func() error
+}
+
+// IsModelFile reports whether the file is in the spaGO model format (rather than, for example, a gob encoding).
+func IsModelFile(filename string) (bool, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return false, err
+	}
+	defer f.Close()
+	magic := make([]byte, len(modelFileMagic))
+	if _, err := io.ReadFull(f, magic); err != nil {
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			return false, nil
+		}
+		return false, err
+	}
+	return string(magic) == modelFileMagic, nil
+}
+
+// OpenModelFile opens a file in the spaGO model format. The file must be closed with Close.
+func OpenModelFile(filename string) (*ModelFile, error) {
+	data, closeFn, err := mapFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	mf, err := newModelFile(data)
+	if err != nil {
+		_ = closeFn()
+		return nil, fmt.Errorf("nn: invalid model file %q: %w", filename, err)
+	}
+	mf.close = closeFn
+	return mf, nil
+}
+
+func newModelFile(data []byte) (*ModelFile, error) {
+	if len(data) < modelFileHeaderSize || string(data[:len(modelFileMagic)]) != modelFileMagic {
+		return nil, errors.New("not a spaGO model file")
+	}
+	mf := &ModelFile{
+		version: binary.LittleEndian.Uint32(data[8:]),
+		tensors: make(map[string]TensorInfo),
+	}
+	if mf.version > ModelFileVersion {
+		return nil, fmt.Errorf("unsupported version %d", mf.version)
+	}
+	indexEnd := modelFileHeaderSize + int64(binary.LittleEndian.Uint64(data[16:]))
+	if indexEnd > int64(len(data)) {
+		return nil, errors.New("truncated index")
+	}
+	if err := json.Unmarshal(data[modelFileHeaderSize:indexEnd], &mf.index); err != nil {
+		return nil, err
+	}
+	dataStart := alignModelFile(indexEnd)
+	if dataStart > int64(len(data)) {
+		dataStart = int64(len(data))
+	}
+	mf.data = data[dataStart:]
+	for _, t := range mf.index.Tensors {
+		if t.Offset < 0 || t.Size < 0 || t.Offset+t.Size > int64(len(mf.data)) {
+			return nil, fmt.Errorf("tensor %q out of range", t.Name)
+		}
+		mf.tensors[t.Name] = t
+	}
+	return mf, nil
+}
+
+// Version returns the version of the format of the file.
+func (mf *ModelFile) Version() int {
+	return int(mf.version)
+}
+
+// Config returns the JSON configuration saved along with the model (nil if there is none).
+func (mf *ModelFile) Config() json.RawMessage {
+	return mf.index.Config
+}
+
+// Tensors returns the description of the tensors of the file, in order.
+func (mf *ModelFile) Tensors() []TensorInfo {
+	return mf.index.Tensors
+}
+
+// TensorData returns the raw data of the tensor with the given name, and whether it exists.
+// The data refers to the (memory-mapped) file, and it is valid until Close is called.
+func (mf *ModelFile) TensorData(name string) ([]byte, bool) {
+	t, ok := mf.tensors[name]
+	if !ok {
+		return nil, false
+	}
+	return mf.data[t.Offset : t.Offset+t.Size], true
+}
+
+// Close closes the file.
+func (mf *ModelFile) Close() error {
+	if mf.close == nil {
+		return nil
+	}
+	err := mf.close()
+	mf.close, mf.data = nil, nil
+	return err
+}
+
+// Load loads the tensors of the file into the model, matching them with the fields of the model by their
+// traversal path. The values are copied, so the model remains valid after Close.
+// It returns an error if a param of the model has no corresponding tensor, or if a tensor has no
+// corresponding field in the model.
+func (mf *ModelFile) Load(m Model) error {
+	used := make(map[string]bool)
+	var err error
+	forEachModelFileEntry(m, func(name string, v reflect.Value) {
+		if err != nil {
+			return
+		}
+		t, ok := mf.tensors[name]
+		if !ok {
+			if v.Type().Implements(paramType) && !v.IsNil() {
+				err = fmt.Errorf("nn: param %q not found in the model file", name)
+			}
+			return // the other values are left unchanged
+		}
+		used[name] = true
+		err = mf.loadTensor(t, v)
+	})
+	if err != nil {
+		return err
+	}
+	for _, t := range mf.index.Tensors {
+		if !used[t.Name] {
+			return fmt.Errorf("nn: tensor %q of the model file not found in the model", t.Name)
+		}
+	}
+	return nil
+}
+
+// loadTensor sets the value v with the data of the tensor.
+func (mf *ModelFile) loadTensor(t TensorInfo, v reflect.Value) error {
+	data := mf.data[t.Offset : t.Offset+t.Size]
+	switch {
+	case v.Type().Implements(paramType):
+		p, ok := v.Interface().(*param)
+		if !ok || p == nil {
+			return fmt.Errorf("nn: unsupported param %q", t.Name)
+		}
+		return p.loadTensor(t, data)
+	case v.Type() == quantizedDenseType:
+		if len(t.Shape) != 2 || t.DType != DTypeQ8 {
+			return fmt.Errorf("nn: invalid tensor %q", t.Name)
+		}
+		q := new(mat.QuantizedDense)
+		if err := q.UnmarshalBinary(append(dimsHeader(t.Shape), data...)); err != nil {
+			return err
+		}
+		return setPointer(v, reflect.ValueOf(q), t.Name)
+	default:
+		if t.DType != DTypeBin {
+			return fmt.Errorf("nn: invalid tensor %q", t.Name)
+		}
+		target := v
+		if v.Kind() == reflect.Ptr && v.IsNil() {
+			target = reflect.New(v.Type().Elem())
+		}
+		u, ok := target.Interface().(encoding.BinaryUnmarshaler)
+		if !ok {
+			return fmt.Errorf("nn: the value of %q can't be unmarshaled", t.Name)
+		}
+		if err := u.UnmarshalBinary(append([]byte(nil), data...)); err != nil {
+			return err
+		}
+		if target != v {
+			return setPointer(v, target, t.Name)
+		}
+		return nil
+	}
+}
+
+func setPointer(v, value reflect.Value, name string) error {
+	if !v.CanSet() {
+		return fmt.Errorf("nn: the field %q can't be set", name)
+	}
+	v.Set(value)
+	return nil
+}
+
+// dimsHeader returns the binary encoding of the dimensions, as expected by the binary unmarshaling of
+// the matrices.
+func dimsHeader(shape []int) []byte {
+	header := make([]byte, 8)
+	binary.LittleEndian.PutUint32(header, uint32(shape[0]))
+	binary.LittleEndian.PutUint32(header[4:], uint32(shape[1]))
+	return header
+}
+
+// loadTensor sets the value of the param with the data of the tensor, keeping its precision.
+func (r *param) loadTensor(t TensorInfo, data []byte) error {
+	if len(t.Shape) != 2 {
+		return fmt.Errorf("nn: invalid shape of tensor %q", t.Name)
+	}
+	rows, cols := t.Shape[0], t.Shape[1]
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	switch t.DType {
+	case DTypeF32:
+		if len(data) != rows*cols*4 {
+			return fmt.Errorf("nn: invalid size of tensor %q", t.Name)
+		}
+		values := make([]mat.Float, rows*cols)
+		for i := range values {
+			values[i] = mat.Float(math.Float32frombits(binary.LittleEndian.Uint32(data[i*4:])))
+		}
+		r.value, r.half, r.master = mat.NewDense(rows, cols, values), nil, false
+	case DTypeF16, DTypeBF16:
+		precision := mat.Float16
+		if t.DType == DTypeBF16 {
+			precision = mat.BFloat16
+		}
+		half := new(mat.HalfDense)
+		if err := half.UnmarshalBinary(append(append(dimsHeader(t.Shape), byte(precision)), data...)); err != nil {
+			return err
+		}
+		r.value, r.half, r.master = nil, half, false
+	default:
+		return fmt.Errorf("nn: invalid data type %s of param %q", t.DType, t.Name)
+	}
+	if r.storage != nil {
+		r.updateStorage()
+	}
+	return nil
+}
+
+// LoadModelFile loads the params of a model from a file in the spaGO model format (see ModelFile.Load).
+// The model must be built with the same configuration used for saving it.
+func LoadModelFile(filename string, m Model) error {
+	mf, err := OpenModelFile(filename)
+	if err != nil {
+		return err
+	}
+	defer mf.Close()
+	return mf.Load(m)
+}
+
+// LoadFromFile loads the params of a model from a file either in the spaGO model format (see LoadModelFile)
+// or gob-encoded (see utils.DeserializeFromFile), detecting the format from its content.
+// The optional hooks (e.g. WithPrecision) are called in order on the loaded model, whatever the format.
+func LoadFromFile(filename string, m Model, hooks ...utils.DecodingHook) error {
+	isModelFile, err := IsModelFile(filename)
+	if err != nil {
+		return err
+	}
+	if !isModelFile {
+		return utils.DeserializeFromFile(filename, m, hooks...)
+	}
+	if err := LoadModelFile(filename, m); err != nil {
+		return err
+	}
+	for _, hook := range hooks {
+		if err := hook(m); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// forEachModelFileEntry calls the callback for each value of the model stored in the model file, with its
+// traversal path: the params, the quantized matrices (even if nil) and the other values implementing
+// encoding.BinaryMarshaler (even if nil).
+// The values shared by different fields are visited once; the fields of the processor scope are skipped.
+func forEachModelFileEntry(m Model, callback func(name string, v reflect.Value)) {
+	walkModelFileEntries(reflect.ValueOf(m), "", make(map[uintptr]bool), callback)
+}
+
+func walkModelFileEntries(v reflect.Value, path string, visited map[uintptr]bool, callback func(string, reflect.Value)) {
+	switch v.Kind() {
+	case reflect.Interface:
+		if !v.IsNil() {
+			walkModelFileEntries(v.Elem(), path, visited, callback)
+		}
+		return
+	case reflect.Ptr:
+		if v.IsNil() {
+			if v.Type() == quantizedDenseType || v.Type().Implements(binaryMarshalerType) {
+				callback(path, v)
+			}
+			return
+		}
+		if visited[v.Pointer()] {
+			return
+		}
+		visited[v.Pointer()] = true
+	}
+
+	t := v.Type()
+	switch {
+	case t.Implements(paramType) && v.Kind() == reflect.Ptr:
+		callback(path, v)
+		return
+	case t == quantizedDenseType:
+		callback(path, v)
+		return
+	case t.Implements(binaryMarshalerType):
+		callback(path, v)
+		return
+	case v.Kind() == reflect.Struct && v.CanAddr() && v.Addr().Type().Implements(binaryMarshalerType):
+		callback(path, v.Addr())
+		return
+	}
+
+	switch v.Kind() {
+	case reflect.Ptr:
+		walkModelFileEntries(v.Elem(), path, visited, callback)
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if field.PkgPath != "" {
+				continue // unexported
+			}
+			tag, err := parseModuleFieldTag(field.Tag.Get("spago"))
+			if err != nil {
+				panic(err)
+			}
+			if tag.Scope == processorModuleFieldScope {
+				continue
+			}
+			name := path
+			if !field.Anonymous {
+				name = joinModelFilePath(path, field.Name)
+			}
+			walkModelFileEntries(v.Field(i), name, visited, callback)
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			walkModelFileEntries(v.Index(i), joinModelFilePath(path, strconv.Itoa(i)), visited, callback)
+		}
+	case reflect.Map:
+		keys := v.MapKeys()
+		names := make(map[string]reflect.Value, len(keys))
+		sorted := make([]string, 0, len(keys))
+		for _, key := range keys {
+			var name string
+			switch key.Kind() {
+			case reflect.String:
+				name = key.String()
+			case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+				name = strconv.FormatInt(key.Int(), 10)
+			default:
+				return // skip map if the key is not a string or an int
+			}
+			names[name] = key
+			sorted = append(sorted, name)
+		}
+		sort.Strings(sorted)
+		for _, name := range sorted {
+			// the values of a map are not addressable: the callback gets a copy, which is written back
+			key := names[name]
+			value := reflect.New(t.Elem()).Elem()
+			value.Set(v.MapIndex(key))
+			walkModelFileEntries(value, joinModelFilePath(path, name), visited, callback)
+			v.SetMapIndex(key, value)
+		}
+	}
+}
+
+func joinModelFilePath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+// mapFileByReading reads the whole file in memory; it is used where memory-mapping is not supported.
+func mapFileByReading(filename string) ([]byte, func() error, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer f.Close()
+	buf := new(bytes.Buffer)
+	if _, err := buf.ReadFrom(f); err != nil {
+		return nil, nil, err
+	}
+	return buf.Bytes(), func() error { return nil }, nil
+}
@@ -0,0 +1,33 @@
+// Copyright 2021 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// +build linux darwin freebsd netbsd openbsd
+
+package nn
+
+import (
+	"os"
+	"syscall"
+)
+
+// mapFile memory-maps the file in read-only mode, returning its content and the function to unmap it.
+func mapFile(filename string) ([]byte, func() error, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return nil, nil, err
+	}
+	if info.Size() == 0 {
+		return []byte{}, func() error { return nil }, nil
+	}
+	data, err := syscall.Mmap(int(f.Fd()), 0, int(info.Size()), syscall.PROT_READ, syscall.MAP_SHARED)
+	if err != nil {
+		return mapFileByReading(filename)
+	}
+	return data, func() error { return syscall.Munmap(data) }, nil
+}
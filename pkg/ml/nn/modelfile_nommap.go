@@ -0,0 +1,12 @@
+// Copyright 2021 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// +build !linux,!darwin,!freebsd,!netbsd,!openbsd
+
+package nn
+
+// mapFile reads the whole file in memory, since memory-mapping is not supported on this platform.
+func mapFile(filename string) ([]byte, func() error, error) {
+	return mapFileByReading(filename)
+}
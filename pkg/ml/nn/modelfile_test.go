@@ -0,0 +1,211 @@
+// Copyright 2021 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package nn
+
+import (
+	"bytes"
+	"encoding/gob"
+	mat "github.com/nlpodyssey/spago/pkg/mat32"
+	"github.com/nlpodyssey/spago/pkg/utils"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+type modelFileLabels struct {
+	Items []string
+}
+
+func (l *modelFileLabels) MarshalBinary() ([]byte, error) {
+	return []byte(strings.Join(l.Items, "\n")), nil
+}
+
+func (l *modelFileLabels) UnmarshalBinary(data []byte) error {
+	l.Items = strings.Split(string(data), "\n")
+	return nil
+}
+
+type modelFileLeaf struct {
+	BaseModel
+	W         Param `spago:"type:weights"`
+	Quantized *mat.QuantizedDense
+}
+
+type modelFileRoot struct {
+	BaseModel
+	Layers []*modelFileLeaf
+	Named  map[string]Param
+	Shared Param
+	Tied   Param
+	Labels *modelFileLabels
+	Cache  map[int]Param `spago:"scope:processor"`
+}
+
+func init() {
+	gob.Register(&modelFileRoot{})
+}
+
+func newModelFileRoot() *modelFileRoot {
+	shared := NewParam(mat.NewVecDense([]mat.Float{0, 0}))
+	return &modelFileRoot{
+		Layers: []*modelFileLeaf{
+			{W: NewParam(mat.NewEmptyDense(2, 3))},
+			{W: NewParam(mat.NewEmptyDense(3, 2))},
+		},
+		Named: map[string]Param{
+			"b": NewParam(mat.NewScalar(0)),
+			"a": NewParam(mat.NewScalar(0)),
+		},
+		Shared: shared,
+		Tied:   shared,
+	}
+}
+
+func newTrainedModelFileRoot() *modelFileRoot {
+	m := newModelFileRoot()
+	m.Layers[0].W.Value().SetData([]mat.Float{1, 2, 3, 4, 5, 6})
+	m.Layers[1].W.Value().SetData([]mat.Float{0.5, -0.5, 1.5, -1.5, 2.5, -2.5})
+	m.Layers[1].W.SetPrecision(mat.BFloat16)
+	m.Layers[1].Quantized = mat.NewQuantizedDense(mat.NewDense(1, 2, []mat.Float{1.0, -0.5}), nil)
+	m.Named["a"].Value().SetData([]mat.Float{7})
+	m.Named["b"].Value().SetData([]mat.Float{8})
+	m.Shared.Value().SetData([]mat.Float{9, 10})
+	m.Labels = &modelFileLabels{Items: []string{"x", "y"}}
+	return m
+}
+
+func TestModelFile(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "model.bin")
+	original := newTrainedModelFileRoot()
+	assert.NoError(t, SaveModelFile(filename, original, map[string]int{"size": 2}))
+
+	isModelFile, err := IsModelFile(filename)
+	assert.NoError(t, err)
+	assert.True(t, isModelFile)
+
+	mf, err := OpenModelFile(filename)
+	assert.NoError(t, err)
+	defer mf.Close()
+	assert.Equal(t, ModelFileVersion, mf.Version())
+	assert.JSONEq(t, `{"size": 2}`, string(mf.Config()))
+
+	var names, dtypes []string
+	for _, tensor := range mf.Tensors() {
+		names = append(names, tensor.Name)
+		dtypes = append(dtypes, tensor.DType)
+		assert.Zero(t, tensor.Offset%modelFileAlignment)
+	}
+	assert.Equal(t, []string{"Layers.0.W", "Layers.1.W", "Layers.1.Quantized", "Named.a", "Named.b", "Shared", "Labels"}, names)
+	assert.Equal(t, []string{DTypeF32, DTypeBF16, DTypeQ8, DTypeF32, DTypeF32, DTypeF32, DTypeBin}, dtypes)
+
+	data, ok := mf.TensorData("Named.a")
+	assert.True(t, ok)
+	assert.Len(t, data, 4)
+
+	loaded := newModelFileRoot()
+	assert.NoError(t, mf.Load(loaded))
+	assert.Equal(t, original.Layers[0].W.Value().Data(), loaded.Layers[0].W.Value().Data())
+	assert.Equal(t, mat.BFloat16, loaded.Layers[1].W.Precision())
+	assert.Equal(t, original.Layers[1].W.Value().Data(), loaded.Layers[1].W.Value().Data())
+	assert.Nil(t, loaded.Layers[0].Quantized)
+	assert.Equal(t, original.Layers[1].Quantized, loaded.Layers[1].Quantized)
+	assert.Equal(t, []mat.Float{7}, loaded.Named["a"].Value().Data())
+	assert.Equal(t, []mat.Float{8}, loaded.Named["b"].Value().Data())
+	assert.Equal(t, []mat.Float{9, 10}, loaded.Tied.Value().Data())
+	assert.Equal(t, []string{"x", "y"}, loaded.Labels.Items)
+}
+
+func TestModelFile_Mismatch(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "model.bin")
+	assert.NoError(t, SaveModelFile(filename, newTrainedModelFileRoot(), nil))
+
+	extraParam := newModelFileRoot()
+	extraParam.Named["c"] = NewParam(mat.NewScalar(0))
+	assert.Error(t, LoadModelFile(filename, extraParam))
+
+	missingParam := newModelFileRoot()
+	delete(missingParam.Named, "b")
+	assert.Error(t, LoadModelFile(filename, missingParam))
+}
+
+// modelFileMapRoot holds submodels and values which are not addressable, since they are stored in maps.
+type modelFileMapRoot struct {
+	BaseModel
+	Heads  map[string]modelFileLeaf
+	Labels map[int]*modelFileLabels
+}
+
+func newModelFileMapRoot() *modelFileMapRoot {
+	return &modelFileMapRoot{
+		Heads:  map[string]modelFileLeaf{"x": {W: NewParam(mat.NewEmptyVecDense(2))}},
+		Labels: map[int]*modelFileLabels{0: nil},
+	}
+}
+
+func TestModelFile_Maps(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "model.bin")
+	original := newModelFileMapRoot()
+	original.Heads["x"].W.Value().SetData([]mat.Float{1, 2})
+	head := original.Heads["x"]
+	head.Quantized = mat.NewQuantizedDense(mat.NewDense(1, 2, []mat.Float{1.0, -0.5}), nil)
+	original.Heads["x"] = head
+	original.Labels[0] = &modelFileLabels{Items: []string{"x", "y"}}
+	assert.NoError(t, SaveModelFile(filename, original, nil))
+
+	loaded := newModelFileMapRoot()
+	assert.NoError(t, LoadModelFile(filename, loaded))
+	assert.Equal(t, []mat.Float{1, 2}, loaded.Heads["x"].W.Value().Data())
+	assert.Equal(t, original.Heads["x"].Quantized, loaded.Heads["x"].Quantized)
+	if assert.NotNil(t, loaded.Labels[0]) {
+		assert.Equal(t, []string{"x", "y"}, loaded.Labels[0].Items)
+	}
+}
+
+func TestLoadFromFile(t *testing.T) {
+	dir := t.TempDir()
+	original := newTrainedModelFileRoot()
+
+	modelFile := filepath.Join(dir, "model.bin")
+	assert.NoError(t, SaveModelFile(modelFile, original, nil))
+	gobFile := filepath.Join(dir, "model.gob")
+	assert.NoError(t, utils.SerializeToFile(gobFile, original))
+
+	for _, filename := range []string{modelFile, gobFile} {
+		loaded := newModelFileRoot()
+		assert.NoError(t, LoadFromFile(filename, loaded))
+		assert.Equal(t, original.Layers[0].W.Value().Data(), loaded.Layers[0].W.Value().Data())
+		assert.Equal(t, []mat.Float{9, 10}, loaded.Shared.Value().Data())
+	}
+}
+
+func TestLoadFromFile_WithPrecision(t *testing.T) {
+	dir := t.TempDir()
+	original := newTrainedModelFileRoot()
+
+	modelFile := filepath.Join(dir, "model.bin")
+	assert.NoError(t, SaveModelFile(modelFile, original, nil))
+	gobFile := filepath.Join(dir, "model.gob")
+	assert.NoError(t, utils.SerializeToFile(gobFile, original))
+
+	for _, filename := range []string{modelFile, gobFile} {
+		loaded := newModelFileRoot()
+		assert.NoError(t, LoadFromFile(filename, loaded, WithPrecision(mat.Float16)))
+		assert.Equal(t, mat.Float16, loaded.Layers[0].W.Precision())
+		assert.Equal(t, mat.Float16, loaded.Shared.Precision())
+		assert.InDeltaSlice(t, original.Layers[0].W.Value().Data(), loaded.Layers[0].W.Value().Data(), 1.0e-2)
+	}
+}
+
+func TestOpenModelFile_Invalid(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "model.bin")
+	buf := new(bytes.Buffer)
+	assert.NoError(t, WriteModelFile(buf, newTrainedModelFileRoot(), nil))
+	data := buf.Bytes()[:40] // truncated index
+	assert.NoError(t, ioutil.WriteFile(filename, data, 0644))
+	_, err := OpenModelFile(filename)
+	assert.Error(t, err)
+}
@@ -0,0 +1,43 @@
+// Copyright 2021 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package nn
+
+import (
+	"github.com/nlpodyssey/spago/pkg/utils"
+	"reflect"
+)
+
+// ForEachModel iterates the model and all its sub-models recursively, in depth-first order.
+// The sub-models are searched in the fields of the model, also within slices and maps.
+func ForEachModel(m Model, callback func(m Model)) {
+	if isNil(m) {
+		return
+	}
+	callback(m)
+	utils.ForEachField(m, func(field interface{}, _ string, _ reflect.StructTag) {
+		forEachModelIn(reflect.ValueOf(field), callback)
+	})
+}
+
+func forEachModelIn(v reflect.Value, callback func(m Model)) {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			return
+		}
+		if m, ok := v.Interface().(Model); ok {
+			ForEachModel(m, callback)
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			forEachModelIn(v.Index(i), callback)
+		}
+	case reflect.Map:
+		mapRange := v.MapRange()
+		for mapRange.Next() {
+			forEachModelIn(mapRange.Value(), callback)
+		}
+	}
+}
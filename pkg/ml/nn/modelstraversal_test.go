@@ -0,0 +1,51 @@
+// Copyright 2021 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package nn
+
+import (
+	mat "github.com/nlpodyssey/spago/pkg/mat32"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+type modelsTraversalLeaf struct {
+	BaseModel
+	P Param
+}
+
+type modelsTraversalNode struct {
+	BaseModel
+	A       *modelsTraversalLeaf
+	B       Model
+	C       []Model
+	D       map[string]*modelsTraversalLeaf
+	E       Model
+	private *modelsTraversalLeaf
+}
+
+func TestForEachModel(t *testing.T) {
+	newLeaf := func() *modelsTraversalLeaf {
+		return &modelsTraversalLeaf{P: NewParam(mat.NewScalar(1))}
+	}
+	inner := &modelsTraversalNode{A: newLeaf()}
+	m := &modelsTraversalNode{
+		A:       newLeaf(),
+		B:       inner,
+		C:       []Model{newLeaf(), nil, newLeaf()},
+		D:       map[string]*modelsTraversalLeaf{"x": newLeaf()},
+		private: newLeaf(),
+	}
+
+	var visited []Model
+	ForEachModel(m, func(m Model) {
+		visited = append(visited, m)
+	})
+
+	expected := []Model{m, m.A, inner, inner.A, m.C[0], m.C[2], m.D["x"]}
+	assert.Len(t, visited, len(expected))
+	for i := range expected {
+		assert.Same(t, expected[i], visited[i])
+	}
+}
@@ -18,7 +18,7 @@ var (
 // Model contains the serializable parameters.
 type Model struct {
 	nn.BaseModel
-	W        nn.Param `spago:"type:weights"`
+	W        nn.Param `spago:"type:gains"`
 	B        nn.Param `spago:"type:biases"`
 	Mean     nn.Param `spago:"type:undefined"`
 	StdDev   nn.Param `spago:"type:undefined"`
@@ -22,7 +22,7 @@ var (
 // Model contains the serializable parameters.
 type Model struct {
 	nn.BaseModel
-	W nn.Param `spago:"type:weights"`
+	W nn.Param `spago:"type:gains"`
 	B nn.Param `spago:"type:biases"`
 }
 
@@ -52,3 +52,20 @@ func (m *Model) Forward(xs ...ag.Node) []ag.Node {
 	}
 	return ys
 }
+
+// ForwardBatch performs the forward step for each input node and returns the result.
+// Each input is a minibatch of vectors stacked as the columns of a matrix, which
+// are normalized independently of each other.
+func (m *Model) ForwardBatch(xs ...ag.Node) []ag.Node {
+	g := m.Graph()
+	eps := g.Constant(1e-12) // avoid underflow errors
+	ys := make([]ag.Node, len(xs))
+	for i, x := range xs {
+		mean := g.BroadcastRowsLike(g.ReduceMeanCols(x), x)
+		dev := g.Sub(x, mean)
+		stdDev := g.Sqrt(g.AddScalar(g.ReduceMeanCols(g.Square(dev)), eps))
+		norm := g.Div(dev, g.BroadcastRowsLike(stdDev, x))
+		ys[i] = g.Add(g.Prod(norm, g.BroadcastColsLike(m.W, x)), g.BroadcastColsLike(m.B, x))
+	}
+	return ys
+}
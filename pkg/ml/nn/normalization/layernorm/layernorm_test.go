@@ -32,9 +32,56 @@ func TestModel_Forward(t *testing.T) {
 	assert.InDeltaSlice(t, []mat.Float{-1.0, -0.2, 0.4, 0.6}, model.B.Grad().Data(), 1.0e-06)
 }
 
+func TestModel_ForwardBatch_LazyGraph(t *testing.T) {
+	model := newTestModel()
+	g := ag.NewGraph(ag.IncrementalForward(false))
+	ctx := nn.Context{Graph: g, Mode: nn.Inference}
+
+	x := g.Stack(
+		g.NewVariable(mat.NewVecDense([]mat.Float{0.4, 0.8, -0.7, -0.5}), false),
+		g.NewVariable(mat.NewVecDense([]mat.Float{0.1, 0.3, 0.2, -0.6}), false),
+	)
+	y := nn.Reify(ctx, model).(*Model).ForwardBatch(g.T(x))[0]
+	assert.Nil(t, y.Value())
+
+	g.Forward()
+	assert.InDeltaSlice(t, []mat.Float{1.157863, 0.2, -0.561554, -0.444658},
+		y.Value().(*mat.Dense).ExtractColumn(0).Data(), 1.0e-06)
+}
+
 func newTestModel() *Model {
 	model := New(4)
 	model.W.Value().SetData([]mat.Float{0.4, 0.0, -0.3, 0.8})
 	model.B.Value().SetData([]mat.Float{0.9, 0.2, -0.9, 0.2})
 	return model
 }
+
+func TestModel_ForwardBatch(t *testing.T) {
+	model := newTestModel()
+	g := ag.NewGraph()
+	ctx := nn.Context{Graph: g, Mode: nn.Training}
+
+	// == Forward
+	x := g.NewVariable(mat.Stack(
+		mat.NewVecDense([]mat.Float{0.4, 0.8, -0.7, -0.5}),
+		mat.NewVecDense([]mat.Float{0.1, 0.3, 0.2, -0.6}),
+	).T(), true)
+	y := nn.Reify(ctx, model).(*Model).ForwardBatch(x)[0]
+
+	assert.InDeltaSlice(t, []mat.Float{1.157863, 0.2, -0.561554, -0.444658},
+		y.Value().(*mat.Dense).ExtractColumn(0).Data(), 1.0e-06)
+
+	// == Backward (the gradients of the second column are zeros)
+	y.PropagateGrad(mat.Stack(
+		mat.NewVecDense([]mat.Float{-1.0, -0.2, 0.4, 0.6}),
+		mat.NewVecDense([]mat.Float{0.0, 0.0, 0.0, 0.0}),
+	).T())
+	g.BackwardAll()
+
+	assert.InDeltaSlice(t, []mat.Float{-0.496261, 0.280677, -0.408772, 0.624355},
+		x.Grad().(*mat.Dense).ExtractColumn(0).Data(), 1.0e-06)
+	assert.InDeltaSlice(t, []mat.Float{0.0, 0.0, 0.0, 0.0},
+		x.Grad().(*mat.Dense).ExtractColumn(1).Data(), 1.0e-06)
+	assert.InDeltaSlice(t, []mat.Float{-0.644658, -0.257863, -0.45126, -0.483493}, model.W.Grad().Data(), 1.0e-06)
+	assert.InDeltaSlice(t, []mat.Float{-1.0, -0.2, 0.4, 0.6}, model.B.Grad().Data(), 1.0e-06)
+}
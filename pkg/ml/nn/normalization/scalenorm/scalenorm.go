@@ -18,7 +18,7 @@ var (
 // Model contains the serializable parameters.
 type Model struct {
 	nn.BaseModel
-	Gain nn.Param `spago:"type:weights"`
+	Gain nn.Param `spago:"type:gains"`
 }
 
 func init() {
@@ -29,6 +29,8 @@ type Param interface {
 	SetRequiresGrad(value bool)
 	// ReplaceValue replaces the value of the parameter and clears the support structure.
 	ReplaceValue(value mat.Matrix)
+	// SetData sets the elements of the value, given a raw row-major slice, keeping the support structure.
+	SetData(data []mat.Float)
 	// ApplyDelta updates the value of the underlying storage applying the delta.
 	ApplyDelta(delta mat.Matrix)
 	// Payload returns the optimizer support structure (can be nil).
@@ -38,6 +40,10 @@ type Param interface {
 	SetPayload(payload *Payload)
 	// ClearPayload clears the support structure.
 	ClearPayload()
+	// Precision returns the floating-point format used to store the value.
+	Precision() mat.Precision
+	// SetPrecision converts the storage of the value to the given floating-point format.
+	SetPrecision(precision mat.Precision)
 }
 
 // Params extends a slice of Param with Nodes() method.
@@ -56,11 +62,13 @@ var _ Param = &param{}
 
 type param struct {
 	name         string
-	pType        ParamsType // lazy initialization
-	mu           sync.Mutex // to avoid data race
-	value        mat.Matrix // store the results of a forward evaluation.
-	grad         mat.Matrix // TODO: support of sparse gradients
-	payload      *Payload   // additional data used for example by gradient-descend optimization methods
+	pType        ParamsType     // lazy initialization
+	mu           sync.Mutex     // to avoid data race
+	value        mat.Matrix     // store the results of a forward evaluation.
+	half         *mat.HalfDense // compact storage of the value, if stored in half precision (value is a cache)
+	master       bool           // whether value is the full precision master copy of a half precision param (half is stale)
+	grad         mat.Matrix     // dense or row-sparse (see ag.AccumulateGrad)
+	payload      *Payload       // additional data used for example by gradient-descend optimization methods
 	hasGrad      bool
 	requiresGrad bool
 	storage      *kvdb.KeyValueDB // default nil
@@ -124,26 +132,120 @@ func (r *param) Type() ParamsType {
 }
 
 // Value returns the value of the delegate itself.
+// If the value is stored in half precision, it returns a full precision copy, which is cached until the value
+// changes. The copy is read-only, since the changes made to it are lost: use SetData, ReplaceValue or ApplyDelta
+// to change the value.
 func (r *param) Value() mat.Matrix {
+	if r.half == nil || r.master {
+		return r.value
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.value == nil {
+		r.value = r.half.Dense()
+	}
 	return r.value
 }
 
+// halfValue returns the value of a param stored in half precision, converting the full precision master
+// copy, if any.
+func (r *param) halfValue() *mat.HalfDense {
+	if r.master {
+		return mat.NewHalfDense(r.value, r.half.Precision())
+	}
+	return r.half
+}
+
+// upcast returns the value of a param stored in half precision converted to full precision, and clears
+// the cache. The value is either the cached matrix, which may still be referenced by the callers of Value,
+// or a new matrix (fresh is true), which can be released after use.
+func (r *param) upcast() (value mat.Matrix, fresh bool) {
+	value, r.value = r.value, nil
+	if value == nil {
+		return r.half.Dense(), true
+	}
+	return value, false
+}
+
+// Precision returns the floating-point format used to store the value.
+func (r *param) Precision() mat.Precision {
+	if r.half != nil {
+		return r.half.Precision()
+	}
+	return mat.FullPrecision
+}
+
+// SetPrecision converts the storage of the value to the given floating-point format.
+// Storing the value in half precision (mat.Float16 or mat.BFloat16) halves the memory footprint of the param:
+// the value is upcast to full precision for the computations, once for each graph on which the param is reified.
+// The conversion to half precision is lossy.
+func (r *param) SetPrecision(precision mat.Precision) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if precision == r.Precision() {
+		return
+	}
+	switch {
+	case precision == mat.FullPrecision:
+		r.value, _ = r.upcast()
+		r.half, r.master = nil, false
+	case r.master:
+		r.half = mat.NewHalfDense(r.value, precision)
+	case r.half != nil:
+		value, fresh := r.upcast()
+		if fresh {
+			defer mat.ReleaseMatrix(value)
+		}
+		r.half = mat.NewHalfDense(value, precision)
+	default:
+		r.value, r.half = nil, mat.NewHalfDense(r.value, precision)
+	}
+	if r.storage != nil {
+		r.updateStorage()
+	}
+}
+
 // ReplaceValue replaces the value of the parameter and clears the support structure.
+// The value is stored with the current precision of the param.
 func (r *param) ReplaceValue(value mat.Matrix) {
 	r.mu.Lock()
 	defer r.mu.Unlock()
-	r.value = value
+	if r.half != nil {
+		r.value, r.half, r.master = nil, mat.NewHalfDense(value, r.half.Precision()), false
+	} else {
+		r.value = value
+	}
 	r.payload = nil
 	if r.storage != nil {
 		r.updateStorage()
 	}
 }
 
+// SetData sets the elements of the value, given a raw row-major slice, keeping the support structure.
+// The value is stored with the current precision of the param.
+func (r *param) SetData(data []mat.Float) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.half != nil && !r.master {
+		value, fresh := r.upcast()
+		if fresh {
+			defer mat.ReleaseMatrix(value)
+		}
+		value.SetData(data)
+		r.half = mat.NewHalfDense(value, r.half.Precision())
+	} else {
+		r.value.SetData(data)
+	}
+	if r.storage != nil {
+		r.updateStorage()
+	}
+}
+
 // ScalarValue returns the the scalar value of the node.
 // It panics if the value is not a scalar.
 // Note that it is not possible to start the backward step from a scalar value.
 func (r *param) ScalarValue() mat.Float {
-	return r.value.Scalar()
+	return r.Value().Scalar()
 }
 
 // Grad returns the gradients accumulated during the backward pass.
@@ -158,10 +260,8 @@ func (r *param) PropagateGrad(grad mat.Matrix) {
 	}
 	r.mu.Lock()
 	defer r.mu.Unlock()
-	if r.grad == nil {
-		r.grad = mat.GetEmptyDenseWorkspace(r.value.Dims()) // this could reduce the number of allocations
-	}
-	r.grad.AddInPlace(grad)
+	rows, cols := r.dims()
+	r.grad = ag.AccumulateGrad(r.grad, grad, rows, cols)
 	r.hasGrad = true
 }
 
@@ -193,10 +293,18 @@ func (r *param) ZeroGrad() {
 }
 
 // ApplyDelta updates the value of the underlying storage applying the delta.
+// The first update of a param stored in half precision makes a full precision master copy of the value, so that
+// the updates smaller than the resolution of the half precision format are not rounded away: from then on, the
+// param is updated and used in full precision, and it is converted to half precision only when it is serialized.
+// Call SetPrecision or ReplaceValue to drop the master copy (e.g. at the end of the training).
 func (r *param) ApplyDelta(delta mat.Matrix) {
 	r.mu.Lock()
 	defer r.mu.Unlock()
-	r.Value().SubInPlace(delta)
+	if r.half != nil && !r.master {
+		r.value, _ = r.upcast()
+		r.master = true
+	}
+	r.value.SubInPlace(delta)
 	if r.storage != nil {
 		r.updateStorage()
 	}
@@ -247,6 +355,14 @@ func (r *param) updateStorage() {
 	}
 }
 
+// dims returns the number of rows and columns of the value.
+func (r *param) dims() (rows, cols int) {
+	if r.half != nil {
+		return r.half.Dims()
+	}
+	return r.value.Dims()
+}
+
 // Graph returns always nil since the "pure" parameter is not associated with any graph.
 func (r *param) Graph() *ag.Graph {
 	return nil
@@ -263,11 +379,32 @@ func (r *param) TimeStep() int {
 }
 
 // wrappedParam returns a new wrappedParam from the param itself.
+// The value of a param stored in half precision is upcast once for all.
 func (r *param) wrappedParam(g *ag.Graph) *wrappedParam {
+	var value ag.GradValue = r
+	if r.half != nil && !r.master {
+		value = &upcastParam{param: r, value: r.half.Dense()}
+	}
 	if r.requiresGrad {
-		return &wrappedParam{param: r, Node: g.NewWrap(r)}
+		return &wrappedParam{param: r, Node: g.NewWrap(value)}
 	}
-	return &wrappedParam{param: r, Node: g.NewWrapNoGrad(r)}
+	return &wrappedParam{param: r, Node: g.NewWrapNoGrad(value)}
+}
+
+// upcastParam is a param stored in half precision whose value has been converted to full precision.
+type upcastParam struct {
+	*param
+	value mat.Matrix
+}
+
+// Value returns the value converted to full precision.
+func (r *upcastParam) Value() mat.Matrix {
+	return r.value
+}
+
+// ScalarValue returns the the scalar value converted to full precision.
+func (r *upcastParam) ScalarValue() mat.Float {
+	return r.value.Scalar()
 }
 
 var _ Param = &wrappedParam{}
@@ -278,6 +415,16 @@ type wrappedParam struct {
 	Node ag.Node
 }
 
+// Value dispatches the call to the Node.
+func (r *wrappedParam) Value() mat.Matrix {
+	return r.Node.Value()
+}
+
+// ScalarValue dispatches the call to the Node.
+func (r *wrappedParam) ScalarValue() mat.Float {
+	return r.Node.Value().Scalar()
+}
+
 // ID dispatches the call to the Node.
 func (r *wrappedParam) ID() int {
 	return r.Node.ID()
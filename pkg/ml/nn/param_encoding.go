@@ -25,7 +25,12 @@ func init() {
 func (r *param) MarshalBinary() ([]byte, error) {
 	buf := new(bytes.Buffer)
 
-	err := mat.MarshalBinaryMatrix(r.value, buf)
+	var err error
+	if r.half != nil {
+		err = mat.MarshalBinaryHalfDense(r.halfValue(), buf)
+	} else {
+		err = mat.MarshalBinaryMatrix(r.value, buf)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -49,7 +54,8 @@ func (r *param) UnmarshalBinary(data []byte) error {
 	var err error
 	buf := bytes.NewReader(data)
 
-	r.value, err = mat.UnmarshalBinaryMatrix(buf)
+	r.master = false
+	r.value, r.half, err = mat.UnmarshalBinaryMatrixOrHalfDense(buf)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,149 @@
+// Copyright 2021 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package nn
+
+import (
+	"bytes"
+	"encoding/gob"
+	mat "github.com/nlpodyssey/spago/pkg/mat32"
+	"github.com/nlpodyssey/spago/pkg/ml/ag"
+	"github.com/nlpodyssey/spago/pkg/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+)
+
+func TestParam_SetPrecision(t *testing.T) {
+	data := []mat.Float{0.1, -0.2, 0.3, 1.5}
+	p := NewParam(mat.NewVecDense(data))
+	assert.Equal(t, mat.FullPrecision, p.Precision())
+
+	p.SetPrecision(mat.Float16)
+	assert.Equal(t, mat.Float16, p.Precision())
+	assert.InDeltaSlice(t, data, p.Value().Data(), 1.0e-3)
+
+	p.ApplyDelta(mat.NewVecDense([]mat.Float{0.1, 0.1, 0.1, 0.1}))
+	assert.Equal(t, mat.Float16, p.Precision())
+	assert.InDeltaSlice(t, []mat.Float{0.0, -0.3, 0.2, 1.4}, p.Value().Data(), 1.0e-3)
+
+	p.ReplaceValue(mat.NewVecDense(data))
+	assert.Equal(t, mat.Float16, p.Precision())
+	assert.InDeltaSlice(t, data, p.Value().Data(), 1.0e-3)
+
+	p.SetPrecision(mat.BFloat16)
+	assert.Equal(t, mat.BFloat16, p.Precision())
+	assert.InDeltaSlice(t, data, p.Value().Data(), 1.0e-2)
+
+	p.SetPrecision(mat.FullPrecision)
+	assert.Equal(t, mat.FullPrecision, p.Precision())
+	assert.InDeltaSlice(t, data, p.Value().Data(), 1.0e-2)
+}
+
+func TestParam_HalfPrecisionValue(t *testing.T) {
+	p := NewParam(mat.NewVecDense([]mat.Float{0.5, -1.0}))
+	p.SetPrecision(mat.Float16)
+	value := p.Value()
+	assert.Same(t, value, p.Value(), "the value is upcast only once")
+
+	p.SetData([]mat.Float{1.5, 2.0})
+	assert.Equal(t, mat.Float16, p.Precision())
+	assert.Equal(t, []mat.Float{1.5, 2.0}, p.Value().Data())
+
+	p.ApplyDelta(mat.NewVecDense([]mat.Float{0.5, 0.5}))
+	assert.Equal(t, []mat.Float{1.0, 1.5}, p.Value().Data())
+
+	p.SetPrecision(mat.FullPrecision)
+	assert.Equal(t, []mat.Float{1.0, 1.5}, p.Value().Data())
+}
+
+func TestParam_HalfPrecisionGraph(t *testing.T) {
+	p := NewParam(mat.NewVecDense([]mat.Float{0.5, -1.0}))
+	p.SetPrecision(mat.Float16)
+
+	g := ag.NewGraph()
+	wp := p.(*param).wrappedParam(g)
+	assert.Same(t, wp.Value(), wp.Value(), "the value is upcast only once")
+
+	y := g.ReduceSum(g.Square(wp))
+	assert.InDelta(t, 1.25, y.ScalarValue(), 1.0e-6)
+
+	g.Backward(y)
+	assert.Equal(t, []mat.Float{1.0, -2.0}, p.Grad().Data())
+}
+
+func TestParam_HalfPrecisionMasterCopy(t *testing.T) {
+	p := NewParam(mat.NewScalar(1.0))
+	p.SetPrecision(mat.Float16)
+
+	// each delta is smaller than half of the resolution of float16 around 1.0
+	for i := 0; i < 10; i++ {
+		p.ApplyDelta(mat.NewScalar(1.0e-4))
+	}
+	assert.Equal(t, mat.Float16, p.Precision())
+	assert.InDelta(t, 0.999, p.ScalarValue(), 1.0e-6)
+
+	g := ag.NewGraph()
+	assert.Same(t, p.Value(), p.(*param).wrappedParam(g).Value(), "the master copy is used for the computations")
+
+	var buf bytes.Buffer
+	require.Nil(t, gob.NewEncoder(&buf).Encode(&p))
+	var decodedParam Param
+	require.Nil(t, gob.NewDecoder(&buf).Decode(&decodedParam))
+	assert.Equal(t, mat.Float16, decodedParam.Precision())
+	assert.InDelta(t, 0.999, decodedParam.ScalarValue(), 1.0e-3)
+	assert.NotEqual(t, mat.Float(1.0), decodedParam.ScalarValue())
+
+	p.SetPrecision(mat.FullPrecision)
+	assert.InDelta(t, 0.999, p.ScalarValue(), 1.0e-6)
+}
+
+func TestParam_HalfPrecisionGob(t *testing.T) {
+	var buf bytes.Buffer
+
+	paramToEncode := NewParam(mat.NewVecDense([]mat.Float{0.5, -1.0, 2.0}))
+	paramToEncode.SetPrecision(mat.BFloat16)
+
+	err := gob.NewEncoder(&buf).Encode(&paramToEncode)
+	require.Nil(t, err)
+
+	var decodedParam Param
+	err = gob.NewDecoder(&buf).Decode(&decodedParam)
+	require.Nil(t, err)
+	assert.Equal(t, mat.BFloat16, decodedParam.Precision())
+	assert.Equal(t, []mat.Float{0.5, -1.0, 2.0}, decodedParam.Value().Data())
+}
+
+type precisionTestModel struct {
+	BaseModel
+	W Param `spago:"type:weights"`
+	B Param `spago:"type:biases"`
+}
+
+func TestWithPrecision(t *testing.T) {
+	dir, err := ioutil.TempDir("", "spago-precision-test-")
+	require.Nil(t, err)
+	defer os.RemoveAll(dir)
+	filename := path.Join(dir, "model.bin")
+
+	gob.Register(&precisionTestModel{})
+	model := &precisionTestModel{
+		W: NewParam(mat.NewDense(2, 2, []mat.Float{0.5, -1.0, 2.0, 0.25})),
+		B: NewParam(mat.NewVecDense([]mat.Float{1.0, -1.0})),
+	}
+	require.Nil(t, utils.SerializeToFile(filename, model))
+
+	var decoded *precisionTestModel
+	err = utils.DeserializeFromFile(filename, &decoded, WithPrecision(mat.Float16))
+	require.Nil(t, err)
+	assert.Equal(t, mat.Float16, decoded.W.Precision())
+	assert.Equal(t, mat.Float16, decoded.B.Precision())
+	assert.Equal(t, []mat.Float{0.5, -1.0, 2.0, 0.25}, decoded.W.Value().Data())
+
+	var notAModel []int
+	assert.NotNil(t, WithPrecision(mat.Float16)(&notAModel))
+}
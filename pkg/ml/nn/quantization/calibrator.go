@@ -0,0 +1,169 @@
+// Copyright 2021 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package quantization
+
+import (
+	mat "github.com/nlpodyssey/spago/pkg/mat32"
+	"github.com/nlpodyssey/spago/pkg/ml/nn"
+	"github.com/nlpodyssey/spago/pkg/ml/nn/linear"
+	"runtime"
+	"sync"
+)
+
+const (
+	// numCandidateRanges is the number of clipping ranges evaluated for each row of the weights.
+	numCandidateRanges = 21
+	// minCandidateRange is the smallest clipping range evaluated, relative to the maximum absolute value of the row.
+	minCandidateRange = 0.5
+)
+
+// Calibrator collects the statistics of the inputs of the linear layers of a model, while the model processes
+// a representative set of samples. The statistics are used by Quantize (see the Calibration option) to choose,
+// for each row of the weights, the clipping range which minimizes the expected squared error of the output.
+//
+// Clipping the few largest weights of a row allows to represent the others with a finer resolution; it is worth
+// it when the inputs corresponding to the clipped weights are small.
+type Calibrator struct {
+	mu     sync.Mutex
+	layers map[*linear.Model]*inputStats
+}
+
+// inputStats contains the statistics of the inputs of a linear layer.
+type inputStats struct {
+	// n is the number of observed input vectors.
+	n int
+	// sumSquares is the sum of the squares of each input feature.
+	sumSquares []float64
+}
+
+// NewCalibrator returns a new Calibrator which observes the inputs of all the linear layers of the model
+// (sub-models included), until Stop is called.
+// The layers already quantized are not observed.
+func NewCalibrator(m nn.Model) *Calibrator {
+	c := &Calibrator{
+		layers: make(map[*linear.Model]*inputStats),
+	}
+	nn.ForEachModel(m, func(m nn.Model) {
+		l, ok := m.(*linear.Model)
+		if !ok || l.Quantized != nil {
+			return
+		}
+		stats := &inputStats{
+			sumSquares: make([]float64, l.W.Value().Columns()),
+		}
+		c.layers[l] = stats
+		l.Observer = func(x mat.Matrix) {
+			c.observe(stats, x)
+		}
+	})
+	return c
+}
+
+// observe updates the statistics with the given input, which is a column vector or a minibatch of column vectors.
+func (c *Calibrator) observe(stats *inputStats, x mat.Matrix) {
+	if x.Rows() != len(stats.sumSquares) {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for i := range stats.sumSquares {
+		for j := 0; j < x.Columns(); j++ {
+			v := float64(x.At(i, j))
+			stats.sumSquares[i] += v * v
+		}
+	}
+	stats.n += x.Columns()
+}
+
+// Stop stops observing the inputs of the layers. The statistics collected so far are kept.
+func (c *Calibrator) Stop() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for l := range c.layers {
+		l.Observer = nil
+	}
+}
+
+// Samples returns the number of inputs of the given layer observed so far.
+func (c *Calibrator) Samples(l *linear.Model) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if stats, ok := c.layers[l]; ok {
+		return stats.n
+	}
+	return 0
+}
+
+// ranges returns the clipping range of each row of the weights of the given layer,
+// or nil if no inputs of the layer were observed.
+func (c *Calibrator) ranges(l *linear.Model) []mat.Float {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	stats, ok := c.layers[l]
+	if !ok || stats.n == 0 {
+		return nil
+	}
+	meanSquares := make([]mat.Float, len(stats.sumSquares))
+	for i, v := range stats.sumSquares {
+		meanSquares[i] = mat.Float(v / float64(stats.n))
+	}
+
+	w := l.W.Value()
+	ranges := make([]mat.Float, w.Rows())
+	rowsPerWorker := (w.Rows() + runtime.NumCPU() - 1) / runtime.NumCPU()
+	var wg sync.WaitGroup
+	for start := 0; start < w.Rows(); start += rowsPerWorker {
+		end := start + rowsPerWorker
+		if end > w.Rows() {
+			end = w.Rows()
+		}
+		wg.Add(1)
+		go func(start, end int) {
+			defer wg.Done()
+			row := make([]mat.Float, w.Columns())
+			for i := start; i < end; i++ {
+				for j := range row {
+					row[j] = w.At(i, j)
+				}
+				ranges[i] = bestRange(row, meanSquares)
+			}
+		}(start, end)
+	}
+	wg.Wait()
+	return ranges
+}
+
+// bestRange returns the clipping range of the given row which minimizes the expected squared error of the output,
+// given the mean squares of the inputs.
+func bestRange(row, meanSquares []mat.Float) mat.Float {
+	var maxAbs mat.Float
+	for _, v := range row {
+		maxAbs = mat.Max(maxAbs, mat.Abs(v))
+	}
+	if maxAbs == 0.0 {
+		return 0.0
+	}
+	best, bestErr := maxAbs, quantizationError(row, meanSquares, maxAbs)
+	step := mat.Float(1.0-minCandidateRange) / (numCandidateRanges - 1)
+	for k := 1; k < numCandidateRanges; k++ {
+		r := maxAbs * (1.0 - mat.Float(k)*step)
+		if err := quantizationError(row, meanSquares, r); err < bestErr {
+			best, bestErr = r, err
+		}
+	}
+	return best
+}
+
+// quantizationError returns the expected squared error of the dot product between the row and an input, caused by
+// the quantization of the row with the given range. The input features are assumed to be uncorrelated.
+func quantizationError(row, meanSquares []mat.Float, r mat.Float) mat.Float {
+	scale := r / 127.0
+	var err mat.Float
+	for j, v := range row {
+		d := v - mat.Float(mat.QuantizeInt8(v, scale))*scale
+		err += d * d * meanSquares[j]
+	}
+	return err
+}
@@ -0,0 +1,88 @@
+// Copyright 2021 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+/*
+Package quantization implements the post-training int8 quantization of the linear layers of a model, which are
+the bulk of the computation of most architectures (e.g. the projections of the attention and the feed-forward
+layers of the Transformers).
+
+The weights of each linear.Model are quantized to int8 with a scale factor for each row, and multiplied by the
+float inputs with a dedicated kernel (see mat.QuantizedDense). The inputs, the biases and all the other params
+remain in full precision.
+
+The quantized model is saved and loaded like any other model, so it can be saved in place of the original
+one and loaded by the same functions (e.g. bert.LoadModel):
+
+    quantization.Quantize(model)
+    err := nn.SaveModelFile(modelFilename, model, config)
+
+The quantization can optionally be calibrated with sample inputs (see Calibrator), to choose, for each row,
+the clipping range of the weights which minimizes the expected error of the outputs.
+*/
+package quantization
+
+import (
+	"github.com/nlpodyssey/spago/pkg/ml/nn"
+	"github.com/nlpodyssey/spago/pkg/ml/nn/linear"
+)
+
+// Option allows to configure the quantization with your specific needs.
+type Option func(*quantizer)
+
+// Calibration sets the Calibrator from which to get the clipping ranges of the weights of each layer.
+// The layers not observed by the calibrator are quantized without clipping.
+func Calibration(c *Calibrator) Option {
+	return func(q *quantizer) {
+		q.calibrator = c
+	}
+}
+
+// Exclude sets the linear layers (or the models containing them) to leave in full precision,
+// for example the output layer of a classifier.
+func Exclude(models ...nn.Model) Option {
+	return func(q *quantizer) {
+		for _, m := range models {
+			nn.ForEachModel(m, func(m nn.Model) {
+				if l, ok := m.(*linear.Model); ok {
+					q.excluded[l] = true
+				}
+			})
+		}
+	}
+}
+
+type quantizer struct {
+	calibrator *Calibrator
+	excluded   map[*linear.Model]bool
+}
+
+// Quantize replaces the weights of all the linear layers of the model (sub-models included) with their int8
+// quantization, and returns the number of quantized layers. The layers already quantized are skipped.
+// If a Calibrator is given, it is stopped before the quantization.
+// The quantized model is meant for the inference only.
+func Quantize(m nn.Model, opts ...Option) int {
+	q := &quantizer{
+		excluded: make(map[*linear.Model]bool),
+	}
+	for _, opt := range opts {
+		opt(q)
+	}
+	if q.calibrator != nil {
+		q.calibrator.Stop()
+	}
+	n := 0
+	nn.ForEachModel(m, func(m nn.Model) {
+		l, ok := m.(*linear.Model)
+		if !ok || l.Quantized != nil || q.excluded[l] {
+			return
+		}
+		if q.calibrator != nil {
+			l.Quantize(q.calibrator.ranges(l))
+		} else {
+			l.Quantize(nil)
+		}
+		n++
+	})
+	return n
+}
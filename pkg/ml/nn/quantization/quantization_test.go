@@ -0,0 +1,110 @@
+// Copyright 2021 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package quantization
+
+import (
+	mat "github.com/nlpodyssey/spago/pkg/mat32"
+	"github.com/nlpodyssey/spago/pkg/mat32/rand"
+	"github.com/nlpodyssey/spago/pkg/ml/ag"
+	"github.com/nlpodyssey/spago/pkg/ml/initializers"
+	"github.com/nlpodyssey/spago/pkg/ml/nn"
+	"github.com/nlpodyssey/spago/pkg/ml/nn/activation"
+	"github.com/nlpodyssey/spago/pkg/ml/nn/linear"
+	"github.com/nlpodyssey/spago/pkg/ml/nn/stack"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func newTestModel() *stack.Model {
+	rndGen := rand.NewLockedRand(42)
+	model := stack.New(
+		linear.New(8, 16),
+		activation.New(ag.OpTanh),
+		linear.New(16, 4),
+	)
+	nn.ForEachParam(model, func(param nn.Param) {
+		initializers.Uniform(param.Value(), -0.5, 0.5, rndGen)
+	})
+	return model
+}
+
+func newTestInputs(n int) []mat.Matrix {
+	rndGen := rand.NewLockedRand(7)
+	xs := make([]mat.Matrix, n)
+	for i := range xs {
+		xs[i] = mat.NewEmptyVecDense(8)
+		initializers.Uniform(xs[i], -1.0, 1.0, rndGen)
+	}
+	return xs
+}
+
+func forward(model *stack.Model, x mat.Matrix) mat.Matrix {
+	g := ag.NewGraph()
+	proc := nn.Reify(nn.Context{Graph: g, Mode: nn.Inference}, model).(*stack.Model)
+	return proc.Forward(g.NewVariable(x, false))[0].Value()
+}
+
+func TestQuantize(t *testing.T) {
+	xs := newTestInputs(10)
+	model := newTestModel()
+	expected := make([]mat.Matrix, len(xs))
+	for i, x := range xs {
+		expected[i] = forward(model, x)
+	}
+
+	assert.Equal(t, 2, Quantize(model))
+	assert.NotNil(t, model.Layers[0].(*linear.Model).Quantized)
+	assert.NotNil(t, model.Layers[2].(*linear.Model).Quantized)
+	for i, x := range xs {
+		assert.InDeltaSlice(t, expected[i].Data(), forward(model, x).Data(), 2.0e-2)
+	}
+
+	assert.Equal(t, 0, Quantize(model), "the layers already quantized are skipped")
+}
+
+func TestQuantize_Exclude(t *testing.T) {
+	model := newTestModel()
+	output := model.Layers[2].(*linear.Model)
+	assert.Equal(t, 1, Quantize(model, Exclude(output)))
+	assert.NotNil(t, model.Layers[0].(*linear.Model).Quantized)
+	assert.Nil(t, output.Quantized)
+}
+
+func TestQuantize_Calibration(t *testing.T) {
+	xs := newTestInputs(20)
+	model := newTestModel()
+	expected := make([]mat.Matrix, len(xs))
+	for i, x := range xs {
+		expected[i] = forward(model, x)
+	}
+
+	calibrator := NewCalibrator(model)
+	for _, x := range xs[:10] {
+		forward(model, x)
+	}
+	first := model.Layers[0].(*linear.Model)
+	assert.Equal(t, 10, calibrator.Samples(first))
+	assert.Equal(t, 0, calibrator.Samples(linear.New(1, 1)))
+
+	assert.Equal(t, 2, Quantize(model, Calibration(calibrator)))
+	assert.Nil(t, first.Observer, "the calibrator is stopped")
+	for i, x := range xs {
+		assert.InDeltaSlice(t, expected[i].Data(), forward(model, x).Data(), 2.0e-2)
+	}
+}
+
+func TestBestRange(t *testing.T) {
+	row := []mat.Float{1.0, 0.011, 0.012, -0.013}
+
+	// the input of the outlier is always zero: the range is reduced to represent the other values precisely
+	r := bestRange(row, []mat.Float{0.0, 1.0, 1.0, 1.0})
+	assert.Less(t, float64(r), 1.0)
+
+	// the outlier dominates the output: it is never clipped
+	r = bestRange(row, []mat.Float{100.0, 0.001, 0.001, 0.001})
+	assert.Equal(t, mat.Float(1.0), r)
+
+	assert.Equal(t, mat.Float(0.0), bestRange([]mat.Float{0.0, 0.0}, []mat.Float{1.0, 1.0}))
+}
@@ -7,6 +7,7 @@ package gru
 import (
 	mat "github.com/nlpodyssey/spago/pkg/mat32"
 	"github.com/nlpodyssey/spago/pkg/ml/ag"
+	"github.com/nlpodyssey/spago/pkg/ml/gradcheck"
 	"github.com/nlpodyssey/spago/pkg/ml/losses"
 	"github.com/nlpodyssey/spago/pkg/ml/nn"
 	"github.com/stretchr/testify/assert"
@@ -321,3 +322,18 @@ func newTestModel2() *Model {
 	model.BCand.Value().SetData([]mat.Float{0.4, 0.3})
 	return model
 }
+
+func TestModel_Gradients(t *testing.T) {
+	model := newTestModel2()
+	results := gradcheck.Model(model, func(g *ag.Graph, processor nn.Model) ag.Node {
+		proc := processor.(*Model)
+		ys := proc.Forward(
+			g.NewVariable(mat.NewVecDense([]mat.Float{0.35, 0.4, -0.1}), false),
+			g.NewVariable(mat.NewVecDense([]mat.Float{0.33, -0.2, 0.1}), false),
+		)
+		return g.ReduceSum(g.Square(g.Concat(ys...)))
+	})
+	for _, r := range results {
+		assert.Less(t, float64(r.MaxRelError), 1.0e-02, r.String())
+	}
+}
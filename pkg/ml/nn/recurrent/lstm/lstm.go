@@ -104,6 +104,24 @@ func (m *Model) Forward(xs ...ag.Node) []ag.Node {
 	return ys
 }
 
+// ForwardBatch performs the forward step for each input node and returns the result.
+// The input is a minibatch of sequences: each node holds the elements of all the sequences
+// at the same position, stacked as the columns of a matrix. The states are minibatches as well.
+// The optional mask marks the padding elements; at those positions the previous state is
+// carried over unchanged, so that the last state of each sequence is the one of its last real element.
+func (m *Model) ForwardBatch(xs []ag.Node, mask nn.PaddingMask) []ag.Node {
+	ys := make([]ag.Node, len(xs))
+	for i, x := range xs {
+		s := m.forwardBatch(x)
+		if mask != nil {
+			s = m.applyMask(s, mask, i)
+		}
+		m.States = append(m.States, s)
+		ys[i] = s.Y
+	}
+	return ys
+}
+
 // LastState returns the last state of the recurrent network.
 // It returns nil if there are no states.
 func (m *Model) LastState() *State {
@@ -121,14 +139,18 @@ func (m *Model) LastState() *State {
 // cand = f(wCand (dot) x + bC + wCandRec (dot) yPrev)
 // cell = inG * cand + forG * cellPrev
 // y = outG * f(cell)
-func (m *Model) forward(x ag.Node) (s *State) {
+func (m *Model) forward(x ag.Node) *State {
+	return m.step(x, nn.Affine)
+}
+
+func (m *Model) step(x ag.Node, affine func(g *ag.Graph, xs ...ag.Node) ag.Node) (s *State) {
 	g := m.Graph()
 	s = new(State)
 	yPrev, cellPrev := m.prev()
-	s.InG = g.Sigmoid(nn.Affine(g, m.BIn, m.WIn, x, m.WInRec, yPrev))
-	s.OutG = g.Sigmoid(nn.Affine(g, m.BOut, m.WOut, x, m.WOutRec, yPrev))
-	s.ForG = g.Sigmoid(nn.Affine(g, m.BFor, m.WFor, x, m.WForRec, yPrev))
-	s.Cand = g.Tanh(nn.Affine(g, m.BCand, m.WCand, x, m.WCandRec, yPrev))
+	s.InG = g.Sigmoid(affine(g, m.BIn, m.WIn, x, m.WInRec, yPrev))
+	s.OutG = g.Sigmoid(affine(g, m.BOut, m.WOut, x, m.WOutRec, yPrev))
+	s.ForG = g.Sigmoid(affine(g, m.BFor, m.WFor, x, m.WForRec, yPrev))
+	s.Cand = g.Tanh(affine(g, m.BCand, m.WCand, x, m.WCandRec, yPrev))
 
 	if m.UseRefinedGates {
 		s.InG = g.Prod(s.InG, x)
@@ -152,3 +174,26 @@ func (m *Model) prev() (yPrev, cellPrev ag.Node) {
 	}
 	return
 }
+
+// forwardBatch computes the same equations of forward, where x and the states are minibatches.
+func (m *Model) forwardBatch(x ag.Node) *State {
+	return m.step(x, nn.AffineBatch)
+}
+
+// applyMask returns a copy of the state where the cell and the output of the padding
+// elements at position t are replaced by the ones of the previous state (or zeros).
+func (m *Model) applyMask(s *State, mask nn.PaddingMask, t int) *State {
+	g := m.Graph()
+	yPrev, cellPrev := m.prev()
+	rows := m.BIn.Value().Size() // the size of the hidden state
+	keep := g.NewVariable(mask.Matrix(t, rows, 1.0, 0.0), false)
+	masked := *s
+	masked.Cell = g.Prod(s.Cell, keep)
+	masked.Y = g.Prod(s.Y, keep)
+	if yPrev != nil {
+		carry := g.NewVariable(mask.Matrix(t, rows, 0.0, 1.0), false)
+		masked.Cell = g.Add(masked.Cell, g.Prod(cellPrev, carry))
+		masked.Y = g.Add(masked.Y, g.Prod(yPrev, carry))
+	}
+	return &masked
+}
@@ -7,6 +7,7 @@ package lstm
 import (
 	mat "github.com/nlpodyssey/spago/pkg/mat32"
 	"github.com/nlpodyssey/spago/pkg/ml/ag"
+	"github.com/nlpodyssey/spago/pkg/ml/gradcheck"
 	"github.com/nlpodyssey/spago/pkg/ml/losses"
 	"github.com/nlpodyssey/spago/pkg/ml/nn"
 	"github.com/stretchr/testify/assert"
@@ -407,3 +408,70 @@ func newTestModel2() *Model {
 	model.BCand.Value().SetData([]mat.Float{0.4, 0.3})
 	return model
 }
+
+func TestModel_ForwardBatch(t *testing.T) {
+	seqs := [][][]mat.Float{
+		{{-0.8, -0.9, -0.9, 1.0}, {0.8, -0.3, 0.5, 0.3}, {-0.2, 0.7, 0.2, 0.4}},
+		{{0.1, 0.3, -0.4, 0.2}, {-0.5, 0.6, 0.1, -0.9}},
+	}
+
+	// == Expected results, processing one sequence at a time
+
+	model := newTestModel()
+	expected := make([]mat.Matrix, len(seqs))
+	expectedWInGrad := model.WIn.Value().ZerosLike()
+	for j, seq := range seqs {
+		g := ag.NewGraph()
+		proc := nn.Reify(nn.Context{Graph: g, Mode: nn.Training}, model).(*Model)
+		for _, x := range seq {
+			proc.Forward(g.NewVariable(mat.NewVecDense(x), true))
+		}
+		y := proc.LastState().Y
+		expected[j] = y.Value().Clone()
+		g.Backward(g.ReduceSum(y))
+		expectedWInGrad.AddInPlace(model.WIn.Grad())
+		nn.ZeroGrad(model)
+	}
+
+	// == Batch
+
+	g := ag.NewGraph()
+	proc := nn.Reify(nn.Context{Graph: g, Mode: nn.Training}, model).(*Model)
+	mask := nn.NewPaddingMask(len(seqs[0]), len(seqs[1]))
+	xs := make([]ag.Node, len(mask))
+	for i := range xs {
+		cols := make([]ag.Node, len(seqs))
+		for j, seq := range seqs {
+			if i < len(seq) {
+				cols[j] = g.NewVariable(mat.NewVecDense(seq[i]), true)
+			} else {
+				cols[j] = g.NewVariable(mat.NewEmptyVecDense(4), false) // padding
+			}
+		}
+		xs[i] = g.StackCols(cols...)
+	}
+	proc.ForwardBatch(xs, mask)
+	y := proc.LastState().Y
+
+	for j := range seqs {
+		assert.InDeltaSlice(t, expected[j].Data(), y.Value().(*mat.Dense).ExtractColumn(j).Data(), 1.0e-06)
+	}
+
+	g.Backward(g.ReduceSum(g.ReduceSumCols(y)))
+	assert.InDeltaSlice(t, expectedWInGrad.Data(), model.WIn.Grad().Data(), 1.0e-06)
+}
+
+func TestModel_Gradients(t *testing.T) {
+	model := newTestModel2()
+	results := gradcheck.Model(model, func(g *ag.Graph, processor nn.Model) ag.Node {
+		proc := processor.(*Model)
+		ys := proc.Forward(
+			g.NewVariable(mat.NewVecDense([]mat.Float{0.35, 0.4, -0.1}), false),
+			g.NewVariable(mat.NewVecDense([]mat.Float{0.33, -0.2, 0.1}), false),
+		)
+		return g.ReduceSum(g.Square(g.Concat(ys...)))
+	})
+	for _, r := range results {
+		assert.Less(t, float64(r.MaxRelError), 1.0e-02, r.String())
+	}
+}
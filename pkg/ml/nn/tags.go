@@ -17,6 +17,7 @@ const (
 	weightsModuleFieldType
 	biasesModuleFieldType
 	undefinedModuleFieldType
+	gainsModuleFieldType
 )
 
 type moduleFieldScope uint8
@@ -39,10 +40,12 @@ const (
 	// Undefined identifies a generic Param, which cannot be described
 	// with other ParamsType values.
 	Undefined
+	// Gains identifies a Param containing the gains of a normalization layer.
+	Gains
 )
 
 func (t ParamsType) String() string {
-	return [...]string{"weights", "biases", "undefined"}[t] // important lower case
+	return [...]string{"weights", "biases", "undefined", "gains"}[t] // important lower case
 }
 
 type moduleFieldTag struct {
@@ -93,6 +96,8 @@ func stringToModuleFieldType(s string) (moduleFieldType, error) {
 		return biasesModuleFieldType, nil
 	case "undefined":
 		return undefinedModuleFieldType, nil
+	case "gains":
+		return gainsModuleFieldType, nil
 	default:
 		return defaultModuleFieldType, fmt.Errorf("unexpected model field type %#v", s)
 	}
@@ -115,6 +120,8 @@ func (m *moduleFieldTag) paramType() ParamsType {
 		return Weights
 	case biasesModuleFieldType:
 		return Biases
+	case gainsModuleFieldType:
+		return Gains
 	default:
 		return Undefined
 	}
@@ -35,6 +35,10 @@ func TestValidModuleFieldTagParsing(t *testing.T) {
 			Type:  undefinedModuleFieldType,
 			Scope: defaultModuleFieldScope,
 		}},
+		{"type:gains", moduleFieldTag{
+			Type:  gainsModuleFieldType,
+			Scope: defaultModuleFieldScope,
+		}},
 		{"scope:processor", moduleFieldTag{
 			Type:  defaultModuleFieldType,
 			Scope: processorModuleFieldScope,
@@ -84,6 +88,7 @@ func TestModuleFieldTag_ParamType(t *testing.T) {
 		{"type:weights", Weights},
 		{"type:biases", Biases},
 		{"type:undefined", Undefined},
+		{"type:gains", Gains},
 	} {
 		t.Run(fmt.Sprintf("%#v", example.tag), func(t *testing.T) {
 			mft, err := parseModuleFieldTag(example.tag)
@@ -0,0 +1,253 @@
+// Copyright 2021 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package adafactor
+
+import (
+	mat "github.com/nlpodyssey/spago/pkg/mat32"
+	"github.com/nlpodyssey/spago/pkg/ml/nn"
+	"github.com/nlpodyssey/spago/pkg/ml/optimizers/gd"
+)
+
+var _ gd.MethodConfig = &Config{}
+
+// Config provides configuration settings for an Adafactor optimizer.
+type Config struct {
+	gd.MethodConfig
+	// LR is the external learning rate, used only if RelativeStep is false.
+	LR mat.Float
+	// Epsilon1 is the regularization constant added to the squared gradients.
+	Epsilon1 mat.Float
+	// Epsilon2 is the minimum scale of the params, used if ScaleParameter is true.
+	Epsilon2 mat.Float
+	// ClipThreshold is the threshold of the root mean square of the update.
+	ClipThreshold mat.Float
+	// DecayRate is the exponent of the decay of the running averages of the squared gradients.
+	DecayRate mat.Float
+	// Beta1 is the coefficient of the running average of the updates. Zero means no momentum (and no memory).
+	Beta1 mat.Float
+	// WeightDecay is the decoupled weight decay rate, scaled by the learning rate.
+	WeightDecay mat.Float
+	// Exclude reports whether a param is excluded from the weight decay.
+	// Nil means gd.ExcludeBiasesAndNorms.
+	Exclude func(param nn.Param) bool
+	// ScaleParameter reports whether the learning rate is scaled by the root mean square of the param.
+	ScaleParameter bool
+	// RelativeStep reports whether the learning rate is 1/sqrt(t), capped to 1e-2, instead of LR.
+	RelativeStep bool
+	// WarmupInit reports whether the relative step is also capped to 1e-6*t, for a linear warmup.
+	WarmupInit bool
+}
+
+// NewDefaultConfig returns a new Config with the default values of the paper: relative step and
+// param-scaled learning rate, without momentum.
+func NewDefaultConfig() Config {
+	return Config{
+		Epsilon1:       1.0e-30,
+		Epsilon2:       1.0e-3,
+		ClipThreshold:  1.0,
+		DecayRate:      -0.8,
+		ScaleParameter: true,
+		RelativeStep:   true,
+	}
+}
+
+// NewConfig returns a new Config with an external learning rate, e.g. for a learning rate scheduler,
+// and the other default values.
+func NewConfig(lr mat.Float) Config {
+	c := NewDefaultConfig()
+	c.LR = lr
+	c.ScaleParameter = false
+	c.RelativeStep = false
+	return c
+}
+
+var _ gd.LearningRateMethod = &Adafactor{}
+
+// Adafactor implements the Adafactor gradient descent optimization method, which reduces the memory of the
+// second moments of Adam: the running averages of the squared gradients of a matrix are factored into
+// their row and column sums, so that a r×c matrix needs r+c values instead of r×c.
+// The vectors (a single row or column) are not factored.
+// The time step is incremented at each new batch.
+// References:
+//     Adafactor: Adaptive Learning Rates with Sublinear Memory Cost
+//     https://arxiv.org/abs/1804.04235
+type Adafactor struct {
+	Config
+	TimeStep int
+}
+
+// New returns a new Adafactor optimizer, initialized according to the given configuration.
+func New(c Config) *Adafactor {
+	if c.Exclude == nil {
+		c.Exclude = gd.ExcludeBiasesAndNorms
+	}
+	return &Adafactor{Config: c}
+}
+
+// Label returns the enumeration-like value which identifies this gradient descent method.
+func (o *Adafactor) Label() int {
+	return gd.Adafactor
+}
+
+// LearningRate returns the external learning rate.
+func (o *Adafactor) LearningRate() mat.Float {
+	return o.LR
+}
+
+// SetLearningRate sets the external learning rate, which is used only if RelativeStep is false.
+func (o *Adafactor) SetLearningRate(lr mat.Float) {
+	o.LR = lr
+}
+
+const (
+	// vRow is the running average of the row means of the squared gradients (r×1), or of the squared
+	// gradients if not factored.
+	vRow int = 0
+	// vCol is the running average of the column means of the squared gradients (1×c), if factored.
+	vCol int = 1
+	// delta is the update, or its running average if beta1 is not zero.
+	delta int = 2
+	// buf is the update before the clipping, then the delta with the weight decay.
+	buf int = 3
+)
+
+// factored reports whether the second moments of a r×c param are factored.
+func factored(r, c int) bool {
+	return r > 1 && c > 1
+}
+
+// NewSupport returns a new support structure with the given dimensions.
+func (o *Adafactor) NewSupport(r, c int) *nn.Payload {
+	supp := make([]mat.Matrix, 4)
+	if factored(r, c) {
+		supp[vRow] = mat.NewEmptyVecDense(r)
+		supp[vCol] = mat.NewEmptyDense(1, c)
+	} else {
+		supp[vRow] = mat.NewEmptyDense(r, c)
+		supp[vCol] = mat.NewEmptyDense(0, 0)
+	}
+	supp[delta] = mat.NewEmptyDense(r, c)
+	supp[buf] = mat.NewEmptyDense(r, c)
+	return &nn.Payload{
+		Label: o.Label(),
+		Data:  supp,
+	}
+}
+
+// IncBatch beats the occurrence of a new batch.
+func (o *Adafactor) IncBatch() {
+	o.TimeStep++
+}
+
+// Delta returns the difference between the current params and where the method wants it to be.
+func (o *Adafactor) Delta(param nn.Param) mat.Matrix {
+	return o.calcDelta(param, gd.GetOrSetPayload(param, o).Data)
+}
+
+func (o *Adafactor) calcDelta(param nn.Param, supp []mat.Matrix) mat.Matrix {
+	timeStep := mat.Float(o.TimeStep)
+	if timeStep < 1 {
+		timeStep = 1
+	}
+	value := param.Value()
+	rows, cols := value.Dims()
+	grads := param.Grad().Data()
+	lr := o.learningRate(value.Data(), timeStep)
+	beta2T := 1.0 - mat.Pow(timeStep, o.DecayRate)
+
+	update := supp[buf].Data()
+	if factored(rows, cols) {
+		o.factoredUpdate(grads, supp, rows, cols, beta2T, update)
+	} else {
+		v := supp[vRow].Data()
+		for i, g := range grads {
+			v[i] = v[i]*beta2T + (g*g+o.Epsilon1)*(1.0-beta2T)
+			update[i] = g / mat.Sqrt(v[i])
+		}
+	}
+
+	// clip the update by its root mean square, then scale by the learning rate
+	scale := lr / mat.Max(1.0, rms(update)/o.ClipThreshold)
+	d := supp[delta].Data()
+	for i, u := range update {
+		if o.Beta1 != 0.0 {
+			d[i] = d[i]*o.Beta1 + u*scale*(1.0-o.Beta1)
+		} else {
+			d[i] = u * scale
+		}
+	}
+	if o.WeightDecay == 0.0 || o.Exclude(param) {
+		return supp[delta]
+	}
+	// the decay is not accumulated in the running average of the updates
+	decay := o.WeightDecay * lr
+	for i, x := range value.Data() {
+		update[i] = d[i] + x*decay
+	}
+	return supp[buf]
+}
+
+// factoredUpdate updates the running averages of the row and column means of the squared gradients,
+// and sets the update to the gradients divided by the square root of their factored approximation:
+//     v ≈ (vRow × vCol) / mean(vRow)
+func (o *Adafactor) factoredUpdate(grads []mat.Float, supp []mat.Matrix, rows, cols int, beta2T mat.Float,
+	update []mat.Float) {
+	rowMeans := make([]mat.Float, rows)
+	colMeans := make([]mat.Float, cols)
+	for i := 0; i < rows; i++ {
+		for j, g := range grads[i*cols : (i+1)*cols] {
+			sq := g*g + o.Epsilon1
+			rowMeans[i] += sq / mat.Float(cols)
+			colMeans[j] += sq / mat.Float(rows)
+		}
+	}
+	vr, vc := supp[vRow].Data(), supp[vCol].Data()
+	var vrMean mat.Float
+	for i, mean := range rowMeans {
+		vr[i] = vr[i]*beta2T + mean*(1.0-beta2T)
+		vrMean += vr[i] / mat.Float(rows)
+	}
+	for j, mean := range colMeans {
+		vc[j] = vc[j]*beta2T + mean*(1.0-beta2T)
+	}
+	for i := 0; i < rows; i++ {
+		rowFactor := vr[i] / vrMean
+		for j := 0; j < cols; j++ {
+			k := i*cols + j
+			update[k] = grads[k] / mat.Sqrt(rowFactor*vc[j])
+		}
+	}
+}
+
+// learningRate returns the learning rate of the param at the time step.
+func (o *Adafactor) learningRate(values []mat.Float, timeStep mat.Float) mat.Float {
+	lr := o.LR
+	if o.RelativeStep {
+		minStep := mat.Float(1.0e-2)
+		if o.WarmupInit {
+			minStep = 1.0e-6 * timeStep
+		}
+		lr = 1.0 / mat.Sqrt(timeStep)
+		if minStep < lr {
+			lr = minStep
+		}
+	}
+	if o.ScaleParameter {
+		lr *= mat.Max(o.Epsilon2, rms(values))
+	}
+	return lr
+}
+
+// rms returns the root mean square of the values.
+func rms(values []mat.Float) mat.Float {
+	if len(values) == 0 {
+		return 0.0
+	}
+	var sum mat.Float
+	for _, x := range values {
+		sum += x * x
+	}
+	return mat.Sqrt(sum / mat.Float(len(values)))
+}
@@ -0,0 +1,66 @@
+// Copyright 2021 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package adafactor
+
+import (
+	mat "github.com/nlpodyssey/spago/pkg/mat32"
+	"github.com/nlpodyssey/spago/pkg/ml/nn"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestAdafactor_FactoredDelta(t *testing.T) {
+	updater := New(NewDefaultConfig())
+	updater.IncBatch()
+
+	param := nn.NewParam(mat.NewDense(2, 3, []mat.Float{
+		0.4, 0.4, 0.5,
+		1.0, 0.8, -0.2,
+	}))
+	param.PropagateGrad(mat.NewDense(2, 3, []mat.Float{
+		0.9, 0.7, 0.4,
+		0.8, 0.1, -0.6,
+	}))
+	delta := updater.Delta(param)
+
+	assert.InDeltaSlice(t, []mat.Float{
+		0.00595314, 0.00788498, 0.00441821,
+		0.00636223, 0.00135431, -0.00796807,
+	}, delta.Data(), 1.0e-6)
+
+	supp := param.Payload().Data
+	assert.InDeltaSlice(t, []mat.Float{0.486667, 0.336667}, supp[vRow].Data(), 1.0e-6)
+	assert.InDeltaSlice(t, []mat.Float{0.725, 0.25, 0.26}, supp[vCol].Data(), 1.0e-6)
+}
+
+func TestAdafactor_VectorDelta(t *testing.T) {
+	config := NewConfig(0.01)
+	config.WeightDecay = 0.1
+	updater := New(config)
+	updater.IncBatch()
+	updater.IncBatch()
+
+	param := nn.NewParam(mat.NewVecDense([]mat.Float{0.4, -0.4, 0.5}))
+	param.PropagateGrad(mat.NewVecDense([]mat.Float{0.9, 0.7, 0.4}))
+	d := updater.Delta(param)
+
+	// the clipped update plus the weight decay
+	assert.InDeltaSlice(t, []mat.Float{0.0104, 0.0096, 0.0105}, d.Data(), 1.0e-6)
+	assert.InDeltaSlice(t, []mat.Float{0.01, 0.01, 0.01}, param.Payload().Data[delta].Data(), 1.0e-6)
+}
+
+func TestAdafactor_ExcludedFromWeightDecay(t *testing.T) {
+	config := NewConfig(0.01)
+	config.WeightDecay = 0.1
+	updater := New(config)
+	updater.IncBatch()
+	updater.IncBatch()
+
+	param := nn.NewParam(mat.NewVecDense([]mat.Float{0.4, -0.4, 0.5}))
+	param.SetType(nn.Gains)
+	param.PropagateGrad(mat.NewVecDense([]mat.Float{0.9, 0.7, 0.4}))
+
+	assert.InDeltaSlice(t, []mat.Float{0.01, 0.01, 0.01}, updater.Delta(param).Data(), 1.0e-6)
+}
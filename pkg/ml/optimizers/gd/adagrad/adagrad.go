@@ -35,7 +35,10 @@ func NewDefaultConfig() Config {
 	}
 }
 
-var _ gd.Method = &AdaGrad{}
+var (
+	_ gd.RowSparseMethod    = &AdaGrad{}
+	_ gd.LearningRateMethod = &AdaGrad{}
+)
 
 // AdaGrad assigns a different learning rate to each parameter using the sum of squares of its all historical gradients.
 // References
@@ -57,6 +60,16 @@ func (o *AdaGrad) Label() int {
 	return gd.AdaGrad
 }
 
+// LearningRate returns the current learning rate.
+func (o *AdaGrad) LearningRate() mat.Float {
+	return o.LR
+}
+
+// SetLearningRate sets the learning rate.
+func (o *AdaGrad) SetLearningRate(lr mat.Float) {
+	o.LR = lr
+}
+
 // NewSupport returns a new support structure with the given dimensions.
 func (o *AdaGrad) NewSupport(r, c int) *nn.Payload {
 	return &nn.Payload{
@@ -67,6 +80,9 @@ func (o *AdaGrad) NewSupport(r, c int) *nn.Payload {
 
 // Delta returns the difference between the current params and where the method wants it to be.
 func (o *AdaGrad) Delta(param nn.Param) mat.Matrix {
+	if grads, ok := param.Grad().(*mat.RowSparse); ok {
+		return o.DeltaRowSparse(param, grads)
+	}
 	return o.calcDelta(param.Grad(), gd.GetOrSetPayload(param, o).Data)
 }
 
@@ -80,3 +96,21 @@ func (o *AdaGrad) calcDelta(grads mat.Matrix, supp []mat.Matrix) mat.Matrix {
 	delta.ProdScalarInPlace(o.LR)
 	return delta
 }
+
+// DeltaRowSparse returns the difference between the rows of the current params with gradients and where the method
+// wants them to be. Only the corresponding rows of the support structure are updated.
+func (o *AdaGrad) DeltaRowSparse(param nn.Param, grads *mat.RowSparse) *mat.RowSparse {
+	supp := gd.GetOrSetPayload(param, o).Data
+	cols := grads.Columns()
+	delta := mat.NewRowSparse(grads.Dims())
+	d := make([]mat.Float, cols)
+	for _, i := range grads.RowIndices() {
+		mi := supp[m].Data()[i*cols : (i+1)*cols]
+		for j, gj := range grads.Row(i) {
+			mi[j] += gj * gj
+			d[j] = gj / (mat.Sqrt(mi[j]) + o.Epsilon) * o.LR
+		}
+		delta.AddToRow(i, d)
+	}
+	return delta
+}
@@ -6,6 +6,7 @@ package adagrad
 
 import (
 	mat "github.com/nlpodyssey/spago/pkg/mat32"
+	"github.com/nlpodyssey/spago/pkg/ml/nn"
 	"github.com/stretchr/testify/assert"
 	"testing"
 )
@@ -80,3 +81,24 @@ func Test_Update2(t *testing.T) {
 		0.698339372135027, -0.399923076933827, 0.198000838875607,
 	}, params.Data(), 1.0e-5)
 }
+
+func Test_DeltaRowSparse(t *testing.T) {
+	updater := New(NewConfig(0.1, 1.0e-8))
+	sparseParam := nn.NewParam(mat.NewEmptyDense(3, 2))
+	denseParam := nn.NewParam(mat.NewEmptyDense(3, 2))
+
+	for _, g := range [][]mat.Float{{0.9, -0.7}, {0.4, 0.1}} {
+		grads := mat.NewRowSparse(3, 2)
+		grads.AddToRow(1, g)
+		sparseParam.PropagateGrad(grads)
+		denseParam.PropagateGrad(grads.ToDense())
+
+		delta, ok := updater.Delta(sparseParam).(*mat.RowSparse)
+		assert.True(t, ok)
+		assert.Equal(t, []int{1}, delta.RowIndices())
+		assert.InDeltaSlice(t, updater.Delta(denseParam).Data(), delta.Data(), 1.0e-6)
+
+		sparseParam.ZeroGrad()
+		denseParam.ZeroGrad()
+	}
+}
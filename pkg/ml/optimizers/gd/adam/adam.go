@@ -47,7 +47,10 @@ func NewDefaultConfig() Config {
 	}
 }
 
-var _ gd.Method = &Adam{}
+var (
+	_ gd.RowSparseMethod    = &Adam{}
+	_ gd.LearningRateMethod = &Adam{}
+)
 
 // Adam implements the Adam gradient descent optimization method.
 type Adam struct {
@@ -71,6 +74,17 @@ func (o *Adam) Label() int {
 	return gd.Adam
 }
 
+// LearningRate returns the current step size.
+func (o *Adam) LearningRate() mat.Float {
+	return o.StepSize
+}
+
+// SetLearningRate sets the step size.
+func (o *Adam) SetLearningRate(lr mat.Float) {
+	o.StepSize = lr
+	o.updateAlpha()
+}
+
 const (
 	v    int = 0
 	m    int = 1
@@ -105,6 +119,9 @@ func (o *Adam) updateAlpha() {
 
 // Delta returns the difference between the current params and where the method wants it to be.
 func (o *Adam) Delta(param nn.Param) mat.Matrix {
+	if grads, ok := param.Grad().(*mat.RowSparse); ok {
+		return o.DeltaRowSparse(param, grads)
+	}
 	return o.calcDelta(param.Grad(), gd.GetOrSetPayload(param, o).Data)
 }
 
@@ -137,3 +154,24 @@ func updateM(grads mat.Matrix, supp []mat.Matrix, beta2 mat.Float) {
 	supp[buf2].ProdMatrixScalarInPlace(sqGrad, 1.0-beta2)
 	supp[m].AddInPlace(supp[buf2])
 }
+
+// DeltaRowSparse returns the difference between the rows of the current params with gradients and where the method
+// wants them to be. Only the corresponding rows of the support structure are updated (lazy Adam), so the moments
+// of the other rows are not decayed.
+func (o *Adam) DeltaRowSparse(param nn.Param, grads *mat.RowSparse) *mat.RowSparse {
+	supp := gd.GetOrSetPayload(param, o).Data
+	cols := grads.Columns()
+	delta := mat.NewRowSparse(grads.Dims())
+	d := make([]mat.Float, cols)
+	for _, i := range grads.RowIndices() {
+		vi := supp[v].Data()[i*cols : (i+1)*cols]
+		mi := supp[m].Data()[i*cols : (i+1)*cols]
+		for j, gj := range grads.Row(i) {
+			vi[j] = vi[j]*o.Beta1 + gj*(1.0-o.Beta1)
+			mi[j] = mi[j]*o.Beta2 + gj*gj*(1.0-o.Beta2)
+			d[j] = vi[j] / (mat.Sqrt(mi[j]) + o.Epsilon) * o.Alpha
+		}
+		delta.AddToRow(i, d)
+	}
+	return delta
+}
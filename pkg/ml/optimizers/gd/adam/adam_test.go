@@ -6,6 +6,7 @@ package adam
 
 import (
 	mat "github.com/nlpodyssey/spago/pkg/mat32"
+	"github.com/nlpodyssey/spago/pkg/ml/nn"
 	"github.com/stretchr/testify/assert"
 	"testing"
 )
@@ -21,6 +22,19 @@ func Test_IncExample(t *testing.T) {
 	assert.InDelta(t, 3.1623e-4, updater.Alpha, 1.0e-08)
 }
 
+func Test_SetLearningRate(t *testing.T) {
+	updater := New(NewConfig(
+		0.001,  // step size
+		0.9,    // beta1
+		0.999,  // beta2
+		1.0e-8, // epsilon
+	))
+	updater.SetLearningRate(0.002)
+
+	assert.Equal(t, mat.Float(0.002), updater.LearningRate())
+	assert.InDelta(t, 6.3246e-4, updater.Alpha, 1.0e-08)
+}
+
 func Test_Update(t *testing.T) {
 	updater := New(NewConfig(
 		0.001,  // step size
@@ -115,3 +129,24 @@ func Test_Update2(t *testing.T) {
 		0.698005353030075, -0.399429341903112, 0.198229065305535,
 	}, params.Data(), 1.0e-5)
 }
+
+func Test_DeltaRowSparse(t *testing.T) {
+	updater := New(NewConfig(0.001, 0.9, 0.999, 1.0e-8))
+	sparseParam := nn.NewParam(mat.NewEmptyDense(3, 2))
+	denseParam := nn.NewParam(mat.NewEmptyDense(3, 2))
+
+	for _, g := range [][]mat.Float{{0.9, -0.7}, {0.4, 0.1}} {
+		grads := mat.NewRowSparse(3, 2)
+		grads.AddToRow(1, g)
+		sparseParam.PropagateGrad(grads)
+		denseParam.PropagateGrad(grads.ToDense())
+
+		delta, ok := updater.Delta(sparseParam).(*mat.RowSparse)
+		assert.True(t, ok)
+		assert.Equal(t, []int{1}, delta.RowIndices())
+		assert.InDeltaSlice(t, updater.Delta(denseParam).Data(), delta.Data(), 1.0e-6)
+		updater.IncExample()
+		sparseParam.ZeroGrad()
+		denseParam.ZeroGrad()
+	}
+}
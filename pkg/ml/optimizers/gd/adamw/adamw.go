@@ -0,0 +1,199 @@
+// Copyright 2021 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package adamw
+
+import (
+	mat "github.com/nlpodyssey/spago/pkg/mat32"
+	"github.com/nlpodyssey/spago/pkg/ml/nn"
+	"github.com/nlpodyssey/spago/pkg/ml/optimizers/gd"
+)
+
+var _ gd.MethodConfig = &Config{}
+
+// Config provides configuration settings for an AdamW optimizer.
+type Config struct {
+	gd.MethodConfig
+	StepSize    mat.Float
+	Beta1       mat.Float
+	Beta2       mat.Float
+	Epsilon     mat.Float
+	WeightDecay mat.Float
+	// Exclude reports whether a param is excluded from the weight decay.
+	// Nil means gd.ExcludeBiasesAndNorms.
+	Exclude func(param nn.Param) bool
+}
+
+// NewConfig returns a new AdamW Config.
+// It panics if beta1 or beta2 are not in the range [0.0, 1.0).
+func NewConfig(stepSize, beta1, beta2, epsilon, weightDecay mat.Float) Config {
+	if !(beta1 >= 0.0 && beta1 < 1.0) {
+		panic("adamw: `beta1` must be in the range [0.0, 1.0)")
+	}
+	if !(beta2 >= 0.0 && beta2 < 1.0) {
+		panic("adamw: `beta2` must be in the range [0.0, 1.0)")
+	}
+	return Config{
+		StepSize:    stepSize,
+		Beta1:       beta1,
+		Beta2:       beta2,
+		Epsilon:     epsilon,
+		WeightDecay: weightDecay,
+	}
+}
+
+// NewDefaultConfig returns a new Config with generically reasonable default values.
+func NewDefaultConfig() Config {
+	return Config{
+		StepSize:    0.001,
+		Beta1:       0.9,
+		Beta2:       0.999,
+		Epsilon:     1.0e-8,
+		WeightDecay: 0.01,
+	}
+}
+
+var (
+	_ gd.RowSparseMethod    = &AdamW{}
+	_ gd.LearningRateMethod = &AdamW{}
+)
+
+// AdamW implements the Adam gradient descent optimization method with decoupled weight decay: the params are
+// decayed by StepSize*WeightDecay at each update, independently of the adaptive gradient step.
+// References:
+//     Decoupled Weight Decay Regularization
+//     https://arxiv.org/abs/1711.05101
+type AdamW struct {
+	Config
+	Alpha    mat.Float
+	TimeStep int
+}
+
+// New returns a new AdamW optimizer, initialized according to the given configuration.
+func New(c Config) *AdamW {
+	if c.Exclude == nil {
+		c.Exclude = gd.ExcludeBiasesAndNorms
+	}
+	adamw := &AdamW{Config: c}
+	adamw.IncExample() // initialize 'alpha' coefficient
+	return adamw
+}
+
+// Label returns the enumeration-like value which identifies this gradient descent method.
+func (o *AdamW) Label() int {
+	return gd.AdamW
+}
+
+// LearningRate returns the current step size.
+func (o *AdamW) LearningRate() mat.Float {
+	return o.StepSize
+}
+
+// SetLearningRate sets the step size.
+func (o *AdamW) SetLearningRate(lr mat.Float) {
+	o.StepSize = lr
+	o.updateAlpha()
+}
+
+const (
+	v    int = 0
+	m    int = 1
+	buf1 int = 2 // contains 'grads.ProdScalar(1.0 - beta1)'
+	buf2 int = 3 // contains 'grads.Prod(grads).ProdScalar(1.0 - beta2)'
+	buf3 int = 4
+)
+
+// NewSupport returns a new support structure with the given dimensions.
+func (o *AdamW) NewSupport(r, c int) *nn.Payload {
+	supp := make([]mat.Matrix, 5)
+	supp[v] = mat.NewEmptyDense(r, c)
+	supp[m] = mat.NewEmptyDense(r, c)
+	supp[buf1] = mat.NewEmptyDense(r, c)
+	supp[buf2] = mat.NewEmptyDense(r, c)
+	supp[buf3] = mat.NewEmptyDense(r, c)
+	return &nn.Payload{
+		Label: o.Label(),
+		Data:  supp,
+	}
+}
+
+// IncExample beats the occurrence of a new example.
+func (o *AdamW) IncExample() {
+	o.TimeStep++
+	o.updateAlpha()
+}
+
+func (o *AdamW) updateAlpha() {
+	o.Alpha = o.StepSize * mat.Sqrt(1.0-mat.Pow(o.Beta2, mat.Float(o.TimeStep))) / (1.0 - mat.Pow(o.Beta1, mat.Float(o.TimeStep)))
+}
+
+// decay returns the weight decay rate of the param.
+func (o *AdamW) decay(param nn.Param) mat.Float {
+	if o.WeightDecay == 0.0 || o.Exclude(param) {
+		return 0.0
+	}
+	return o.StepSize * o.WeightDecay
+}
+
+// Delta returns the difference between the current params and where the method wants it to be.
+func (o *AdamW) Delta(param nn.Param) mat.Matrix {
+	if grads, ok := param.Grad().(*mat.RowSparse); ok {
+		return o.DeltaRowSparse(param, grads)
+	}
+	delta := o.calcDelta(param.Grad(), gd.GetOrSetPayload(param, o).Data)
+	if decay := o.decay(param); decay != 0.0 {
+		scaled := param.Value().ProdScalar(decay)
+		defer mat.ReleaseMatrix(scaled)
+		delta.AddInPlace(scaled)
+	}
+	return delta
+}
+
+// v = v*beta1 + grads*(1.0-beta1)
+// m = m*beta2 + (grads*grads)*(1.0-beta2)
+// d = (v / (sqrt(m) + eps)) * alpha
+func (o *AdamW) calcDelta(grads mat.Matrix, supp []mat.Matrix) mat.Matrix {
+	supp[v].ProdScalarInPlace(o.Beta1)
+	supp[buf1].ProdMatrixScalarInPlace(grads, 1.0-o.Beta1)
+	supp[v].AddInPlace(supp[buf1])
+	supp[m].ProdScalarInPlace(o.Beta2)
+	sqGrad := grads.Prod(grads)
+	defer mat.ReleaseMatrix(sqGrad)
+	supp[buf2].ProdMatrixScalarInPlace(sqGrad, 1.0-o.Beta2)
+	supp[m].AddInPlace(supp[buf2])
+	buf := supp[m].Sqrt().AddScalarInPlace(o.Epsilon)
+	defer mat.ReleaseMatrix(buf)
+	suppDiv := supp[v].Div(buf)
+	defer mat.ReleaseMatrix(suppDiv)
+	supp[buf3].ProdMatrixScalarInPlace(suppDiv, o.Alpha)
+	return supp[buf3]
+}
+
+// DeltaRowSparse returns the difference between the rows of the current params with gradients and where the method
+// wants them to be. Only the corresponding rows of the support structure are updated, and decayed.
+func (o *AdamW) DeltaRowSparse(param nn.Param, grads *mat.RowSparse) *mat.RowSparse {
+	supp := gd.GetOrSetPayload(param, o).Data
+	decay := o.decay(param)
+	var values []mat.Float
+	if decay != 0.0 {
+		values = param.Value().Data()
+	}
+	cols := grads.Columns()
+	delta := mat.NewRowSparse(grads.Dims())
+	d := make([]mat.Float, cols)
+	for _, i := range grads.RowIndices() {
+		vi := supp[v].Data()[i*cols : (i+1)*cols]
+		mi := supp[m].Data()[i*cols : (i+1)*cols]
+		for j, gj := range grads.Row(i) {
+			vi[j] = vi[j]*o.Beta1 + gj*(1.0-o.Beta1)
+			mi[j] = mi[j]*o.Beta2 + gj*gj*(1.0-o.Beta2)
+			d[j] = vi[j] / (mat.Sqrt(mi[j]) + o.Epsilon) * o.Alpha
+			if decay != 0.0 {
+				d[j] += values[i*cols+j] * decay
+			}
+		}
+		delta.AddToRow(i, d)
+	}
+	return delta
+}
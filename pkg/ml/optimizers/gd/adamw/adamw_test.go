@@ -0,0 +1,89 @@
+// Copyright 2021 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package adamw
+
+import (
+	mat "github.com/nlpodyssey/spago/pkg/mat32"
+	"github.com/nlpodyssey/spago/pkg/ml/nn"
+	"github.com/nlpodyssey/spago/pkg/ml/optimizers/gd"
+	"github.com/nlpodyssey/spago/pkg/ml/optimizers/gd/adam"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func newTestParam(value mat.Matrix, pType nn.ParamsType) nn.Param {
+	param := nn.NewParam(value)
+	param.SetType(pType)
+	grads := value.ZerosLike()
+	for i := range grads.Data() {
+		grads.Data()[i] = mat.Float(i+1) * 0.1
+	}
+	param.PropagateGrad(grads)
+	return param
+}
+
+func TestAdamW_Delta(t *testing.T) {
+	updater := New(NewConfig(
+		0.001,  // step size
+		0.9,    // beta1
+		0.999,  // beta2
+		1.0e-8, // epsilon
+		0.1,    // weight decay
+	))
+	reference := adam.New(adam.NewConfig(0.001, 0.9, 0.999, 1.0e-8))
+
+	value := []mat.Float{0.4, -0.4, 0.5, 1.0, 0.8, 0.2}
+	expected := reference.Delta(newTestParam(mat.NewDense(2, 3, value), nn.Weights)).Data()
+	delta := updater.Delta(newTestParam(mat.NewDense(2, 3, value), nn.Weights)).Data()
+	for i, x := range value {
+		assert.InDelta(t, expected[i]+x*0.001*0.1, delta[i], 1.0e-7)
+	}
+
+	// the biases and the gains of the normalization layers are not decayed, unlike the weight vectors
+	for _, param := range []nn.Param{
+		newTestParam(mat.NewDense(2, 3, value), nn.Biases),
+		newTestParam(mat.NewVecDense(value), nn.Gains),
+	} {
+		assert.InDeltaSlice(t, expected, updater.Delta(param).Data(), 1.0e-7)
+	}
+	delta = updater.Delta(newTestParam(mat.NewVecDense(value), nn.Weights)).Data()
+	for i, x := range value {
+		assert.InDelta(t, expected[i]+x*0.001*0.1, delta[i], 1.0e-7)
+	}
+}
+
+func TestAdamW_DeltaRowSparse(t *testing.T) {
+	updater := New(NewDefaultConfig())
+	value := []mat.Float{0.4, -0.4, 0.5, 1.0, 0.8, 0.2}
+	dense := newTestParam(mat.NewDense(3, 2, value), nn.Undefined)
+
+	sparse := nn.NewParam(mat.NewDense(3, 2, value))
+	grads := mat.NewRowSparse(3, 2)
+	grads.AddToRow(1, []mat.Float{0.3, 0.4})
+	sparse.PropagateGrad(grads)
+
+	expected := updater.Delta(dense).Data()[2:4]
+	delta := updater.Delta(sparse).(*mat.RowSparse)
+	assert.Equal(t, []int{1}, delta.RowIndices())
+	assert.InDeltaSlice(t, expected, delta.Row(1), 1.0e-7)
+}
+
+type paramsList []nn.Param
+
+func (p paramsList) Params() []nn.Param {
+	return p
+}
+
+func TestAdamW_Checkpoint(t *testing.T) {
+	original := New(NewDefaultConfig())
+	original.IncExample()
+	checkpoint, err := gd.NewCheckpoint(gd.NewOptimizer(original, paramsList{}), nil)
+	assert.NoError(t, err)
+
+	resumed := New(NewDefaultConfig())
+	assert.NoError(t, checkpoint.Restore(gd.NewOptimizer(resumed, paramsList{}), nil))
+	assert.Equal(t, original.TimeStep, resumed.TimeStep)
+	assert.NotNil(t, resumed.Exclude)
+}
@@ -0,0 +1,175 @@
+// Copyright 2021 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gd
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/gob"
+	"fmt"
+	mat "github.com/nlpodyssey/spago/pkg/mat32"
+	"github.com/nlpodyssey/spago/pkg/mat32/rand"
+	"github.com/nlpodyssey/spago/pkg/ml/nn"
+	"github.com/nlpodyssey/spago/pkg/utils"
+)
+
+// Checkpoint contains the state of a training, which allows to resume an interrupted training exactly where
+// the checkpoint was taken: the state of the optimizer (the params of the model included, see
+// GradientDescent.MarshalBinary), the state of the random generator and the progress counters of the
+// training loop.
+type Checkpoint struct {
+	// Epoch is the number of completed epochs.
+	Epoch int
+	// Batch is the number of completed batches.
+	Batch int
+	// Example is the number of processed examples.
+	Example int
+	// Optimizer is the binary state of the optimizer.
+	Optimizer []byte
+	// Rand is the binary state of the random generator, if any.
+	Rand []byte
+}
+
+// NewCheckpoint returns a new Checkpoint with the current state of the optimizer and of the
+// random generator (which can be nil). The counters of the training loop are left to the caller.
+func NewCheckpoint(o *GradientDescent, rndGen *rand.LockedRand) (*Checkpoint, error) {
+	optimizer, err := o.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	c := &Checkpoint{Optimizer: optimizer}
+	if rndGen != nil {
+		if c.Rand, err = rndGen.MarshalBinary(); err != nil {
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+// Restore restores the state of the optimizer (the params of the model included) and of the
+// random generator (which can be nil).
+func (c *Checkpoint) Restore(o *GradientDescent, rndGen *rand.LockedRand) error {
+	if err := o.UnmarshalBinary(c.Optimizer); err != nil {
+		return err
+	}
+	if rndGen != nil && c.Rand != nil {
+		return rndGen.UnmarshalBinary(c.Rand)
+	}
+	return nil
+}
+
+// SaveCheckpoint saves the checkpoint to file.
+func SaveCheckpoint(filename string, c *Checkpoint) error {
+	return utils.SerializeToFile(filename, c)
+}
+
+// LoadCheckpoint loads a checkpoint from file.
+func LoadCheckpoint(filename string) (*Checkpoint, error) {
+	c := new(Checkpoint)
+	if err := utils.DeserializeFromFile(filename, c); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// optimizerState is the serializable state of a GradientDescent.
+type optimizerState struct {
+	// Params contains the binary encoding of each param (value and payload), in the order of the ParamsGetter.
+	Params [][]byte
+	// Method contains the gob encoding of the exported fields of the method (e.g. the Adam time step).
+	Method []byte
+	// LossScale and GoodSteps are the state of the loss scaling, if enabled.
+	LossScale mat.Float
+	GoodSteps int
+	// Schedulers contains the binary state of each scheduler which implements encoding.BinaryMarshaler,
+	// nil for the others.
+	Schedulers [][]byte
+}
+
+// MarshalBinary returns the state of the optimizer: the params to optimize with their support structures,
+// the state of the optimization method (i.e. its exported fields, counters included), the state of the
+// loss scaling and of the schedulers.
+func (o *GradientDescent) MarshalBinary() ([]byte, error) {
+	params := o.paramsGetter.Params()
+	state := optimizerState{
+		Params: make([][]byte, len(params)),
+	}
+	for i, param := range params {
+		buf := new(bytes.Buffer)
+		if err := nn.MarshalBinaryParam(param, buf); err != nil {
+			return nil, err
+		}
+		state.Params[i] = buf.Bytes()
+	}
+	method := new(bytes.Buffer)
+	if err := gob.NewEncoder(method).Encode(o.method); err != nil {
+		return nil, err
+	}
+	state.Method = method.Bytes()
+	if o.lossScaler != nil {
+		state.LossScale = o.lossScaler.scale
+		state.GoodSteps = o.lossScaler.goodSteps
+	}
+	state.Schedulers = make([][]byte, len(o.schedulers))
+	for i, scheduler := range o.schedulers {
+		if scheduler, ok := scheduler.(encoding.BinaryMarshaler); ok {
+			data, err := scheduler.MarshalBinary()
+			if err != nil {
+				return nil, err
+			}
+			state.Schedulers[i] = data
+		}
+	}
+	buf := new(bytes.Buffer)
+	if err := gob.NewEncoder(buf).Encode(state); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// UnmarshalBinary restores a state of the optimizer returned by MarshalBinary.
+// The optimizer must have the same method and the same params (in number, order and dimensions).
+// The gradients of the params are discarded.
+func (o *GradientDescent) UnmarshalBinary(data []byte) error {
+	var state optimizerState
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&state); err != nil {
+		return err
+	}
+	params := o.paramsGetter.Params()
+	if len(params) != len(state.Params) {
+		return fmt.Errorf("gd: checkpoint with %d params, %d expected", len(state.Params), len(params))
+	}
+	for i, param := range params {
+		rows, cols := param.Value().Dims()
+		param.ZeroGrad()
+		if err := nn.UnmarshalBinaryParamWithReceiver(bytes.NewReader(state.Params[i]), param); err != nil {
+			return err
+		}
+		if r, c := param.Value().Dims(); r != rows || c != cols {
+			return fmt.Errorf("gd: checkpoint param %d is %dx%d, %dx%d expected", i, r, c, rows, cols)
+		}
+	}
+	if err := gob.NewDecoder(bytes.NewReader(state.Method)).Decode(o.method); err != nil {
+		return err
+	}
+	if o.lossScaler != nil && state.LossScale != 0 {
+		o.lossScaler.scale = state.LossScale
+		o.lossScaler.goodSteps = state.GoodSteps
+	}
+	if state.Schedulers == nil {
+		return nil // saved without schedulers
+	}
+	if len(o.schedulers) != len(state.Schedulers) {
+		return fmt.Errorf("gd: checkpoint with %d schedulers, %d expected", len(state.Schedulers), len(o.schedulers))
+	}
+	for i, scheduler := range o.schedulers {
+		if scheduler, ok := scheduler.(encoding.BinaryUnmarshaler); ok && state.Schedulers[i] != nil {
+			if err := scheduler.UnmarshalBinary(state.Schedulers[i]); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,83 @@
+// Copyright 2021 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gd_test
+
+import (
+	mat "github.com/nlpodyssey/spago/pkg/mat32"
+	"github.com/nlpodyssey/spago/pkg/mat32/rand"
+	"github.com/nlpodyssey/spago/pkg/ml/nn"
+	"github.com/nlpodyssey/spago/pkg/ml/optimizers/gd"
+	"github.com/nlpodyssey/spago/pkg/ml/optimizers/gd/adam"
+	"github.com/stretchr/testify/assert"
+	"path/filepath"
+	"testing"
+)
+
+type checkpointTest struct {
+	param     nn.Param
+	rndGen    *rand.LockedRand
+	optimizer *gd.GradientDescent
+}
+
+func newCheckpointTest() *checkpointTest {
+	param := nn.NewParam(mat.NewDense(2, 2, []mat.Float{1.0, 2.0, 3.0, 4.0}))
+	return &checkpointTest{
+		param:  param,
+		rndGen: rand.NewLockedRand(42),
+		optimizer: gd.NewOptimizer(
+			adam.New(adam.NewDefaultConfig()),
+			paramsList{param},
+			gd.LossScaling(8.0, 2),
+		),
+	}
+}
+
+func (c *checkpointTest) step() {
+	grads := mat.NewEmptyDense(2, 2)
+	for i := range grads.Data() {
+		grads.Data()[i] = (c.rndGen.Float() - 0.5) * 8.0
+	}
+	c.param.PropagateGrad(grads)
+	c.optimizer.IncExample()
+	c.optimizer.Optimize()
+}
+
+func TestCheckpoint(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "checkpoint")
+
+	original := newCheckpointTest()
+	for i := 0; i < 5; i++ {
+		original.step()
+	}
+	checkpoint, err := gd.NewCheckpoint(original.optimizer, original.rndGen)
+	assert.NoError(t, err)
+	checkpoint.Example = 5
+	assert.NoError(t, gd.SaveCheckpoint(filename, checkpoint))
+	for i := 0; i < 5; i++ {
+		original.step()
+	}
+
+	resumed := newCheckpointTest()
+	loaded, err := gd.LoadCheckpoint(filename)
+	assert.NoError(t, err)
+	assert.Equal(t, 5, loaded.Example)
+	assert.NoError(t, loaded.Restore(resumed.optimizer, resumed.rndGen))
+	for i := 0; i < 5; i++ {
+		resumed.step()
+	}
+
+	assert.Equal(t, original.param.Value().Data(), resumed.param.Value().Data())
+	assert.Equal(t, original.param.Payload().Data[0].Data(), resumed.param.Payload().Data[0].Data())
+	assert.Equal(t, original.optimizer.LossScale(), resumed.optimizer.LossScale())
+}
+
+func TestCheckpoint_IncompatibleParams(t *testing.T) {
+	original := newCheckpointTest()
+	checkpoint, err := gd.NewCheckpoint(original.optimizer, nil)
+	assert.NoError(t, err)
+
+	other := gd.NewOptimizer(adam.New(adam.NewDefaultConfig()), paramsList{})
+	assert.Error(t, checkpoint.Restore(other, nil))
+}
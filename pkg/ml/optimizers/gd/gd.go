@@ -19,6 +19,10 @@ type GradientDescent struct {
 	gradClipper      clipper.GradClipper
 	paramsGetter     nn.ParamsGetter
 	paramsToOptimize []nn.Param
+	// lossScaler is used for the loss scaling (default nil, disabled).
+	lossScaler *lossScaler
+	// schedulers are notified of the new epochs, batches and examples after the method.
+	schedulers []Scheduler
 	// processingQueue allows proper handling for computationally heavy operations
 	// such as the params update step.
 	// The default size is defaultProcessingQueueSize.
@@ -61,6 +65,14 @@ func ConcurrentComputations(value int) Option {
 	}
 }
 
+// Schedulers is an option to notify the schedulers (e.g. a learning rate scheduler) of the occurrence of new
+// epochs, batches and examples, after the optimization method, in order.
+func Schedulers(schedulers ...Scheduler) Option {
+	return func(f *GradientDescent) {
+		f.schedulers = append(f.schedulers, schedulers...)
+	}
+}
+
 // NewOptimizer returns a new GradientDescent optimizer. The gradient clipper can be set to nil.
 func NewOptimizer(method Method, paramsIterator nn.ParamsGetter, opts ...Option) *GradientDescent {
 	optimizer := &GradientDescent{
@@ -82,6 +94,11 @@ func (o *GradientDescent) Optimize() {
 	if o.paramsToOptimize == nil {
 		return
 	}
+	if o.lossScaler != nil && !o.lossScaler.unscaleGrads(o.paramsToOptimize) {
+		o.zeroGrads() // the gradients overflowed: skip the update
+		o.paramsToOptimize = nil
+		return
+	}
 	o.clipGrads()
 	o.updateParams()
 	o.paramsToOptimize = nil
@@ -91,7 +108,7 @@ func (o *GradientDescent) Optimize() {
 func (o *GradientDescent) updateParamsSerial() {
 	for _, param := range o.paramsToOptimize {
 		if param.HasGrad() {
-			delta := o.method.Delta(param) // important: don't release delta here
+			delta := o.delta(param) // important: don't release delta here
 			param.ApplyDelta(delta)
 			param.ZeroGrad()
 		}
@@ -109,7 +126,7 @@ func (o *GradientDescent) updateParams() {
 		go func(param nn.Param) {
 			defer wg.Done()
 			o.processingQueue.Run(func() {
-				delta := o.method.Delta(param)
+				delta := o.delta(param)
 				param.ApplyDelta(delta)
 			})
 			param.ZeroGrad()
@@ -118,6 +135,30 @@ func (o *GradientDescent) updateParams() {
 	wg.Wait()
 }
 
+// delta returns the delta of the param computed by the optimization method.
+// The row-sparse gradients are converted to dense gradients if the method is not a RowSparseMethod.
+func (o *GradientDescent) delta(param nn.Param) mat.Matrix {
+	grads, ok := param.Grad().(*mat.RowSparse)
+	if !ok {
+		return o.method.Delta(param)
+	}
+	if method, ok := o.method.(RowSparseMethod); ok {
+		return method.DeltaRowSparse(param, grads)
+	}
+	dense := grads.ToDense()
+	defer mat.ReleaseDense(dense)
+	param.ZeroGrad()
+	param.PropagateGrad(dense)
+	return o.method.Delta(param)
+}
+
+// zeroGrads sets the gradients of all the observed parameters to zero.
+func (o *GradientDescent) zeroGrads() {
+	for _, param := range o.paramsToOptimize {
+		param.ZeroGrad()
+	}
+}
+
 // clipGrad applies the gradient clipping to all the observed parameters.
 func (o *GradientDescent) clipGrads() {
 	if o.gradClipper == nil {
@@ -137,6 +178,11 @@ func (o *GradientDescent) IncExample() {
 	if method, ok := o.method.(ExampleScheduler); ok {
 		method.IncExample()
 	}
+	for _, scheduler := range o.schedulers {
+		if scheduler, ok := scheduler.(ExampleScheduler); ok {
+			scheduler.IncExample()
+		}
+	}
 }
 
 // IncBatch beats the occurrence of a new batch.
@@ -144,6 +190,11 @@ func (o *GradientDescent) IncBatch() {
 	if method, ok := o.method.(BatchScheduler); ok {
 		method.IncBatch()
 	}
+	for _, scheduler := range o.schedulers {
+		if scheduler, ok := scheduler.(BatchScheduler); ok {
+			scheduler.IncBatch()
+		}
+	}
 }
 
 // IncEpoch beats the occurrence of a new epoch.
@@ -151,4 +202,9 @@ func (o *GradientDescent) IncEpoch() {
 	if method, ok := o.method.(EpochScheduler); ok {
 		method.IncEpoch()
 	}
+	for _, scheduler := range o.schedulers {
+		if scheduler, ok := scheduler.(EpochScheduler); ok {
+			scheduler.IncEpoch()
+		}
+	}
 }
@@ -0,0 +1,33 @@
+// Copyright 2021 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gd_test
+
+import (
+	mat "github.com/nlpodyssey/spago/pkg/mat32"
+	"github.com/nlpodyssey/spago/pkg/ml/nn"
+	"github.com/nlpodyssey/spago/pkg/ml/optimizers/gd"
+	"github.com/nlpodyssey/spago/pkg/ml/optimizers/gd/rmsprop"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestGradientDescent_RowSparseGradsWithDenseMethod(t *testing.T) {
+	sparse := nn.NewParam(mat.NewDense(2, 2, []mat.Float{1.0, 2.0, 3.0, 4.0}))
+	dense := nn.NewParam(mat.NewDense(2, 2, []mat.Float{1.0, 2.0, 3.0, 4.0}))
+	newOptimizer := func(p nn.Param) *gd.GradientDescent {
+		return gd.NewOptimizer(rmsprop.New(rmsprop.NewConfig(0.1, 1.0e-8, 0.9)), paramsList{p})
+	}
+	sparseOptimizer, denseOptimizer := newOptimizer(sparse), newOptimizer(dense)
+
+	grads := mat.NewRowSparse(2, 2)
+	grads.AddToRow(1, []mat.Float{0.5, -0.5})
+	sparse.PropagateGrad(grads)
+	dense.PropagateGrad(grads.ToDense())
+	sparseOptimizer.Optimize()
+	denseOptimizer.Optimize()
+
+	assert.InDeltaSlice(t, dense.Value().Data(), sparse.Value().Data(), 1.0e-6)
+	assert.False(t, sparse.HasGrad())
+}
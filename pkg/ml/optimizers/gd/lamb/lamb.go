@@ -0,0 +1,155 @@
+// Copyright 2021 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package lamb
+
+import (
+	mat "github.com/nlpodyssey/spago/pkg/mat32"
+	"github.com/nlpodyssey/spago/pkg/ml/nn"
+	"github.com/nlpodyssey/spago/pkg/ml/optimizers/gd"
+)
+
+var _ gd.MethodConfig = &Config{}
+
+// Config provides configuration settings for a LAMB optimizer.
+type Config struct {
+	gd.MethodConfig
+	StepSize    mat.Float
+	Beta1       mat.Float
+	Beta2       mat.Float
+	Epsilon     mat.Float
+	WeightDecay mat.Float
+	// Exclude reports whether a param is excluded from both the weight decay and the layer-wise adaptation
+	// of the step size. Nil means gd.ExcludeBiasesAndNorms.
+	Exclude func(param nn.Param) bool
+}
+
+// NewConfig returns a new LAMB Config.
+// It panics if beta1 or beta2 are not in the range [0.0, 1.0).
+func NewConfig(stepSize, beta1, beta2, epsilon, weightDecay mat.Float) Config {
+	if !(beta1 >= 0.0 && beta1 < 1.0) {
+		panic("lamb: `beta1` must be in the range [0.0, 1.0)")
+	}
+	if !(beta2 >= 0.0 && beta2 < 1.0) {
+		panic("lamb: `beta2` must be in the range [0.0, 1.0)")
+	}
+	return Config{
+		StepSize:    stepSize,
+		Beta1:       beta1,
+		Beta2:       beta2,
+		Epsilon:     epsilon,
+		WeightDecay: weightDecay,
+	}
+}
+
+// NewDefaultConfig returns a new Config with generically reasonable default values.
+func NewDefaultConfig() Config {
+	return Config{
+		StepSize:    0.001,
+		Beta1:       0.9,
+		Beta2:       0.999,
+		Epsilon:     1.0e-6,
+		WeightDecay: 0.01,
+	}
+}
+
+var _ gd.LearningRateMethod = &LAMB{}
+
+// LAMB (Layer-wise Adaptive Moments for Batch training) implements a variant of AdamW for large batches:
+// the update of each param is rescaled by the trust ratio ||param|| / ||update||.
+// The time step of the bias correction is incremented at each new batch.
+// References:
+//     Large Batch Optimization for Deep Learning: Training BERT in 76 minutes
+//     https://arxiv.org/abs/1904.00962
+type LAMB struct {
+	Config
+	TimeStep int
+}
+
+// New returns a new LAMB optimizer, initialized according to the given configuration.
+func New(c Config) *LAMB {
+	if c.Exclude == nil {
+		c.Exclude = gd.ExcludeBiasesAndNorms
+	}
+	return &LAMB{Config: c}
+}
+
+// Label returns the enumeration-like value which identifies this gradient descent method.
+func (o *LAMB) Label() int {
+	return gd.LAMB
+}
+
+// LearningRate returns the current step size.
+func (o *LAMB) LearningRate() mat.Float {
+	return o.StepSize
+}
+
+// SetLearningRate sets the step size.
+func (o *LAMB) SetLearningRate(lr mat.Float) {
+	o.StepSize = lr
+}
+
+const (
+	m     int = 0
+	v     int = 1
+	delta int = 2
+)
+
+// NewSupport returns a new support structure with the given dimensions.
+func (o *LAMB) NewSupport(r, c int) *nn.Payload {
+	supp := make([]mat.Matrix, 3)
+	supp[m] = mat.NewEmptyDense(r, c)
+	supp[v] = mat.NewEmptyDense(r, c)
+	supp[delta] = mat.NewEmptyDense(r, c)
+	return &nn.Payload{
+		Label: o.Label(),
+		Data:  supp,
+	}
+}
+
+// IncBatch beats the occurrence of a new batch.
+func (o *LAMB) IncBatch() {
+	o.TimeStep++
+}
+
+// Delta returns the difference between the current params and where the method wants it to be.
+func (o *LAMB) Delta(param nn.Param) mat.Matrix {
+	return o.calcDelta(param, gd.GetOrSetPayload(param, o).Data)
+}
+
+// m = m*beta1 + grads*(1.0-beta1)
+// v = v*beta2 + (grads*grads)*(1.0-beta2)
+// r = (m / (1.0-beta1^t)) / (sqrt(v / (1.0-beta2^t)) + eps) + param*weightDecay
+// d = r * stepSize * ||param|| / ||r||
+func (o *LAMB) calcDelta(param nn.Param, supp []mat.Matrix) mat.Matrix {
+	timeStep := mat.Float(o.TimeStep)
+	if timeStep < 1 {
+		timeStep = 1
+	}
+	b1T := 1.0 - mat.Pow(o.Beta1, timeStep)
+	b2T := 1.0 - mat.Pow(o.Beta2, timeStep)
+	excluded := o.Exclude(param)
+	weightDecay := o.WeightDecay
+	if excluded {
+		weightDecay = 0.0
+	}
+
+	values := param.Value().Data()
+	mData, vData, d := supp[m].Data(), supp[v].Data(), supp[delta].Data()
+	var paramNorm, updateNorm mat.Float
+	for i, g := range param.Grad().Data() {
+		mData[i] = mData[i]*o.Beta1 + g*(1.0-o.Beta1)
+		vData[i] = vData[i]*o.Beta2 + g*g*(1.0-o.Beta2)
+		d[i] = (mData[i]/b1T)/(mat.Sqrt(vData[i]/b2T)+o.Epsilon) + values[i]*weightDecay
+		paramNorm += values[i] * values[i]
+		updateNorm += d[i] * d[i]
+	}
+
+	stepSize := o.StepSize
+	if !excluded && paramNorm > 0.0 && updateNorm > 0.0 {
+		stepSize *= mat.Sqrt(paramNorm) / mat.Sqrt(updateNorm)
+	}
+	supp[delta].ProdScalarInPlace(stepSize)
+	return supp[delta]
+}
@@ -0,0 +1,37 @@
+// Copyright 2021 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package lamb
+
+import (
+	mat "github.com/nlpodyssey/spago/pkg/mat32"
+	"github.com/nlpodyssey/spago/pkg/ml/nn"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func newTestParam(pType nn.ParamsType) nn.Param {
+	param := nn.NewParam(mat.NewDense(1, 5, []mat.Float{0.4, 0.4, 0.5, 1.0, 0.8}))
+	param.SetType(pType)
+	param.PropagateGrad(mat.NewDense(1, 5, []mat.Float{0.9, 0.7, 0.4, 0.8, 0.1}))
+	return param
+}
+
+func TestLAMB_Delta(t *testing.T) {
+	updater := New(NewConfig(
+		0.001,  // step size
+		0.9,    // beta1
+		0.999,  // beta2
+		1.0e-6, // epsilon
+		0.01,   // weight decay
+	))
+	updater.IncBatch()
+
+	delta := updater.Delta(newTestParam(nn.Weights))
+	assert.InDeltaSlice(t, []mat.Float{0.00066338, 0.00066338, 0.00066404, 0.00066734, 0.00066601}, delta.Data(), 1.0e-7)
+
+	// no weight decay and no trust ratio
+	delta = updater.Delta(newTestParam(nn.Biases))
+	assert.InDeltaSlice(t, []mat.Float{0.001, 0.001, 0.001, 0.001, 0.00099999}, delta.Data(), 1.0e-7)
+}
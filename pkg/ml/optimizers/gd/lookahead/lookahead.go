@@ -0,0 +1,192 @@
+// Copyright 2021 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package lookahead
+
+import (
+	"bytes"
+	"encoding/gob"
+	mat "github.com/nlpodyssey/spago/pkg/mat32"
+	"github.com/nlpodyssey/spago/pkg/ml/nn"
+	"github.com/nlpodyssey/spago/pkg/ml/optimizers/gd"
+)
+
+var _ gd.MethodConfig = &Config{}
+
+// Config provides configuration settings for a Lookahead optimizer.
+type Config struct {
+	gd.MethodConfig
+	// Method is the configuration of the inner optimization method.
+	Method gd.MethodConfig
+	// K is the number of updates of the fast weights before each update of the slow weights.
+	K int
+	// Alpha is the step size of the slow weights towards the fast weights.
+	Alpha mat.Float
+}
+
+// NewConfig returns a new Lookahead Config.
+// It panics if k < 1 or alpha is not in the range (0.0, 1.0].
+func NewConfig(method gd.MethodConfig, k int, alpha mat.Float) Config {
+	if k < 1 {
+		panic("lookahead: `k` must be >= 1")
+	}
+	if !(alpha > 0.0 && alpha <= 1.0) {
+		panic("lookahead: `alpha` must be in the range (0.0, 1.0]")
+	}
+	return Config{
+		Method: method,
+		K:      k,
+		Alpha:  alpha,
+	}
+}
+
+// NewDefaultConfig returns a new Config of the inner method with generically reasonable default values.
+func NewDefaultConfig(method gd.MethodConfig) Config {
+	return Config{
+		Method: method,
+		K:      5,
+		Alpha:  0.5,
+	}
+}
+
+var _ gd.LearningRateMethod = &Lookahead{}
+
+// Lookahead wraps an optimization method (the fast weights are updated by the inner method), and every K
+// updates moves the slow weights by Alpha towards the fast weights, which are then reset to the slow weights.
+// The updates are counted at each new batch.
+// The support structure is the one of the inner method, with the slow weights and the delta appended, and has
+// its label.
+// References:
+//     Lookahead Optimizer: k steps forward, 1 step back
+//     https://arxiv.org/abs/1907.08610
+type Lookahead struct {
+	Config
+	TimeStep int
+	method   gd.Method
+	// innerSize is the size of the support structure of the inner method.
+	innerSize int
+}
+
+// New returns a new Lookahead optimizer of the inner method, initialized according to the given configuration.
+// The inner method must not be used by other optimizers.
+func New(c Config, method gd.Method) *Lookahead {
+	return &Lookahead{
+		Config:    c,
+		method:    method,
+		innerSize: len(method.NewSupport(0, 0).Data),
+	}
+}
+
+// Label returns the enumeration-like value which identifies the inner gradient descent method.
+func (o *Lookahead) Label() int {
+	return o.method.Label()
+}
+
+// Method returns the inner optimization method.
+func (o *Lookahead) Method() gd.Method {
+	return o.method
+}
+
+// LearningRate returns the learning rate of the inner method.
+// It panics if the inner method is not a gd.LearningRateMethod.
+func (o *Lookahead) LearningRate() mat.Float {
+	return o.method.(gd.LearningRateMethod).LearningRate()
+}
+
+// SetLearningRate sets the learning rate of the inner method.
+// It panics if the inner method is not a gd.LearningRateMethod.
+func (o *Lookahead) SetLearningRate(lr mat.Float) {
+	o.method.(gd.LearningRateMethod).SetLearningRate(lr)
+}
+
+// NewSupport returns a new support structure with the given dimensions.
+func (o *Lookahead) NewSupport(r, c int) *nn.Payload {
+	payload := o.method.NewSupport(r, c)
+	payload.Data = append(payload.Data, mat.NewEmptyDense(r, c), mat.NewEmptyDense(r, c))
+	return payload
+}
+
+// support returns the slow weights of the param, initialized to its value the first time, and the
+// buffer of the delta towards them.
+func (o *Lookahead) support(param nn.Param) (slow, delta mat.Matrix) {
+	payload := param.Payload()
+	if payload == nil || payload.Label == gd.None {
+		payload = gd.GetOrSetPayload(param, o)
+		payload.Data[o.innerSize].SetData(param.Value().Data())
+		return payload.Data[o.innerSize], payload.Data[o.innerSize+1]
+	}
+	if payload.Label != o.Label() {
+		panic("lookahead: support structure non compatible with the optimization method")
+	}
+	if len(payload.Data) == o.innerSize {
+		// support structure of the inner method alone (e.g. restored from a checkpoint without lookahead)
+		value := param.Value()
+		payload.Data = append(payload.Data, value.Clone(), value.ZerosLike())
+	}
+	return payload.Data[o.innerSize], payload.Data[o.innerSize+1]
+}
+
+// Delta returns the difference between the current params and where the method wants it to be.
+func (o *Lookahead) Delta(param nn.Param) mat.Matrix {
+	slow, buf := o.support(param)
+	delta := o.method.Delta(param)
+	if o.TimeStep == 0 || o.TimeStep%o.K != 0 {
+		return delta
+	}
+	// fast = param - delta
+	// slow = slow + (fast - slow) * alpha
+	// delta = param - slow
+	value := param.Value()
+	fast := value.Sub(delta)
+	defer mat.ReleaseMatrix(fast)
+	fast.SubInPlace(slow)
+	fast.ProdScalarInPlace(o.Alpha)
+	slow.AddInPlace(fast)
+	buf.SetData(value.Data())
+	return buf.SubInPlace(slow)
+}
+
+// IncExample beats the occurrence of a new example.
+func (o *Lookahead) IncExample() {
+	if method, ok := o.method.(gd.ExampleScheduler); ok {
+		method.IncExample()
+	}
+}
+
+// IncBatch beats the occurrence of a new batch.
+func (o *Lookahead) IncBatch() {
+	o.TimeStep++
+	if method, ok := o.method.(gd.BatchScheduler); ok {
+		method.IncBatch()
+	}
+}
+
+// IncEpoch beats the occurrence of a new epoch.
+func (o *Lookahead) IncEpoch() {
+	if method, ok := o.method.(gd.EpochScheduler); ok {
+		method.IncEpoch()
+	}
+}
+
+// GobEncode encodes the state of the optimizer: the time step and the state of the inner method.
+func (o *Lookahead) GobEncode() ([]byte, error) {
+	buf := new(bytes.Buffer)
+	enc := gob.NewEncoder(buf)
+	if err := enc.Encode(o.TimeStep); err != nil {
+		return nil, err
+	}
+	if err := enc.Encode(o.method); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// GobDecode decodes a state of the optimizer encoded by GobEncode.
+func (o *Lookahead) GobDecode(data []byte) error {
+	dec := gob.NewDecoder(bytes.NewReader(data))
+	if err := dec.Decode(&o.TimeStep); err != nil {
+		return err
+	}
+	return dec.Decode(o.method)
+}
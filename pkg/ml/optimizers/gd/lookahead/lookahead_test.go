@@ -0,0 +1,63 @@
+// Copyright 2021 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package lookahead
+
+import (
+	mat "github.com/nlpodyssey/spago/pkg/mat32"
+	"github.com/nlpodyssey/spago/pkg/ml/nn"
+	"github.com/nlpodyssey/spago/pkg/ml/optimizers/gd"
+	"github.com/nlpodyssey/spago/pkg/ml/optimizers/gd/adam"
+	"github.com/nlpodyssey/spago/pkg/ml/optimizers/gd/sgd"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+type paramsList []nn.Param
+
+func (p paramsList) Params() []nn.Param {
+	return p
+}
+
+func TestLookahead_Optimize(t *testing.T) {
+	param := nn.NewParam(mat.NewScalar(0))
+	method := New(NewConfig(sgd.NewConfig(1, 0, false), 2, 0.5), sgd.New(sgd.NewConfig(1, 0, false)))
+	optimizer := gd.NewOptimizer(method, paramsList{param})
+
+	var values []mat.Float
+	for i := 0; i < 4; i++ {
+		optimizer.IncBatch()
+		param.PropagateGrad(mat.NewScalar(1))
+		optimizer.Optimize()
+		values = append(values, param.ScalarValue())
+	}
+	// every two steps the param is reset to the slow weights, moved halfway towards the fast weights
+	assert.Equal(t, []mat.Float{-1, -1, -2, -2}, values)
+	assert.Equal(t, gd.SGD, param.Payload().Label)
+	assert.Equal(t, mat.Float(-2), param.Payload().Data[1].Scalar())
+}
+
+func TestLookahead_Checkpoint(t *testing.T) {
+	newOptimizer := func() (*gd.GradientDescent, *Lookahead, nn.Param) {
+		param := nn.NewParam(mat.NewDense(1, 2, []mat.Float{1, 2}))
+		method := New(NewDefaultConfig(adam.NewDefaultConfig()), adam.New(adam.NewDefaultConfig()))
+		return gd.NewOptimizer(method, paramsList{param}), method, param
+	}
+	original, method, param := newOptimizer()
+	for i := 0; i < 3; i++ {
+		original.IncBatch()
+		original.IncExample()
+		param.PropagateGrad(mat.NewDense(1, 2, []mat.Float{0.5, -0.5}))
+		original.Optimize()
+	}
+	checkpoint, err := gd.NewCheckpoint(original, nil)
+	assert.NoError(t, err)
+
+	resumed, resumedMethod, resumedParam := newOptimizer()
+	assert.NoError(t, checkpoint.Restore(resumed, nil))
+	assert.Equal(t, 3, resumedMethod.TimeStep)
+	assert.Equal(t, method.Method().(*adam.Adam).TimeStep, resumedMethod.Method().(*adam.Adam).TimeStep)
+	assert.Equal(t, param.Value().Data(), resumedParam.Value().Data())
+	assert.Len(t, resumedParam.Payload().Data, 7)
+}
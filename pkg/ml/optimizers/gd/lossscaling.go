@@ -0,0 +1,100 @@
+// Copyright 2021 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gd
+
+import (
+	mat "github.com/nlpodyssey/spago/pkg/mat32"
+	"github.com/nlpodyssey/spago/pkg/ml/nn"
+)
+
+// lossScaler implements the (dynamic) loss scaling, which prevents small gradients from
+// vanishing when the params are stored in half precision.
+type lossScaler struct {
+	scale          mat.Float
+	growthInterval int
+	goodSteps      int
+}
+
+// LossScaling is an option to enable the loss scaling: the loss is multiplied by a scale factor before
+// the back-propagation, and the gradients are divided by the same factor before the update of the params.
+// The backward step must start from the scaled loss, for example:
+//
+//     g.Backward(loss, ag.OutputGrad(mat.NewScalar(optimizer.LossScale())))
+//
+// If the growthInterval is greater than zero, the scale is dynamic: whenever the gradients overflow
+// (i.e. they contain Inf or NaN values) the update is skipped and the scale is halved; after growthInterval
+// consecutive updates without overflows, the scale is doubled.
+func LossScaling(initScale mat.Float, growthInterval int) Option {
+	if initScale <= 0 {
+		panic("gd: the loss scale must be greater than zero")
+	}
+	return func(f *GradientDescent) {
+		f.lossScaler = &lossScaler{
+			scale:          initScale,
+			growthInterval: growthInterval,
+		}
+	}
+}
+
+// LossScale returns the current scale factor of the loss, or 1 if the loss scaling is disabled.
+func (o *GradientDescent) LossScale() mat.Float {
+	if o.lossScaler == nil {
+		return 1.0
+	}
+	return o.lossScaler.scale
+}
+
+// unscaleGrads divides the gradients of the params by the loss scale, and updates the scale.
+// It returns false if the gradients overflowed, so the update must be skipped.
+func (s *lossScaler) unscaleGrads(params []nn.Param) bool {
+	overflow := false
+	for _, param := range params {
+		if !param.HasGrad() {
+			continue
+		}
+		grad := param.Grad()
+		grad.ProdScalarInPlace(1.0 / s.scale)
+		if !overflow && hasInfOrNaN(grad) {
+			overflow = true
+		}
+	}
+	if s.growthInterval <= 0 {
+		return !overflow
+	}
+	if overflow {
+		s.scale /= 2.0
+		s.goodSteps = 0
+		return false
+	}
+	s.goodSteps++
+	if s.goodSteps == s.growthInterval {
+		s.scale *= 2.0
+		s.goodSteps = 0
+	}
+	return true
+}
+
+// hasInfOrNaN reports whether the matrix contains infinite or NaN values.
+// Only the stored rows of a row-sparse matrix are checked.
+func hasInfOrNaN(m mat.Matrix) bool {
+	if s, ok := m.(*mat.RowSparse); ok {
+		for _, i := range s.RowIndices() {
+			if hasInfOrNaNValues(s.Row(i)) {
+				return true
+			}
+		}
+		return false
+	}
+	return hasInfOrNaNValues(m.Data())
+}
+
+func hasInfOrNaNValues(values []mat.Float) bool {
+	for _, v := range values {
+		if mat.IsInf(v, 0) || mat.IsNaN(v) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,59 @@
+// Copyright 2021 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gd_test
+
+import (
+	mat "github.com/nlpodyssey/spago/pkg/mat32"
+	"github.com/nlpodyssey/spago/pkg/ml/nn"
+	"github.com/nlpodyssey/spago/pkg/ml/optimizers/gd"
+	"github.com/nlpodyssey/spago/pkg/ml/optimizers/gd/sgd"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+type paramsList []nn.Param
+
+func (p paramsList) Params() []nn.Param {
+	return p
+}
+
+func TestGradientDescent_LossScale(t *testing.T) {
+	p := nn.NewParam(mat.NewVecDense([]mat.Float{1.0, 2.0}))
+	optimizer := gd.NewOptimizer(sgd.New(sgd.NewConfig(0.1, 0.0, false)), paramsList{p})
+	assert.Equal(t, mat.Float(1.0), optimizer.LossScale())
+}
+
+func TestGradientDescent_LossScaling(t *testing.T) {
+	p := nn.NewParam(mat.NewVecDense([]mat.Float{1.0, 2.0}))
+	optimizer := gd.NewOptimizer(
+		sgd.New(sgd.NewConfig(0.1, 0.0, false)),
+		paramsList{p},
+		gd.LossScaling(1024.0, 2),
+	)
+	assert.Equal(t, mat.Float(1024.0), optimizer.LossScale())
+
+	// the gradients are unscaled before the update
+	p.PropagateGrad(mat.NewVecDense([]mat.Float{1024.0, -2048.0}))
+	optimizer.Optimize()
+	assert.InDeltaSlice(t, []mat.Float{0.9, 2.2}, p.Value().Data(), 1.0e-6)
+	assert.False(t, p.HasGrad())
+	assert.Equal(t, mat.Float(1024.0), optimizer.LossScale())
+
+	// the scale is doubled after growthInterval steps without overflows
+	p.PropagateGrad(mat.NewVecDense([]mat.Float{0.0, 0.0}))
+	optimizer.Optimize()
+	assert.Equal(t, mat.Float(2048.0), optimizer.LossScale())
+
+	// the update is skipped and the scale is halved on overflow
+	p.PropagateGrad(mat.NewVecDense([]mat.Float{mat.Inf(1), 1.0}))
+	optimizer.Optimize()
+	assert.InDeltaSlice(t, []mat.Float{0.9, 2.2}, p.Value().Data(), 1.0e-6)
+	assert.False(t, p.HasGrad())
+	assert.Equal(t, mat.Float(1024.0), optimizer.LossScale())
+}
+
+func TestLossScaling_InvalidScale(t *testing.T) {
+	assert.Panics(t, func() { gd.LossScaling(0.0, 0) })
+}
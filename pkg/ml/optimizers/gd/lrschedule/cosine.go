@@ -0,0 +1,82 @@
+// Copyright 2021 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package lrschedule
+
+import mat "github.com/nlpodyssey/spago/pkg/mat32"
+
+var _ Schedule = &Cosine{}
+
+// Cosine is a linear warmup from 0 to the learning rate over the warmup steps, followed by a cosine decay
+// with the given number of cycles over the remaining steps (0.5 cycles decay to 0 at the total number of steps):
+//     lr * 0.5 * (1 + cos(2 * pi * cycles * progress))
+// where progress is the ratio of the steps after the warmup.
+type Cosine struct {
+	lr          mat.Float
+	warmupSteps int
+	totalSteps  int
+	cycles      mat.Float
+}
+
+// NewCosine returns a new Cosine schedule.
+// It panics if warmupSteps < 0 or totalSteps < warmupSteps.
+func NewCosine(lr mat.Float, warmupSteps, totalSteps int, cycles mat.Float) *Cosine {
+	checkSteps(warmupSteps, totalSteps)
+	return &Cosine{
+		lr:          lr,
+		warmupSteps: warmupSteps,
+		totalSteps:  totalSteps,
+		cycles:      cycles,
+	}
+}
+
+// LearningRate returns the learning rate at the given step, starting from 0.
+func (c *Cosine) LearningRate(step int) mat.Float {
+	if factor, ok := warmup(step, c.warmupSteps); ok {
+		return c.lr * factor
+	}
+	p := progress(step, c.warmupSteps, c.totalSteps)
+	return c.lr * mat.Max(0, 0.5*(1+mat.Cos(2*mat.Pi*c.cycles*p)))
+}
+
+var _ Schedule = &CosineWithRestarts{}
+
+// CosineWithRestarts is a linear warmup from 0 to the learning rate over the warmup steps, followed by the
+// given number of cosine decays to 0 over the remaining steps, each one restarting from the learning rate
+// (SGDR without the increase of the period).
+type CosineWithRestarts struct {
+	lr          mat.Float
+	warmupSteps int
+	totalSteps  int
+	cycles      int
+}
+
+// NewCosineWithRestarts returns a new CosineWithRestarts schedule.
+// It panics if warmupSteps < 0, totalSteps < warmupSteps or cycles < 1.
+func NewCosineWithRestarts(lr mat.Float, warmupSteps, totalSteps, cycles int) *CosineWithRestarts {
+	checkSteps(warmupSteps, totalSteps)
+	if cycles < 1 {
+		panic("lrschedule: the number of cycles must be >= 1")
+	}
+	return &CosineWithRestarts{
+		lr:          lr,
+		warmupSteps: warmupSteps,
+		totalSteps:  totalSteps,
+		cycles:      cycles,
+	}
+}
+
+// LearningRate returns the learning rate at the given step, starting from 0.
+func (c *CosineWithRestarts) LearningRate(step int) mat.Float {
+	if factor, ok := warmup(step, c.warmupSteps); ok {
+		return c.lr * factor
+	}
+	p := progress(step, c.warmupSteps, c.totalSteps)
+	if p >= 1 {
+		return 0
+	}
+	cycleProgress := mat.Float(c.cycles) * p
+	cycleProgress -= mat.Floor(cycleProgress)
+	return c.lr * 0.5 * (1 + mat.Cos(mat.Pi*cycleProgress))
+}
@@ -0,0 +1,63 @@
+// Copyright 2021 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package lrschedule
+
+import mat "github.com/nlpodyssey/spago/pkg/mat32"
+
+var _ Schedule = &Linear{}
+
+// Linear is a linear warmup from 0 to the learning rate over the warmup steps, followed by a linear decay
+// to 0 at the total number of steps:
+//     lr * step / warmup                            if step < warmup
+//     lr * (total - step) / (total - warmup)        otherwise
+type Linear struct {
+	lr          mat.Float
+	warmupSteps int
+	totalSteps  int
+}
+
+// NewLinear returns a new Linear schedule.
+// It panics if warmupSteps < 0 or totalSteps < warmupSteps.
+func NewLinear(lr mat.Float, warmupSteps, totalSteps int) *Linear {
+	checkSteps(warmupSteps, totalSteps)
+	return &Linear{
+		lr:          lr,
+		warmupSteps: warmupSteps,
+		totalSteps:  totalSteps,
+	}
+}
+
+// LearningRate returns the learning rate at the given step, starting from 0.
+func (l *Linear) LearningRate(step int) mat.Float {
+	if factor, ok := warmup(step, l.warmupSteps); ok {
+		return l.lr * factor
+	}
+	return l.lr * (1 - progress(step, l.warmupSteps, l.totalSteps))
+}
+
+var _ Schedule = &ConstantWithWarmup{}
+
+// ConstantWithWarmup is a linear warmup from 0 to the learning rate over the warmup steps, after which the
+// learning rate is constant.
+type ConstantWithWarmup struct {
+	lr          mat.Float
+	warmupSteps int
+}
+
+// NewConstantWithWarmup returns a new ConstantWithWarmup schedule.
+// It panics if warmupSteps < 0.
+func NewConstantWithWarmup(lr mat.Float, warmupSteps int) *ConstantWithWarmup {
+	checkSteps(warmupSteps, warmupSteps)
+	return &ConstantWithWarmup{
+		lr:          lr,
+		warmupSteps: warmupSteps,
+	}
+}
+
+// LearningRate returns the learning rate at the given step, starting from 0.
+func (c *ConstantWithWarmup) LearningRate(step int) mat.Float {
+	factor, _ := warmup(step, c.warmupSteps)
+	return c.lr * factor
+}
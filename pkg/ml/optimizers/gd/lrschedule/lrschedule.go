@@ -0,0 +1,146 @@
+// Copyright 2021 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package lrschedule provides learning rate schedules, such as the linear warmup followed by a linear or cosine
+// decay, the cosine annealing with hard restarts and the one-cycle policy, and a Scheduler which applies them
+// to any gd.LearningRateMethod (SGD, AdaGrad, Adam, RAdam, RMSProp).
+package lrschedule
+
+import (
+	"bytes"
+	"encoding/gob"
+	mat "github.com/nlpodyssey/spago/pkg/mat32"
+	"github.com/nlpodyssey/spago/pkg/ml/optimizers/gd"
+)
+
+// Schedule is implemented by any learning rate schedule.
+type Schedule interface {
+	// LearningRate returns the learning rate at the given step, starting from 0.
+	LearningRate(step int) mat.Float
+}
+
+// Func is a function which implements the Schedule interface.
+type Func func(step int) mat.Float
+
+// LearningRate returns the learning rate at the given step, starting from 0.
+func (f Func) LearningRate(step int) mat.Float {
+	return f(step)
+}
+
+var (
+	_ gd.BatchScheduler = &Scheduler{}
+	_ gd.EpochScheduler = &Scheduler{}
+)
+
+// Scheduler sets the learning rate of an optimization method according to a Schedule, at the beginning of
+// each batch or, with the PerEpoch option, of each epoch. It is notified by the GradientDescent optimizer
+// with the gd.Schedulers option, e.g.:
+//
+//     method := gdmbuilder.NewMethod(config)
+//     scheduler := lrschedule.New(method, lrschedule.NewLinear(5e-5, 100, 1000))
+//     optimizer := gd.NewOptimizer(method, params, gd.Schedulers(scheduler))
+//
+// Its state is saved in the checkpoints of the optimizer (see gd.Checkpoint).
+type Scheduler struct {
+	method   gd.LearningRateMethod
+	schedule Schedule
+	perEpoch bool
+	step     int
+}
+
+// Option allows to configure a new Scheduler with your specific needs.
+type Option func(*Scheduler)
+
+// PerEpoch is an option to update the learning rate at each epoch, instead of each batch.
+func PerEpoch() Option {
+	return func(s *Scheduler) {
+		s.perEpoch = true
+	}
+}
+
+// New returns a new Scheduler of the learning rate of the method.
+// It panics if the method does not implement gd.LearningRateMethod.
+func New(method gd.Method, schedule Schedule, opts ...Option) *Scheduler {
+	lrMethod, ok := method.(gd.LearningRateMethod)
+	if !ok {
+		panic("lrschedule: the optimization method does not support the learning rate scheduling")
+	}
+	s := &Scheduler{
+		method:   lrMethod,
+		schedule: schedule,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// IncBatch sets the learning rate of the new batch, unless the schedule is per epoch.
+func (s *Scheduler) IncBatch() {
+	if !s.perEpoch {
+		s.next()
+	}
+}
+
+// IncEpoch sets the learning rate of the new epoch, if the schedule is per epoch.
+func (s *Scheduler) IncEpoch() {
+	if s.perEpoch {
+		s.next()
+	}
+}
+
+func (s *Scheduler) next() {
+	s.method.SetLearningRate(s.schedule.LearningRate(s.step))
+	s.step++
+}
+
+// Step returns the number of steps (batches or epochs) started so far.
+func (s *Scheduler) Step() int {
+	return s.step
+}
+
+// MarshalBinary returns the state of the scheduler, i.e. the number of steps started so far.
+func (s *Scheduler) MarshalBinary() ([]byte, error) {
+	buf := new(bytes.Buffer)
+	if err := gob.NewEncoder(buf).Encode(s.step); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// UnmarshalBinary restores a state of the scheduler returned by MarshalBinary.
+func (s *Scheduler) UnmarshalBinary(data []byte) error {
+	return gob.NewDecoder(bytes.NewReader(data)).Decode(&s.step)
+}
+
+// warmup returns the factor of the learning rate during the linear warmup from 0, and whether the step is
+// in the warmup.
+func warmup(step, warmupSteps int) (mat.Float, bool) {
+	if step >= warmupSteps {
+		return 1, false
+	}
+	return mat.Float(step) / mat.Float(warmupSteps), true
+}
+
+// progress returns the ratio of the steps after the warmup, in the range [0, 1].
+func progress(step, warmupSteps, totalSteps int) mat.Float {
+	if totalSteps <= warmupSteps {
+		return 1
+	}
+	p := mat.Float(step-warmupSteps) / mat.Float(totalSteps-warmupSteps)
+	if p > 1 {
+		return 1
+	}
+	return p
+}
+
+// checkSteps panics if the numbers of steps are not valid.
+func checkSteps(warmupSteps, totalSteps int) {
+	if warmupSteps < 0 {
+		panic("lrschedule: the number of warmup steps must be >= 0")
+	}
+	if totalSteps < warmupSteps {
+		panic("lrschedule: the total number of steps must be >= than the warmup steps")
+	}
+}
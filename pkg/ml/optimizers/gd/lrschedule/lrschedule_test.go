@@ -0,0 +1,117 @@
+// Copyright 2021 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package lrschedule
+
+import (
+	mat "github.com/nlpodyssey/spago/pkg/mat32"
+	"github.com/nlpodyssey/spago/pkg/ml/nn"
+	"github.com/nlpodyssey/spago/pkg/ml/optimizers/gd"
+	"github.com/nlpodyssey/spago/pkg/ml/optimizers/gd/adam"
+	"github.com/nlpodyssey/spago/pkg/ml/optimizers/gd/sgd"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func learningRates(s Schedule, steps int) []mat.Float {
+	lrs := make([]mat.Float, steps)
+	for i := range lrs {
+		lrs[i] = s.LearningRate(i)
+	}
+	return lrs
+}
+
+func TestLinear(t *testing.T) {
+	lrs := learningRates(NewLinear(1, 2, 6), 8)
+	assert.InDeltaSlice(t, []mat.Float{0, 0.5, 1, 0.75, 0.5, 0.25, 0, 0}, lrs, 1.0e-6)
+}
+
+func TestConstantWithWarmup(t *testing.T) {
+	lrs := learningRates(NewConstantWithWarmup(0.1, 4), 6)
+	assert.InDeltaSlice(t, []mat.Float{0, 0.025, 0.05, 0.075, 0.1, 0.1}, lrs, 1.0e-6)
+}
+
+func TestCosine(t *testing.T) {
+	lrs := learningRates(NewCosine(1, 1, 5, 0.5), 6)
+	assert.InDeltaSlice(t, []mat.Float{0, 1, 0.853553, 0.5, 0.146447, 0}, lrs, 1.0e-6)
+
+	lrs = learningRates(NewCosine(1, 0, 4, 1), 5)
+	assert.InDeltaSlice(t, []mat.Float{1, 0.5, 0, 0.5, 1}, lrs, 1.0e-6)
+}
+
+func TestCosineWithRestarts(t *testing.T) {
+	lrs := learningRates(NewCosineWithRestarts(1, 0, 8, 2), 9)
+	assert.InDeltaSlice(t, []mat.Float{1, 0.853553, 0.5, 0.146447, 1, 0.853553, 0.5, 0.146447, 0}, lrs, 1.0e-6)
+	assert.Panics(t, func() { NewCosineWithRestarts(1, 0, 8, 0) })
+}
+
+func TestOneCycle(t *testing.T) {
+	s := NewOneCycle(1, 11, 0.3, 10, 100)
+	lrs := learningRates(s, 12)
+	assert.InDeltaSlice(t, []mat.Float{
+		0.1, 0.458445, 0.962745, // annealing to the peak at step 2.3
+		0.979767, 0.884591, 0.72636, 0.53105, 0.330725, 0.158272, 0.042001, 0.001, // annealing to the minimum
+		0.001,
+	}, lrs, 1.0e-6)
+	assert.Panics(t, func() { NewOneCycle(1, 10, 1, 10, 100) })
+	assert.Panics(t, func() { NewOneCycle(1, 2, 0.9, 10, 100) })
+}
+
+func TestScheduler(t *testing.T) {
+	method := sgd.New(sgd.NewConfig(1, 0, false))
+	scheduler := New(method, NewLinear(0.1, 2, 4))
+	param := nn.NewParam(mat.NewScalar(0))
+	optimizer := gd.NewOptimizer(method, paramsList{param}, gd.Schedulers(scheduler))
+
+	var lrs []mat.Float
+	for i := 0; i < 4; i++ {
+		optimizer.IncEpoch()
+		optimizer.IncBatch()
+		lrs = append(lrs, method.LearningRate())
+		param.PropagateGrad(mat.NewScalar(-1))
+		optimizer.Optimize()
+	}
+	assert.InDeltaSlice(t, []mat.Float{0, 0.05, 0.1, 0.05}, lrs, 1.0e-6)
+	assert.InDelta(t, 0.2, param.ScalarValue(), 1.0e-6)
+	assert.Equal(t, 4, scheduler.Step())
+}
+
+func TestScheduler_PerEpoch(t *testing.T) {
+	method := adam.New(adam.NewDefaultConfig())
+	scheduler := New(method, Func(func(step int) mat.Float { return mat.Float(step + 1) }), PerEpoch())
+	optimizer := gd.NewOptimizer(method, paramsList{}, gd.Schedulers(scheduler))
+	optimizer.IncEpoch()
+	optimizer.IncBatch()
+	optimizer.IncBatch()
+	assert.Equal(t, mat.Float(1), method.LearningRate())
+	optimizer.IncEpoch()
+	assert.Equal(t, mat.Float(2), method.LearningRate())
+}
+
+func TestScheduler_Checkpoint(t *testing.T) {
+	newOptimizer := func() (*gd.GradientDescent, *Scheduler) {
+		method := sgd.New(sgd.NewConfig(1, 0, false))
+		scheduler := New(method, NewLinear(1, 0, 10))
+		return gd.NewOptimizer(method, paramsList{}, gd.Schedulers(scheduler)), scheduler
+	}
+	original, scheduler := newOptimizer()
+	for i := 0; i < 3; i++ {
+		original.IncBatch()
+	}
+	checkpoint, err := gd.NewCheckpoint(original, nil)
+	assert.NoError(t, err)
+
+	resumed, resumedScheduler := newOptimizer()
+	assert.NoError(t, checkpoint.Restore(resumed, nil))
+	assert.Equal(t, scheduler.Step(), resumedScheduler.Step())
+
+	other := gd.NewOptimizer(sgd.New(sgd.NewConfig(1, 0, false)), paramsList{})
+	assert.Error(t, checkpoint.Restore(other, nil))
+}
+
+type paramsList []nn.Param
+
+func (p paramsList) Params() []nn.Param {
+	return p
+}
@@ -0,0 +1,73 @@
+// Copyright 2021 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package lrschedule
+
+import mat "github.com/nlpodyssey/spago/pkg/mat32"
+
+var _ Schedule = &OneCycle{}
+
+// OneCycle implements the 1cycle policy: the learning rate is annealed with a cosine from maxLR/divFactor
+// to maxLR over the first pctStart of the steps, and then from maxLR to maxLR/(divFactor*finalDivFactor)
+// over the remaining ones. Only the learning rate is scheduled, not the momentum.
+// References:
+//     Super-Convergence: Very Fast Training of Neural Networks Using Large Learning Rates
+//     https://arxiv.org/abs/1708.07120
+type OneCycle struct {
+	maxLR     mat.Float
+	initialLR mat.Float
+	minLR     mat.Float
+	// peakStep is the (fractional) step of the maximum learning rate.
+	peakStep mat.Float
+	// lastStep is the step of the minimum learning rate.
+	lastStep mat.Float
+}
+
+// NewOneCycle returns a new OneCycle schedule.
+// The phases end at the same (fractional) steps as in the OneCycleLR of PyTorch, with the cosine annealing.
+// It panics if pctStart is not in the range (0, 1), the division factors are not > 0, or the steps are too few.
+func NewOneCycle(maxLR mat.Float, totalSteps int, pctStart, divFactor, finalDivFactor mat.Float) *OneCycle {
+	if !(pctStart > 0 && pctStart < 1) {
+		panic("lrschedule: `pctStart` must be in the range (0.0, 1.0)")
+	}
+	if !(divFactor > 0 && finalDivFactor > 0) {
+		panic("lrschedule: the division factors must be > 0")
+	}
+	peakStep := mat.Max(pctStart*mat.Float(totalSteps)-1, 1)
+	lastStep := mat.Float(totalSteps - 1)
+	if peakStep >= lastStep {
+		panic("lrschedule: too few steps for `pctStart`")
+	}
+	initialLR := maxLR / divFactor
+	return &OneCycle{
+		maxLR:     maxLR,
+		initialLR: initialLR,
+		minLR:     initialLR / finalDivFactor,
+		peakStep:  peakStep,
+		lastStep:  lastStep,
+	}
+}
+
+// NewDefaultOneCycle returns a new OneCycle schedule with generically reasonable default values:
+// pctStart 0.3, divFactor 25 and finalDivFactor 1e4.
+func NewDefaultOneCycle(maxLR mat.Float, totalSteps int) *OneCycle {
+	return NewOneCycle(maxLR, totalSteps, 0.3, 25, 1e4)
+}
+
+// LearningRate returns the learning rate at the given step, starting from 0.
+func (o *OneCycle) LearningRate(step int) mat.Float {
+	t := mat.Float(step)
+	if t <= o.peakStep {
+		return cosineAnnealing(o.initialLR, o.maxLR, t/o.peakStep)
+	}
+	if t >= o.lastStep {
+		return o.minLR
+	}
+	return cosineAnnealing(o.maxLR, o.minLR, (t-o.peakStep)/(o.lastStep-o.peakStep))
+}
+
+// cosineAnnealing anneals from start to end as pct goes from 0 to 1.
+func cosineAnnealing(start, end, pct mat.Float) mat.Float {
+	return end + (start-end)/2*(mat.Cos(mat.Pi*pct)+1)
+}
@@ -22,10 +22,16 @@ const (
 	RAdam
 	// RMSProp represents the RMSProp gradient descent optimization method.
 	RMSProp
+	// AdamW represents the AdamW gradient descent optimization method.
+	AdamW
+	// LAMB represents the LAMB gradient descent optimization method.
+	LAMB
+	// Adafactor represents the Adafactor gradient descent optimization method.
+	Adafactor
 )
 
 // MethodConfig is an empty interface implemented by the configuration structures of
-// AdaGrad, Adam, RMSProp and SGD.
+// the optimization methods (e.g. AdaGrad, Adam, RMSProp and SGD).
 type MethodConfig interface{}
 
 // Method is implemented by any optimization method.
@@ -38,6 +44,40 @@ type Method interface {
 	NewSupport(r, c int) *nn.Payload
 }
 
+// RowSparseMethod is implemented by the optimization methods supporting the row-sparse gradients (see mat.RowSparse),
+// e.g. the gradients of a large embedding matrix of which only a few rows have been used.
+// Their Delta applies a lazy update: only the rows with gradients (and the corresponding rows of the support
+// structure) are updated, and the returned delta is row-sparse too.
+// The row-sparse gradients are converted to dense gradients before the update of the other methods.
+type RowSparseMethod interface {
+	Method
+	// DeltaRowSparse returns the difference between the rows of the current params with gradients
+	// and where the method wants them to be.
+	DeltaRowSparse(param nn.Param, grads *mat.RowSparse) *mat.RowSparse
+}
+
+// LearningRateMethod is implemented by the optimization methods whose learning rate can be changed during the
+// training, e.g. by a learning rate scheduler (see the lrschedule package).
+type LearningRateMethod interface {
+	Method
+	// LearningRate returns the current learning rate (or step size) of the method.
+	LearningRate() mat.Float
+	// SetLearningRate sets the learning rate (or step size) of the method.
+	SetLearningRate(lr mat.Float)
+}
+
+// ExcludeBiasesAndNorms reports whether a param is excluded from the weight decay by default: the biases
+// and the gains of the normalization layers (i.e. the params of type nn.Biases and nn.Gains) are not decayed,
+// while the weights and the other params (e.g. the embeddings) are.
+func ExcludeBiasesAndNorms(param nn.Param) bool {
+	switch param.Type() {
+	case nn.Biases, nn.Gains:
+		return true
+	default:
+		return false
+	}
+}
+
 // GetOrSetPayload returns the payload from param, if it already exists, otherwise
 // a new payload is created, assigned to the param, and returned.
 func GetOrSetPayload(param nn.Param, m Method) *nn.Payload {
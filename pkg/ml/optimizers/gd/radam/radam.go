@@ -48,7 +48,7 @@ func NewDefaultConfig() Config {
 	}
 }
 
-var _ gd.Method = &RAdam{}
+var _ gd.LearningRateMethod = &RAdam{}
 
 // RAdam implements the RAdam gradient descent optimization method.
 type RAdam struct {
@@ -72,6 +72,16 @@ func (o *RAdam) Label() int {
 	return gd.RAdam
 }
 
+// LearningRate returns the current step size.
+func (o *RAdam) LearningRate() mat.Float {
+	return o.StepSize
+}
+
+// SetLearningRate sets the step size.
+func (o *RAdam) SetLearningRate(lr mat.Float) {
+	o.StepSize = lr
+}
+
 const (
 	m    int = 0
 	v    int = 1
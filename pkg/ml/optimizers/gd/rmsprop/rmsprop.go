@@ -38,7 +38,7 @@ func NewDefaultConfig() Config {
 	}
 }
 
-var _ gd.Method = &RMSProp{}
+var _ gd.LearningRateMethod = &RMSProp{}
 
 // The RMSProp method is a variant of AdaGrad where the squared sum of previous gradients is replaced with a moving average.
 // References:
@@ -58,6 +58,16 @@ func (o *RMSProp) Label() int {
 	return gd.RMSProp
 }
 
+// LearningRate returns the current learning rate.
+func (o *RMSProp) LearningRate() mat.Float {
+	return o.LR
+}
+
+// SetLearningRate sets the learning rate.
+func (o *RMSProp) SetLearningRate(lr mat.Float) {
+	o.LR = lr
+}
+
 const v = 0
 
 // NewSupport returns a new support structure with the given dimensions.
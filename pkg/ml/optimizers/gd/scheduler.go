@@ -21,3 +21,8 @@ type ExampleScheduler interface {
 	// IncExample beats the occurrence of a new example.
 	IncExample()
 }
+
+// Scheduler is an empty interface implemented by the values which are notified by a GradientDescent of the
+// occurrence of new epochs, batches or examples, along with the optimization method (see the Schedulers option).
+// It should implement at least one of EpochScheduler, BatchScheduler and ExampleScheduler.
+type Scheduler interface{}
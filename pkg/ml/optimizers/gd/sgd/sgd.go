@@ -29,7 +29,10 @@ func NewConfig(lr, momentum mat.Float, nesterov bool) Config {
 	}
 }
 
-var _ gd.Method = &SGD{}
+var (
+	_ gd.RowSparseMethod    = &SGD{}
+	_ gd.LearningRateMethod = &SGD{}
+)
 
 // SGD implements the SGD gradient descent optimization method.
 type SGD struct {
@@ -47,6 +50,16 @@ func (o *SGD) Label() int {
 	return gd.SGD
 }
 
+// LearningRate returns the current learning rate.
+func (o *SGD) LearningRate() mat.Float {
+	return o.Alpha
+}
+
+// SetLearningRate sets the learning rate.
+func (o *SGD) SetLearningRate(lr mat.Float) {
+	o.Alpha = lr
+}
+
 const (
 	v     int = 0
 	buf   int = 1
@@ -85,6 +98,9 @@ func (o *SGD) NewSupport(r, c int) *nn.Payload {
 
 // Delta returns the difference between the current params and where the method wants it to be.
 func (o *SGD) Delta(param nn.Param) mat.Matrix {
+	if grads, ok := param.Grad().(*mat.RowSparse); ok {
+		return o.DeltaRowSparse(param, grads)
+	}
 	return o.calcDelta(param.Grad(), gd.GetOrSetPayload(param, o).Data)
 }
 
@@ -119,3 +135,34 @@ func (o *SGD) calcNesterovMomentumDelta(grads mat.Matrix, supp []mat.Matrix) mat
 	supp[vTmp].SubInPlace(supp[vPrev])
 	return supp[vTmp]
 }
+
+// DeltaRowSparse returns the difference between the rows of the current params with gradients and where the method
+// wants them to be. Only the corresponding rows of the support structure are updated.
+func (o *SGD) DeltaRowSparse(param nn.Param, grads *mat.RowSparse) *mat.RowSparse {
+	supp := gd.GetOrSetPayload(param, o).Data
+	cols := grads.Columns()
+	delta := mat.NewRowSparse(grads.Dims())
+	d := make([]mat.Float, cols)
+	for _, i := range grads.RowIndices() {
+		g := grads.Row(i)
+		if o.Mu == 0.0 {
+			for j, gj := range g {
+				d[j] = gj * o.Alpha
+			}
+			delta.AddToRow(i, d)
+			continue
+		}
+		vi := supp[v].Data()[i*cols : (i+1)*cols]
+		for j, gj := range g {
+			vPrevJ := vi[j] * o.Mu
+			vi[j] = vPrevJ + gj*o.Alpha
+			if o.Nesterov {
+				d[j] = vi[j]*(1.0+o.Mu) - vPrevJ
+			} else {
+				d[j] = vi[j]
+			}
+		}
+		delta.AddToRow(i, d)
+	}
+	return delta
+}
@@ -6,6 +6,7 @@ package sgd
 
 import (
 	mat "github.com/nlpodyssey/spago/pkg/mat32"
+	"github.com/nlpodyssey/spago/pkg/ml/nn"
 	"github.com/stretchr/testify/assert"
 	"testing"
 )
@@ -181,3 +182,24 @@ func TestSGDNesterovMomentum_Update2(t *testing.T) {
 		0.697809, -0.40111, 0.195093,
 	}, params.Data(), 1.0e-6)
 }
+
+func Test_DeltaRowSparse(t *testing.T) {
+	updater := New(NewConfig(0.1, 0.9, true))
+	sparseParam := nn.NewParam(mat.NewEmptyDense(3, 2))
+	denseParam := nn.NewParam(mat.NewEmptyDense(3, 2))
+
+	for _, g := range [][]mat.Float{{0.9, -0.7}, {0.4, 0.1}} {
+		grads := mat.NewRowSparse(3, 2)
+		grads.AddToRow(1, g)
+		sparseParam.PropagateGrad(grads)
+		denseParam.PropagateGrad(grads.ToDense())
+
+		delta, ok := updater.Delta(sparseParam).(*mat.RowSparse)
+		assert.True(t, ok)
+		assert.Equal(t, []int{1}, delta.RowIndices())
+		assert.InDeltaSlice(t, updater.Delta(denseParam).Data(), delta.Data(), 1.0e-6)
+
+		sparseParam.ZeroGrad()
+		denseParam.ZeroGrad()
+	}
+}
@@ -88,7 +88,7 @@ func (c *ClassMetrics) Accuracy() mat.Float {
 
 // zeroIfNaN returns zero if the value is NaN otherwise the value.
 func zeroIfNaN(value mat.Float) mat.Float {
-	if value == mat.NaN() {
+	if mat.IsNaN(value) {
 		return 0.0
 	}
 	return value
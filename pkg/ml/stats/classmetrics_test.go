@@ -0,0 +1,26 @@
+// Copyright 2021 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package stats
+
+import (
+	mat "github.com/nlpodyssey/spago/pkg/mat32"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestClassMetrics(t *testing.T) {
+	c := NewMetricCounter()
+	assert.Equal(t, mat.Float(0), c.Precision())
+	assert.Equal(t, mat.Float(0), c.F1Score())
+
+	c.IncTruePos()
+	c.IncFalsePos()
+	c.IncFalseNeg()
+	c.IncTrueNeg()
+	assert.Equal(t, mat.Float(0.5), c.Precision())
+	assert.Equal(t, mat.Float(0.5), c.Recall())
+	assert.Equal(t, mat.Float(0.5), c.F1Score())
+	assert.Equal(t, mat.Float(0.5), c.Accuracy())
+}
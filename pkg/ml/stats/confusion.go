@@ -0,0 +1,143 @@
+// Copyright 2021 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package stats
+
+import (
+	"fmt"
+	mat "github.com/nlpodyssey/spago/pkg/mat32"
+	"strings"
+)
+
+// ConfusionMatrix counts the predictions of a multi-class classifier: Counts[i][j] is the number of
+// examples of the i-th label predicted as the j-th label.
+type ConfusionMatrix struct {
+	Labels []string `json:"labels"`
+	Counts [][]int  `json:"counts"`
+}
+
+// NewConfusionMatrix returns a new empty ConfusionMatrix of the labels. The labels first found by Add
+// are appended.
+func NewConfusionMatrix(labels ...string) *ConfusionMatrix {
+	c := &ConfusionMatrix{}
+	for _, label := range labels {
+		c.labelIndex(label)
+	}
+	return c
+}
+
+// labelIndex returns the index of the label, adding it if needed.
+func (c *ConfusionMatrix) labelIndex(label string) int {
+	for i, l := range c.Labels {
+		if l == label {
+			return i
+		}
+	}
+	c.Labels = append(c.Labels, label)
+	for i := range c.Counts {
+		c.Counts[i] = append(c.Counts[i], 0)
+	}
+	c.Counts = append(c.Counts, make([]int, len(c.Labels)))
+	return len(c.Labels) - 1
+}
+
+// Add counts an example of the expected label, predicted as the predicted label.
+func (c *ConfusionMatrix) Add(expected, predicted string) {
+	i := c.labelIndex(expected)
+	j := c.labelIndex(predicted)
+	c.Counts[i][j]++
+}
+
+// Count returns the number of examples of the expected label predicted as the predicted label.
+func (c *ConfusionMatrix) Count(expected, predicted string) int {
+	i, j := c.find(expected), c.find(predicted)
+	if i < 0 || j < 0 {
+		return 0
+	}
+	return c.Counts[i][j]
+}
+
+// find returns the index of the label, or -1 if it is unknown.
+func (c *ConfusionMatrix) find(label string) int {
+	for i, l := range c.Labels {
+		if l == label {
+			return i
+		}
+	}
+	return -1
+}
+
+// Total returns the number of examples.
+func (c *ConfusionMatrix) Total() int {
+	total := 0
+	for _, row := range c.Counts {
+		for _, count := range row {
+			total += count
+		}
+	}
+	return total
+}
+
+// Accuracy returns the ratio of the examples correctly predicted.
+func (c *ConfusionMatrix) Accuracy() mat.Float {
+	correct := 0
+	for i, row := range c.Counts {
+		correct += row[i]
+	}
+	return zeroIfNaN(mat.Float(correct) / mat.Float(c.Total()))
+}
+
+// Metrics returns the one-vs-rest metrics of each label.
+func (c *ConfusionMatrix) Metrics() map[string]*ClassMetrics {
+	total := c.Total()
+	metrics := make(map[string]*ClassMetrics, len(c.Labels))
+	for k, label := range c.Labels {
+		m := NewMetricCounter()
+		for i, row := range c.Counts {
+			for j, count := range row {
+				switch {
+				case i == k && j == k:
+					m.TruePos += count
+				case i == k:
+					m.FalseNeg += count
+				case j == k:
+					m.FalsePos += count
+				}
+			}
+		}
+		m.TrueNeg = total - m.TruePos - m.FalseNeg - m.FalsePos
+		metrics[label] = m
+	}
+	return metrics
+}
+
+// Summary returns the Summary of the metrics of the labels. Its micro average is the accuracy.
+func (c *ConfusionMatrix) Summary() Summary {
+	return NewSummary(c.Metrics())
+}
+
+// String returns the matrix as a table, with the expected labels on the rows and the predicted labels on
+// the columns.
+func (c *ConfusionMatrix) String() string {
+	width := len("expected")
+	for _, label := range c.Labels {
+		if len(label) > width {
+			width = len(label)
+		}
+	}
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%*s", width, "expected")
+	for _, label := range c.Labels {
+		fmt.Fprintf(&sb, " %*s", width, label)
+	}
+	sb.WriteString("\n")
+	for i, row := range c.Counts {
+		fmt.Fprintf(&sb, "%*s", width, c.Labels[i])
+		for _, count := range row {
+			fmt.Fprintf(&sb, " %*d", width, count)
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
@@ -0,0 +1,46 @@
+// Copyright 2021 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package stats
+
+import (
+	"encoding/json"
+	mat "github.com/nlpodyssey/spago/pkg/mat32"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestConfusionMatrix(t *testing.T) {
+	c := NewConfusionMatrix("cat", "dog")
+	assert.Equal(t, mat.Float(0), c.Accuracy())
+	c.Add("cat", "cat")
+	c.Add("cat", "dog")
+	c.Add("dog", "dog")
+	c.Add("bird", "cat")
+
+	assert.Equal(t, []string{"cat", "dog", "bird"}, c.Labels)
+	assert.Equal(t, [][]int{{1, 1, 0}, {0, 1, 0}, {1, 0, 0}}, c.Counts)
+	assert.Equal(t, 1, c.Count("cat", "dog"))
+	assert.Equal(t, 0, c.Count("cat", "fish"))
+	assert.Equal(t, 4, c.Total())
+	assert.Equal(t, mat.Float(0.5), c.Accuracy())
+
+	metrics := c.Metrics()
+	assert.Equal(t, &ClassMetrics{TruePos: 1, FalsePos: 1, FalseNeg: 1, TrueNeg: 1}, metrics["cat"])
+	assert.Equal(t, &ClassMetrics{TruePos: 1, FalsePos: 1, TrueNeg: 2}, metrics["dog"])
+	assert.Equal(t, &ClassMetrics{FalseNeg: 1, TrueNeg: 3}, metrics["bird"])
+	assert.Equal(t, c.Accuracy(), c.Summary().Micro.F1Score)
+	assert.Equal(t, "expected      cat      dog     bird\n"+
+		"     cat        1        1        0\n"+
+		"     dog        0        1        0\n"+
+		"    bird        1        0        0\n", c.String())
+
+	data, err := json.Marshal(c)
+	require.NoError(t, err)
+	decoded := &ConfusionMatrix{}
+	require.NoError(t, json.Unmarshal(data, decoded))
+	decoded.Add("dog", "dog")
+	assert.Equal(t, 2, decoded.Count("dog", "dog"))
+}
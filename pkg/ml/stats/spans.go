@@ -0,0 +1,228 @@
+// Copyright 2021 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package stats
+
+import (
+	"fmt"
+)
+
+// LabelScheme is the scheme of the labels of the tokens of a sequence, which encodes the spans.
+type LabelScheme string
+
+const (
+	// IOB1 labels the tokens inside a span with "I-", except the first token of a span which immediately
+	// follows another span of the same type, labeled with "B-".
+	IOB1 LabelScheme = "iob1"
+	// BIO labels the first token of a span with "B-" and the following ones with "I-".
+	BIO LabelScheme = "bio"
+	// BIOES labels the first token of a span with "B-", the following ones with "I-", except the last
+	// one, labeled with "E-", and the spans of a single token with "S-".
+	BIOES LabelScheme = "bioes"
+)
+
+// OutsideLabel is the label of the tokens outside the spans.
+const OutsideLabel = "O"
+
+// Span is a labeled span of a sequence, from Start to End (exclusive).
+type Span struct {
+	Label string `json:"label"`
+	Start int    `json:"start"`
+	End   int    `json:"end"`
+}
+
+// Overlaps reports whether the spans have at least one position in common.
+func (s Span) Overlaps(other Span) bool {
+	return s.Start < other.End && other.Start < s.End
+}
+
+// SchemePrefixes returns the prefixes of the labels of the spans of the scheme (e.g. "B" and "I" for BIO).
+// It panics if the scheme is invalid.
+func SchemePrefixes(scheme LabelScheme) []string {
+	switch scheme {
+	case IOB1, BIO:
+		return []string{"B", "I"}
+	case BIOES:
+		return []string{"B", "I", "E", "S"}
+	default:
+		panic(fmt.Errorf("stats: invalid label scheme `%s`", scheme))
+	}
+}
+
+// splitLabel returns the prefix and the type of the label. The prefix is zero for the labels outside
+// the spans, including the special ones such as "<unk>".
+func splitLabel(label string) (prefix byte, spanType string) {
+	if len(label) < 3 || label[1] != '-' {
+		return 0, ""
+	}
+	switch label[0] {
+	case 'B', 'I', 'E', 'S':
+		return label[0], label[2:]
+	default:
+		return 0, ""
+	}
+}
+
+// ExtractSpans returns the spans of the labels, regardless of their scheme (IOB1, BIO or BIOES), like
+// the conlleval script: the invalid sequences (e.g. "O I-PER" or "B-PER I-LOC") start a new span.
+func ExtractSpans(labels []string) []Span {
+	spans := make([]Span, 0)
+	var current *Span
+	closeCurrent := func(end int) {
+		if current != nil {
+			current.End = end
+			spans = append(spans, *current)
+			current = nil
+		}
+	}
+	for i, label := range labels {
+		prefix, spanType := splitLabel(label)
+		switch prefix {
+		case 0:
+			closeCurrent(i)
+			continue
+		case 'B', 'S':
+			closeCurrent(i)
+		case 'I', 'E':
+			if current != nil && current.Label != spanType {
+				closeCurrent(i)
+			}
+		}
+		if current == nil {
+			current = &Span{Label: spanType, Start: i}
+		}
+		if prefix == 'E' || prefix == 'S' {
+			closeCurrent(i + 1)
+		}
+	}
+	closeCurrent(len(labels))
+	return spans
+}
+
+// EncodeSpans returns the labels of a sequence of the given length with the spans, according to the scheme.
+// The spans must be sorted and must not overlap. It panics if the scheme is invalid.
+func EncodeSpans(spans []Span, length int, scheme LabelScheme) []string {
+	labels := make([]string, length)
+	for i := range labels {
+		labels[i] = OutsideLabel
+	}
+	previous := Span{End: -1}
+	for _, s := range spans {
+		for i := s.Start; i < s.End; i++ {
+			labels[i] = "I-" + s.Label
+		}
+		switch scheme {
+		case IOB1:
+			if s.Start == previous.End && s.Label == previous.Label {
+				labels[s.Start] = "B-" + s.Label
+			}
+		case BIO:
+			labels[s.Start] = "B-" + s.Label
+		case BIOES:
+			if s.End-s.Start == 1 {
+				labels[s.Start] = "S-" + s.Label
+			} else {
+				labels[s.Start] = "B-" + s.Label
+				labels[s.End-1] = "E-" + s.Label
+			}
+		default:
+			panic(fmt.Errorf("stats: invalid label scheme `%s`", scheme))
+		}
+		previous = s
+	}
+	return labels
+}
+
+// MatchMode is the criterion by which a predicted span matches an expected span.
+type MatchMode int
+
+const (
+	// StrictMatch requires the same boundaries and the same type.
+	StrictMatch MatchMode = iota
+	// PartialMatch requires overlapping boundaries and the same type.
+	PartialMatch
+)
+
+// SpanEvaluator accumulates the span-level metrics of each type of span (e.g. of the entities recognized
+// in a set of sentences). Each expected span is matched by at most one predicted span: the matched spans are
+// true positives, the other predicted spans false positives, and the other expected spans false negatives.
+type SpanEvaluator struct {
+	Mode    MatchMode
+	Metrics map[string]*ClassMetrics
+}
+
+// NewSpanEvaluator returns a new SpanEvaluator with the given match mode.
+func NewSpanEvaluator(mode MatchMode) *SpanEvaluator {
+	return &SpanEvaluator{
+		Mode:    mode,
+		Metrics: make(map[string]*ClassMetrics),
+	}
+}
+
+// AddLabels compares the spans of the predicted labels of a sequence with the spans of the expected ones
+// (see ExtractSpans).
+func (e *SpanEvaluator) AddLabels(expected, predicted []string) {
+	e.Add(ExtractSpans(expected), ExtractSpans(predicted))
+}
+
+// Add compares the predicted spans of a sequence with the expected ones.
+func (e *SpanEvaluator) Add(expected, predicted []Span) {
+	matched := make([]bool, len(expected))
+	for _, p := range predicted {
+		if i := e.match(expected, matched, p); i >= 0 {
+			matched[i] = true
+			e.metrics(p.Label).IncTruePos()
+		} else {
+			e.metrics(p.Label).IncFalsePos()
+		}
+	}
+	for i, x := range expected {
+		if !matched[i] {
+			e.metrics(x.Label).IncFalseNeg()
+		}
+	}
+}
+
+// match returns the index of the first expected span, not matched yet, which matches the predicted span,
+// or -1 if there is none.
+func (e *SpanEvaluator) match(expected []Span, matched []bool, predicted Span) int {
+	for i, x := range expected {
+		if matched[i] || x.Label != predicted.Label {
+			continue
+		}
+		switch e.Mode {
+		case StrictMatch:
+			if x == predicted {
+				return i
+			}
+		case PartialMatch:
+			if x.Overlaps(predicted) {
+				return i
+			}
+		default:
+			panic(fmt.Errorf("stats: invalid match mode %d", e.Mode))
+		}
+	}
+	return -1
+}
+
+// metrics returns the metrics of the type of span, creating them if needed.
+func (e *SpanEvaluator) metrics(label string) *ClassMetrics {
+	m, ok := e.Metrics[label]
+	if !ok {
+		m = NewMetricCounter()
+		e.Metrics[label] = m
+	}
+	return m
+}
+
+// Micro returns the metrics of all the types of span together.
+func (e *SpanEvaluator) Micro() *ClassMetrics {
+	return sumMetrics(e.Metrics)
+}
+
+// Summary returns the Summary of the metrics.
+func (e *SpanEvaluator) Summary() Summary {
+	return NewSummary(e.Metrics)
+}
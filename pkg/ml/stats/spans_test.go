@@ -0,0 +1,64 @@
+// Copyright 2021 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package stats
+
+import (
+	"encoding/json"
+	mat "github.com/nlpodyssey/spago/pkg/mat32"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+)
+
+func TestExtractSpans(t *testing.T) {
+	expected := []Span{{"PER", 0, 2}, {"PER", 2, 3}, {"LOC", 4, 5}, {"ORG", 5, 7}}
+	iob1 := []string{"I-PER", "I-PER", "B-PER", "O", "I-LOC", "I-ORG", "I-ORG"}
+	bio := []string{"B-PER", "I-PER", "B-PER", "O", "B-LOC", "B-ORG", "I-ORG"}
+	bioes := []string{"B-PER", "E-PER", "S-PER", "O", "S-LOC", "B-ORG", "E-ORG"}
+	for _, labels := range [][]string{iob1, bio, bioes} {
+		spans := ExtractSpans(labels)
+		assert.Equal(t, expected, spans)
+		assert.Equal(t, iob1, EncodeSpans(spans, len(labels), IOB1))
+		assert.Equal(t, bio, EncodeSpans(spans, len(labels), BIO))
+		assert.Equal(t, bioes, EncodeSpans(spans, len(labels), BIOES))
+	}
+	assert.Equal(t, []Span{{"PER", 1, 2}, {"LOC", 2, 3}}, ExtractSpans([]string{"<unk>", "I-PER", "E-LOC", "O"}))
+	assert.Panics(t, func() { EncodeSpans(expected, 7, "foo") })
+	assert.Equal(t, []string{"B", "I", "E", "S"}, SchemePrefixes(BIOES))
+}
+
+func TestSpanEvaluator(t *testing.T) {
+	expected := []string{"B-PER", "I-PER", "O", "B-LOC", "O", "B-ORG"}
+	predicted := []string{"B-PER", "I-PER", "O", "B-LOC", "I-LOC", "B-PER"}
+
+	strict := NewSpanEvaluator(StrictMatch)
+	strict.AddLabels(expected, predicted)
+	assert.Equal(t, &ClassMetrics{TruePos: 1, FalsePos: 1}, strict.Metrics["PER"])
+	assert.Equal(t, &ClassMetrics{FalsePos: 1, FalseNeg: 1}, strict.Metrics["LOC"])
+	assert.Equal(t, &ClassMetrics{FalseNeg: 1}, strict.Metrics["ORG"])
+	assert.Equal(t, &ClassMetrics{TruePos: 1, FalsePos: 2, FalseNeg: 2}, strict.Micro())
+
+	partial := NewSpanEvaluator(PartialMatch)
+	partial.AddLabels(expected, predicted)
+	assert.Equal(t, &ClassMetrics{TruePos: 1}, partial.Metrics["LOC"])
+	assert.Equal(t, &ClassMetrics{TruePos: 2, FalsePos: 1, FalseNeg: 1}, partial.Micro())
+
+	summary := strict.Summary()
+	assert.Equal(t, Scores{Precision: 1.0 / 3.0, Recall: 1.0 / 3.0, F1Score: 1.0 / 3.0, Support: 3}, summary.Micro)
+	assert.InDelta(t, (0.5+0+0)/3.0, float64(summary.Macro.Precision), 1.0e-6)
+	assert.InDelta(t, (1.0+0+0)/3.0, float64(summary.Macro.Recall), 1.0e-6)
+	assert.InDelta(t, (2.0/3.0)/3.0, float64(summary.Weighted.F1Score), 1.0e-6)
+	report := summary.String()
+	assert.True(t, strings.Index(report, "LOC") < strings.Index(report, "ORG"))
+	assert.Contains(t, report, "micro avg")
+
+	data, err := json.Marshal(summary)
+	require.NoError(t, err)
+	var decoded Summary
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, summary, decoded)
+	assert.Equal(t, mat.Float(0.5), decoded.Labels["PER"].Precision)
+}
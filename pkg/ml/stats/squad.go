@@ -0,0 +1,133 @@
+// Copyright 2021 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package stats
+
+import (
+	"fmt"
+	mat "github.com/nlpodyssey/spago/pkg/mat32"
+	"strings"
+	"unicode"
+)
+
+// NormalizeAnswer normalizes the text of an answer as the official SQuAD evaluation script: lower case,
+// without punctuation, articles ("a", "an", "the") and extra white spaces.
+func NormalizeAnswer(text string) string {
+	text = strings.Map(func(r rune) rune {
+		if unicode.IsPunct(r) || unicode.IsSymbol(r) {
+			return -1
+		}
+		return unicode.ToLower(r)
+	}, text)
+	words := make([]string, 0)
+	for _, word := range strings.Fields(text) {
+		if word == "a" || word == "an" || word == "the" {
+			continue
+		}
+		words = append(words, word)
+	}
+	return strings.Join(words, " ")
+}
+
+// AnswerExactMatch reports whether the normalized prediction is equal to the normalized ground truth.
+func AnswerExactMatch(prediction, groundTruth string) bool {
+	return NormalizeAnswer(prediction) == NormalizeAnswer(groundTruth)
+}
+
+// AnswerF1Score returns the F1 score of the words of the normalized prediction against the ones of the
+// normalized ground truth. If one of them is empty (no answer), the score is 1 if both are empty, 0 otherwise.
+func AnswerF1Score(prediction, groundTruth string) mat.Float {
+	predictionWords := strings.Fields(NormalizeAnswer(prediction))
+	groundTruthWords := strings.Fields(NormalizeAnswer(groundTruth))
+	if len(predictionWords) == 0 || len(groundTruthWords) == 0 {
+		if len(predictionWords) == len(groundTruthWords) {
+			return 1
+		}
+		return 0
+	}
+	counts := make(map[string]int, len(groundTruthWords))
+	for _, word := range groundTruthWords {
+		counts[word]++
+	}
+	common := 0
+	for _, word := range predictionWords {
+		if counts[word] > 0 {
+			counts[word]--
+			common++
+		}
+	}
+	if common == 0 {
+		return 0
+	}
+	precision := mat.Float(common) / mat.Float(len(predictionWords))
+	recall := mat.Float(common) / mat.Float(len(groundTruthWords))
+	return 2 * precision * recall / (precision + recall)
+}
+
+// SQuADEvaluator accumulates the exact match and the F1 score of the answers to a set of questions, as the
+// official SQuAD evaluation script: the score of each answer is the best one among its ground truths.
+type SQuADEvaluator struct {
+	exactMatch mat.Float
+	f1Score    mat.Float
+	count      int
+}
+
+// NewSQuADEvaluator returns a new SQuADEvaluator.
+func NewSQuADEvaluator() *SQuADEvaluator {
+	return &SQuADEvaluator{}
+}
+
+// Add evaluates the predicted answer of a question against its ground truths (the acceptable answers).
+// The empty prediction and ground truth mean no answer.
+func (e *SQuADEvaluator) Add(prediction string, groundTruths ...string) {
+	if len(groundTruths) == 0 {
+		groundTruths = []string{""}
+	}
+	var exactMatch, f1Score mat.Float
+	for _, groundTruth := range groundTruths {
+		if AnswerExactMatch(prediction, groundTruth) {
+			exactMatch = 1
+		}
+		f1Score = mat.Max(f1Score, AnswerF1Score(prediction, groundTruth))
+	}
+	e.exactMatch += exactMatch
+	e.f1Score += f1Score
+	e.count++
+}
+
+// Count returns the number of evaluated answers.
+func (e *SQuADEvaluator) Count() int {
+	return e.count
+}
+
+// ExactMatch returns the ratio of the answers which exactly match one of their ground truths.
+func (e *SQuADEvaluator) ExactMatch() mat.Float {
+	return zeroIfNaN(e.exactMatch / mat.Float(e.count))
+}
+
+// F1Score returns the average F1 score of the answers.
+func (e *SQuADEvaluator) F1Score() mat.Float {
+	return zeroIfNaN(e.f1Score / mat.Float(e.count))
+}
+
+// Summary returns the SQuADSummary of the evaluation.
+func (e *SQuADEvaluator) Summary() SQuADSummary {
+	return SQuADSummary{
+		ExactMatch: e.ExactMatch(),
+		F1Score:    e.F1Score(),
+		Count:      e.count,
+	}
+}
+
+// SQuADSummary is a report of the scores of a SQuADEvaluator, which can be printed or marshaled to JSON.
+type SQuADSummary struct {
+	ExactMatch mat.Float `json:"exact_match"`
+	F1Score    mat.Float `json:"f1_score"`
+	Count      int       `json:"count"`
+}
+
+// String returns the report as text.
+func (s SQuADSummary) String() string {
+	return fmt.Sprintf("exact match: %.4f f1-score: %.4f count: %d\n", s.ExactMatch, s.F1Score, s.Count)
+}
@@ -0,0 +1,35 @@
+// Copyright 2021 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package stats
+
+import (
+	mat "github.com/nlpodyssey/spago/pkg/mat32"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestNormalizeAnswer(t *testing.T) {
+	assert.Equal(t, "eiffel tower", NormalizeAnswer("  The Eiffel   Tower!"))
+	assert.Equal(t, "1889", NormalizeAnswer("\"1889\"."))
+	assert.True(t, AnswerExactMatch("the Eiffel Tower", "Eiffel tower"))
+	assert.Equal(t, mat.Float(0.5), AnswerF1Score("in Paris France", "Paris"))
+	assert.Equal(t, mat.Float(0), AnswerF1Score("London", "Paris"))
+	assert.Equal(t, mat.Float(1), AnswerF1Score("", "the"))
+	assert.Equal(t, mat.Float(0), AnswerF1Score("", "Paris"))
+}
+
+func TestSQuADEvaluator(t *testing.T) {
+	e := NewSQuADEvaluator()
+	assert.Equal(t, SQuADSummary{}, e.Summary())
+	e.Add("The Eiffel Tower", "Eiffel Tower", "the tower")
+	e.Add("in Paris France", "London", "Paris")
+	e.Add("")
+	e.Add("Rome", "Milan")
+
+	assert.Equal(t, 4, e.Count())
+	assert.Equal(t, mat.Float(0.5), e.ExactMatch())
+	assert.Equal(t, mat.Float(2.5/4.0), e.F1Score())
+	assert.Equal(t, "exact match: 0.5000 f1-score: 0.6250 count: 4\n", e.Summary().String())
+}
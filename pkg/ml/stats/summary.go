@@ -0,0 +1,114 @@
+// Copyright 2021 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package stats
+
+import (
+	"fmt"
+	mat "github.com/nlpodyssey/spago/pkg/mat32"
+	"sort"
+	"strings"
+)
+
+// Scores contains the precision, the recall and the F1 score of a label, or their average, with the number
+// of expected positives (the support).
+type Scores struct {
+	Precision mat.Float `json:"precision"`
+	Recall    mat.Float `json:"recall"`
+	F1Score   mat.Float `json:"f1_score"`
+	Support   int       `json:"support"`
+}
+
+// NewScores returns the Scores of the metrics.
+func NewScores(m *ClassMetrics) Scores {
+	return Scores{
+		Precision: m.Precision(),
+		Recall:    m.Recall(),
+		F1Score:   m.F1Score(),
+		Support:   m.ExpectedPos(),
+	}
+}
+
+// Summary is a report of the Scores of each label and of their averages, which can be printed (see String)
+// or marshaled to JSON.
+type Summary struct {
+	Labels map[string]Scores `json:"labels"`
+	// Micro contains the scores of the sum of the metrics of all the labels.
+	Micro Scores `json:"micro_avg"`
+	// Macro contains the unweighted mean of the scores of the labels.
+	Macro Scores `json:"macro_avg"`
+	// Weighted contains the mean of the scores of the labels, weighted by their support.
+	Weighted Scores `json:"weighted_avg"`
+}
+
+// NewSummary returns the Summary of the metrics of each label.
+func NewSummary(metrics map[string]*ClassMetrics) Summary {
+	s := Summary{
+		Labels: make(map[string]Scores, len(metrics)),
+		Micro:  NewScores(sumMetrics(metrics)),
+	}
+	for label, m := range metrics {
+		scores := NewScores(m)
+		s.Labels[label] = scores
+		s.Macro.Precision += scores.Precision
+		s.Macro.Recall += scores.Recall
+		s.Macro.F1Score += scores.F1Score
+		s.Weighted.Precision += scores.Precision * mat.Float(scores.Support)
+		s.Weighted.Recall += scores.Recall * mat.Float(scores.Support)
+		s.Weighted.F1Score += scores.F1Score * mat.Float(scores.Support)
+	}
+	s.Macro.Support = s.Micro.Support
+	s.Weighted.Support = s.Micro.Support
+	if n := len(metrics); n > 0 {
+		s.Macro.Precision /= mat.Float(n)
+		s.Macro.Recall /= mat.Float(n)
+		s.Macro.F1Score /= mat.Float(n)
+	}
+	if s.Weighted.Support > 0 {
+		s.Weighted.Precision /= mat.Float(s.Weighted.Support)
+		s.Weighted.Recall /= mat.Float(s.Weighted.Support)
+		s.Weighted.F1Score /= mat.Float(s.Weighted.Support)
+	}
+	return s
+}
+
+// String returns the report as a table, with a row for each label, sorted by name, and for each average.
+func (s Summary) String() string {
+	labels := make([]string, 0, len(s.Labels))
+	width := len("weighted avg")
+	for label := range s.Labels {
+		labels = append(labels, label)
+		if len(label) > width {
+			width = len(label)
+		}
+	}
+	sort.Strings(labels)
+
+	var sb strings.Builder
+	row := func(name string, scores Scores) {
+		fmt.Fprintf(&sb, "%*s %10.4f %10.4f %10.4f %10d\n", width, name,
+			scores.Precision, scores.Recall, scores.F1Score, scores.Support)
+	}
+	fmt.Fprintf(&sb, "%*s %10s %10s %10s %10s\n\n", width, "", "precision", "recall", "f1-score", "support")
+	for _, label := range labels {
+		row(label, s.Labels[label])
+	}
+	sb.WriteString("\n")
+	row("micro avg", s.Micro)
+	row("macro avg", s.Macro)
+	row("weighted avg", s.Weighted)
+	return sb.String()
+}
+
+// sumMetrics returns the sum of the metrics.
+func sumMetrics(metrics map[string]*ClassMetrics) *ClassMetrics {
+	sum := NewMetricCounter()
+	for _, m := range metrics {
+		sum.TruePos += m.TruePos
+		sum.TrueNeg += m.TrueNeg
+		sum.FalsePos += m.FalsePos
+		sum.FalseNeg += m.FalseNeg
+	}
+	return sum
+}
@@ -0,0 +1,228 @@
+// Copyright 2021 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package training
+
+import (
+	"fmt"
+	mat "github.com/nlpodyssey/spago/pkg/mat32"
+	"io"
+	"os"
+)
+
+// Callback is notified of the progress of the training by a Trainer.
+type Callback interface {
+	// OnStep is called after each update of the params.
+	OnStep(state *State) error
+	// OnEpochEnd is called at the end of each epoch.
+	OnEpochEnd(state *State) error
+}
+
+var (
+	_ Callback = &CallbackFuncs{}
+	_ Callback = &Logger{}
+	_ Callback = &Evaluation{}
+	_ Callback = &EarlyStopping{}
+	_ Callback = &Checkpoint{}
+	_ Callback = &LearningRateScheduler{}
+)
+
+// CallbackFuncs implements a Callback with optional functions.
+type CallbackFuncs struct {
+	Step     func(state *State) error
+	EpochEnd func(state *State) error
+}
+
+// OnStep calls the Step function, if any.
+func (c *CallbackFuncs) OnStep(state *State) error {
+	if c.Step == nil {
+		return nil
+	}
+	return c.Step(state)
+}
+
+// OnEpochEnd calls the EpochEnd function, if any.
+func (c *CallbackFuncs) OnEpochEnd(state *State) error {
+	if c.EpochEnd == nil {
+		return nil
+	}
+	return c.EpochEnd(state)
+}
+
+// Logger prints the loss every Interval steps, and the loss and the metrics at the end of each epoch.
+type Logger struct {
+	// Writer is the destination of the logs. Nil means the standard output.
+	Writer io.Writer
+	// Interval is the number of steps between two logs. Zero means no logs of the steps.
+	Interval int
+}
+
+// OnStep prints the loss of the step every Interval steps.
+func (l *Logger) OnStep(state *State) error {
+	if l.Interval <= 0 || state.Step%l.Interval != 0 {
+		return nil
+	}
+	_, err := fmt.Fprintf(l.writer(), "Epoch: %d Step: %d Loss: %.6f\n", state.Epoch, state.Step, state.Loss)
+	return err
+}
+
+// OnEpochEnd prints the loss of the epoch and the metrics.
+func (l *Logger) OnEpochEnd(state *State) error {
+	_, err := fmt.Fprintf(l.writer(), "Epoch: %d Train loss: %.6f%s\n", state.Epoch, state.EpochLoss, state.Metrics)
+	return err
+}
+
+func (l *Logger) writer() io.Writer {
+	if l.Writer == nil {
+		return os.Stdout
+	}
+	return l.Writer
+}
+
+// Evaluation evaluates the model at the end of each epoch, and optionally every Interval steps, setting the
+// metrics of the state.
+type Evaluation struct {
+	// Evaluate returns the metrics of the model (e.g. on a development set, see Evaluate).
+	Evaluate func() (Metrics, error)
+	// Interval is the number of steps between two evaluations. Zero means only at the end of the epochs.
+	Interval int
+}
+
+// OnStep evaluates the model every Interval steps.
+func (e *Evaluation) OnStep(state *State) error {
+	if e.Interval <= 0 || state.Step%e.Interval != 0 {
+		return nil
+	}
+	return e.evaluate(state)
+}
+
+// OnEpochEnd evaluates the model.
+func (e *Evaluation) OnEpochEnd(state *State) error {
+	return e.evaluate(state)
+}
+
+func (e *Evaluation) evaluate(state *State) error {
+	metrics, err := e.Evaluate()
+	if err != nil {
+		return err
+	}
+	state.Metrics = metrics
+	return nil
+}
+
+// Mode is the direction in which a metric improves.
+type Mode int
+
+const (
+	// Minimize means that the lower values are better (e.g. a loss).
+	Minimize Mode = iota
+	// Maximize means that the higher values are better (e.g. an accuracy).
+	Maximize
+)
+
+// Monitor tracks the best value of a metric of the state, set by an Evaluation.
+type Monitor struct {
+	Metric  string
+	Mode    Mode
+	best    mat.Float
+	hasBest bool
+}
+
+// Improved reports whether the metric of the state is better than the best value so far, which is updated.
+// It returns an error if the state has no such metric.
+func (m *Monitor) Improved(state *State) (bool, error) {
+	value, ok := state.Metrics[m.Metric]
+	if !ok {
+		return false, fmt.Errorf("training: metric `%s` not found", m.Metric)
+	}
+	if m.hasBest && (m.Mode == Minimize && value >= m.best || m.Mode == Maximize && value <= m.best) {
+		return false, nil
+	}
+	m.best, m.hasBest = value, true
+	return true, nil
+}
+
+// Best returns the best value of the metric so far.
+func (m *Monitor) Best() mat.Float {
+	return m.best
+}
+
+// EarlyStopping stops the training when the monitored metric does not improve for Patience epochs.
+type EarlyStopping struct {
+	Monitor
+	Patience    int
+	staleEpochs int
+}
+
+// OnStep does nothing.
+func (e *EarlyStopping) OnStep(*State) error {
+	return nil
+}
+
+// OnEpochEnd stops the training after Patience epochs without improvement of the metric.
+func (e *EarlyStopping) OnEpochEnd(state *State) error {
+	improved, err := e.Improved(state)
+	if err != nil {
+		return err
+	}
+	if improved {
+		e.staleEpochs = 0
+		return nil
+	}
+	e.staleEpochs++
+	if e.staleEpochs >= e.Patience {
+		state.Stop = true
+	}
+	return nil
+}
+
+// Checkpoint saves the model, and possibly the state of the training, every Interval steps and at the end of
+// each epoch; if a Monitor is set, only when its metric improves at the end of an epoch.
+type Checkpoint struct {
+	// Save saves the model.
+	Save func(state *State) error
+	// Interval is the number of steps between two saves. Zero means only at the end of the epochs.
+	Interval int
+	Monitor  *Monitor
+}
+
+// OnStep saves the model every Interval steps.
+func (c *Checkpoint) OnStep(state *State) error {
+	if c.Interval <= 0 || state.Step%c.Interval != 0 {
+		return nil
+	}
+	return c.Save(state)
+}
+
+// OnEpochEnd saves the model, if the monitored metric improves.
+func (c *Checkpoint) OnEpochEnd(state *State) error {
+	if c.Monitor != nil {
+		improved, err := c.Monitor.Improved(state)
+		if err != nil || !improved {
+			return err
+		}
+	}
+	return c.Save(state)
+}
+
+// LearningRateScheduler sets the learning rate of the optimization method after each step, according
+// to a schedule. Alternatively, a schedule of the lrschedule package can be applied by the optimizer
+// itself, with the gd.Schedulers option.
+type LearningRateScheduler struct {
+	// Schedule returns the learning rate of the next step.
+	Schedule func(state *State) mat.Float
+	// Set sets the learning rate of the optimization method (e.g. gd.LearningRateMethod.SetLearningRate).
+	Set func(lr mat.Float)
+}
+
+// OnStep sets the learning rate of the next step.
+func (s *LearningRateScheduler) OnStep(state *State) error {
+	s.Set(s.Schedule(state))
+	return nil
+}
+
+// OnEpochEnd does nothing.
+func (s *LearningRateScheduler) OnEpochEnd(*State) error {
+	return nil
+}
@@ -0,0 +1,88 @@
+// Copyright 2021 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package training
+
+import (
+	"bytes"
+	mat "github.com/nlpodyssey/spago/pkg/mat32"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+// metricEvaluation returns an Evaluation of the metric with the given values, one for each epoch.
+func metricEvaluation(metric string, values ...mat.Float) *Evaluation {
+	i := 0
+	return &Evaluation{
+		Evaluate: func() (Metrics, error) {
+			i++
+			return Metrics{metric: values[i-1]}, nil
+		},
+	}
+}
+
+func TestEarlyStopping(t *testing.T) {
+	model := newLinearRegression()
+	evaluation := metricEvaluation("F1", 0.5, 0.7, 0.6, 0.7, 0.8, 0.9)
+	earlyStopping := &EarlyStopping{Monitor: Monitor{Metric: "F1", Mode: Maximize}, Patience: 2}
+	trainer := New(Config{Epochs: 6}, model.optimizer(0.1), model.learn, evaluation, earlyStopping)
+	assert.NoError(t, trainer.Train(newExamples(2)))
+
+	assert.Equal(t, 4, trainer.State.Epoch)
+	assert.Equal(t, mat.Float(0.7), earlyStopping.Best())
+}
+
+func TestCheckpoint(t *testing.T) {
+	model := newLinearRegression()
+	var saved []int
+	checkpoint := &Checkpoint{
+		Save: func(state *State) error {
+			saved = append(saved, state.Step)
+			return nil
+		},
+		Interval: 3,
+		Monitor:  &Monitor{Metric: "Loss", Mode: Minimize},
+	}
+	evaluation := metricEvaluation("Loss", 0.5, 0.3, 0.4)
+	trainer := New(Config{Epochs: 3, BatchSize: 2}, model.optimizer(0.1), model.learn, evaluation, checkpoint)
+	assert.NoError(t, trainer.Train(newExamples(4)))
+
+	assert.Equal(t, []int{2, 3, 4, 6}, saved)
+}
+
+func TestMonitor_MissingMetric(t *testing.T) {
+	monitor := &Monitor{Metric: "F1"}
+	_, err := monitor.Improved(&State{Metrics: Metrics{"Accuracy": 1}})
+	assert.Error(t, err)
+}
+
+func TestLogger(t *testing.T) {
+	model := newLinearRegression()
+	var buf bytes.Buffer
+	logger := &Logger{Writer: &buf, Interval: 2}
+	evaluation := metricEvaluation("F1", 0.5)
+	trainer := New(Config{Epochs: 1}, model.optimizer(0), model.learn, evaluation, logger)
+	assert.NoError(t, trainer.Train(NewExamples([]example{{x: 1, y: 1}, {x: 1, y: 3}})))
+
+	expected := "Epoch: 1 Step: 2 Loss: 9.000000\n" +
+		"Epoch: 1 Train loss: 5.000000 F1: 0.5000\n"
+	assert.Equal(t, expected, buf.String())
+}
+
+func TestLearningRateScheduler(t *testing.T) {
+	model := newLinearRegression()
+	var rates []mat.Float
+	scheduler := &LearningRateScheduler{
+		Schedule: func(state *State) mat.Float {
+			return 1 / mat.Float(state.Step+1)
+		},
+		Set: func(lr mat.Float) {
+			rates = append(rates, lr)
+		},
+	}
+	trainer := New(Config{Epochs: 2}, model.optimizer(0.1), model.learn, scheduler)
+	assert.NoError(t, trainer.Train(newExamples(2)))
+
+	assert.Equal(t, []mat.Float{1.0 / 2, 1.0 / 3, 1.0 / 4, 1.0 / 5}, rates)
+}
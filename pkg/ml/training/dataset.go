@@ -0,0 +1,89 @@
+// Copyright 2021 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package training
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Dataset is implemented by any collection of examples which can be iterated, once for each epoch.
+type Dataset interface {
+	// ForEach calls the callback for each example, in order, until the callback returns false.
+	ForEach(callback func(example interface{}) bool) error
+}
+
+// IndexedDataset is implemented by the datasets with random access to the examples, which can be shuffled.
+type IndexedDataset interface {
+	Dataset
+	// Len returns the number of examples.
+	Len() int
+	// Example returns the i-th example.
+	Example(i int) interface{}
+}
+
+var (
+	_ IndexedDataset = Examples{}
+	_ Dataset        = DatasetFunc(nil)
+)
+
+// Examples is an IndexedDataset of examples in memory.
+type Examples []interface{}
+
+// NewExamples returns the Examples of a slice of any type (e.g. []Example). It panics if the value is not
+// a slice.
+func NewExamples(slice interface{}) Examples {
+	v := reflect.ValueOf(slice)
+	if v.Kind() != reflect.Slice {
+		panic(fmt.Errorf("training: expected a slice, got %T", slice))
+	}
+	examples := make(Examples, v.Len())
+	for i := range examples {
+		examples[i] = v.Index(i).Interface()
+	}
+	return examples
+}
+
+// ForEach calls the callback for each example, in order, until the callback returns false.
+func (e Examples) ForEach(callback func(example interface{}) bool) error {
+	for _, example := range e {
+		if !callback(example) {
+			break
+		}
+	}
+	return nil
+}
+
+// Len returns the number of examples.
+func (e Examples) Len() int {
+	return len(e)
+}
+
+// Example returns the i-th example.
+func (e Examples) Example(i int) interface{} {
+	return e[i]
+}
+
+// DatasetFunc is a function which implements the Dataset interface, e.g. to stream the lines of a corpus.
+type DatasetFunc func(callback func(example interface{}) bool) error
+
+// ForEach calls the callback for each example, in order, until the callback returns false.
+func (f DatasetFunc) ForEach(callback func(example interface{}) bool) error {
+	return f(callback)
+}
+
+// Lines returns a Dataset of the lines of a text corpus, given its iteration function, which calls the
+// callback for each line (e.g. corpora.TextCorpusIterator.ForEachLine). The examples are strings.
+func Lines(forEachLine func(callback func(i int, line string))) Dataset {
+	return DatasetFunc(func(callback func(example interface{}) bool) error {
+		stopped := false
+		forEachLine(func(_ int, line string) {
+			if !stopped {
+				stopped = !callback(line)
+			}
+		})
+		return nil
+	})
+}
@@ -0,0 +1,124 @@
+// Copyright 2021 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package training
+
+import (
+	"fmt"
+	mat "github.com/nlpodyssey/spago/pkg/mat32"
+	"github.com/nlpodyssey/spago/pkg/ml/stats"
+	"sort"
+	"strings"
+)
+
+// Metrics maps the names of the metrics to their values.
+type Metrics map[string]mat.Float
+
+// String returns the metrics sorted by name, each one preceded by a space (e.g. " Accuracy: 0.9500").
+func (m Metrics) String() string {
+	names := make([]string, 0, len(m))
+	for name := range m {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var sb strings.Builder
+	for _, name := range names {
+		fmt.Fprintf(&sb, " %s: %.4f", name, m[name])
+	}
+	return sb.String()
+}
+
+// Metric accumulates the comparison of the predicted values with the expected ones on a set of examples.
+type Metric interface {
+	// Reset clears the accumulated values.
+	Reset()
+	// Add compares the predicted value of an example with the expected one.
+	Add(expected, predicted interface{})
+	// Value returns the value of the metric.
+	Value() mat.Float
+}
+
+var (
+	_ Metric = &Accuracy{}
+	_ Metric = &MacroF1Score{}
+)
+
+// Accuracy is the ratio of the predicted values equal to the expected ones.
+type Accuracy struct {
+	correct int
+	total   int
+}
+
+// Reset clears the accumulated values.
+func (a *Accuracy) Reset() {
+	a.correct, a.total = 0, 0
+}
+
+// Add compares the predicted value of an example with the expected one.
+func (a *Accuracy) Add(expected, predicted interface{}) {
+	if expected == predicted {
+		a.correct++
+	}
+	a.total++
+}
+
+// Value returns the accuracy.
+func (a *Accuracy) Value() mat.Float {
+	if a.total == 0 {
+		return 0
+	}
+	return mat.Float(a.correct) / mat.Float(a.total)
+}
+
+// MacroF1Score is the unweighted mean of the F1 scores of the classes, for multi-class classification.
+// The classes are the string representations of the values.
+type MacroF1Score struct {
+	matrix *stats.ConfusionMatrix
+}
+
+// Reset clears the accumulated values.
+func (m *MacroF1Score) Reset() {
+	m.matrix = nil
+}
+
+// Add compares the predicted class of an example with the expected one.
+func (m *MacroF1Score) Add(expected, predicted interface{}) {
+	if m.matrix == nil {
+		m.matrix = stats.NewConfusionMatrix()
+	}
+	m.matrix.Add(fmt.Sprint(expected), fmt.Sprint(predicted))
+}
+
+// Value returns the macro-averaged F1 score.
+func (m *MacroF1Score) Value() mat.Float {
+	if m.matrix == nil {
+		return 0
+	}
+	return m.matrix.Summary().Macro.F1Score
+}
+
+// PredictFunc returns the expected and the predicted values of an example.
+type PredictFunc func(example interface{}) (expected, predicted interface{})
+
+// Evaluate returns the values of the metrics on the examples of the dataset, predicted by the function.
+func Evaluate(dataset Dataset, predict PredictFunc, metrics map[string]Metric) (Metrics, error) {
+	for _, metric := range metrics {
+		metric.Reset()
+	}
+	err := dataset.ForEach(func(example interface{}) bool {
+		expected, predicted := predict(example)
+		for _, metric := range metrics {
+			metric.Add(expected, predicted)
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	values := make(Metrics, len(metrics))
+	for name, metric := range metrics {
+		values[name] = metric.Value()
+	}
+	return values, nil
+}
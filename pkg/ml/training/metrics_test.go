@@ -0,0 +1,39 @@
+// Copyright 2021 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package training
+
+import (
+	mat "github.com/nlpodyssey/spago/pkg/mat32"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestEvaluate(t *testing.T) {
+	dataset := NewExamples([][2]string{
+		{"cat", "cat"},
+		{"cat", "dog"},
+		{"dog", "dog"},
+		{"bird", "dog"},
+	})
+	predict := func(example interface{}) (interface{}, interface{}) {
+		pair := example.([2]string)
+		return pair[0], pair[1]
+	}
+	metrics := map[string]Metric{
+		"Accuracy": &Accuracy{},
+		"F1":       &MacroF1Score{},
+	}
+	values, err := Evaluate(dataset, predict, metrics)
+	assert.NoError(t, err)
+
+	assert.Equal(t, mat.Float(0.5), values["Accuracy"])
+	// cat: P=1 R=0.5 F1=2/3; dog: P=1/3 R=1 F1=0.5; bird: F1=0
+	assert.InDelta(t, (2.0/3+0.5)/3, values["F1"], 1.0e-6)
+	assert.Equal(t, " Accuracy: 0.5000 F1: 0.3889", values.String())
+
+	values, err = Evaluate(dataset[:1], predict, metrics)
+	assert.NoError(t, err)
+	assert.Equal(t, Metrics{"Accuracy": 1, "F1": 1}, values)
+}
@@ -0,0 +1,223 @@
+// Copyright 2021 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package training provides a reusable training loop: the examples of a Dataset are grouped in minibatches,
+// optionally shuffled, and learned by a LearnFunc, whose gradients can be accumulated over several batches
+// before each update of the params. The progress of the training is notified to a list of Callbacks
+// (e.g. logging, evaluation, early stopping, checkpointing and learning rate scheduling).
+package training
+
+import (
+	"fmt"
+	mat "github.com/nlpodyssey/spago/pkg/mat32"
+	"github.com/nlpodyssey/spago/pkg/mat32/rand"
+	"github.com/nlpodyssey/spago/pkg/ml/optimizers/gd"
+)
+
+// Config provides configuration settings for a Trainer.
+type Config struct {
+	Epochs int
+	// BatchSize is the number of examples of a batch. Zero means one.
+	BatchSize int
+	// GradientAccumulationSteps is the number of batches whose gradients are accumulated before each update
+	// of the params, so that the effective batch size is BatchSize*GradientAccumulationSteps. Zero means one.
+	GradientAccumulationSteps int
+	// Shuffle reports whether the examples are shuffled at each epoch; the Dataset must be an IndexedDataset.
+	// The order of the examples of an epoch depends only on the Seed and on the epoch.
+	Shuffle bool
+	Seed    uint64
+}
+
+// LearnFunc computes the loss of a batch of examples, and propagates its gradients multiplied by the gradient
+// scale, which is the inverse of the number of batches accumulated before the update of the params, so that
+// the accumulated gradients are the average of the batches. The gradient scale is 1/GradientAccumulationSteps,
+// except for the last group of batches of an epoch, which can be smaller.
+// It returns the average loss of the examples of the batch.
+type LearnFunc func(batch []interface{}, gradScale mat.Float) mat.Float
+
+// State is the state of the training, which is notified to the Callbacks.
+type State struct {
+	// Epoch is the current epoch, starting from 1.
+	Epoch int
+	// Step is the number of updates of the params.
+	Step int
+	// Example is the number of examples of the current epoch already learned.
+	Example int
+	// Loss is the average loss of the batches of the last update.
+	Loss mat.Float
+	// EpochLoss is the average loss of the batches of the current epoch so far.
+	EpochLoss mat.Float
+	// Metrics contains the results of the last evaluation, if any (see Evaluation).
+	Metrics Metrics
+	// Stop, set by a Callback, stops the training after the current update.
+	Stop bool
+
+	epochLossSum mat.Float
+	epochBatches int
+}
+
+// Trainer implements a training loop.
+type Trainer struct {
+	Config
+	// State is the state of the training. It can be set before Train to resume an interrupted training: the
+	// epoch is resumed skipping the examples already learned, if any, otherwise the next epoch is started.
+	State     State
+	optimizer *gd.GradientDescent
+	learn     LearnFunc
+	callbacks []Callback
+}
+
+// New returns a new Trainer, which updates the params with the optimizer after the learning of each group of
+// batches, and notifies the callbacks, in order. If the optimizer is nil, the LearnFunc is in charge of the
+// updates, and each batch is a step.
+func New(config Config, optimizer *gd.GradientDescent, learn LearnFunc, callbacks ...Callback) *Trainer {
+	if config.BatchSize <= 0 {
+		config.BatchSize = 1
+	}
+	if config.GradientAccumulationSteps <= 0 || optimizer == nil {
+		config.GradientAccumulationSteps = 1
+	}
+	return &Trainer{
+		Config:    config,
+		optimizer: optimizer,
+		learn:     learn,
+		callbacks: callbacks,
+	}
+}
+
+// Train trains the model on the dataset for the configured number of epochs, unless a Callback stops the
+// training earlier.
+func (t *Trainer) Train(dataset Dataset) error {
+	if _, ok := dataset.(IndexedDataset); t.Shuffle && !ok {
+		return fmt.Errorf("training: the shuffling requires an IndexedDataset, got %T", dataset)
+	}
+	s := &t.State
+	s.Stop = false
+	epoch := s.Epoch + 1
+	if s.Example > 0 {
+		epoch = s.Epoch // resume the interrupted epoch
+	}
+	for ; epoch <= t.Epochs && !s.Stop; epoch++ {
+		if err := t.trainEpoch(dataset, epoch); err != nil {
+			return err
+		}
+		if s.Stop {
+			break
+		}
+		for _, callback := range t.callbacks {
+			if err := callback.OnEpochEnd(s); err != nil {
+				return err
+			}
+		}
+		s.Example = 0
+	}
+	return nil
+}
+
+// trainEpoch performs an epoch of training, skipping the examples already learned in the epoch.
+// The schedulers of the optimizer are notified of the new epoch only when it is not resumed.
+func (t *Trainer) trainEpoch(dataset Dataset, epoch int) error {
+	s := &t.State
+	skip := 0
+	if s.Epoch == epoch {
+		skip = s.Example
+	} else {
+		s.Epoch, s.Example, s.EpochLoss, s.epochLossSum, s.epochBatches = epoch, 0, 0, 0, 0
+		if t.optimizer != nil {
+			t.optimizer.IncEpoch()
+		}
+	}
+
+	// the examples of the batches whose gradients are accumulated before an update of the params
+	groupSize := t.BatchSize * t.GradientAccumulationSteps
+	group := make([]interface{}, 0, groupSize)
+	var callbackErr error
+	err := t.forEachExample(dataset, skip, func(example interface{}) bool {
+		group = append(group, example)
+		if len(group) < groupSize {
+			return true
+		}
+		callbackErr = t.learnGroup(group)
+		group = group[:0]
+		return callbackErr == nil && !s.Stop
+	})
+	if err != nil {
+		return err
+	}
+	if callbackErr == nil && !s.Stop && len(group) > 0 {
+		callbackErr = t.learnGroup(group) // the last examples of the epoch
+	}
+	return callbackErr
+}
+
+// learnGroup learns the batches of a group of examples, accumulating their gradients, then updates the params
+// and notifies the callbacks. The gradients are scaled by the actual number of batches of the group.
+func (t *Trainer) learnGroup(examples []interface{}) error {
+	s := &t.State
+	batches := (len(examples) + t.BatchSize - 1) / t.BatchSize
+	gradScale := 1 / mat.Float(batches)
+	if t.optimizer != nil {
+		t.optimizer.IncBatch()
+	}
+	var loss mat.Float
+	for start := 0; start < len(examples); start += t.BatchSize {
+		end := start + t.BatchSize
+		if end > len(examples) {
+			end = len(examples)
+		}
+		batch := examples[start:end]
+		if t.optimizer != nil {
+			for range batch {
+				t.optimizer.IncExample()
+			}
+		}
+		batchLoss := t.learn(batch, gradScale)
+		s.Example += len(batch)
+		loss += batchLoss
+		s.epochLossSum += batchLoss
+		s.epochBatches++
+		s.EpochLoss = s.epochLossSum / mat.Float(s.epochBatches)
+	}
+	return t.step(loss / mat.Float(batches))
+}
+
+// forEachExample calls the callback for each example of the epoch, shuffled if required, after skipping
+// the given number of examples.
+func (t *Trainer) forEachExample(dataset Dataset, skip int, callback func(example interface{}) bool) error {
+	if indexed, ok := dataset.(IndexedDataset); ok && t.Shuffle {
+		indices := rand.NewLockedRand(t.Seed + uint64(t.State.Epoch)).Perm(indexed.Len())
+		if skip > len(indices) {
+			skip = len(indices)
+		}
+		for _, i := range indices[skip:] {
+			if !callback(indexed.Example(i)) {
+				break
+			}
+		}
+		return nil
+	}
+	i := 0
+	return dataset.ForEach(func(example interface{}) bool {
+		i++
+		if i <= skip {
+			return true // already learned before the interruption
+		}
+		return callback(example)
+	})
+}
+
+// step updates the params and notifies the callbacks.
+func (t *Trainer) step(loss mat.Float) error {
+	if t.optimizer != nil {
+		t.optimizer.Optimize()
+	}
+	t.State.Step++
+	t.State.Loss = loss
+	for _, callback := range t.callbacks {
+		if err := callback.OnStep(&t.State); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,255 @@
+// Copyright 2021 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package training
+
+import (
+	mat "github.com/nlpodyssey/spago/pkg/mat32"
+	"github.com/nlpodyssey/spago/pkg/ml/nn"
+	"github.com/nlpodyssey/spago/pkg/ml/optimizers/gd"
+	"github.com/nlpodyssey/spago/pkg/ml/optimizers/gd/sgd"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+type paramsList []nn.Param
+
+func (p paramsList) Params() []nn.Param {
+	return p
+}
+
+type example struct {
+	x, y mat.Float
+}
+
+// newExamples returns examples of the function y = 3x.
+func newExamples(n int) Examples {
+	examples := make([]example, n)
+	for i := range examples {
+		x := mat.Float(i+1) / mat.Float(n)
+		examples[i] = example{x: x, y: 3 * x}
+	}
+	return NewExamples(examples)
+}
+
+// linearRegression learns the weight of the function y = wx with the squared error.
+type linearRegression struct {
+	w       nn.Param
+	learned []interface{}
+}
+
+func newLinearRegression() *linearRegression {
+	return &linearRegression{w: nn.NewParam(mat.NewScalar(0))}
+}
+
+func (r *linearRegression) optimizer(lr mat.Float) *gd.GradientDescent {
+	return gd.NewOptimizer(sgd.New(sgd.NewConfig(lr, 0, false)), paramsList{r.w})
+}
+
+func (r *linearRegression) learn(batch []interface{}, gradScale mat.Float) mat.Float {
+	w := r.w.ScalarValue()
+	var loss, grad mat.Float
+	for _, e := range batch {
+		e := e.(example)
+		diff := w*e.x - e.y
+		loss += diff * diff
+		grad += 2 * diff * e.x
+	}
+	n := mat.Float(len(batch))
+	r.w.PropagateGrad(mat.NewScalar(grad * gradScale / n))
+	r.learned = append(r.learned, batch...)
+	return loss / n
+}
+
+func TestTrainer_Train(t *testing.T) {
+	model := newLinearRegression()
+	config := Config{Epochs: 100, BatchSize: 2, Shuffle: true, Seed: 42}
+	trainer := New(config, model.optimizer(0.5), model.learn)
+	assert.NoError(t, trainer.Train(newExamples(5)))
+
+	assert.InDelta(t, 3.0, model.w.ScalarValue(), 1.0e-4)
+	assert.Equal(t, 100, trainer.State.Epoch)
+	assert.Equal(t, 300, trainer.State.Step)
+	assert.Equal(t, 0, trainer.State.Example)
+	assert.Len(t, model.learned, 500)
+	assert.InDelta(t, 0.0, trainer.State.EpochLoss, 1.0e-6)
+}
+
+func TestTrainer_GradientAccumulation(t *testing.T) {
+	batched := newLinearRegression()
+	trainer := New(Config{Epochs: 3, BatchSize: 4}, batched.optimizer(0.1), batched.learn)
+	assert.NoError(t, trainer.Train(newExamples(8)))
+
+	accumulated := newLinearRegression()
+	config := Config{Epochs: 3, BatchSize: 2, GradientAccumulationSteps: 2}
+	var steps, examples []int
+	callback := &CallbackFuncs{
+		Step: func(state *State) error {
+			steps = append(steps, state.Step)
+			examples = append(examples, state.Example)
+			return nil
+		},
+	}
+	trainer = New(config, accumulated.optimizer(0.1), accumulated.learn, callback)
+	assert.NoError(t, trainer.Train(newExamples(8)))
+
+	assert.InDelta(t, batched.w.ScalarValue(), accumulated.w.ScalarValue(), 1.0e-6)
+	assert.Equal(t, []int{1, 2, 3, 4, 5, 6}, steps)
+	assert.Equal(t, []int{4, 8, 4, 8, 4, 8}, examples)
+}
+
+func TestTrainer_LastBatches(t *testing.T) {
+	batched := newLinearRegression()
+	trainer := New(Config{Epochs: 1, BatchSize: 4}, batched.optimizer(0.1), batched.learn)
+	assert.NoError(t, trainer.Train(newExamples(5)))
+
+	model := newLinearRegression()
+	config := Config{Epochs: 1, BatchSize: 2, GradientAccumulationSteps: 2}
+	var examples []int
+	var gradScales []mat.Float
+	callback := &CallbackFuncs{
+		Step: func(state *State) error {
+			examples = append(examples, state.Example)
+			return nil
+		},
+	}
+	learn := func(batch []interface{}, gradScale mat.Float) mat.Float {
+		gradScales = append(gradScales, gradScale)
+		return model.learn(batch, gradScale)
+	}
+	trainer = New(config, model.optimizer(0.1), learn, callback)
+	assert.NoError(t, trainer.Train(newExamples(5)))
+
+	assert.Equal(t, []int{4, 5}, examples)
+	assert.Len(t, model.learned, 5)
+	// the last group is made of a single batch
+	assert.Equal(t, []mat.Float{0.5, 0.5, 1}, gradScales)
+	assert.InDelta(t, batched.w.ScalarValue(), model.w.ScalarValue(), 1.0e-6)
+}
+
+func TestTrainer_Shuffle(t *testing.T) {
+	dataset := newExamples(10)
+	run := func() []interface{} {
+		model := newLinearRegression()
+		trainer := New(Config{Epochs: 2, Shuffle: true, Seed: 1}, model.optimizer(0.1), model.learn)
+		assert.NoError(t, trainer.Train(dataset))
+		return model.learned
+	}
+	learned := run()
+
+	assert.Equal(t, learned, run())
+	assert.NotEqual(t, []interface{}(dataset), learned[:10])
+	assert.NotEqual(t, learned[:10], learned[10:])
+	assert.ElementsMatch(t, []interface{}(dataset), learned[:10])
+	assert.ElementsMatch(t, []interface{}(dataset), learned[10:])
+}
+
+func TestTrainer_ShuffleRequiresIndexedDataset(t *testing.T) {
+	model := newLinearRegression()
+	trainer := New(Config{Epochs: 1, Shuffle: true}, model.optimizer(0.1), model.learn)
+	dataset := DatasetFunc(newExamples(3).ForEach)
+	assert.Error(t, trainer.Train(dataset))
+}
+
+func TestTrainer_Resume(t *testing.T) {
+	dataset := newExamples(6)
+	config := Config{Epochs: 2, BatchSize: 2, Shuffle: true, Seed: 7}
+
+	original := newLinearRegression()
+	assert.NoError(t, New(config, original.optimizer(0.1), original.learn).Train(dataset))
+
+	interrupted := newLinearRegression()
+	stop := &CallbackFuncs{
+		Step: func(state *State) error {
+			state.Stop = state.Step == 4
+			return nil
+		},
+	}
+	trainer := New(config, interrupted.optimizer(0.1), interrupted.learn, stop)
+	assert.NoError(t, trainer.Train(dataset))
+	assert.Equal(t, State{Epoch: 2, Step: 4, Example: 2}, withoutLosses(trainer.State))
+
+	resumed := New(config, interrupted.optimizer(0.1), interrupted.learn)
+	resumed.State = trainer.State
+	assert.NoError(t, resumed.Train(dataset))
+
+	assert.Equal(t, original.learned, interrupted.learned)
+	assert.InDelta(t, original.w.ScalarValue(), interrupted.w.ScalarValue(), 1.0e-6)
+	assert.Equal(t, 6, resumed.State.Step)
+}
+
+// epochCounter is a gd.EpochScheduler which counts the epochs.
+type epochCounter struct {
+	epochs int
+}
+
+func (c *epochCounter) IncEpoch() {
+	c.epochs++
+}
+
+func TestTrainer_ResumeSchedulers(t *testing.T) {
+	dataset := newExamples(6)
+	config := Config{Epochs: 3, BatchSize: 2}
+
+	model := newLinearRegression()
+	counter := &epochCounter{}
+	optimizer := gd.NewOptimizer(sgd.New(sgd.NewConfig(0.1, 0, false)), paramsList{model.w}, gd.Schedulers(counter))
+	stop := &CallbackFuncs{
+		Step: func(state *State) error {
+			state.Stop = state.Step == 4
+			return nil
+		},
+	}
+	trainer := New(config, optimizer, model.learn, stop)
+	assert.NoError(t, trainer.Train(dataset))
+	assert.Equal(t, State{Epoch: 2, Step: 4, Example: 2}, withoutLosses(trainer.State))
+	assert.Equal(t, 2, counter.epochs)
+
+	// the optimizer is restored along with the state, e.g. from a gd.Checkpoint
+	resumed := New(config, optimizer, model.learn)
+	resumed.State = trainer.State
+	assert.NoError(t, resumed.Train(dataset))
+	assert.Equal(t, 3, counter.epochs, "the resumed epoch is not notified again")
+	assert.Equal(t, 9, resumed.State.Step)
+}
+
+func withoutLosses(state State) State {
+	return State{Epoch: state.Epoch, Step: state.Step, Example: state.Example}
+}
+
+func TestTrainer_ManualOptimization(t *testing.T) {
+	var batches [][]interface{}
+	learn := func(batch []interface{}, gradScale mat.Float) mat.Float {
+		assert.Equal(t, mat.Float(1), gradScale)
+		batches = append(batches, append([]interface{}(nil), batch...))
+		return 0
+	}
+	trainer := New(Config{Epochs: 1, BatchSize: 2, GradientAccumulationSteps: 3}, nil, learn)
+	assert.NoError(t, trainer.Train(newExamples(3)))
+
+	assert.Len(t, batches, 2)
+	assert.Equal(t, 2, trainer.State.Step)
+}
+
+func TestNewExamples(t *testing.T) {
+	examples := NewExamples([]string{"a", "b"})
+	assert.Equal(t, 2, examples.Len())
+	assert.Equal(t, "b", examples.Example(1))
+	assert.Panics(t, func() { NewExamples("a") })
+}
+
+func TestLines(t *testing.T) {
+	forEachLine := func(callback func(i int, line string)) {
+		for i, line := range []string{"a", "b", "c"} {
+			callback(i+1, line)
+		}
+	}
+	var lines []interface{}
+	err := Lines(forEachLine).ForEach(func(example interface{}) bool {
+		lines = append(lines, example)
+		return len(lines) < 2
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []interface{}{"a", "b"}, lines)
+}
@@ -10,11 +10,11 @@ package charlm
 
 import (
 	"encoding/gob"
-	mat "github.com/nlpodyssey/spago/pkg/mat32"
 	"github.com/nlpodyssey/spago/pkg/mat32/rand"
 	"github.com/nlpodyssey/spago/pkg/ml/ag"
 	"github.com/nlpodyssey/spago/pkg/ml/initializers"
 	"github.com/nlpodyssey/spago/pkg/ml/nn"
+	"github.com/nlpodyssey/spago/pkg/ml/nn/embedding"
 	"github.com/nlpodyssey/spago/pkg/ml/nn/linear"
 	"github.com/nlpodyssey/spago/pkg/ml/nn/recurrent/lstm"
 	"github.com/nlpodyssey/spago/pkg/nlp/vocabulary"
@@ -40,10 +40,8 @@ type Model struct {
 	Decoder          *linear.Model
 	Projection       *linear.Model
 	RNN              *lstm.Model
-	Embeddings       []nn.Param `spago:"type:weights;scope:model"`
-	Vocabulary       *vocabulary.Vocabulary
-	UsedEmbeddings   map[int]ag.Node `spago:"scope:processor"`
-	UnknownEmbedding ag.Node         `spago:"scope:processor"`
+	Embeddings *embedding.Model
+	Vocabulary *vocabulary.Vocabulary
 }
 
 func init() {
@@ -67,7 +65,7 @@ func New(config Config) *Model {
 			Decoder:    linear.New(config.OutputSize, config.VocabularySize),
 			Projection: linear.New(config.HiddenSize, config.OutputSize),
 			RNN:        lstm.New(config.EmbeddingSize, config.HiddenSize),
-			Embeddings: newEmptyEmbeddings(config.VocabularySize, config.EmbeddingSize),
+			Embeddings: embedding.New(config.VocabularySize, config.EmbeddingSize),
 		}
 	}
 
@@ -77,18 +75,10 @@ func New(config Config) *Model {
 		Decoder:    linear.New(config.HiddenSize, config.VocabularySize),
 		Projection: linear.New(config.HiddenSize, config.HiddenSize), // TODO: Find a way to set to nil?
 		RNN:        lstm.New(config.EmbeddingSize, config.HiddenSize),
-		Embeddings: newEmptyEmbeddings(config.VocabularySize, config.EmbeddingSize),
+		Embeddings: embedding.New(config.VocabularySize, config.EmbeddingSize),
 	}
 }
 
-func newEmptyEmbeddings(vocabularySize, embeddingSize int) []nn.Param {
-	embeddings := make([]nn.Param, vocabularySize)
-	for i := range embeddings {
-		embeddings[i] = nn.NewParam(mat.NewEmptyVecDense(embeddingSize))
-	}
-	return embeddings
-}
-
 // Initialize initializes the Model m using the given random generator.
 func (m *Model) Initialize(rndGen *rand.LockedRand) {
 	nn.ForEachParam(m, func(param nn.Param) {
@@ -101,12 +91,6 @@ func (m *Model) Initialize(rndGen *rand.LockedRand) {
 	})
 }
 
-// InitProcessor initializes embeddings needed by the Forward().
-func (m *Model) InitProcessor() {
-	m.UsedEmbeddings = make(map[int]ag.Node)
-	m.UnknownEmbedding = m.Graph().NewWrap(m.Embeddings[m.Vocabulary.MustID(m.UnknownToken)])
-}
-
 // Forward performs the forward step for each input and returns the result.
 func (m *Model) Forward(in interface{}) interface{} {
 	xs := in.([]string)
@@ -131,21 +115,15 @@ func (m *Model) UseProjection(xs []ag.Node) []ag.Node {
 }
 
 // GetEmbeddings transforms the string sequence xs into a sequence of
-// embeddings nodes.
+// embeddings nodes. The unknown items are encoded with the embedding of the UnknownToken.
 func (m *Model) GetEmbeddings(xs []string) []ag.Node {
-	ys := make([]ag.Node, len(xs))
+	ids := make([]int, len(xs))
 	for i, item := range xs {
 		id, ok := m.Vocabulary.ID(item)
 		if !ok {
-			ys[i] = m.UnknownEmbedding
-			continue
+			id = m.Vocabulary.MustID(m.UnknownToken)
 		}
-		if embedding, ok := m.UsedEmbeddings[id]; ok {
-			ys[i] = embedding
-			continue
-		}
-		ys[i] = m.Graph().NewWrap(m.Embeddings[id])
-		m.UsedEmbeddings[id] = ys[i]
+		ids[i] = id
 	}
-	return ys
+	return m.Embeddings.Encode(ids)
 }
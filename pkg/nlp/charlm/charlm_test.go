@@ -0,0 +1,38 @@
+// Copyright 2021 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package charlm
+
+import (
+	mat "github.com/nlpodyssey/spago/pkg/mat32"
+	"github.com/nlpodyssey/spago/pkg/ml/ag"
+	"github.com/nlpodyssey/spago/pkg/ml/nn"
+	"github.com/nlpodyssey/spago/pkg/nlp/vocabulary"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestModel_GetEmbeddings(t *testing.T) {
+	model := New(Config{VocabularySize: 3, EmbeddingSize: 2, HiddenSize: 2})
+	model.Vocabulary = vocabulary.New([]string{"a", "b", DefaultUnknownToken})
+	model.Embeddings.W.Value().SetData([]mat.Float{
+		0.1, 0.2,
+		0.3, 0.4,
+		0.5, 0.6,
+	})
+
+	g := ag.NewGraph()
+	proc := nn.Reify(nn.Context{Graph: g, Mode: nn.Training}, model).(*Model)
+	ys := proc.GetEmbeddings([]string{"b", "x", "b", "y"})
+	assert.Same(t, ys[0], ys[2])
+	assert.Same(t, ys[1], ys[3], "the unknown items share the embedding of the unknown token")
+	assert.Equal(t, []mat.Float{0.3, 0.4}, ys[0].Value().Data())
+	assert.Equal(t, []mat.Float{0.5, 0.6}, ys[1].Value().Data())
+
+	g.Backward(g.ReduceSum(g.Add(ys[0], ys[1])))
+	grad, ok := model.Embeddings.W.Grad().(*mat.RowSparse)
+	if assert.True(t, ok, "the gradients of the embeddings are row-sparse") {
+		assert.ElementsMatch(t, []int{1, 2}, grad.RowIndices())
+	}
+}
@@ -16,7 +16,6 @@ import (
 	"github.com/nlpodyssey/spago/pkg/ml/nn/recurrent/lstm"
 	"github.com/nlpodyssey/spago/pkg/nlp/embeddings"
 	"github.com/nlpodyssey/spago/pkg/nlp/vocabulary"
-	"github.com/nlpodyssey/spago/pkg/utils"
 	"github.com/nlpodyssey/spago/pkg/utils/gopickleutils"
 	"io"
 	"io/ioutil"
@@ -25,8 +24,10 @@ import (
 )
 
 const (
-	defaultModelFilename     = "model.bin"
-	defaultConfigFilename    = "config.json"
+	// DefaultModelFilename is the default name of the model file in the model folder.
+	DefaultModelFilename = "model.bin"
+	// DefaultConfigFilename is the default name of the configuration file in the model folder.
+	DefaultConfigFilename    = "config.json"
 	defaultSequenceSeparator = "\n"
 	defaultUnknownToken      = "<unk>"
 )
@@ -61,10 +62,10 @@ func Convert(modelPath string, flairModelName, configFileName, modelFileName str
 	defer embeddings.Close()
 
 	if configFileName == "" {
-		configFileName = defaultConfigFilename
+		configFileName = DefaultConfigFilename
 	}
 	if modelFileName == "" {
-		modelFileName = defaultModelFilename
+		modelFileName = DefaultModelFilename
 	}
 
 	c := newConverter(path.Join(modelPath, flairModelName))
@@ -86,10 +87,7 @@ func Convert(modelPath string, flairModelName, configFileName, modelFileName str
 	dict := c.tagger.MustGet("dictionary").(*flairDictionary)
 	lm.Vocabulary = vocabulary.New(dict.GetItems())
 
-	assignToParamsList(stateDict["embeddings.weight"],
-		lm.Embeddings,
-		lm.Config.VocabularySize,
-		lm.Config.EmbeddingSize)
+	lm.Embeddings.W.SetData(stateDict["embeddings.weight"])
 	delete(stateDict, "embeddings.weight") // already assigned
 
 	c.mapLSTM(lm.RNN, fmt.Sprintf("%slstm.", ""))
@@ -115,7 +113,7 @@ func Convert(modelPath string, flairModelName, configFileName, modelFileName str
 
 	output := path.Join(modelPath, modelFileName)
 	log.Printf("Serializing full model to \"%s\"... ", output)
-	err := utils.SerializeToFile(output, lm)
+	err := nn.SaveModelFile(output, lm, lm.Config)
 	if err != nil {
 		panic("error during model serialization.")
 	}
@@ -221,12 +219,6 @@ func sumVectors(a, b []mat.Float) []mat.Float {
 	return y
 }
 
-func assignToParamsList(source []mat.Float, dest []nn.Param, rows, cols int) {
-	for i := 0; i < rows; i++ {
-		dest[i].Value().SetData(source[i*cols : (i+1)*cols])
-	}
-}
-
 func (c *flairConverter) unpickleModel() {
 	newUnpickler := func(r io.Reader) pickle.Unpickler {
 		u := pickle.NewUnpickler(r)
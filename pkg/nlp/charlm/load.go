@@ -9,13 +9,13 @@ import (
 	"log"
 	"path"
 
-	"github.com/nlpodyssey/spago/pkg/utils"
+	"github.com/nlpodyssey/spago/pkg/ml/nn"
 )
 
 // LoadModel loads a Model model from file.
 func LoadModel(modelPath string) (*Model, error) {
-	configFilename := path.Join(modelPath, defaultConfigFilename)
-	modelFilename := path.Join(modelPath, defaultModelFilename)
+	configFilename := path.Join(modelPath, DefaultConfigFilename)
+	modelFilename := path.Join(modelPath, DefaultModelFilename)
 
 	fmt.Printf("Start loading pre-trained model from \"%s\"\n", modelPath)
 	fmt.Printf("[1/2] Loading configuration... ")
@@ -28,7 +28,7 @@ func LoadModel(modelPath string) (*Model, error) {
 	model := New(config)
 
 	fmt.Printf("[2/2] Loading model weights... ")
-	err = utils.DeserializeFromFile(modelFilename, model)
+	err = nn.LoadFromFile(modelFilename, model)
 	if err != nil {
 		log.Fatal(fmt.Sprintf("charlm: error during model deserialization (%s)", err.Error()))
 	}
@@ -13,6 +13,7 @@ import (
 	"github.com/nlpodyssey/spago/pkg/ml/nn"
 	"github.com/nlpodyssey/spago/pkg/ml/optimizers/gd"
 	"github.com/nlpodyssey/spago/pkg/ml/optimizers/gd/gdmbuilder"
+	"github.com/nlpodyssey/spago/pkg/ml/training"
 	"github.com/nlpodyssey/spago/pkg/nlp/corpora"
 	"github.com/nlpodyssey/spago/pkg/utils"
 	"runtime"
@@ -28,6 +29,9 @@ type TrainingConfig struct {
 	SerializationInterval int
 	UpdateMethod          gd.MethodConfig
 	ModelPath             string
+	// CheckpointPath, if not empty, is the file where the state of the training is saved along with the model,
+	// so that an interrupted training can be resumed (see Trainer.LoadCheckpoint).
+	CheckpointPath string
 }
 
 // Trainer implements the training process for a Character-level Language Model.
@@ -39,7 +43,10 @@ type Trainer struct {
 	optimizer     *gd.GradientDescent
 	bestLoss      mat.Float
 	lastBatchLoss mat.Float
-	curPerplexity mat.Float
+	// passages is the index of the last processed passage of the corpus.
+	passages int
+	// batches is the number of processed batches.
+	batches int
 }
 
 // NewTrainer returns a new Trainer.
@@ -56,22 +63,75 @@ func NewTrainer(config TrainingConfig, corpus corpora.TextCorpusIterator, model
 	}
 }
 
-// Train executes the training process.
+// Train executes the training process: the model is trained on each passage of the corpus, and serialized
+// every SerializationInterval passages.
 func (t *Trainer) Train() {
-	t.corpus.ForEachLine(func(i int, line string) {
-		t.trainPassage(i, line)
-		// TODO: save the model only if it is better against a validation criterion (yet to be defined)
-		if i > 0 && i%t.SerializationInterval == 0 {
-			fmt.Println("=== MODEL SERIALIZATION")
-			err := utils.SerializeToFile(t.ModelPath, t.model)
-			if err != nil {
-				panic("charlm: error during model serialization.")
-			}
+	// the params are updated after each batch of characters by trainPassage
+	trainer := training.New(
+		training.Config{Epochs: 1, BatchSize: 1},
+		nil,
+		t.learn,
+		&training.Logger{Interval: 1},
+		&training.Checkpoint{Save: t.serialize, Interval: t.SerializationInterval},
+	)
+	if t.passages > 0 {
+		// resume the training skipping the passages already processed before the checkpoint
+		trainer.State = training.State{Epoch: 1, Step: t.passages, Example: t.passages}
+	}
+	if err := trainer.Train(training.Lines(t.corpus.ForEachLine)); err != nil {
+		panic(fmt.Sprintf("charlm: %v", err))
+	}
+}
+
+// learn trains the model on a passage of the corpus.
+func (t *Trainer) learn(batch []interface{}, _ mat.Float) mat.Float {
+	t.passages++
+	t.trainPassage(batch[0].(string))
+	return t.lastBatchLoss
+}
+
+// serialize saves the model, and the checkpoint if required.
+// TODO: save the model only if it is better against a validation criterion (yet to be defined)
+func (t *Trainer) serialize(*training.State) error {
+	if err := nn.SaveModelFile(t.ModelPath, t.model, t.model.Config); err != nil {
+		return fmt.Errorf("error during model serialization: %w", err)
+	}
+	if t.CheckpointPath != "" {
+		if err := t.SaveCheckpoint(t.CheckpointPath); err != nil {
+			return fmt.Errorf("error during checkpoint serialization: %w", err)
 		}
-	})
+	}
+	return nil
 }
 
-func (t *Trainer) trainPassage(index int, text string) {
+// SaveCheckpoint saves the state of the training to file: the params of the model, the state of the optimizer
+// and of the random generator, and the progress on the corpus.
+func (t *Trainer) SaveCheckpoint(filename string) error {
+	checkpoint, err := gd.NewCheckpoint(t.optimizer, t.randGen)
+	if err != nil {
+		return err
+	}
+	checkpoint.Example = t.passages
+	checkpoint.Batch = t.batches
+	return gd.SaveCheckpoint(filename, checkpoint)
+}
+
+// LoadCheckpoint restores the state of the training from a file written by SaveCheckpoint.
+// The passages of the corpus processed before the checkpoint are skipped by Train.
+func (t *Trainer) LoadCheckpoint(filename string) error {
+	checkpoint, err := gd.LoadCheckpoint(filename)
+	if err != nil {
+		return err
+	}
+	if err := checkpoint.Restore(t.optimizer, t.randGen); err != nil {
+		return err
+	}
+	t.passages = checkpoint.Example
+	t.batches = checkpoint.Batch
+	return nil
+}
+
+func (t *Trainer) trainPassage(text string) {
 	// This is a particular case where computing the forward after the graph definition can be more efficient.
 	g := ag.NewGraph(
 		ag.Rand(t.randGen),
@@ -97,22 +157,17 @@ func (t *Trainer) trainPassage(index int, text string) {
 			break // there is no subsequent character to predict, nothing more to learn.
 		}
 		cnt += len(batch)
+		t.batches++
 		t.optimizer.IncBatch()
 		t.optimizer.IncExample()
 		loss := t.trainBatch(proc, batch)
 		t.optimizer.Optimize()
 		t.lastBatchLoss = loss
-		t.curPerplexity = mat.Exp(loss)
 	}
 	if g.TimeStep() != cnt {
 		panic(fmt.Sprintf("charlm: time-step `%d` different than processed items `%d`. Something goes wrong.",
 			g.TimeStep(), cnt))
 	}
-
-	// TODO: improve print
-	fmt.Println(text)
-	fmt.Printf("Cnt: %d Sentene length: %d Loss: %.6f Perplexity: %.6f\n\n",
-		index, length, t.lastBatchLoss, t.curPerplexity)
 }
 
 // trainBatch performs both the forward step and the truncated back-propagation on a given batch.
@@ -23,6 +23,8 @@ var (
 var allModels []*Model
 
 // Model implements an embeddings model.
+// Each used embedding is a separate param, retrieved from the Storage; for a trainable vocabulary which fits
+// in memory, consider the embedding.Model (package nn/embedding), which is updated with row-sparse gradients.
 type Model struct {
 	nn.BaseModel
 	Config
@@ -44,6 +46,9 @@ type Config struct {
 	ReadOnly bool
 	// Whether to force the deletion of any existing DB to start with an empty embeddings map.
 	ForceNewDB bool
+	// The floating-point format used to store new embeddings (default mat.FullPrecision).
+	// The embeddings already stored keep their own precision.
+	Precision mat.Precision
 }
 
 func init() {
@@ -122,6 +127,7 @@ func (m *Model) SetEmbedding(word string, value mat.Matrix) {
 	}
 
 	embedding := nn.NewParam(value)
+	embedding.SetPrecision(m.Precision)
 	embedding.SetPayload(nn.NewPayload())
 
 	buf := new(bytes.Buffer)
@@ -0,0 +1,102 @@
+// Copyright 2021 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sequencelabeler
+
+import (
+	"bufio"
+	"fmt"
+	"github.com/nlpodyssey/spago/pkg/ml/stats"
+	"os"
+	"sort"
+	"strings"
+)
+
+// LabelScheme is the scheme of the labels of the tokens, which encodes the entity spans.
+type LabelScheme = stats.LabelScheme
+
+// The label schemes (see stats.LabelScheme).
+const (
+	IOB1  = stats.IOB1
+	BIO   = stats.BIO
+	BIOES = stats.BIOES // used by the Flair models
+)
+
+// Example is a sentence whose tokens are labeled.
+type Example struct {
+	Tokens []string
+	Labels []string
+}
+
+// ReadCoNLLExamples reads the examples from a file in the CoNLL format (e.g. CoNLL-2003): one token per line,
+// with the token in the first column and its label in the last column, separated by spaces or tabs.
+// The sentences are separated by blank lines; the document separators (-DOCSTART-) are skipped.
+func ReadCoNLLExamples(filename string) ([]Example, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	examples := make([]Example, 0)
+	example := Example{}
+	flush := func() {
+		if len(example.Tokens) > 0 {
+			examples = append(examples, example)
+		}
+		example = Example{}
+	}
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		fields := strings.Fields(scanner.Text())
+		switch {
+		case len(fields) == 0:
+			flush()
+		case fields[0] == "-DOCSTART-":
+			flush()
+		case len(fields) < 2:
+			return nil, fmt.Errorf("sequencelabeler: %s:%d: missing label", filename, lineNumber)
+		default:
+			example.Tokens = append(example.Tokens, fields[0])
+			example.Labels = append(example.Labels, fields[len(fields)-1])
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	flush()
+	return examples, nil
+}
+
+// ConvertLabels returns the labels converted to the scheme. The input labels may follow any of the
+// IOB1, BIO and BIOES schemes; the invalid sequences (e.g. "O I-PER") are decoded leniently, starting
+// a new entity (see stats.ExtractSpans).
+func ConvertLabels(labels []string, scheme LabelScheme) []string {
+	return stats.EncodeSpans(stats.ExtractSpans(labels), len(labels), scheme)
+}
+
+// CollectLabels returns the sorted set of the labels of the examples converted to the scheme, that is
+// the outside label "O" followed by the labels of each entity type.
+func CollectLabels(examples []Example, scheme LabelScheme) []string {
+	types := make(map[string]bool)
+	for _, example := range examples {
+		for _, span := range stats.ExtractSpans(example.Labels) {
+			types[span.Label] = true
+		}
+	}
+	sortedTypes := make([]string, 0, len(types))
+	for label := range types {
+		sortedTypes = append(sortedTypes, label)
+	}
+	sort.Strings(sortedTypes)
+
+	labels := []string{stats.OutsideLabel}
+	for _, label := range sortedTypes {
+		for _, prefix := range stats.SchemePrefixes(scheme) {
+			labels = append(labels, prefix+"-"+label)
+		}
+	}
+	return labels
+}
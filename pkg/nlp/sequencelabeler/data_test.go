@@ -0,0 +1,60 @@
+// Copyright 2021 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sequencelabeler
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io/ioutil"
+	"path"
+	"testing"
+)
+
+func TestReadCoNLLExamples(t *testing.T) {
+	filename := path.Join(t.TempDir(), "train.txt")
+	data := `-DOCSTART- -X- -X- O
+
+EU NNP B-NP B-ORG
+rejects VBZ B-VP O
+German JJ B-NP B-MISC
+
+Peter NNP B-NP B-PER
+Blackburn NNP I-NP I-PER
+`
+	require.NoError(t, ioutil.WriteFile(filename, []byte(data), 0644))
+	examples, err := ReadCoNLLExamples(filename)
+	require.NoError(t, err)
+	assert.Equal(t, []Example{
+		{Tokens: []string{"EU", "rejects", "German"}, Labels: []string{"B-ORG", "O", "B-MISC"}},
+		{Tokens: []string{"Peter", "Blackburn"}, Labels: []string{"B-PER", "I-PER"}},
+	}, examples)
+
+	require.NoError(t, ioutil.WriteFile(filename, []byte("EU B-ORG\nrejects\n"), 0644))
+	_, err = ReadCoNLLExamples(filename)
+	assert.Error(t, err)
+}
+
+func TestConvertLabels(t *testing.T) {
+	iob1 := []string{"I-PER", "I-PER", "B-PER", "O", "I-LOC", "I-ORG", "I-ORG"}
+	bio := []string{"B-PER", "I-PER", "B-PER", "O", "B-LOC", "B-ORG", "I-ORG"}
+	bioes := []string{"B-PER", "E-PER", "S-PER", "O", "S-LOC", "B-ORG", "E-ORG"}
+	for _, labels := range [][]string{iob1, bio, bioes} {
+		assert.Equal(t, iob1, ConvertLabels(labels, IOB1))
+		assert.Equal(t, bio, ConvertLabels(labels, BIO))
+		assert.Equal(t, bioes, ConvertLabels(labels, BIOES))
+	}
+	assert.Equal(t, []string{"O", "S-PER", "O"}, ConvertLabels([]string{"<unk>", "I-PER", "O"}, BIOES))
+}
+
+func TestCollectLabels(t *testing.T) {
+	examples := []Example{
+		{Tokens: []string{"Peter", "Blackburn"}, Labels: []string{"B-PER", "I-PER"}},
+		{Tokens: []string{"EU", "rejects"}, Labels: []string{"B-ORG", "O"}},
+	}
+	assert.Equal(t, []string{"O", "B-ORG", "I-ORG", "B-PER", "I-PER"}, CollectLabels(examples, BIO))
+	assert.Equal(t, []string{"O", "B-ORG", "I-ORG", "E-ORG", "S-ORG", "B-PER", "I-PER", "E-PER", "S-PER"},
+		CollectLabels(examples, BIOES))
+	assert.Panics(t, func() { CollectLabels(examples, "foo") })
+}
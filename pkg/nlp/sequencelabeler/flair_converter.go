@@ -21,7 +21,6 @@ import (
 	"github.com/nlpodyssey/spago/pkg/nlp/contextualstringembeddings"
 	"github.com/nlpodyssey/spago/pkg/nlp/embeddings"
 	"github.com/nlpodyssey/spago/pkg/nlp/vocabulary"
-	"github.com/nlpodyssey/spago/pkg/utils"
 	"github.com/nlpodyssey/spago/pkg/utils/gopickleutils"
 	"io"
 	"io/ioutil"
@@ -107,17 +106,8 @@ func Convert(modelPath string, flairModelName string) {
 	voc := vocabulary.New(embeddingsLMForward.Dictionary.GetItems())
 	lm.Vocabulary, lmRev.Vocabulary = voc, voc
 
-	assignToParamsList(
-		stateDict["lm.forward.embeddings.weight"],
-		lm.Embeddings,
-		lm.Config.VocabularySize,
-		lm.Config.EmbeddingSize)
-
-	assignToParamsList(
-		stateDict["lm.backward.embeddings.weight"],
-		lmRev.Embeddings,
-		lmRev.Config.VocabularySize,
-		lmRev.Config.EmbeddingSize)
+	lm.Embeddings.W.SetData(stateDict["lm.forward.embeddings.weight"])
+	lmRev.Embeddings.W.SetData(stateDict["lm.backward.embeddings.weight"])
 
 	c.mapCharLM(lm, "lm.forward.")
 	c.mapCharLM(lmRev, "lm.backward.")
@@ -146,7 +136,7 @@ func Convert(modelPath string, flairModelName string) {
 
 	output := path.Join(modelPath, config.ModelFilename)
 	log.Printf("Serializing full model to \"%s\"... ", output)
-	err := utils.SerializeToFile(output, model)
+	err := nn.SaveModelFile(output, model, model.Config)
 	if err != nil {
 		panic("error during model serialization.")
 	}
@@ -461,12 +451,6 @@ func (c *converter) config() Config {
 	}
 }
 
-func assignToParamsList(source []mat.Float, dest []nn.Param, rows, cols int) {
-	for i := 0; i < rows; i++ {
-		dest[i].Value().SetData(source[i*cols : (i+1)*cols])
-	}
-}
-
 func (c *converter) mapTagger(model *birnncrf.Model) {
 	c.mapBiLSTM(model.BiRNN, "")
 	c.mapLinear(model.Scorer, "scorer.")
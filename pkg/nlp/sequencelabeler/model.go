@@ -23,7 +23,6 @@ import (
 	"github.com/nlpodyssey/spago/pkg/nlp/stackedembeddings"
 	"github.com/nlpodyssey/spago/pkg/nlp/tokenizers"
 	"github.com/nlpodyssey/spago/pkg/nlp/tokenizers/basetokenizer"
-	"github.com/nlpodyssey/spago/pkg/utils"
 	"path/filepath"
 	"runtime"
 )
@@ -99,27 +98,43 @@ func NewDefaultModel(config Config, path string, readOnlyEmbeddings bool, forceN
 
 // LoadModel loads a Model from file.
 func LoadModel(modelPath string) (*Model, error) {
+	return load(modelPath, true)
+}
+
+// LoadModelForTraining loads a Model from file, like LoadModel, but with the word embeddings storage
+// opened writable, so that the word embeddings can be trained.
+func LoadModelForTraining(modelPath string) (*Model, error) {
+	return load(modelPath, false)
+}
+
+func load(modelPath string, readOnlyEmbeddings bool) (*Model, error) {
 	config := LoadConfig(filepath.Join(modelPath, "config.json"))
 	model := NewDefaultModel(
 		config,
 		modelPath,
-		true,  // read-only embeddings
+		readOnlyEmbeddings,
 		false, // don't force new embeddings DB
 	)
 
 	file := filepath.Join(modelPath, config.ModelFilename)
 	fmt.Printf("Loading model parameters from `%s`... ", file)
-	err := utils.DeserializeFromFile(file, model)
+	isModelFile, err := nn.IsModelFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("sequencelabeler: error during model deserialization (%s)", err.Error())
+	}
+	err = nn.LoadFromFile(file, model)
 	if err != nil {
 		return nil, fmt.Errorf("sequencelabeler: error during model deserialization")
 	}
-	// TODO: find a general solution to set embeddings lost during deserialization
-	model.loadEmbeddings(
-		config,
-		modelPath,
-		true,  // read-only embeddings
-		false, // don't force new embeddings DB
-	)
+	if !isModelFile {
+		// TODO: find a general solution to set embeddings lost during deserialization
+		model.loadEmbeddings(
+			config,
+			modelPath,
+			readOnlyEmbeddings,
+			false, // don't force new embeddings DB
+		)
+	}
 	fmt.Println("ok")
 	return model, nil
 }
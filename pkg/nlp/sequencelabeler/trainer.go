@@ -0,0 +1,328 @@
+// Copyright 2021 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sequencelabeler
+
+import (
+	"encoding/json"
+	"fmt"
+	mat "github.com/nlpodyssey/spago/pkg/mat32"
+	"github.com/nlpodyssey/spago/pkg/mat32/rand"
+	"github.com/nlpodyssey/spago/pkg/ml/ag"
+	"github.com/nlpodyssey/spago/pkg/ml/initializers"
+	"github.com/nlpodyssey/spago/pkg/ml/nn"
+	"github.com/nlpodyssey/spago/pkg/ml/nn/crf"
+	"github.com/nlpodyssey/spago/pkg/ml/nn/linear"
+	"github.com/nlpodyssey/spago/pkg/ml/optimizers/gd"
+	"github.com/nlpodyssey/spago/pkg/ml/optimizers/gd/gdmbuilder"
+	"github.com/nlpodyssey/spago/pkg/ml/stats"
+	"github.com/nlpodyssey/spago/pkg/ml/training"
+	"github.com/nlpodyssey/spago/pkg/nlp/contextualstringembeddings"
+	"github.com/nlpodyssey/spago/pkg/nlp/embeddings"
+	"io/ioutil"
+	"path/filepath"
+	"runtime"
+)
+
+// Names of the metrics of the evaluation on the development set, during the training.
+const (
+	devLossMetric   = "Dev loss"
+	precisionMetric = "Precision"
+	recallMetric    = "Recall"
+	f1ScoreMetric   = "F1"
+)
+
+// TrainingConfig provides configuration settings for a sequence labeling Trainer.
+type TrainingConfig struct {
+	Seed             uint64
+	Epochs           int
+	BatchSize        int
+	GradientClipping mat.Float
+	UpdateMethod     gd.MethodConfig
+	// Scheme is the label scheme of the model, to which the labels of the examples are converted.
+	// The empty scheme means BIOES, as in the Flair models.
+	Scheme LabelScheme
+	// Patience is the number of epochs without improvement of the score on the development set after
+	// which the training stops. Zero means no early stopping.
+	Patience int
+	// FreezeCharLM reports whether the character-level language models of the contextual string
+	// embeddings are not trained.
+	FreezeCharLM bool
+	// FreezeWordEmbeddings reports whether the word embeddings are not trained: they are read-only during
+	// the training, and saved as writable. They are never trained if the model has been loaded with
+	// read-only embeddings (see LoadModelForTraining).
+	FreezeWordEmbeddings bool
+	// ModelPath is the directory of the model, where the configuration and the model file are saved after
+	// each epoch, or only when the score on the development set improves.
+	ModelPath string
+}
+
+// Evaluation contains the results of the evaluation of a model on a set of examples.
+type Evaluation struct {
+	// Loss is the average negative log-likelihood of the labels of the examples.
+	Loss mat.Float
+	// Entities contains the entity-level metrics of each entity type: a predicted entity is a true positive
+	// if both its span and its type are correct.
+	Entities *stats.SpanEvaluator
+}
+
+// Score returns the micro-averaged F1 score of the entities.
+func (e Evaluation) Score() mat.Float {
+	return e.Entities.Micro().F1Score()
+}
+
+// Trainer implements the training of a sequence labeling Model: the tagger layer (BiRNN, scorer and CRF)
+// is trained together with the stacked embeddings, unless they are frozen.
+// The word embeddings storage is updated in place.
+type Trainer struct {
+	TrainingConfig
+	model     *Model
+	randGen   *rand.LockedRand
+	optimizer *gd.GradientDescent
+	// frozenEmbeddings are the writable word embeddings which are read-only during the training.
+	frozenEmbeddings []*embeddings.Model
+}
+
+// trainingInput is a training Example with the indices of its labels.
+type trainingInput struct {
+	example Example
+	targets []int
+}
+
+// NewTrainer returns a new Trainer of the model for the labels (see CollectLabels). If the labels are not
+// a subset of the ones of the model, the scorer and the CRF of the tagger layer are replaced with new ones,
+// randomly initialized, for the labels.
+func NewTrainer(model *Model, config TrainingConfig, labels []string) *Trainer {
+	if config.Scheme == "" {
+		config.Scheme = BIOES
+	}
+	randGen := rand.NewLockedRand(config.Seed)
+	if !containsAll(model.Labels, labels) {
+		model.resetLabels(labels, randGen)
+	}
+	var frozenEmbeddings []*embeddings.Model
+	for _, encoder := range model.EmbeddingsLayer.WordsEncoders {
+		switch encoder := encoder.(type) {
+		case *embeddings.Model:
+			if config.FreezeWordEmbeddings && !encoder.ReadOnly {
+				frozenEmbeddings = append(frozenEmbeddings, encoder)
+			}
+		case *contextualstringembeddings.Model:
+			if config.FreezeCharLM {
+				nn.ForEachParam(encoder, func(param nn.Param) {
+					param.SetRequiresGrad(false)
+				})
+			}
+		}
+	}
+	optimizer := gd.NewOptimizer(gdmbuilder.NewMethod(config.UpdateMethod), nn.NewDefaultParamsIterator(model))
+	if config.GradientClipping != 0.0 {
+		gd.ClipGradByNorm(config.GradientClipping, 2.0)(optimizer)
+	}
+	return &Trainer{
+		TrainingConfig:   config,
+		model:            model,
+		randGen:          randGen,
+		optimizer:        optimizer,
+		frozenEmbeddings: frozenEmbeddings,
+	}
+}
+
+// containsAll reports whether all the items are in the set.
+func containsAll(set, items []string) bool {
+	index := make(map[string]bool, len(set))
+	for _, item := range set {
+		index[item] = true
+	}
+	for _, item := range items {
+		if !index[item] {
+			return false
+		}
+	}
+	return true
+}
+
+// resetLabels replaces the scorer and the CRF of the tagger layer with new ones for the labels.
+func (m *Model) resetLabels(labels []string, randGen *rand.LockedRand) {
+	scorer := linear.New(m.Config.ScorerInputSize, len(labels))
+	initializers.XavierUniform(scorer.W.Value(), 1, randGen)
+	m.TaggerLayer.Scorer = scorer
+	m.TaggerLayer.CRF = crf.New(len(labels))
+	m.Config.ScorerOutputSize = len(labels)
+	m.Config.Labels = labels
+	m.Labels = labels
+}
+
+// Train trains the model on the training examples. After each epoch, the model is evaluated on the
+// development examples, and saved if its score is the best so far; the training stops early if the score
+// does not improve for Patience epochs. Without development examples, the model is saved after each epoch.
+func (t *Trainer) Train(trainSet, devSet []Example) error {
+	targets, err := t.targets(trainSet)
+	if err != nil {
+		return err
+	}
+	inputs := make([]trainingInput, len(trainSet))
+	for i, example := range trainSet {
+		inputs[i] = trainingInput{example: example, targets: targets[i]}
+	}
+	trainer := training.New(
+		training.Config{Epochs: t.Epochs, BatchSize: t.BatchSize, Shuffle: true, Seed: t.Seed},
+		t.optimizer,
+		t.learn,
+		t.callbacks(devSet)...,
+	)
+	t.setFrozenEmbeddings(true)
+	defer t.setFrozenEmbeddings(false)
+	return trainer.Train(training.NewExamples(inputs))
+}
+
+// setFrozenEmbeddings sets the frozen word embeddings as read-only, or writable again, clearing the cached
+// embeddings, whose params require gradients only if the embeddings are writable.
+func (t *Trainer) setFrozenEmbeddings(readOnly bool) {
+	for _, encoder := range t.frozenEmbeddings {
+		encoder.ReadOnly = readOnly
+		encoder.ClearUsedEmbeddings()
+	}
+}
+
+// callbacks returns the callbacks of the training: the evaluation on the development examples, if any,
+// the logging, the saving of the best model and the early stopping.
+func (t *Trainer) callbacks(devSet []Example) []training.Callback {
+	save := &training.Checkpoint{Save: func(*training.State) error { return t.SaveModel() }}
+	if len(devSet) == 0 {
+		return []training.Callback{&training.Logger{}, save}
+	}
+	evaluation := &training.Evaluation{
+		Evaluate: func() (training.Metrics, error) {
+			evaluation, err := t.Evaluate(devSet)
+			if err != nil {
+				return nil, err
+			}
+			micro := evaluation.Entities.Micro()
+			return training.Metrics{
+				devLossMetric:   evaluation.Loss,
+				precisionMetric: micro.Precision(),
+				recallMetric:    micro.Recall(),
+				f1ScoreMetric:   micro.F1Score(),
+			}, nil
+		},
+	}
+	save.Monitor = &training.Monitor{Metric: f1ScoreMetric, Mode: training.Maximize}
+	callbacks := []training.Callback{evaluation, &training.Logger{}, save}
+	if t.Patience > 0 {
+		callbacks = append(callbacks, &training.EarlyStopping{
+			Monitor:  training.Monitor{Metric: f1ScoreMetric, Mode: training.Maximize},
+			Patience: t.Patience,
+		})
+	}
+	return callbacks
+}
+
+// SaveModel saves the configuration, which includes the labels, and the model file to the model path.
+// The word embeddings frozen during the training are saved as writable.
+func (t *Trainer) SaveModel() error {
+	for _, encoder := range t.frozenEmbeddings {
+		readOnly := encoder.ReadOnly
+		encoder.ReadOnly = false
+		defer func(encoder *embeddings.Model) { encoder.ReadOnly = readOnly }(encoder)
+	}
+	config := t.model.Config
+	if config.ModelFilename == "" {
+		config.ModelFilename = defaultModelFilename
+	}
+	data, err := json.MarshalIndent(config, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(filepath.Join(t.ModelPath, defaultConfigFilename), data, 0644); err != nil {
+		return err
+	}
+	if err := nn.SaveModelFile(filepath.Join(t.ModelPath, config.ModelFilename), t.model, config); err != nil {
+		return fmt.Errorf("sequencelabeler: error during model serialization: %w", err)
+	}
+	return nil
+}
+
+// targets returns the indices of the labels of the examples, converted to the scheme of the model.
+func (t *Trainer) targets(examples []Example) ([][]int, error) {
+	labelIndex := make(map[string]int, len(t.model.Labels))
+	for i, label := range t.model.Labels {
+		labelIndex[label] = i
+	}
+	targets := make([][]int, len(examples))
+	for i, example := range examples {
+		if len(example.Tokens) == 0 || len(example.Tokens) != len(example.Labels) {
+			return nil, fmt.Errorf("sequencelabeler: invalid example %d: %d tokens and %d labels",
+				i, len(example.Tokens), len(example.Labels))
+		}
+		targets[i] = make([]int, len(example.Labels))
+		for j, label := range ConvertLabels(example.Labels, t.Scheme) {
+			index, ok := labelIndex[label]
+			if !ok {
+				return nil, fmt.Errorf("sequencelabeler: unknown label `%s` of example %d", label, i)
+			}
+			targets[i][j] = index
+		}
+	}
+	return targets, nil
+}
+
+// learn accumulates the gradients of the losses of the batch of inputs, averaged and multiplied by the
+// gradient scale, and returns the average loss.
+func (t *Trainer) learn(batch []interface{}, gradScale mat.Float) mat.Float {
+	var totalLoss mat.Float
+	for _, input := range batch {
+		input := input.(trainingInput)
+		totalLoss += t.learnExample(input.example, input.targets, gradScale/mat.Float(len(batch)))
+	}
+	return totalLoss / mat.Float(len(batch))
+}
+
+// learnExample accumulates the gradients of the loss of the example, multiplied by the gradient scale,
+// and returns the loss.
+func (t *Trainer) learnExample(example Example, targets []int, gradScale mat.Float) mat.Float {
+	g := ag.NewGraph(ag.Rand(t.randGen), ag.ConcurrentComputations(runtime.NumCPU()))
+	defer g.Clear()
+	loss, _ := t.forward(nn.Context{Graph: g, Mode: nn.Training}, example, targets)
+	g.Backward(g.ProdScalar(loss, g.NewScalar(gradScale)))
+	return loss.ScalarValue()
+}
+
+// forward returns the loss of the example and the indices of the predicted labels.
+func (t *Trainer) forward(ctx nn.Context, example Example, targets []int) (ag.Node, []int) {
+	proc := nn.Reify(ctx, t.model).(*Model)
+	scores := proc.TaggerLayer.Forward(proc.EmbeddingsLayer.Encode(example.Tokens)...)
+	loss := proc.NegativeLogLoss(scores, targets)
+	if ctx.Mode == nn.Training {
+		return loss, nil
+	}
+	return loss, proc.TaggerLayer.Decode(scores)
+}
+
+// Evaluate returns the Evaluation of the model on the examples.
+func (t *Trainer) Evaluate(examples []Example) (Evaluation, error) {
+	targets, err := t.targets(examples)
+	if err != nil {
+		return Evaluation{}, err
+	}
+	evaluation := Evaluation{
+		Entities: stats.NewSpanEvaluator(stats.StrictMatch),
+	}
+	for i, example := range examples {
+		g := ag.NewGraph(ag.ConcurrentComputations(runtime.NumCPU()))
+		loss, predicted := t.forward(nn.Context{Graph: g, Mode: nn.Inference}, example, targets[i])
+		evaluation.Loss += loss.ScalarValue()
+		g.Clear()
+
+		predictedLabels := make([]string, len(predicted))
+		for j, index := range predicted {
+			predictedLabels[j] = t.model.Labels[index]
+		}
+		evaluation.Entities.AddLabels(example.Labels, predictedLabels)
+	}
+	if len(examples) > 0 {
+		evaluation.Loss /= mat.Float(len(examples))
+	}
+	return evaluation, nil
+}
@@ -0,0 +1,146 @@
+// Copyright 2021 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sequencelabeler
+
+import (
+	mat "github.com/nlpodyssey/spago/pkg/mat32"
+	"github.com/nlpodyssey/spago/pkg/mat32/rand"
+	"github.com/nlpodyssey/spago/pkg/ml/initializers"
+	"github.com/nlpodyssey/spago/pkg/ml/nn"
+	"github.com/nlpodyssey/spago/pkg/ml/optimizers/gd/adam"
+	"github.com/nlpodyssey/spago/pkg/nlp/contextualstringembeddings"
+	"github.com/nlpodyssey/spago/pkg/nlp/embeddings"
+	"github.com/nlpodyssey/spago/pkg/nlp/vocabulary"
+	"github.com/nlpodyssey/spago/pkg/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+)
+
+var testExamples = []Example{
+	{Tokens: []string{"John", "lives", "in", "Rome"}, Labels: []string{"B-PER", "O", "O", "B-LOC"}},
+	{Tokens: []string{"Mary", "Ann", "visited", "Paris"}, Labels: []string{"B-PER", "I-PER", "O", "B-LOC"}},
+	{Tokens: []string{"Rome", "is", "far"}, Labels: []string{"B-LOC", "O", "O"}},
+}
+
+func newTestModel(t *testing.T, modelPath string) *Model {
+	chars := map[string]bool{"\n": true, " ": true, "<unk>": true}
+	for _, example := range testExamples {
+		for _, char := range utils.SplitByRune(strings.Join(example.Tokens, "")) {
+			chars[char] = true
+		}
+	}
+	terms := make([]string, 0, len(chars))
+	for char := range chars {
+		terms = append(terms, char)
+	}
+	config := Config{
+		ModelFilename:  "model.bin",
+		WordEmbeddings: []WordEmbeddingsConfig{{WordEmbeddingsFilename: "words", WordEmbeddingsSize: 3}},
+		ContextualStringEmbeddings: ContextualEmbeddingsConfig{
+			VocabularySize: len(terms),
+			EmbeddingSize:  3,
+			HiddenSize:     4,
+			UnknownToken:   "<unk>",
+		},
+		EmbeddingsProjectionInputSize:  11,
+		EmbeddingsProjectionOutputSize: 6,
+		RecurrentInputSize:             6,
+		RecurrentOutputSize:            4,
+		ScorerInputSize:                8,
+		ScorerOutputSize:               2,
+		Labels:                         []string{"<unk>", "O"},
+	}
+	model := NewDefaultModel(config, modelPath, false, true)
+	cse := model.EmbeddingsLayer.WordsEncoders[1].(*contextualstringembeddings.Model)
+	cse.LeftToRight.Vocabulary = vocabulary.New(terms)
+	cse.RightToLeft.Vocabulary = vocabulary.New(terms)
+
+	rndGen := rand.NewLockedRand(42)
+	nn.ForEachParam(model, func(param nn.Param) {
+		initializers.Normal(param.Value(), 0, 0.5, rndGen)
+	})
+	words := model.EmbeddingsLayer.WordsEncoders[0].(*embeddings.Model)
+	for _, word := range []string{"John", "Mary", "Rome", "Paris", "in", "is"} {
+		data := make([]mat.Float, 3)
+		for i := range data {
+			data[i] = mat.Float(rndGen.Float()) - 0.5
+		}
+		words.SetEmbeddingFromData(word, data)
+	}
+	return model
+}
+
+func newTestTrainingConfig(modelPath string) TrainingConfig {
+	return TrainingConfig{
+		Seed:             1,
+		Epochs:           30,
+		BatchSize:        2,
+		GradientClipping: 1.0,
+		UpdateMethod:     adam.NewConfig(0.02, 0.9, 0.999, 1.0e-8),
+		Patience:         10,
+		FreezeCharLM:     true,
+		ModelPath:        modelPath,
+	}
+}
+
+func TestTrainer(t *testing.T) {
+	modelPath := t.TempDir()
+	model := newTestModel(t, modelPath)
+	charLMParam := model.EmbeddingsLayer.WordsEncoders[1].(*contextualstringembeddings.Model).LeftToRight.Decoder.W
+	charLMValue := charLMParam.Value().Clone()
+
+	labels := CollectLabels(testExamples, BIOES)
+	trainer := NewTrainer(model, newTestTrainingConfig(modelPath), labels)
+	assert.Equal(t, labels, model.Labels)
+	assert.Equal(t, len(labels), model.Config.ScorerOutputSize)
+
+	initial, err := trainer.Evaluate(testExamples)
+	require.NoError(t, err)
+	require.NoError(t, trainer.Train(testExamples, testExamples))
+	evaluation, err := trainer.Evaluate(testExamples)
+	require.NoError(t, err)
+	assert.Less(t, float64(evaluation.Loss), float64(initial.Loss))
+	assert.Equal(t, mat.Float(1.0), evaluation.Score())
+	assert.Equal(t, 3, evaluation.Entities.Metrics["LOC"].TruePos)
+	assert.Equal(t, 2, evaluation.Entities.Metrics["PER"].TruePos)
+	assert.Equal(t, charLMValue.Data(), charLMParam.Value().Data())
+
+	_, err = trainer.Evaluate([]Example{{Tokens: []string{"Rome"}, Labels: []string{"B-ORG"}}})
+	assert.Error(t, err)
+	_, err = trainer.Evaluate([]Example{{Tokens: []string{"Rome"}}})
+	assert.Error(t, err)
+
+	embeddings.Close()
+	loaded, err := LoadModel(modelPath)
+	require.NoError(t, err)
+	defer embeddings.Close()
+	assert.Equal(t, labels, loaded.Labels)
+	reloaded, err := NewTrainer(loaded, newTestTrainingConfig(modelPath), labels).Evaluate(testExamples)
+	require.NoError(t, err)
+	assert.Equal(t, mat.Float(1.0), reloaded.Score())
+}
+
+func TestTrainer_FreezeWordEmbeddings(t *testing.T) {
+	modelPath := t.TempDir()
+	model := newTestModel(t, modelPath)
+	words := model.EmbeddingsLayer.WordsEncoders[0].(*embeddings.Model)
+	wordValue := words.GetStoredEmbedding("Rome").Value().Clone()
+
+	config := newTestTrainingConfig(modelPath)
+	config.Epochs = 2
+	config.FreezeWordEmbeddings = true
+	trainer := NewTrainer(model, config, CollectLabels(testExamples, BIOES))
+	require.NoError(t, trainer.Train(testExamples, nil))
+	assert.Equal(t, wordValue.Data(), words.GetStoredEmbedding("Rome").Value().Data())
+	assert.False(t, words.ReadOnly) // frozen only during the training
+
+	embeddings.Close()
+	loaded, err := LoadModelForTraining(modelPath)
+	require.NoError(t, err)
+	defer embeddings.Close()
+	assert.False(t, loaded.EmbeddingsLayer.WordsEncoders[0].(*embeddings.Model).ReadOnly)
+}
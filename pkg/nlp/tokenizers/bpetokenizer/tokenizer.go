@@ -24,6 +24,7 @@ import (
 type BPETokenizer struct {
 	preTokenizer *bytelevelpretokenizer.ByteLevelPreTokenizer
 	model        *bpemodel.BPEModel
+	vocab        *vocabulary.Vocabulary // used by Decode
 }
 
 // New returns a new BPETokenizer.
@@ -85,7 +86,9 @@ func NewFromModelFolder(path string) (*BPETokenizer, error) {
 		defaultUnknownFusionEnabled,
 	)
 
-	return New(preTokenizer, model), nil
+	tokenizer := New(preTokenizer, model)
+	tokenizer.vocab = vocab
+	return tokenizer, nil
 }
 
 // Tokenize performs byte-level pre-tokenization and BPE tokenization.
@@ -169,3 +172,43 @@ func (t *BPETokenizer) Encode(text string) (*encodings.Encoding, error) {
 	}
 	return encoding, nil
 }
+
+// Decode converts the IDs of the tokens back into text, reversing the byte-level mapping of the
+// pre-tokenization. It requires the vocabulary, so it is available only for tokenizers created with
+// NewFromModelFolder.
+func (t *BPETokenizer) Decode(ids []int) (string, error) {
+	if t.vocab == nil {
+		return "", fmt.Errorf("BPETokenizer Decode: vocabulary not available")
+	}
+	var bytes []byte
+	for _, id := range ids {
+		token, ok := t.vocab.GetString(id)
+		if !ok {
+			return "", fmt.Errorf("BPETokenizer Decode: unknown token ID %d", id)
+		}
+		for _, r := range token {
+			b, ok := runeToByte[r]
+			if !ok {
+				return "", fmt.Errorf("BPETokenizer Decode: invalid byte-level rune %q in token %q", r, token)
+			}
+			bytes = append(bytes, b)
+		}
+	}
+	return string(bytes), nil
+}
+
+// runeToByte is the inverse of the mapping from bytes to printable runes used by the byte-level
+// pre-tokenization (see bytelevelpretokenizer).
+var runeToByte = func() map[rune]byte {
+	m := make(map[rune]byte, 0x100)
+	n := 0
+	for i := 0; i < 0x100; i++ {
+		if (i >= '!' && i <= '~') || (i >= 0xA1 && i <= 0xAC) || (i >= 0xAE && i <= 0xFF) {
+			m[rune(i)] = byte(i)
+		} else {
+			m[rune(0x100+n)] = byte(i)
+			n++
+		}
+	}
+	return m
+}()
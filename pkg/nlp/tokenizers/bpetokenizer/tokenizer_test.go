@@ -35,3 +35,34 @@ func TestNewFromModelFolder(t *testing.T) {
 		t.Errorf("expected:\n  %#v\nactual:\n  %#v\n", expected, actual)
 	}
 }
+
+func TestBPETokenizer_Decode(t *testing.T) {
+	tokenizer, err := NewFromModelFolder("testdata/dummy-roberta-model")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	actual, err := tokenizer.Decode([]int{11, 14})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := "unrelated"; actual != expected {
+		t.Errorf("expected %q, actual %q", expected, actual)
+	}
+
+	if _, err := tokenizer.Decode([]int{100}); err == nil {
+		t.Error("expected error for unknown token ID")
+	}
+}
+
+func TestRuneToByte(t *testing.T) {
+	if len(runeToByte) != 0x100 {
+		t.Fatalf("expected 256 runes, actual %d", len(runeToByte))
+	}
+	if b := runeToByte['\u0120']; b != ' ' { // "Ġ"
+		t.Errorf("expected space, actual %q", b)
+	}
+	if b := runeToByte['a']; b != 'a' {
+		t.Errorf("expected 'a', actual %q", b)
+	}
+}
@@ -7,9 +7,14 @@ package sentencepiece
 import (
 	"fmt"
 	"github.com/nlpodyssey/gotokenizers/vocabulary"
+	"github.com/nlpodyssey/spago/pkg/nlp/tokenizers"
 	"github.com/nlpodyssey/spago/pkg/nlp/tokenizers/sentencepiece/internal/sentencepiece"
+	"google.golang.org/protobuf/proto"
+	"io/ioutil"
 	"path/filepath"
 	"strings"
+	"unicode"
+	"unicode/utf8"
 )
 
 const defaultUnknownToken = "<unk>"
@@ -41,6 +46,37 @@ func NewFromModelFolder(path string, lowercase bool) (*Tokenizer, error) {
 	}, nil
 }
 
+// NewFromSentencePieceModel returns a new Tokenizer whose vocabulary consists of the pieces of the
+// sentence-piece model file, in the same order, followed by the additional tokens (e.g. the sentinel
+// tokens of T5, which are not part of the model).
+func NewFromSentencePieceModel(filename string, lowercase bool, additionalTokens ...string) (*Tokenizer, error) {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("loading sentence-piece from file %s: %w", filename, err)
+	}
+	var model sentencepiece.ModelProto
+	if err := proto.Unmarshal(data, &model); err != nil {
+		return nil, fmt.Errorf("loading sentence-piece from file %s: %w", filename, err)
+	}
+	vocab := vocabulary.NewVocabulary()
+	for _, piece := range model.GetPieces() {
+		vocab.AddTerm(piece.GetPiece())
+	}
+	for _, token := range additionalTokens {
+		vocab.AddTerm(token)
+	}
+
+	sp, err := sentencepiece.NewSentencepieceFromFile(filename, lowercase)
+	if err != nil {
+		return nil, fmt.Errorf("loading sentence-piece from file %s: %w", filename, err)
+	}
+
+	return &Tokenizer{
+		sp:    &sp,
+		vocab: vocab,
+	}, nil
+}
+
 // Tokenize performs sentence-piece tokenization.
 func (t *Tokenizer) Tokenize(text string) []string {
 	tokens := t.sp.Tokenize(text)
@@ -52,6 +88,40 @@ func (t *Tokenizer) Tokenize(text string) []string {
 	return result
 }
 
+// TokenizeWithOffsets performs sentence-piece tokenization, returning the tokens along with their offsets
+// (in runes) in the text. The leading separator of a token, which stands for the preceding whitespace, is
+// excluded from its offsets. The offsets are approximated when the normalization changes the length of the text.
+func (t *Tokenizer) TokenizeWithOffsets(text string) []tokenizers.StringOffsetsPair {
+	runes := []rune(text)
+	tokens := t.Tokenize(text)
+	result := make([]tokenizers.StringOffsetsPair, len(tokens))
+	pos := 0
+	for i, token := range tokens {
+		for pos < len(runes) && unicode.IsSpace(runes[pos]) {
+			pos++
+		}
+		start := pos
+		pos += utf8.RuneCountInString(strings.TrimPrefix(token, defaultSeparator))
+		if pos > len(runes) {
+			pos = len(runes)
+		}
+		result[i] = tokenizers.StringOffsetsPair{
+			String:  token,
+			Offsets: tokenizers.OffsetsType{Start: start, End: pos},
+		}
+	}
+	return result
+}
+
+// Terms returns the terms of the vocabulary, ordered by ID.
+func (t *Tokenizer) Terms() []string {
+	terms := make([]string, t.vocab.Size())
+	for i := range terms {
+		terms[i], _ = t.vocab.GetString(i)
+	}
+	return terms
+}
+
 // TokensToIDs returns a list of token IDs from a list of string tokens.
 // It panics if a token is not found in the vocabulary and no unknown token is found.
 func (t *Tokenizer) TokensToIDs(tokens []string) []int {
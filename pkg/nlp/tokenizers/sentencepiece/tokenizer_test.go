@@ -0,0 +1,63 @@
+// Copyright 2021 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sentencepiece
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+)
+
+const testModelFilename = "internal/sentencepiece/test_data/xlnet-base-cased-spiece.model"
+
+func TestNewFromSentencePieceModel(t *testing.T) {
+	tokenizer, err := NewFromSentencePieceModel(testModelFilename, false, "<extra_id_0>")
+	require.NoError(t, err)
+
+	text := "This is a sentence-piece test."
+	tokens := tokenizer.Tokenize(text)
+	ids := tokenizer.TokensToIDs(tokens)
+	expected := make([]int, 0, len(ids))
+	for _, id := range tokenizer.sp.TokenizeToIDs(text) {
+		expected = append(expected, int(id))
+	}
+	assert.Equal(t, expected, ids)
+	assert.Equal(t, tokens, tokenizer.IDsToTokens(ids))
+	assert.Equal(t, text, tokenizer.Detokenize(tokens))
+
+	size := tokenizer.vocab.Size()
+	assert.Equal(t, []string{"<extra_id_0>"}, tokenizer.IDsToTokens([]int{size - 1}))
+}
+
+func TestNewFromSentencePieceModel_MissingFile(t *testing.T) {
+	_, err := NewFromSentencePieceModel("missing.model", false)
+	assert.Error(t, err)
+}
+
+func TestTokenizer_TokenizeWithOffsets(t *testing.T) {
+	tokenizer, err := NewFromSentencePieceModel(testModelFilename, false)
+	require.NoError(t, err)
+
+	text := "This is  a sentence-piece test."
+	tokens := tokenizer.TokenizeWithOffsets(text)
+	require.NotEmpty(t, tokens)
+	runes := []rune(text)
+	for _, token := range tokens {
+		expected := strings.TrimPrefix(token.String, defaultSeparator)
+		assert.Equal(t, expected, string(runes[token.Offsets.Start:token.Offsets.End]))
+	}
+	assert.Equal(t, len(runes), tokens[len(tokens)-1].Offsets.End)
+}
+
+func TestTokenizer_Terms(t *testing.T) {
+	tokenizer, err := NewFromSentencePieceModel(testModelFilename, false, "<extra_id_0>")
+	require.NoError(t, err)
+
+	terms := tokenizer.Terms()
+	assert.Equal(t, tokenizer.vocab.Size(), len(terms))
+	assert.Equal(t, tokenizer.IDsToTokens([]int{0, 1, 2}), terms[:3])
+	assert.Equal(t, "<extra_id_0>", terms[len(terms)-1])
+}
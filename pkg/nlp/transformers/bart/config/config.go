@@ -64,6 +64,20 @@ type Config struct {
 	NumBeams                   int               `json:"num_beams"`
 	MaxLength                  int               `json:"max_length"`
 	BadWordsIDs                [][]int           `json:"bad_words_ids"`
+	MinLength                  int               `json:"min_length"`
+	LengthPenalty              *mat.Float        `json:"length_penalty"` // nil means 1.0
+	EarlyStopping              bool              `json:"early_stopping"`
+	RepetitionPenalty          mat.Float         `json:"repetition_penalty"`
+	NoRepeatNGramSize          int               `json:"no_repeat_ngram_size"`
+	NumBeamGroups              int               `json:"num_beam_groups"`
+	DiversityPenalty           mat.Float         `json:"diversity_penalty"`
+	ForcedBOSTokenID           *int              `json:"forced_bos_token_id"`
+	ForcedEOSTokenID           *int              `json:"forced_eos_token_id"` // nil means EosTokenID
+	DoSample                   bool              `json:"do_sample"`
+	Temperature                mat.Float         `json:"temperature"`
+	TopK                       int               `json:"top_k"`
+	TopP                       mat.Float         `json:"top_p"`
+	TypicalP                   mat.Float         `json:"typical_p"`
 	Training                   bool              `json:"training"` // Custom for spaGO
 }
 
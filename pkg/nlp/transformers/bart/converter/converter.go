@@ -20,7 +20,6 @@ import (
 	"github.com/nlpodyssey/spago/pkg/nlp/transformers/bart/head/conditionalgeneration"
 	"github.com/nlpodyssey/spago/pkg/nlp/transformers/bart/head/sequenceclassification"
 	"github.com/nlpodyssey/spago/pkg/nlp/transformers/bart/positionalencoder/learnedpositionalencoder"
-	"github.com/nlpodyssey/spago/pkg/utils"
 	"github.com/nlpodyssey/spago/pkg/utils/gopickleutils"
 	"log"
 	"os"
@@ -33,9 +32,20 @@ import (
 
 const defaultHuggingFaceModelFile = "pytorch_model.bin"
 
+// ConverterOption allows to adapt the conversion to your specific needs.
+type ConverterOption func(*huggingFacePreTrainedConverter)
+
+// ParamsPrecision sets the floating-point format used to store the params and the
+// word embeddings of the converted model (default mat.FullPrecision).
+func ParamsPrecision(precision mat.Precision) ConverterOption {
+	return func(c *huggingFacePreTrainedConverter) {
+		c.precision = precision
+	}
+}
+
 // ConvertHuggingFacePreTrained converts a HuggingFace pre-trained BART
 // transformer model to a corresponding spaGO model.
-func ConvertHuggingFacePreTrained(modelPath string) error {
+func ConvertHuggingFacePreTrained(modelPath string, opts ...ConverterOption) error {
 	configFilename, err := exists(path.Join(modelPath, pkgconfig.DefaultConfigurationFile))
 	if err != nil {
 		return err
@@ -71,7 +81,12 @@ func ConvertHuggingFacePreTrained(modelPath string) error {
 		classificationHead:   classification,
 		generationHead:       linear.New(config.DModel, config.VocabSize),
 		modelMapping:         make(map[string]*mappedParam), // lazy initialization
+		precision:            mat.FullPrecision,
 	}
+	for _, opt := range opts {
+		opt(handler)
+	}
+	model.Embeddings.Precision = handler.precision
 	err = handler.convert()
 	if err != nil {
 		return err
@@ -89,6 +104,7 @@ type huggingFacePreTrainedConverter struct {
 	classificationHead   *sequenceclassification.Classifier
 	generationHead       *linear.Model
 	modelMapping         map[string]*mappedParam
+	precision            mat.Precision
 }
 
 type mappedParam struct {
@@ -155,6 +171,13 @@ func (c *huggingFacePreTrainedConverter) convert() error {
 		}
 	}
 
+	if c.precision != mat.FullPrecision {
+		log.Printf("Convert the params to %s precision...", c.precision)
+		nn.SetPrecision(c.model, c.precision)
+		nn.SetPrecision(c.classificationHead, c.precision)
+		nn.SetPrecision(c.generationHead, c.precision)
+	}
+
 	fmt.Printf("Serializing model to \"%s\"... ", c.modelFilename)
 	if err := c.serializeModel(); err != nil {
 		return err
@@ -195,7 +218,7 @@ func (c *huggingFacePreTrainedConverter) serializeModel() error {
 		}
 	}
 
-	err := utils.SerializeToFile(c.modelFilename, model)
+	err := nn.SaveModelFile(c.modelFilename, model, c.config)
 	if err != nil {
 		return fmt.Errorf("bert: error during model serialization: %w", err)
 	}
@@ -311,7 +334,7 @@ func mapGenerationHead(model *linear.Model) map[string]mat.Matrix {
 
 func assignToParamsList(source []mat.Float, dest []nn.Param, rows, cols int) {
 	for i := 0; i < rows; i++ {
-		dest[i].Value().SetData(source[i*cols : (i+1)*cols])
+		dest[i].SetData(source[i*cols : (i+1)*cols])
 	}
 }
 
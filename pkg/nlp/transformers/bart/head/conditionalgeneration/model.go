@@ -5,6 +5,7 @@
 package conditionalgeneration
 
 import (
+	"context"
 	"encoding/gob"
 	"github.com/nlpodyssey/spago/pkg/ml/ag"
 	"github.com/nlpodyssey/spago/pkg/ml/nn"
@@ -57,8 +58,38 @@ func (m *Model) PredictNext(
 	return nn.ToNode(logits), nextCache
 }
 
-// Generate generates sequences using generation-search decoding.
-func (m *Model) Generate(inputIDs []int) []int {
+// Generate generates sequences using either beam-search decoding or sampling, as set in the configuration
+// of the model. The decoding options override the configuration.
+func (m *Model) Generate(inputIDs []int, opts ...generation.DecodingOption) []int {
+	out, _ := m.GenerateContext(context.Background(), inputIDs, nil, opts...)
+	return out
+}
+
+// GenerateContext is like Generate, but it stops as soon as the context is done, and it calls onStep
+// (if not nil) with the best partial sequence at each step (see generation.Generator.GenerateContext).
+func (m *Model) GenerateContext(
+	ctx context.Context,
+	inputIDs []int,
+	onStep generation.StepFunc,
+	opts ...generation.DecodingOption,
+) ([]int, error) {
+	return m.newGenerator(opts...).GenerateContext(ctx, inputIDs, onStep)
+}
+
+// GenerateBatch is like GenerateContext, but it generates a sequence for each input of the batch, decoding
+// them together (see generation.Generator.GenerateBatch).
+func (m *Model) GenerateBatch(
+	ctx context.Context,
+	inputs [][]int,
+	onStep generation.BatchStepFunc,
+	opts ...generation.DecodingOption,
+) ([][]int, error) {
+	return m.newGenerator(opts...).GenerateBatch(ctx, inputs, onStep)
+}
+
+// newGenerator returns a new generation.Generator configured according to the configuration of the model,
+// overridden by the decoding options.
+func (m *Model) newGenerator(opts ...generation.DecodingOption) *generation.Generator {
 	incrementalForward := m.Graph().IncrementalForwardEnabled()
 
 	maxConcurrentComputations := runtime.NumCPU()
@@ -66,9 +97,9 @@ func (m *Model) Generate(inputIDs []int) []int {
 		maxConcurrentComputations = runtime.NumCPU() / 2
 	}
 
-	generator := generation.NewGenerator(generation.GeneratorConfig{
+	generatorConfig := generation.GeneratorConfig{
 		NumBeams:                  m.BART.Config.NumBeams,
-		MinLength:                 0,
+		MinLength:                 m.BART.Config.MinLength,
 		MaxLength:                 m.BART.Config.MaxLength,
 		IsEncoderDecoder:          m.BART.Config.IsEncoderDecoder,
 		BOSTokenID:                m.BART.Config.BosTokenID,
@@ -77,13 +108,38 @@ func (m *Model) Generate(inputIDs []int) []int {
 		VocabSize:                 m.BART.Config.VocabSize,
 		DecoderStartTokenID:       m.BART.Config.DecoderStartTokenID,
 		LengthPenalty:             1.0,
-		EarlyStopping:             false,
+		EarlyStopping:             m.BART.Config.EarlyStopping,
 		BadWordsIDs:               m.BART.Config.BadWordsIDs,
+		RepetitionPenalty:         m.BART.Config.RepetitionPenalty,
+		NoRepeatNGramSize:         m.BART.Config.NoRepeatNGramSize,
+		ForceBOSToken:             m.BART.Config.ForceBosTokenToBeGenerated,
+		ForceEOSToken:             true,
+		ForcedEOSTokenID:          m.BART.Config.EosTokenID,
+		NumBeamGroups:             m.BART.Config.NumBeamGroups,
+		DiversityPenalty:          m.BART.Config.DiversityPenalty,
 		MaxConcurrentComputations: maxConcurrentComputations,
 		IncrementalForward:        incrementalForward,
-	}, m)
+		DoSample:                  m.BART.Config.DoSample,
+		Temperature:               m.BART.Config.Temperature,
+		TopK:                      m.BART.Config.TopK,
+		TopP:                      m.BART.Config.TopP,
+		TypicalP:                  m.BART.Config.TypicalP,
+	}
+	if m.BART.Config.LengthPenalty != nil {
+		generatorConfig.LengthPenalty = *m.BART.Config.LengthPenalty
+	}
+	if m.BART.Config.ForcedBOSTokenID != nil {
+		generatorConfig.ForceBOSToken = true
+		generatorConfig.BOSTokenID = *m.BART.Config.ForcedBOSTokenID
+	}
+	if m.BART.Config.ForcedEOSTokenID != nil {
+		generatorConfig.ForcedEOSTokenID = *m.BART.Config.ForcedEOSTokenID
+	}
+	for _, opt := range opts {
+		opt(&generatorConfig)
+	}
 
-	return generator.Generate(inputIDs)
+	return generation.NewGenerator(generatorConfig, m)
 }
 
 // Encode satisfies pkg/nlp/transformers/generation/Encoder.
@@ -11,13 +11,22 @@ import (
 	"github.com/nlpodyssey/spago/pkg/nlp/transformers/bart/config"
 	"github.com/nlpodyssey/spago/pkg/nlp/transformers/bart/head/conditionalgeneration"
 	"github.com/nlpodyssey/spago/pkg/nlp/transformers/bart/head/sequenceclassification"
-	"github.com/nlpodyssey/spago/pkg/utils"
 	"log"
 	"path"
 )
 
 // Load loads a Model model from file.
 func Load(modelPath string) (nn.Model, error) {
+	return load(modelPath, false)
+}
+
+// LoadForTraining loads a Model model from file, like Load, but with the embeddings storage opened writable,
+// regardless of the configuration, so that the embeddings can be trained.
+func LoadForTraining(modelPath string) (nn.Model, error) {
+	return load(modelPath, true)
+}
+
+func load(modelPath string, training bool) (nn.Model, error) {
 	configFilename := path.Join(modelPath, config.DefaultConfigurationFile)
 	embeddingsPath := path.Join(modelPath, config.DefaultEmbeddingsStorage)
 	modelFilename := path.Join(modelPath, config.DefaultModelFile)
@@ -29,6 +38,7 @@ func Load(modelPath string) (nn.Model, error) {
 		return nil, err
 	}
 	fmt.Printf("ok\n")
+	c.Training = c.Training || training
 
 	var model nn.Model
 	if len(c.Architecture) == 0 {
@@ -45,7 +55,7 @@ func Load(modelPath string) (nn.Model, error) {
 	}
 
 	fmt.Printf("[2/2] Loading model weights... ")
-	err = utils.DeserializeFromFile(modelFilename, model)
+	err = nn.LoadFromFile(modelFilename, model)
 	if err != nil {
 		log.Fatal(fmt.Sprintf("bert: error during model deserialization (%s)", err.Error()))
 	}
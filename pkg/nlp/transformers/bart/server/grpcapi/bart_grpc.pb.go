@@ -23,6 +23,8 @@ type BARTClient interface {
 	ClassifyNLI(ctx context.Context, in *ClassifyNLIRequest, opts ...grpc.CallOption) (*ClassifyReply, error)
 	// Send a request to generate.
 	Generate(ctx context.Context, in *GenerateRequest, opts ...grpc.CallOption) (*GenerateReply, error)
+	// Send a request to generate, receiving the partial hypotheses while the generation advances.
+	GenerateStream(ctx context.Context, in *GenerateRequest, opts ...grpc.CallOption) (BART_GenerateStreamClient, error)
 }
 
 type bARTClient struct {
@@ -60,6 +62,38 @@ func (c *bARTClient) Generate(ctx context.Context, in *GenerateRequest, opts ...
 	return out, nil
 }
 
+func (c *bARTClient) GenerateStream(ctx context.Context, in *GenerateRequest, opts ...grpc.CallOption) (BART_GenerateStreamClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_BART_serviceDesc.Streams[0], "/bart.grpcapi.BART/GenerateStream", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &bARTGenerateStreamClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type BART_GenerateStreamClient interface {
+	Recv() (*GenerateReply, error)
+	grpc.ClientStream
+}
+
+type bARTGenerateStreamClient struct {
+	grpc.ClientStream
+}
+
+func (x *bARTGenerateStreamClient) Recv() (*GenerateReply, error) {
+	m := new(GenerateReply)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // BARTServer is the server API for BART service.
 // All implementations must embed UnimplementedBARTServer
 // for forward compatibility
@@ -70,6 +104,8 @@ type BARTServer interface {
 	ClassifyNLI(context.Context, *ClassifyNLIRequest) (*ClassifyReply, error)
 	// Send a request to generate.
 	Generate(context.Context, *GenerateRequest) (*GenerateReply, error)
+	// Send a request to generate, receiving the partial hypotheses while the generation advances.
+	GenerateStream(*GenerateRequest, BART_GenerateStreamServer) error
 	mustEmbedUnimplementedBARTServer()
 }
 
@@ -86,6 +122,9 @@ func (UnimplementedBARTServer) ClassifyNLI(context.Context, *ClassifyNLIRequest)
 func (UnimplementedBARTServer) Generate(context.Context, *GenerateRequest) (*GenerateReply, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Generate not implemented")
 }
+func (UnimplementedBARTServer) GenerateStream(*GenerateRequest, BART_GenerateStreamServer) error {
+	return status.Errorf(codes.Unimplemented, "method GenerateStream not implemented")
+}
 func (UnimplementedBARTServer) mustEmbedUnimplementedBARTServer() {}
 
 // UnsafeBARTServer may be embedded to opt out of forward compatibility for this service.
@@ -153,6 +192,27 @@ func _BART_Generate_Handler(srv interface{}, ctx context.Context, dec func(inter
 	return interceptor(ctx, in, info, handler)
 }
 
+func _BART_GenerateStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(GenerateRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(BARTServer).GenerateStream(m, &bARTGenerateStreamServer{stream})
+}
+
+type BART_GenerateStreamServer interface {
+	Send(*GenerateReply) error
+	grpc.ServerStream
+}
+
+type bARTGenerateStreamServer struct {
+	grpc.ServerStream
+}
+
+func (x *bARTGenerateStreamServer) Send(m *GenerateReply) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 var _BART_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "bart.grpcapi.BART",
 	HandlerType: (*BARTServer)(nil),
@@ -170,6 +230,12 @@ var _BART_serviceDesc = grpc.ServiceDesc{
 			Handler:    _BART_Generate_Handler,
 		},
 	},
-	Streams:  []grpc.StreamDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "GenerateStream",
+			Handler:       _BART_GenerateStream_Handler,
+			ServerStreams: true,
+		},
+	},
 	Metadata: "bart.proto",
 }
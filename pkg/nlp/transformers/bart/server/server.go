@@ -8,6 +8,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"fmt"
 	"github.com/nlpodyssey/spago/pkg/ml/nn"
 	"github.com/nlpodyssey/spago/pkg/nlp/tokenizers/bpetokenizer"
 	"github.com/nlpodyssey/spago/pkg/nlp/tokenizers/sentencepiece"
@@ -19,6 +20,8 @@ import (
 	"github.com/nlpodyssey/spago/pkg/utils/httputils"
 	"github.com/nlpodyssey/spago/pkg/webui/bartnli"
 	"net/http"
+	"sync"
+	"time"
 )
 
 // Server contains everything needed to run a BART server.
@@ -28,6 +31,14 @@ type Server struct {
 	spTokenizer     *sentencepiece.Tokenizer
 	TimeoutSeconds  int
 	MaxRequestBytes int
+	// GenerateBatchWindow is the time waited for further generation requests after the first request of
+	// a batch, so that the texts of concurrent requests are generated together. Zero disables the batching.
+	GenerateBatchWindow time.Duration
+	// MaxGenerateBatchSize is the maximum number of generation requests of a batch (no limit if <= 0).
+	MaxGenerateBatchSize int
+
+	batcherOnce sync.Once
+	batcher     *generateBatcher
 
 	// UnimplementedBARTServer must be embedded to have forward compatible implementations for gRPC.
 	grpcapi.UnimplementedBARTServer
@@ -76,6 +87,7 @@ func (s *Server) StartDefaultHTTPServer(address, tlsCert, tlsKey string, tlsDisa
 		mux.HandleFunc("/classify-nli", s.ClassifyNLIHandler)
 	case *conditionalgeneration.Model:
 		mux.HandleFunc("/generate", s.GenerateHandler)
+		mux.HandleFunc("/generate-stream", s.GenerateStreamHandler)
 	default:
 		panic("bart: invalid model type")
 	}
@@ -111,15 +123,44 @@ func (s *Server) ClassifyNLI(_ context.Context, req *grpcapi.ClassifyNLIRequest)
 }
 
 // Generate handles a conditional generation request over gRPC.
-func (s *Server) Generate(_ context.Context, req *grpcapi.GenerateRequest) (*grpcapi.GenerateReply, error) {
-	result, err := s.generate(req.GetText())
+func (s *Server) Generate(ctx context.Context, req *grpcapi.GenerateRequest) (*grpcapi.GenerateReply, error) {
+	result, err := s.generateBatched(ctx, req.GetText(), samplingParamsFrom(req))
 	if err != nil {
 		return nil, err
 	}
+	return generateReplyFrom(result), nil
+}
+
+// GenerateStream handles a conditional generation request over gRPC, sending the partial hypotheses
+// while the generation advances, followed by the final result.
+func (s *Server) GenerateStream(req *grpcapi.GenerateRequest, stream grpcapi.BART_GenerateStreamServer) error {
+	onPartial := func(partial *GenerateResponse) error {
+		return stream.Send(generateReplyFrom(partial))
+	}
+	result, err := s.generate(stream.Context(), req.GetText(), onPartial, samplingParamsFrom(req).decodingOptions()...)
+	if err != nil {
+		return err
+	}
+	return stream.Send(generateReplyFrom(result))
+}
+
+func samplingParamsFrom(req *grpcapi.GenerateRequest) samplingParams {
+	return samplingParams{
+		DoSample:    req.GetDoSample(),
+		Temperature: req.GetTemperature(),
+		TopK:        int(req.GetTopK()),
+		TopP:        req.GetTopP(),
+		TypicalP:    req.GetTypicalP(),
+		Seed:        req.GetSeed(),
+	}
+}
+
+func generateReplyFrom(resp *GenerateResponse) *grpcapi.GenerateReply {
 	return &grpcapi.GenerateReply{
-		Text: result.Text,
-		Took: result.Took,
-	}, nil
+		Text:    resp.Text,
+		Partial: resp.Partial,
+		Took:    resp.Took,
+	}
 }
 
 type body struct {
@@ -129,6 +170,8 @@ type body struct {
 	HypothesisTemplate string   `json:"hypothesis_template"`
 	PossibleLabels     []string `json:"possible_labels"`
 	MultiClass         bool     `json:"multi_class"`
+	// Following fields used by Generate
+	samplingParams
 }
 
 // ClassifyHandler handles a classify request over HTTP.
@@ -207,7 +250,7 @@ func (s *Server) GenerateHandler(w http.ResponseWriter, req *http.Request) {
 		return
 	}
 
-	result, err := s.generate(content.Text)
+	result, err := s.generateBatched(req.Context(), content.Text, content.samplingParams)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -227,10 +270,58 @@ func (s *Server) GenerateHandler(w http.ResponseWriter, req *http.Request) {
 	}
 }
 
+// GenerateStreamHandler handles a conditional generation request over HTTP, sending the partial hypotheses
+// as server-sent events while the generation advances, followed by the final result. The generation is
+// stopped if the client closes the connection.
+func (s *Server) GenerateStreamHandler(w http.ResponseWriter, req *http.Request) {
+	w.Header().Set("Access-Control-Allow-Origin", "*") // that's intended for testing purposes only
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming not supported", http.StatusInternalServerError)
+		return
+	}
+
+	var content body
+	err := json.NewDecoder(req.Body).Decode(&content)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	sendEvent := func(event string, value interface{}) error {
+		data, err := json.Marshal(value)
+		if err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, data); err != nil {
+			return err
+		}
+		flusher.Flush()
+		return nil
+	}
+
+	onPartial := func(partial *GenerateResponse) error {
+		return sendEvent("partial", partial)
+	}
+	result, err := s.generate(req.Context(), content.Text, onPartial, content.samplingParams.decodingOptions()...)
+	if err != nil {
+		if req.Context().Err() == nil {
+			_ = sendEvent("error", err.Error())
+		}
+		return
+	}
+	_ = sendEvent("result", result)
+}
+
 // GenerateResponse is a JSON-serializable structure which holds server
 // generation response data.
 type GenerateResponse struct {
 	Text string `json:"text"`
+	// Partial reports whether the text is a partial hypothesis, rather than the final result of the generation.
+	Partial bool `json:"partial,omitempty"`
 	// Took is the number of milliseconds it took the server to execute the request.
 	Took int64 `json:"took"`
 }
@@ -0,0 +1,123 @@
+// Copyright 2021 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package server
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// generateBatcher collects the generation requests received within a time window, to generate the texts
+// of all of them in a single batch.
+type generateBatcher struct {
+	// window is the time waited for further requests after the first request of a batch.
+	window time.Duration
+	// maxSize is the maximum number of requests of a batch (no limit if <= 0).
+	maxSize int
+	// run generates the texts of a batch.
+	run func(ctx context.Context, texts []string) ([]string, error)
+
+	mu      sync.Mutex
+	pending *pendingBatch
+}
+
+// pendingBatch is a batch of requests waiting to be executed.
+type pendingBatch struct {
+	requests []*batchRequest
+	timer    *time.Timer
+}
+
+// batchRequest is a generation request waiting for the result of its batch.
+type batchRequest struct {
+	ctx    context.Context
+	text   string
+	result chan batchResult
+}
+
+// batchResult is the result of a request of a batch.
+type batchResult struct {
+	text string
+	err  error
+}
+
+// generate adds the text to the pending batch and waits for the result of the generation.
+// It returns the error of the context if the context is done before the result is ready.
+func (b *generateBatcher) generate(ctx context.Context, text string) (string, error) {
+	r := &batchRequest{
+		ctx:    ctx,
+		text:   text,
+		result: make(chan batchResult, 1),
+	}
+
+	b.mu.Lock()
+	batch := b.pending
+	if batch == nil {
+		batch = &pendingBatch{}
+		batch.timer = time.AfterFunc(b.window, func() { b.flush(batch) })
+		b.pending = batch
+	}
+	batch.requests = append(batch.requests, r)
+	full := b.maxSize > 0 && len(batch.requests) >= b.maxSize
+	if full {
+		batch.timer.Stop()
+		b.pending = nil
+	}
+	b.mu.Unlock()
+
+	if full {
+		go b.execute(batch.requests)
+	}
+
+	select {
+	case res := <-r.result:
+		return res.text, res.err
+	case <-ctx.Done():
+		return "", ctx.Err()
+	}
+}
+
+// flush executes the batch, unless it was already executed because it reached the maximum size.
+func (b *generateBatcher) flush(batch *pendingBatch) {
+	b.mu.Lock()
+	if b.pending != batch {
+		b.mu.Unlock()
+		return
+	}
+	b.pending = nil
+	b.mu.Unlock()
+
+	b.execute(batch.requests)
+}
+
+// execute generates the texts of the requests, and sends the results to them. The generation is stopped
+// only when the contexts of all the requests are done.
+func (b *generateBatcher) execute(requests []*batchRequest) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		for _, r := range requests {
+			select {
+			case <-r.ctx.Done():
+			case <-ctx.Done():
+				return
+			}
+		}
+		cancel()
+	}()
+
+	texts := make([]string, len(requests))
+	for i, r := range requests {
+		texts[i] = r.text
+	}
+	generated, err := b.run(ctx, texts)
+	for i, r := range requests {
+		if err != nil {
+			r.result <- batchResult{err: err}
+			continue
+		}
+		r.result <- batchResult{text: generated[i]}
+	}
+}
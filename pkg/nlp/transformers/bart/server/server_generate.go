@@ -5,12 +5,65 @@
 package server
 
 import (
+	"context"
+	cryptorand "crypto/rand"
+	"encoding/binary"
+	mat "github.com/nlpodyssey/spago/pkg/mat32"
 	"github.com/nlpodyssey/spago/pkg/nlp/transformers/bart/head/conditionalgeneration"
 	"github.com/nlpodyssey/spago/pkg/nlp/transformers/bart/tasks/seq2seq"
+	"github.com/nlpodyssey/spago/pkg/nlp/transformers/generation"
 	"time"
 )
 
-func (s *Server) generate(text string) (*GenerateResponse, error) {
+// samplingParams contains the sampling settings of a generation request.
+type samplingParams struct {
+	DoSample    bool    `json:"do_sample"`
+	Temperature float64 `json:"temperature"`
+	TopK        int     `json:"top_k"`
+	TopP        float64 `json:"top_p"`
+	TypicalP    float64 `json:"typical_p"`
+	// Seed is the seed of the random generator. Zero means a random seed, so that the requests without
+	// a seed are sampled independently.
+	Seed uint64 `json:"seed"`
+}
+
+// decodingOptions returns the decoding options corresponding to the sampling settings; none if the
+// sampling is not enabled, so that the decoding strategy of the model configuration is used.
+func (p samplingParams) decodingOptions() []generation.DecodingOption {
+	if !p.DoSample {
+		return nil
+	}
+	seed := p.Seed
+	if seed == 0 {
+		seed = randomSeed()
+	}
+	return []generation.DecodingOption{
+		generation.Sampling(seed),
+		generation.Temperature(mat.Float(p.Temperature)),
+		generation.TopK(p.TopK),
+		generation.TopP(mat.Float(p.TopP)),
+		generation.TypicalP(mat.Float(p.TypicalP)),
+	}
+}
+
+// randomSeed returns a seed read from the cryptographically secure random generator, or derived from the
+// current time if it is not available.
+func randomSeed() uint64 {
+	var b [8]byte
+	if _, err := cryptorand.Read(b[:]); err != nil {
+		return uint64(time.Now().UnixNano())
+	}
+	return binary.LittleEndian.Uint64(b[:])
+}
+
+// generate generates a text starting from the input. If onPartial is not nil, it is called with the best
+// partial hypothesis at each step of the generation. The generation is stopped as soon as the context is done.
+func (s *Server) generate(
+	ctx context.Context,
+	text string,
+	onPartial func(*GenerateResponse) error,
+	opts ...generation.DecodingOption,
+) (*GenerateResponse, error) {
 	start := time.Now()
 
 	task := seq2seq.BartForConditionalGeneration{
@@ -18,7 +71,18 @@ func (s *Server) generate(text string) (*GenerateResponse, error) {
 		Tokenizer: s.spTokenizer,
 	}
 
-	generated, err := task.Generate(text)
+	var onPartialText func(string) error
+	if onPartial != nil {
+		onPartialText = func(partial string) error {
+			return onPartial(&GenerateResponse{
+				Text:    partial,
+				Partial: true,
+				Took:    time.Since(start).Milliseconds(),
+			})
+		}
+	}
+
+	generated, err := task.GenerateStream(ctx, text, onPartialText, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -28,3 +92,41 @@ func (s *Server) generate(text string) (*GenerateResponse, error) {
 		Took: time.Since(start).Milliseconds(),
 	}, nil
 }
+
+// generateBatched is like generate, without the partial hypotheses, but it adds the request to a batch
+// when the micro-batching is enabled (see Server.GenerateBatchWindow). The sampling requests are never
+// batched, so that the same seed always gives the same result.
+func (s *Server) generateBatched(ctx context.Context, text string, params samplingParams) (*GenerateResponse, error) {
+	if s.GenerateBatchWindow <= 0 || params.DoSample {
+		return s.generate(ctx, text, nil, params.decodingOptions()...)
+	}
+
+	start := time.Now()
+	generated, err := s.generateBatcher().generate(ctx, text)
+	if err != nil {
+		return nil, err
+	}
+
+	return &GenerateResponse{
+		Text: generated,
+		Took: time.Since(start).Milliseconds(),
+	}, nil
+}
+
+// generateBatcher returns the batcher of the generation requests, creating it at the first use.
+func (s *Server) generateBatcher() *generateBatcher {
+	s.batcherOnce.Do(func() {
+		task := seq2seq.BartForConditionalGeneration{
+			Model:     s.model.(*conditionalgeneration.Model),
+			Tokenizer: s.spTokenizer,
+		}
+		s.batcher = &generateBatcher{
+			window:  s.GenerateBatchWindow,
+			maxSize: s.MaxGenerateBatchSize,
+			run: func(ctx context.Context, texts []string) ([]string, error) {
+				return task.GenerateBatch(ctx, texts)
+			},
+		}
+	})
+	return s.batcher
+}
@@ -5,12 +5,14 @@
 package seq2seq
 
 import (
+	"context"
 	"github.com/nlpodyssey/spago/pkg/ml/ag"
 	"github.com/nlpodyssey/spago/pkg/ml/nn"
 	"github.com/nlpodyssey/spago/pkg/nlp/tokenizers/sentencepiece"
 	"github.com/nlpodyssey/spago/pkg/nlp/transformers/bart/config"
 	"github.com/nlpodyssey/spago/pkg/nlp/transformers/bart/head/conditionalgeneration"
 	"github.com/nlpodyssey/spago/pkg/nlp/transformers/bart/loader"
+	"github.com/nlpodyssey/spago/pkg/nlp/transformers/generation"
 )
 
 var _ nn.Model = &BartForConditionalGeneration{}
@@ -41,25 +43,82 @@ func LoadModel(modelPath string) (*BartForConditionalGeneration, error) {
 }
 
 // Generate generates new texts starting from the input.
-func (t *BartForConditionalGeneration) Generate(text string) (string, error) {
+// The decoding options override the decoding strategy set in the configuration of the model
+// (e.g. generation.Sampling to sample the next tokens instead of performing a beam search).
+func (t *BartForConditionalGeneration) Generate(text string, opts ...generation.DecodingOption) (string, error) {
+	return t.GenerateStream(context.Background(), text, nil, opts...)
+}
+
+// GenerateStream is like Generate, but it calls onPartial (if not nil) with the text of the best partial
+// hypothesis at each step of the generation, and it stops as soon as the context is done, returning the
+// error of the context. If onPartial returns an error, the generation is stopped and the error is returned.
+func (t *BartForConditionalGeneration) GenerateStream(
+	ctx context.Context,
+	text string,
+	onPartial func(text string) error,
+	opts ...generation.DecodingOption,
+) (string, error) {
 	g := ag.NewGraph(ag.IncrementalForward(false))
 	defer g.Clear()
 
 	proc := nn.Reify(nn.Context{Graph: g, Mode: nn.Inference}, t.Model).(*conditionalgeneration.Model)
 	bartConfig := proc.BART.Config
 
-	tokens := t.Tokenizer.Tokenize(text)
-	tokenIDs := t.Tokenizer.TokensToIDs(tokens)
+	tokenIDs := t.encode(text, bartConfig)
 
-	tokenIDs = append(tokenIDs, bartConfig.EosTokenID)
+	var onStep generation.StepFunc
+	if onPartial != nil {
+		onStep = func(partialIDs []int) error {
+			return onPartial(t.detokenize(partialIDs, bartConfig))
+		}
+	}
+	rawGeneratedIDs, err := proc.GenerateContext(ctx, tokenIDs, onStep, opts...)
+	if err != nil {
+		return "", err
+	}
+	return t.detokenize(rawGeneratedIDs, bartConfig), nil
+}
 
-	rawGeneratedIDs := proc.Generate(tokenIDs)
-	generatedIDs := t.stripBadTokens(rawGeneratedIDs, bartConfig)
+// GenerateBatch generates a new text for each input text, decoding them together.
+// It stops as soon as the context is done, returning the error of the context.
+func (t *BartForConditionalGeneration) GenerateBatch(
+	ctx context.Context,
+	texts []string,
+	opts ...generation.DecodingOption,
+) ([]string, error) {
+	g := ag.NewGraph(ag.IncrementalForward(false))
+	defer g.Clear()
 
-	generatedTokens := t.Tokenizer.IDsToTokens(generatedIDs)
-	generatedText := t.Tokenizer.Detokenize(generatedTokens)
+	proc := nn.Reify(nn.Context{Graph: g, Mode: nn.Inference}, t.Model).(*conditionalgeneration.Model)
+	bartConfig := proc.BART.Config
 
-	return generatedText, nil
+	inputs := make([][]int, len(texts))
+	for i, text := range texts {
+		inputs[i] = t.encode(text, bartConfig)
+	}
+	rawGeneratedIDs, err := proc.GenerateBatch(ctx, inputs, nil, opts...)
+	if err != nil {
+		return nil, err
+	}
+	outputs := make([]string, len(rawGeneratedIDs))
+	for i, ids := range rawGeneratedIDs {
+		outputs[i] = t.detokenize(ids, bartConfig)
+	}
+	return outputs, nil
+}
+
+// encode returns the IDs of the tokens of the text, followed by the EOS token.
+func (t *BartForConditionalGeneration) encode(text string, bartConfig config.Config) []int {
+	tokens := t.Tokenizer.Tokenize(text)
+	tokenIDs := t.Tokenizer.TokensToIDs(tokens)
+	return append(tokenIDs, bartConfig.EosTokenID)
+}
+
+// detokenize returns the text corresponding to the generated IDs, without the special tokens.
+func (t *BartForConditionalGeneration) detokenize(ids []int, bartConfig config.Config) string {
+	generatedIDs := t.stripBadTokens(ids, bartConfig)
+	generatedTokens := t.Tokenizer.IDsToTokens(generatedIDs)
+	return t.Tokenizer.Detokenize(generatedTokens)
 }
 
 func (t *BartForConditionalGeneration) stripBadTokens(ids []int, bartConfig config.Config) []int {
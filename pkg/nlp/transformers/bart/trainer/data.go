@@ -0,0 +1,147 @@
+// Copyright 2021 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package trainer
+
+import (
+	"bufio"
+	"fmt"
+	"github.com/nlpodyssey/spago/pkg/nlp/tokenizers/bpetokenizer"
+	"github.com/nlpodyssey/spago/pkg/nlp/tokenizers/sentencepiece"
+	"github.com/nlpodyssey/spago/pkg/nlp/transformers/bart/config"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Example is a training example of a BART model.
+type Example struct {
+	// InputIDs are the IDs of the input tokens, including the special tokens: the source text of the
+	// sequence-to-sequence tasks, or the premise and the hypothesis of the classification.
+	InputIDs []int
+	// TargetIDs are the IDs of the tokens of the target text of the sequence-to-sequence tasks, ending with
+	// the end-of-sequence token.
+	TargetIDs []int
+	// Label is the index of the class of the classification.
+	Label int
+}
+
+// Encoder converts a text into the IDs of its tokens, without special tokens.
+type Encoder func(text string) ([]int, error)
+
+// SentencePieceEncoder returns an Encoder based on the sentence-piece tokenizer, as used for the
+// conditional generation (see seq2seq.BartForConditionalGeneration).
+func SentencePieceEncoder(tokenizer *sentencepiece.Tokenizer) Encoder {
+	return func(text string) ([]int, error) {
+		return tokenizer.TokensToIDs(tokenizer.Tokenize(text)), nil
+	}
+}
+
+// BPEEncoder returns an Encoder based on the byte-level BPE tokenizer, as used for the classification
+// (see zsc.BartForZeroShotClassification).
+func BPEEncoder(tokenizer *bpetokenizer.BPETokenizer) Encoder {
+	return func(text string) ([]int, error) {
+		encoded, err := tokenizer.Encode(text)
+		if err != nil {
+			return nil, err
+		}
+		return encoded.IDs, nil
+	}
+}
+
+// ReadSeq2SeqExamples reads the examples of a sequence-to-sequence task from a pair of text files with the same
+// number of lines, where each line of the target file is the expected output of the same line of the source file.
+// Both the source and the target token IDs end with the end-of-sequence token.
+func ReadSeq2SeqExamples(sourceFilename, targetFilename string, encode Encoder, c config.Config) ([]Example, error) {
+	sources, err := readLines(sourceFilename)
+	if err != nil {
+		return nil, err
+	}
+	targets, err := readLines(targetFilename)
+	if err != nil {
+		return nil, err
+	}
+	if len(sources) != len(targets) {
+		return nil, fmt.Errorf("bart: %d source lines and %d target lines", len(sources), len(targets))
+	}
+	examples := make([]Example, len(sources))
+	for i := range sources {
+		source, err := encode(sources[i])
+		if err != nil {
+			return nil, fmt.Errorf("bart: %s line %d: %w", sourceFilename, i+1, err)
+		}
+		target, err := encode(targets[i])
+		if err != nil {
+			return nil, fmt.Errorf("bart: %s line %d: %w", targetFilename, i+1, err)
+		}
+		examples[i] = Example{
+			InputIDs:  append(source, c.EosTokenID),
+			TargetIDs: append(target, c.EosTokenID),
+		}
+	}
+	return examples, nil
+}
+
+// ReadClassificationExamples reads the examples of a sentence-pair classification task (e.g. NLI) from a
+// tab-separated file, with the premise, the hypothesis and the label on each line. The labels are the ones
+// of the configuration (e.g. "entailment"). The input IDs are `<s> premise </s></s> hypothesis </s>`.
+func ReadClassificationExamples(filename string, encode Encoder, c config.Config) ([]Example, error) {
+	lines, err := readLines(filename)
+	if err != nil {
+		return nil, err
+	}
+	labels := make(map[string]int, len(c.ID2Label))
+	for id, label := range c.ID2Label {
+		if labels[label], err = strconv.Atoi(id); err != nil {
+			return nil, fmt.Errorf("bart: invalid label ID `%s`", id)
+		}
+	}
+	examples := make([]Example, len(lines))
+	for i, line := range lines {
+		fields := strings.Split(line, "\t")
+		if len(fields) != 3 {
+			return nil, fmt.Errorf("bart: %s line %d: expected premise, hypothesis and label", filename, i+1)
+		}
+		label, ok := labels[fields[2]]
+		if !ok {
+			return nil, fmt.Errorf("bart: %s line %d: unknown label `%s`", filename, i+1, fields[2])
+		}
+		premise, err := encode(fields[0])
+		if err != nil {
+			return nil, fmt.Errorf("bart: %s line %d: %w", filename, i+1, err)
+		}
+		hypothesis, err := encode(fields[1])
+		if err != nil {
+			return nil, fmt.Errorf("bart: %s line %d: %w", filename, i+1, err)
+		}
+		inputIDs := append(append([]int{c.BosTokenID}, premise...), c.EosTokenID, c.EosTokenID)
+		examples[i] = Example{
+			InputIDs: append(append(inputIDs, hypothesis...), c.EosTokenID),
+			Label:    label,
+		}
+	}
+	return examples, nil
+}
+
+// readLines returns the lines of the file, skipping the trailing empty lines.
+func readLines(filename string) ([]string, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	lines := make([]string, 0)
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		lines = append(lines, strings.TrimRight(scanner.Text(), "\r"))
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	for len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines, nil
+}
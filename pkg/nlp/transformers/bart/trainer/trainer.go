@@ -0,0 +1,226 @@
+// Copyright 2021 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package trainer implements the fine-tuning of the BART models: the sequence-to-sequence tasks (e.g. translation
+// and summarization) of the conditional generation model, trained with teacher forcing and label smoothing, and
+// the sentence-level classification tasks (e.g. NLI) of the sequence classification model.
+package trainer
+
+import (
+	"fmt"
+	mat "github.com/nlpodyssey/spago/pkg/mat32"
+	"github.com/nlpodyssey/spago/pkg/mat32/floatutils"
+	"github.com/nlpodyssey/spago/pkg/mat32/rand"
+	"github.com/nlpodyssey/spago/pkg/ml/ag"
+	"github.com/nlpodyssey/spago/pkg/ml/losses"
+	"github.com/nlpodyssey/spago/pkg/ml/nn"
+	"github.com/nlpodyssey/spago/pkg/ml/optimizers/gd"
+	"github.com/nlpodyssey/spago/pkg/ml/optimizers/gd/gdmbuilder"
+	"github.com/nlpodyssey/spago/pkg/ml/training"
+	"github.com/nlpodyssey/spago/pkg/nlp/transformers/bart/config"
+	"github.com/nlpodyssey/spago/pkg/nlp/transformers/bart/head/conditionalgeneration"
+	"github.com/nlpodyssey/spago/pkg/nlp/transformers/bart/head/sequenceclassification"
+	"path"
+	"runtime"
+)
+
+// Names of the metrics of the evaluation on the development set, during the training.
+const (
+	devLossMetric  = "Dev loss"
+	accuracyMetric = "Accuracy"
+)
+
+// Config provides configuration settings for a BART Trainer.
+type Config struct {
+	Seed      uint64
+	Epochs    int
+	BatchSize int
+	// GradientAccumulationSteps is the number of batches whose gradients are accumulated before each update
+	// of the params, so that the effective batch size is BatchSize*GradientAccumulationSteps. Zero means one.
+	GradientAccumulationSteps int
+	GradientClipping          mat.Float
+	// LabelSmoothing is the smoothing factor of the cross-entropy of the sequence-to-sequence tasks.
+	// Zero means no smoothing.
+	LabelSmoothing mat.Float
+	UpdateMethod   gd.MethodConfig
+	// ModelPath is the directory of the model, where the model file is saved after each epoch, or only when the
+	// loss on the development set improves.
+	ModelPath string
+}
+
+// Evaluation contains the results of the evaluation of a model on a set of examples.
+type Evaluation struct {
+	// Loss is the average cross-entropy (without label smoothing) of the target tokens, or of the labels.
+	Loss mat.Float
+	// Accuracy is the ratio of the target tokens predicted with teacher forcing, or of the labels, which are
+	// correctly predicted.
+	Accuracy mat.Float
+}
+
+// Trainer implements the fine-tuning of a conditional generation or a sequence classification BART model.
+// The embeddings are trained too if the model has been loaded with a writable storage (see loader.LoadForTraining);
+// their storage is updated in place.
+type Trainer struct {
+	Config
+	model      nn.Model
+	bartConfig config.Config
+	randGen    *rand.LockedRand
+	optimizer  *gd.GradientDescent
+	// bestLoss monitors the loss on the development examples, if any.
+	bestLoss *training.Monitor
+}
+
+// New returns a new Trainer for a *conditionalgeneration.Model or a *sequenceclassification.Model.
+// It panics if the model is not supported.
+func New(model nn.Model, c Config) *Trainer {
+	var bartConfig config.Config
+	switch m := model.(type) {
+	case *conditionalgeneration.Model:
+		bartConfig = m.BART.Config
+	case *sequenceclassification.Model:
+		bartConfig = m.BART.Config
+	default:
+		panic(fmt.Errorf("bart: unsupported model %T", model))
+	}
+	optimizer := gd.NewOptimizer(gdmbuilder.NewMethod(c.UpdateMethod), nn.NewDefaultParamsIterator(model))
+	if c.GradientClipping != 0.0 {
+		gd.ClipGradByNorm(c.GradientClipping, 2.0)(optimizer)
+	}
+	return &Trainer{
+		Config:     c,
+		model:      model,
+		bartConfig: bartConfig,
+		randGen:    rand.NewLockedRand(c.Seed),
+		optimizer:  optimizer,
+	}
+}
+
+// Train fine-tunes the model on the training examples. After each epoch, the model is evaluated on the
+// development examples, and saved if its loss is the lowest so far; without development examples, the model
+// is saved after each epoch.
+func (t *Trainer) Train(trainSet, devSet []Example) error {
+	trainer := training.New(
+		training.Config{
+			Epochs:                    t.Epochs,
+			BatchSize:                 t.BatchSize,
+			GradientAccumulationSteps: t.GradientAccumulationSteps,
+			Shuffle:                   true,
+			Seed:                      t.Seed,
+		},
+		t.optimizer,
+		t.learn,
+		t.callbacks(devSet)...,
+	)
+	return trainer.Train(training.NewExamples(trainSet))
+}
+
+// callbacks returns the callbacks of the training: the evaluation on the development examples, if any,
+// the logging and the saving of the best model.
+func (t *Trainer) callbacks(devSet []Example) []training.Callback {
+	save := &training.Checkpoint{Save: func(*training.State) error { return t.SaveModel() }}
+	if len(devSet) == 0 {
+		return []training.Callback{&training.Logger{}, save}
+	}
+	evaluation := &training.Evaluation{
+		Evaluate: func() (training.Metrics, error) {
+			evaluation := t.Evaluate(devSet)
+			return training.Metrics{devLossMetric: evaluation.Loss, accuracyMetric: evaluation.Accuracy}, nil
+		},
+	}
+	t.bestLoss = &training.Monitor{Metric: devLossMetric, Mode: training.Minimize}
+	save.Monitor = t.bestLoss
+	return []training.Callback{evaluation, &training.Logger{}, save}
+}
+
+// SaveModel saves the model to the model file of the model path.
+func (t *Trainer) SaveModel() error {
+	filename := path.Join(t.ModelPath, config.DefaultModelFile)
+	if err := nn.SaveModelFile(filename, t.model, t.bartConfig); err != nil {
+		return fmt.Errorf("bart: error during model serialization: %w", err)
+	}
+	return nil
+}
+
+// learn accumulates the gradients of the losses of the batch of examples, averaged and multiplied by the
+// gradient scale, and returns the average loss.
+func (t *Trainer) learn(batch []interface{}, gradScale mat.Float) mat.Float {
+	var totalLoss mat.Float
+	for _, example := range batch {
+		totalLoss += t.learnExample(example.(Example), gradScale/mat.Float(len(batch)))
+	}
+	return totalLoss / mat.Float(len(batch))
+}
+
+// learnExample accumulates the gradients of the loss of the example, multiplied by the gradient scale, and
+// returns the loss.
+func (t *Trainer) learnExample(example Example, gradScale mat.Float) mat.Float {
+	g := ag.NewGraph(ag.Rand(t.randGen), ag.ConcurrentComputations(runtime.NumCPU()))
+	defer g.Clear()
+	loss, _ := t.forward(nn.Context{Graph: g, Mode: nn.Training}, example, t.LabelSmoothing)
+	g.Backward(g.ProdScalar(loss, g.NewScalar(gradScale)))
+	return loss.ScalarValue()
+}
+
+// Evaluate returns the Evaluation of the model on the examples.
+func (t *Trainer) Evaluate(examples []Example) Evaluation {
+	var evaluation Evaluation
+	correct, total := 0, 0
+	for _, example := range examples {
+		g := ag.NewGraph(ag.ConcurrentComputations(runtime.NumCPU()))
+		loss, predicted := t.forward(nn.Context{Graph: g, Mode: nn.Inference}, example, 0)
+		evaluation.Loss += loss.ScalarValue()
+		for i, id := range expectedIDs(example) {
+			if predicted[i] == id {
+				correct++
+			}
+			total++
+		}
+		g.Clear()
+	}
+	if len(examples) > 0 {
+		evaluation.Loss /= mat.Float(len(examples))
+	}
+	if total > 0 {
+		evaluation.Accuracy = mat.Float(correct) / mat.Float(total)
+	}
+	return evaluation
+}
+
+// expectedIDs returns the target token IDs, or the label, of the example.
+func expectedIDs(example Example) []int {
+	if example.TargetIDs != nil {
+		return example.TargetIDs
+	}
+	return []int{example.Label}
+}
+
+// forward returns the loss of the example, with the given label smoothing, and the predicted target token IDs,
+// or the predicted label.
+func (t *Trainer) forward(ctx nn.Context, example Example, labelSmoothing mat.Float) (ag.Node, []int) {
+	g := ctx.Graph
+	switch proc := nn.Reify(ctx, t.model).(type) {
+	case *conditionalgeneration.Model:
+		encoded := proc.BART.Encode(example.InputIDs)
+		decoded, _ := proc.BART.Decode(t.decoderInputIDs(example.TargetIDs), encoded, nil)
+		var loss ag.Node
+		predicted := make([]int, len(decoded))
+		for i, logits := range proc.Projection.Forward(decoded...) {
+			loss = g.Add(loss, losses.LabelSmoothedCrossEntropy(g, logits, example.TargetIDs[i], labelSmoothing))
+			predicted[i] = floatutils.ArgMax(logits.Value().Data())
+		}
+		return g.DivScalar(loss, g.NewScalar(mat.Float(len(decoded)))), predicted
+	case *sequenceclassification.Model:
+		logits := proc.Classify(example.InputIDs)
+		predicted := []int{floatutils.ArgMax(logits.Value().Data())}
+		return losses.CrossEntropy(g, logits, example.Label), predicted
+	default:
+		panic(fmt.Errorf("bart: unsupported model %T", proc))
+	}
+}
+
+// decoderInputIDs returns the input of the decoder for teacher forcing: the target IDs shifted right,
+// starting with the decoder start token.
+func (t *Trainer) decoderInputIDs(targetIDs []int) []int {
+	return append([]int{t.bartConfig.DecoderStartTokenID}, targetIDs[:len(targetIDs)-1]...)
+}
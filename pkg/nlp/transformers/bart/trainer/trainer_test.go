@@ -0,0 +1,167 @@
+// Copyright 2021 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package trainer
+
+import (
+	"encoding/json"
+	mat "github.com/nlpodyssey/spago/pkg/mat32"
+	"github.com/nlpodyssey/spago/pkg/mat32/rand"
+	"github.com/nlpodyssey/spago/pkg/ml/initializers"
+	"github.com/nlpodyssey/spago/pkg/ml/nn"
+	"github.com/nlpodyssey/spago/pkg/ml/optimizers/gd/adam"
+	"github.com/nlpodyssey/spago/pkg/nlp/embeddings"
+	"github.com/nlpodyssey/spago/pkg/nlp/transformers/bart/config"
+	"github.com/nlpodyssey/spago/pkg/nlp/transformers/bart/head/conditionalgeneration"
+	"github.com/nlpodyssey/spago/pkg/nlp/transformers/bart/head/sequenceclassification"
+	"github.com/nlpodyssey/spago/pkg/nlp/transformers/bart/loader"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io/ioutil"
+	"path"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func newTestConfig(architecture string) config.Config {
+	return config.Config{
+		NumLabels:             3,
+		ActivationFunction:    "gelu",
+		Architecture:          []string{architecture},
+		BosTokenID:            0,
+		DModel:                4,
+		DecoderAttentionHeads: 2,
+		DecoderFFNDim:         8,
+		DecoderLayers:         1,
+		DecoderStartTokenID:   2,
+		EncoderAttentionHeads: 2,
+		EncoderFFNDim:         8,
+		EncoderLayers:         1,
+		EosTokenID:            2,
+		ExtraPosEmbedding:     2,
+		ID2Label:              map[string]string{"0": "contradiction", "1": "neutral", "2": "entailment"},
+		IsEncoderDecoder:      true,
+		NormalizeEmbedding:    true,
+		PadTokenID:            1,
+		VocabSize:             8,
+		Training:              true,
+	}
+}
+
+func initTestModel(model nn.Model, emb *embeddings.Model, c config.Config) {
+	rndGen := rand.NewLockedRand(42)
+	nn.ForEachParam(model, func(param nn.Param) {
+		initializers.Normal(param.Value(), 0, 0.5, rndGen)
+	})
+	for i := 0; i < c.VocabSize; i++ {
+		data := make([]mat.Float, c.DModel)
+		for j := range data {
+			data[j] = mat.Float(rndGen.Float()) - 0.5
+		}
+		emb.SetEmbeddingFromData(strconv.Itoa(i), data)
+	}
+}
+
+func newTestTrainingConfig(modelPath string) Config {
+	return Config{
+		Seed:                      1,
+		Epochs:                    30,
+		BatchSize:                 1,
+		GradientAccumulationSteps: 2,
+		GradientClipping:          1.0,
+		LabelSmoothing:            0.1,
+		UpdateMethod:              adam.NewConfig(0.02, 0.9, 0.999, 1.0e-8),
+		ModelPath:                 modelPath,
+	}
+}
+
+func TestTrainer_Seq2Seq(t *testing.T) {
+	c := newTestConfig("MarianMTModel")
+	modelPath := t.TempDir()
+	model := conditionalgeneration.New(c, path.Join(modelPath, config.DefaultEmbeddingsStorage))
+	initTestModel(model, model.BART.Embeddings, c)
+
+	examples := []Example{
+		{InputIDs: []int{3, 4, 2}, TargetIDs: []int{5, 6, 2}},
+		{InputIDs: []int{4, 3, 2}, TargetIDs: []int{7, 2}},
+		{InputIDs: []int{5, 2}, TargetIDs: []int{3, 3, 2}},
+	}
+	trainer := New(model, newTestTrainingConfig(modelPath))
+	assert.Equal(t, []int{2, 5, 6}, trainer.decoderInputIDs(examples[0].TargetIDs))
+
+	initial := trainer.Evaluate(examples)
+	require.NoError(t, trainer.Train(examples, examples))
+	evaluation := trainer.Evaluate(examples)
+	assert.Less(t, float64(evaluation.Loss), float64(initial.Loss))
+	assert.Greater(t, float64(evaluation.Accuracy), float64(initial.Accuracy))
+
+	data, err := json.Marshal(c)
+	require.NoError(t, err)
+	require.NoError(t, ioutil.WriteFile(path.Join(modelPath, config.DefaultConfigurationFile), data, 0644))
+	model.Close()
+	loaded, err := loader.LoadForTraining(modelPath)
+	require.NoError(t, err)
+	defer loaded.(*conditionalgeneration.Model).Close()
+	assert.False(t, loaded.(*conditionalgeneration.Model).BART.Embeddings.ReadOnly)
+	reloaded := New(loaded, newTestTrainingConfig(modelPath)).Evaluate(examples)
+	assert.InDelta(t, float64(trainer.bestLoss.Best()), float64(reloaded.Loss), 1.0e-5) // the best model
+}
+
+func TestTrainer_Classification(t *testing.T) {
+	c := newTestConfig("BartForSequenceClassification")
+	model := sequenceclassification.New(c, t.TempDir())
+	defer model.Close()
+	initTestModel(model, model.BART.Embeddings, c)
+
+	examples := []Example{
+		{InputIDs: []int{0, 3, 2, 2, 3, 2}, Label: 2},
+		{InputIDs: []int{0, 3, 2, 2, 4, 2}, Label: 0},
+		{InputIDs: []int{0, 5, 2, 2, 6, 2}, Label: 1},
+	}
+	config := newTestTrainingConfig(t.TempDir())
+	config.Epochs = 40
+	trainer := New(model, config)
+	require.NoError(t, trainer.Train(examples, nil))
+	evaluation := trainer.Evaluate(examples)
+	assert.Equal(t, mat.Float(1.0), evaluation.Accuracy)
+}
+
+func TestReadExamples(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, data string) string {
+		filename := path.Join(dir, name)
+		require.NoError(t, ioutil.WriteFile(filename, []byte(data), 0644))
+		return filename
+	}
+	encode := func(text string) ([]int, error) {
+		ids := make([]int, 0)
+		for _, field := range strings.Fields(text) {
+			id, err := strconv.Atoi(field)
+			if err != nil {
+				return nil, err
+			}
+			ids = append(ids, id)
+		}
+		return ids, nil
+	}
+	c := newTestConfig("BartForSequenceClassification")
+
+	examples, err := ReadSeq2SeqExamples(write("src.txt", "3 4\n5\n"), write("tgt.txt", "6\n7 7\n\n"), encode, c)
+	require.NoError(t, err)
+	assert.Equal(t, []Example{
+		{InputIDs: []int{3, 4, 2}, TargetIDs: []int{6, 2}},
+		{InputIDs: []int{5, 2}, TargetIDs: []int{7, 7, 2}},
+	}, examples)
+
+	_, err = ReadSeq2SeqExamples(write("src.txt", "3 4\n5\n"), write("tgt.txt", "6\n"), encode, c)
+	assert.Error(t, err)
+
+	examples, err = ReadClassificationExamples(write("nli.tsv", "3 4\t5\tentailment\n"), encode, c)
+	require.NoError(t, err)
+	assert.Equal(t, []Example{{InputIDs: []int{0, 3, 4, 2, 2, 5, 2}, Label: 2}}, examples)
+
+	_, err = ReadClassificationExamples(write("nli.tsv", "3\t5\tunknown\n"), encode, c)
+	assert.Error(t, err)
+}
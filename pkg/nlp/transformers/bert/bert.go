@@ -11,10 +11,10 @@ import (
 	"github.com/nlpodyssey/spago/pkg/ml/ag"
 	"github.com/nlpodyssey/spago/pkg/ml/nn"
 	"github.com/nlpodyssey/spago/pkg/ml/nn/linear"
+	"github.com/nlpodyssey/spago/pkg/nlp/tokenizers"
 	"github.com/nlpodyssey/spago/pkg/nlp/vocabulary"
-	"github.com/nlpodyssey/spago/pkg/utils"
+	"io/ioutil"
 	"log"
-	"os"
 	"path"
 	"strconv"
 )
@@ -28,42 +28,94 @@ const (
 	DefaultModelFile = "spago_model.bin"
 	// DefaultEmbeddingsStorage is the default directory name for BERT model's embedding storage.
 	DefaultEmbeddingsStorage = "embeddings_storage"
+	// DefaultBPEVocabularyFile is the default filename of the byte-level BPE vocabulary of RoBERTa.
+	DefaultBPEVocabularyFile = "vocab.json"
+	// DefaultBPEMergesFile is the default filename of the byte-level BPE merges of RoBERTa.
+	DefaultBPEMergesFile = "merges.txt"
+	// DefaultSentencePieceModelFile is the default filename of the sentence-piece model of XLM-RoBERTa.
+	DefaultSentencePieceModelFile = "sentencepiece.bpe.model"
 )
 
 var (
 	_ nn.Model = &Model{}
 )
 
+// Supported model types, besides the original BERT.
+const (
+	ModelTypeELECTRA    = "electra"
+	ModelTypeRoBERTa    = "roberta"
+	ModelTypeXLMRoBERTa = "xlm-roberta"
+	ModelTypeDistilBERT = "distilbert"
+)
+
 // Config provides configuration settings for a BERT Model.
+// The same model also implements ELECTRA, RoBERTa, XLM-RoBERTa and DistilBERT, according to the ModelType.
 type Config struct {
+	ModelType             string            `json:"model_type"`
 	HiddenAct             string            `json:"hidden_act"`
 	HiddenSize            int               `json:"hidden_size"`
 	IntermediateSize      int               `json:"intermediate_size"`
 	MaxPositionEmbeddings int               `json:"max_position_embeddings"`
 	NumAttentionHeads     int               `json:"num_attention_heads"`
 	NumHiddenLayers       int               `json:"num_hidden_layers"`
+	PadTokenID            int               `json:"pad_token_id"`
 	TypeVocabSize         int               `json:"type_vocab_size"`
 	VocabSize             int               `json:"vocab_size"`
 	ID2Label              map[string]string `json:"id2label"`
 	Training              bool              `json:"training"` // Custom for spaGO
 }
 
+// distilBERTConfig contains the DistilBERT settings whose names differ from the BERT ones.
+type distilBERTConfig struct {
+	Activation string `json:"activation"`
+	Dim        int    `json:"dim"`
+	HiddenDim  int    `json:"hidden_dim"`
+	NHeads     int    `json:"n_heads"`
+	NLayers    int    `json:"n_layers"`
+}
+
+// IsRoBERTaLike reports whether the model follows the RoBERTa conventions: the special tokens of the
+// byte-level BPE, the position IDs starting after the padding index, and a double separator between
+// the sequences of a pair.
+func (c Config) IsRoBERTaLike() bool {
+	return c.ModelType == ModelTypeRoBERTa || c.ModelType == ModelTypeXLMRoBERTa
+}
+
+// PositionOffset returns the index of the position embedding of the first token.
+func (c Config) PositionOffset() int {
+	if c.IsRoBERTaLike() {
+		return c.PadTokenID + 1
+	}
+	return 0
+}
+
 func init() {
 	gob.Register(&Model{})
 }
 
 // LoadConfig loads a BERT model Config from file.
+// The settings of a DistilBERT configuration are mapped to the corresponding BERT ones.
 func LoadConfig(file string) (Config, error) {
-	var config Config
-	configFile, err := os.Open(file)
+	data, err := ioutil.ReadFile(file)
 	if err != nil {
 		return Config{}, err
 	}
-	defer configFile.Close()
-	err = json.NewDecoder(configFile).Decode(&config)
-	if err != nil {
+	var config Config
+	if err := json.Unmarshal(data, &config); err != nil {
 		return Config{}, err
 	}
+	if config.ModelType == ModelTypeDistilBERT {
+		var distilConfig distilBERTConfig
+		if err := json.Unmarshal(data, &distilConfig); err != nil {
+			return Config{}, err
+		}
+		config.HiddenAct = distilConfig.Activation
+		config.HiddenSize = distilConfig.Dim
+		config.IntermediateSize = distilConfig.HiddenDim
+		config.NumAttentionHeads = distilConfig.NHeads
+		config.NumHiddenLayers = distilConfig.NLayers
+		config.TypeVocabSize = 0 // no token type embeddings
+	}
 	return config, nil
 }
 
@@ -80,6 +132,8 @@ type Model struct {
 	SeqRelationship *linear.Model
 	SpanClassifier  *SpanClassifier
 	Classifier      *Classifier
+	// Tokenizer splits the texts into the tokens of the vocabulary. If nil, the WordPiece tokenizer is used.
+	Tokenizer tokenizers.Tokenizer `spago:"scope:model"`
 }
 
 // NewDefaultBERT returns a new model based on the original BERT architecture.
@@ -91,7 +145,9 @@ func NewDefaultBERT(config Config, embeddingsStoragePath string) *Model {
 			Size:                config.HiddenSize,
 			OutputSize:          config.HiddenSize,
 			MaxPositions:        config.MaxPositionEmbeddings,
+			PositionOffset:      config.PositionOffset(),
 			TokenTypes:          config.TypeVocabSize,
+			UnknownToken:        config.SpecialTokens().Unknown,
 			WordsMapFilename:    embeddingsStoragePath,
 			WordsMapReadOnly:    !config.Training,
 			DeletePreEmbeddings: false,
@@ -119,6 +175,12 @@ func NewDefaultBERT(config Config, embeddingsStoragePath string) *Model {
 		Pooler: NewPooler(PoolerConfig{
 			InputSize:  config.HiddenSize,
 			OutputSize: config.HiddenSize,
+			Activation: func() ag.OpName {
+				if config.ModelType == ModelTypeDistilBERT {
+					return ag.OpReLU // the "pre-classifier" of DistilBERT
+				}
+				return ag.OpTanh
+			}(),
 		}),
 		SeqRelationship: linear.New(config.HiddenSize, 2),
 		SpanClassifier: NewSpanClassifier(SpanClassifierConfig{
@@ -147,29 +209,35 @@ func NewDefaultBERT(config Config, embeddingsStoragePath string) *Model {
 // LoadModel loads a BERT Model from file.
 func LoadModel(modelPath string) (*Model, error) {
 	configFilename := path.Join(modelPath, DefaultConfigurationFile)
-	vocabFilename := path.Join(modelPath, DefaultVocabularyFile)
 	embeddingsFilename := path.Join(modelPath, DefaultEmbeddingsStorage)
 	modelFilename := path.Join(modelPath, DefaultModelFile)
 
 	log.Printf("Start loading pre-trained model from \"%s\"\n", modelPath)
-	log.Printf("[1/4] Load configuration... ")
+	log.Printf("[1/5] Load configuration... ")
 	config, err := LoadConfig(configFilename)
 	if err != nil {
 		return nil, err
 	}
 
-	log.Printf("[2/4] Instantiate a new model... ")
+	log.Printf("[2/5] Instantiate a new model... ")
 	model := NewDefaultBERT(config, embeddingsFilename)
 
-	log.Printf("[3/4] Load vocabulary... ")
-	vocab, err := vocabulary.NewFromFile(vocabFilename)
+	log.Printf("[3/5] Load vocabulary... ")
+	vocab, err := LoadVocabulary(modelPath, config)
 	if err != nil {
 		return nil, err
 	}
 	model.Vocabulary = vocab
 
-	log.Printf("[4/4] Load model weights... ")
-	err = utils.DeserializeFromFile(modelFilename, model)
+	log.Printf("[4/5] Load tokenizer... ")
+	tokenizer, err := NewTokenizer(modelPath, config, vocab)
+	if err != nil {
+		return nil, err
+	}
+	model.Tokenizer = tokenizer
+
+	log.Printf("[5/5] Load model weights... ")
+	err = nn.LoadFromFile(modelFilename, model)
 	if err != nil {
 		return nil, fmt.Errorf("bert: error during model deserialization (%s)", err.Error())
 	}
@@ -5,7 +5,9 @@
 package bert
 
 import (
+	"fmt"
 	matsort "github.com/nlpodyssey/spago/pkg/mat32/sort"
+	"github.com/nlpodyssey/spago/pkg/ml/stats"
 	"runtime"
 	"sort"
 	"strings"
@@ -15,7 +17,6 @@ import (
 	"github.com/nlpodyssey/spago/pkg/ml/ag"
 	"github.com/nlpodyssey/spago/pkg/ml/nn"
 	"github.com/nlpodyssey/spago/pkg/nlp/tokenizers"
-	"github.com/nlpodyssey/spago/pkg/nlp/tokenizers/wordpiecetokenizer"
 )
 
 const defaultMaxAnswerLength = 20     // TODO: from options
@@ -56,13 +57,30 @@ func (p Answers) Sort() {
 	sort.Sort(p)
 }
 
+// EvaluateAnswers returns the SQuAD exact match and F1 score of the answers to a set of questions (see
+// Model.Answer) against their ground truths. The first answer of each question is the predicted one; no answers
+// mean the empty prediction.
+func EvaluateAnswers(answers []Answers, groundTruths [][]string) (*stats.SQuADEvaluator, error) {
+	if len(answers) != len(groundTruths) {
+		return nil, fmt.Errorf("bert: %d answers and %d ground truths", len(answers), len(groundTruths))
+	}
+	evaluator := stats.NewSQuADEvaluator()
+	for i, candidates := range answers {
+		prediction := ""
+		if len(candidates) > 0 {
+			prediction = candidates[0].Text
+		}
+		evaluator.Add(prediction, groundTruths[i]...)
+	}
+	return evaluator, nil
+}
+
 // Answer returns a slice of candidate answers for the given question-passage pair.
 // The answers are sorted by confidence level in descending order.
 func (m *Model) Answer(question string, passage string) Answers {
-	tokenizer := wordpiecetokenizer.New(m.Vocabulary)
-	questionTokens := tokenizer.Tokenize(question)
-	passageTokens := tokenizer.Tokenize(passage)
-	tokenized := mixQuestionAndPassageTokens(questionTokens, passageTokens)
+	questionTokens := m.Tokenize(question)
+	passageTokens := m.Tokenize(passage)
+	tokenized := m.padPair(tokenizers.GetStrings(questionTokens), tokenizers.GetStrings(passageTokens))
 
 	g := ag.NewGraph(ag.ConcurrentComputations(runtime.NumCPU()))
 	defer g.Clear()
@@ -71,7 +89,7 @@ func (m *Model) Answer(question string, passage string) Answers {
 	encoded := proc.Encode(tokenized)
 
 	startLogits, endLogits := proc.SpanClassifier.Classify(encoded)
-	startLogits, endLogits = adjustLogitsForInference(startLogits, endLogits, questionTokens, passageTokens)
+	startLogits, endLogits = adjustLogitsForInference(startLogits, endLogits, passageTokens)
 
 	startIndices := getBestIndices(extractScores(startLogits), defaultMaxCandidateLogits)
 	endIndices := getBestIndices(extractScores(endLogits), defaultMaxCandidateLogits)
@@ -96,20 +114,12 @@ func (m *Model) Answer(question string, passage string) Answers {
 	return answers
 }
 
-func mixQuestionAndPassageTokens(question, passage []tokenizers.StringOffsetsPair) []string {
-	cls := wordpiecetokenizer.DefaultClassToken
-	sep := wordpiecetokenizer.DefaultSequenceSeparator
-	tokenized := append([]string{cls}, append(tokenizers.GetStrings(question), sep)...)
-	tokenized = append(tokenized, append(tokenizers.GetStrings(passage), sep)...)
-	return tokenized
-}
-
 func adjustLogitsForInference(
 	startLogits, endLogits []ag.Node,
-	question, passage []tokenizers.StringOffsetsPair,
+	passage []tokenizers.StringOffsetsPair,
 ) ([]ag.Node, []ag.Node) {
-	passageStartIndex := len(question) + 2 // +2 because of [CLS] and [SEP]
-	passageEndIndex := passageStartIndex + len(passage)
+	passageEndIndex := len(startLogits) - 1 // -1 because of the final separator
+	passageStartIndex := passageEndIndex - len(passage)
 	return startLogits[passageStartIndex:passageEndIndex], endLogits[passageStartIndex:passageEndIndex] // cut invalid positions
 }
 
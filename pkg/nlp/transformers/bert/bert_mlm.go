@@ -9,7 +9,6 @@ import (
 	"github.com/nlpodyssey/spago/pkg/ml/ag"
 	"github.com/nlpodyssey/spago/pkg/ml/nn"
 	"github.com/nlpodyssey/spago/pkg/nlp/tokenizers"
-	"github.com/nlpodyssey/spago/pkg/nlp/tokenizers/wordpiecetokenizer"
 	"runtime"
 	"sort"
 )
@@ -17,9 +16,9 @@ import (
 // PredictMLM performs the Masked-Language-Model (MLM) prediction.
 // It returns the best guess for the masked (i.e. `[MASK]`) tokens in the input text.
 func (m *Model) PredictMLM(text string) []Token {
-	tokenizer := wordpiecetokenizer.New(m.Vocabulary)
-	origTokens := tokenizer.Tokenize(text)
-	tokenized := pad(tokenizers.GetStrings(origTokens))
+	origTokens := m.Tokenize(text)
+	tokenized := m.pad(tokenizers.GetStrings(origTokens))
+	special := m.Config.SpecialTokens()
 
 	g := ag.NewGraph(ag.ConcurrentComputations(runtime.NumCPU()))
 	defer g.Clear()
@@ -28,7 +27,7 @@ func (m *Model) PredictMLM(text string) []Token {
 
 	masked := make([]int, 0)
 	for i := range tokenized {
-		if tokenized[i] == wordpiecetokenizer.DefaultMaskToken {
+		if tokenized[i] == special.Mask {
 			masked = append(masked, i) // target tokens
 		}
 	}
@@ -38,7 +37,7 @@ func (m *Model) PredictMLM(text string) []Token {
 		bestPredictedWordIndex := floatutils.ArgMax(prediction.Value().Data())
 		word, ok := m.Vocabulary.Term(bestPredictedWordIndex)
 		if !ok {
-			word = wordpiecetokenizer.DefaultUnknownToken // if this is returned, there's a misalignment with the vocabulary
+			word = special.Unknown // if this is returned, there's a misalignment with the vocabulary
 		}
 		label := DefaultPredictedLabel
 		retTokens = append(retTokens, Token{
@@ -14,7 +14,6 @@ import (
 	"github.com/nlpodyssey/spago/pkg/ml/nn/normalization/layernorm"
 	"github.com/nlpodyssey/spago/pkg/nlp/embeddings"
 	"github.com/nlpodyssey/spago/pkg/nlp/vocabulary"
-	"github.com/nlpodyssey/spago/pkg/utils"
 	"github.com/nlpodyssey/spago/pkg/utils/gopickleutils"
 	"log"
 	"os"
@@ -27,17 +26,26 @@ import (
 const defaultHuggingFaceModelFile = "pytorch_model.bin"
 const huggingFaceEmoji = "🤗"
 
+// ConverterOption allows to adapt the conversion to your specific needs.
+type ConverterOption func(*huggingFacePreTrainedConverter)
+
+// ParamsPrecision sets the floating-point format used to store the params and the
+// word embeddings of the converted model (default mat.FullPrecision).
+func ParamsPrecision(precision mat.Precision) ConverterOption {
+	return func(c *huggingFacePreTrainedConverter) {
+		c.precision = precision
+	}
+}
+
 // ConvertHuggingFacePreTrained converts a HuggingFace pre-trained BERT
 // transformer model to a corresponding spaGO model.
-func ConvertHuggingFacePreTrained(modelPath string) error {
+// It also converts the ELECTRA, RoBERTa, XLM-RoBERTa and DistilBERT models, according to the model type of the
+// configuration, and the sentence embedding head of the sentence-transformers checkpoints (see SentenceTransformer).
+func ConvertHuggingFacePreTrained(modelPath string, opts ...ConverterOption) error {
 	configFilename, err := exists(path.Join(modelPath, DefaultConfigurationFile))
 	if err != nil {
 		return err
 	}
-	vocabFilename, err := exists(path.Join(modelPath, DefaultVocabularyFile))
-	if err != nil {
-		return err
-	}
 	pyTorchModelFilename, err := exists(path.Join(modelPath, defaultHuggingFaceModelFile))
 	if err != nil {
 		return err
@@ -49,7 +57,7 @@ func ConvertHuggingFacePreTrained(modelPath string) error {
 	// Enable training mode, so that we have writing permissions
 	// (for example, for embeddings storage files).
 	config.Training = true
-	vocab, err := vocabulary.NewFromFile(vocabFilename)
+	vocab, err := LoadVocabulary(modelPath, config)
 	if err != nil {
 		return err
 	}
@@ -61,15 +69,22 @@ func ConvertHuggingFacePreTrained(modelPath string) error {
 		modelPath:            modelPath,
 		configFilename:       configFilename,
 		pyTorchModelFilename: pyTorchModelFilename,
-		vocabFilename:        vocabFilename,
 		modelFilename:        path.Join(modelPath, DefaultModelFile),
 		model:                model,
 		modelMapping:         make(map[string]*mappedParam), // lazy initialization
+		precision:            mat.FullPrecision,
 	}
+	for _, opt := range opts {
+		opt(handler)
+	}
+	model.Embeddings.Words.Precision = handler.precision
 	err = handler.convert()
 	if err != nil {
 		return err
 	}
+	if _, err := os.Stat(path.Join(modelPath, DefaultSentenceTransformerModulesFile)); err == nil {
+		return convertSentenceTransformer(modelPath, model, handler.precision)
+	}
 	return nil
 }
 
@@ -78,10 +93,10 @@ type huggingFacePreTrainedConverter struct {
 	modelPath            string
 	configFilename       string
 	pyTorchModelFilename string
-	vocabFilename        string
 	modelFilename        string
 	model                *Model
 	modelMapping         map[string]*mappedParam
+	precision            mat.Precision
 }
 
 type mappedParam struct {
@@ -132,6 +147,11 @@ func (c *huggingFacePreTrainedConverter) convert() error {
 		}
 	}
 
+	if c.precision != mat.FullPrecision {
+		log.Printf("Convert the params to %s precision...", c.precision)
+		nn.SetPrecision(c.model, c.precision)
+	}
+
 	fmt.Printf("Serializing model to \"%s\"... ", c.modelFilename)
 	if err := c.serializeModel(); err != nil {
 		return err
@@ -141,7 +161,7 @@ func (c *huggingFacePreTrainedConverter) convert() error {
 }
 
 func (c *huggingFacePreTrainedConverter) serializeModel() error {
-	err := utils.SerializeToFile(c.modelFilename, c.model)
+	err := nn.SaveModelFile(c.modelFilename, c.model, c.model.Config)
 	if err != nil {
 		return fmt.Errorf("bert: error during model serialization: %w", err)
 	}
@@ -197,13 +217,54 @@ func (c *huggingFacePreTrainedConverter) enrichHuggingFaceParams(paramsMap map[s
 	}
 }
 
+// distilBERTParamNames maps the names of the DistilBERT params to the corresponding BERT ones.
+var distilBERTParamNames = strings.NewReplacer(
+	".attention.q_lin.", ".attention.self.query.",
+	".attention.k_lin.", ".attention.self.key.",
+	".attention.v_lin.", ".attention.self.value.",
+	".attention.out_lin.", ".attention.output.dense.",
+	".sa_layer_norm.", ".attention.output.LayerNorm.",
+	".ffn.lin1.", ".intermediate.dense.",
+	".ffn.lin2.", ".output.dense.",
+	".output_layer_norm.", ".output.LayerNorm.",
+)
+
+// headParamNames maps the names of the params of the RoBERTa and DistilBERT heads to the corresponding BERT ones.
+// The dense layer of the classification head is mapped to the pooler, whose activation depends on the model type.
+var headParamNames = map[string]string{
+	"lm_head.dense.":                "cls.predictions.transform.dense.",
+	"lm_head.layer_norm.":           "cls.predictions.transform.LayerNorm.",
+	"lm_head.decoder.":              "cls.predictions.decoder.",
+	"lm_head.bias":                  "cls.predictions.decoder.bias",
+	"vocab_transform.":              "cls.predictions.transform.dense.",
+	"vocab_layer_norm.":             "cls.predictions.transform.LayerNorm.",
+	"vocab_projector.":              "cls.predictions.decoder.",
+	"classifier.dense.":             "bert.pooler.dense.",
+	"classifier.out_proj.":          "classifier.",
+	"pre_classifier.":               "bert.pooler.dense.",
+	"distilbert.transformer.layer.": "bert.encoder.layer.",
+	"transformer.layer.":            "bert.encoder.layer.", // DistilBERT without prefix (e.g. sentence-transformers)
+}
+
 // normalizeParamName applies the following transformation:
-//    electra -> bert
+//    electra, roberta, distilbert -> bert
 //    gamma -> weight
 //    beta -> bias
+// The names of the DistilBERT layers and of the RoBERTa and DistilBERT heads are mapped to the BERT ones.
 func normalizeParamName(orig string) (normalized string) {
 	normalized = orig
+	for prefix, replacement := range headParamNames {
+		if strings.HasPrefix(normalized, prefix) {
+			normalized = replacement + strings.TrimPrefix(normalized, prefix)
+			break
+		}
+	}
+	if strings.HasPrefix(orig, "distilbert.transformer.") || strings.HasPrefix(orig, "transformer.layer.") {
+		normalized = distilBERTParamNames.Replace(normalized)
+	}
 	normalized = strings.Replace(normalized, "electra.", "bert.", -1)
+	normalized = strings.Replace(normalized, "roberta.", "bert.", -1)
+	normalized = strings.Replace(normalized, "distilbert.", "bert.", -1)
 	normalized = strings.Replace(normalized, ".gamma", ".weight", -1)
 	normalized = strings.Replace(normalized, ".beta", ".bias", -1)
 	if strings.HasPrefix(normalized, "embeddings.") {
@@ -241,7 +302,7 @@ func (c *huggingFacePreTrainedConverter) convertEmbeddings(pyTorchParams map[str
 
 func assignToParamsList(source []mat.Float, dest []nn.Param, rows, cols int) {
 	for i := 0; i < rows; i++ {
-		dest[i].Value().SetData(source[i*cols : (i+1)*cols])
+		dest[i].SetData(source[i*cols : (i+1)*cols])
 	}
 }
 
@@ -0,0 +1,38 @@
+// Copyright 2021 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package bert
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestNormalizeParamName(t *testing.T) {
+	cases := map[string]string{
+		"bert.encoder.layer.0.attention.self.query.weight":      "bert.encoder.layer.0.attention.self.query.weight",
+		"electra.embeddings.LayerNorm.gamma":                    "bert.embeddings.LayerNorm.weight",
+		"encoder.layer.1.output.dense.bias":                     "bert.encoder.layer.1.output.dense.bias",
+		"roberta.embeddings.position_embeddings.weight":         "bert.embeddings.position_embeddings.weight",
+		"roberta.encoder.layer.2.intermediate.dense.weight":     "bert.encoder.layer.2.intermediate.dense.weight",
+		"lm_head.layer_norm.weight":                             "cls.predictions.transform.LayerNorm.weight",
+		"lm_head.bias":                                          "cls.predictions.decoder.bias",
+		"classifier.dense.weight":                               "bert.pooler.dense.weight",
+		"classifier.out_proj.bias":                              "classifier.bias",
+		"classifier.weight":                                     "classifier.weight",
+		"distilbert.embeddings.word_embeddings.weight":          "bert.embeddings.word_embeddings.weight",
+		"distilbert.transformer.layer.3.attention.q_lin.weight": "bert.encoder.layer.3.attention.self.query.weight",
+		"distilbert.transformer.layer.3.attention.out_lin.bias": "bert.encoder.layer.3.attention.output.dense.bias",
+		"distilbert.transformer.layer.3.sa_layer_norm.weight":   "bert.encoder.layer.3.attention.output.LayerNorm.weight",
+		"distilbert.transformer.layer.3.ffn.lin1.weight":        "bert.encoder.layer.3.intermediate.dense.weight",
+		"distilbert.transformer.layer.3.ffn.lin2.bias":          "bert.encoder.layer.3.output.dense.bias",
+		"distilbert.transformer.layer.3.output_layer_norm.bias": "bert.encoder.layer.3.output.LayerNorm.bias",
+		"transformer.layer.0.ffn.lin1.bias":                     "bert.encoder.layer.0.intermediate.dense.bias",
+		"pre_classifier.weight":                                 "bert.pooler.dense.weight",
+		"vocab_projector.bias":                                  "cls.predictions.decoder.bias",
+	}
+	for name, expected := range cases {
+		assert.Equal(t, expected, normalizeParamName(name), name)
+	}
+}
@@ -24,7 +24,9 @@ type EmbeddingsConfig struct {
 	Size                int
 	OutputSize          int
 	MaxPositions        int
-	TokenTypes          int
+	PositionOffset      int // index of the position embedding of the first token (e.g. 2 in RoBERTa)
+	TokenTypes          int // zero means no token type embeddings (e.g. DistilBERT)
+	UnknownToken        string
 	WordsMapFilename    string
 	WordsMapReadOnly    bool
 	DeletePreEmbeddings bool
@@ -65,7 +67,11 @@ func NewEmbeddings(config EmbeddingsConfig) *Embeddings {
 
 // InitProcessor initializes the unknown embeddings.
 func (m *Embeddings) InitProcessor() {
-	m.UnknownEmbedding = m.Graph().NewWrap(m.Words.GetStoredEmbedding(wordpiecetokenizer.DefaultUnknownToken))
+	unknownToken := m.UnknownToken
+	if unknownToken == "" {
+		unknownToken = wordpiecetokenizer.DefaultUnknownToken
+	}
+	m.UnknownEmbedding = m.Graph().NewWrap(m.Words.GetStoredEmbedding(unknownToken))
 }
 
 func newPositionEmbeddings(size, maxPositions int) []nn.Param {
@@ -98,9 +104,12 @@ func (m *Embeddings) Encode(words []string) []ag.Node {
 	sequenceIndex := 0
 	for i := 0; i < len(words); i++ {
 		encoded[i] = wordEmbeddings[i]
-		encoded[i] = m.Graph().Add(encoded[i], m.Graph().NewWrap(m.Position[i]))
+		encoded[i] = m.Graph().Add(encoded[i], m.Graph().NewWrap(m.Position[i+m.PositionOffset]))
+		if len(m.TokenType) == 0 {
+			continue
+		}
 		encoded[i] = m.Graph().Add(encoded[i], m.TokenType[sequenceIndex])
-		if words[i] == wordpiecetokenizer.DefaultSequenceSeparator {
+		if words[i] == wordpiecetokenizer.DefaultSequenceSeparator && sequenceIndex+1 < len(m.TokenType) {
 			sequenceIndex++
 		}
 	}
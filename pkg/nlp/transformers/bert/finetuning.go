@@ -0,0 +1,547 @@
+// Copyright 2021 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package bert
+
+import (
+	"encoding/json"
+	"fmt"
+	mat "github.com/nlpodyssey/spago/pkg/mat32"
+	"github.com/nlpodyssey/spago/pkg/mat32/floatutils"
+	"github.com/nlpodyssey/spago/pkg/mat32/rand"
+	"github.com/nlpodyssey/spago/pkg/ml/ag"
+	"github.com/nlpodyssey/spago/pkg/ml/initializers"
+	"github.com/nlpodyssey/spago/pkg/ml/losses"
+	"github.com/nlpodyssey/spago/pkg/ml/nn"
+	"github.com/nlpodyssey/spago/pkg/ml/optimizers/gd"
+	"github.com/nlpodyssey/spago/pkg/ml/optimizers/gd/gdmbuilder"
+	"github.com/nlpodyssey/spago/pkg/ml/stats"
+	"github.com/nlpodyssey/spago/pkg/ml/training"
+	"github.com/nlpodyssey/spago/pkg/nlp/tokenizers"
+	"github.com/nlpodyssey/spago/pkg/nlp/tokenizers/wordpiecetokenizer"
+	"io/ioutil"
+	"os"
+	"path"
+	"reflect"
+	"runtime"
+	"strconv"
+	"unicode/utf8"
+)
+
+// FineTuningTask is the supervised task of a FineTuner.
+type FineTuningTask string
+
+// Supported fine-tuning tasks.
+const (
+	// SequenceClassificationTask trains the Classifier on the pooled encoding of a text, or of a pair of texts,
+	// as used by the classification endpoint of the Server.
+	SequenceClassificationTask FineTuningTask = "sequence-classification"
+	// TokenClassificationTask trains the Classifier on the average encoding of the pieces of each word,
+	// as used by the labeler endpoint of the Server.
+	TokenClassificationTask FineTuningTask = "token-classification"
+	// QuestionAnsweringTask trains the SpanClassifier to find the start and the end of the answer in a passage,
+	// as used by the answer endpoint of the Server.
+	QuestionAnsweringTask FineTuningTask = "question-answering"
+)
+
+// answerMetricsKey is the key of the metrics of the question-answering Evaluation.
+const answerMetricsKey = "answer"
+
+// Names of the metrics of the evaluation on the development set, during the training.
+const (
+	devLossMetric = "Dev loss"
+	scoreMetric   = "Score"
+)
+
+// FineTuningConfig provides configuration settings for a BERT FineTuner.
+type FineTuningConfig struct {
+	Task             FineTuningTask
+	Seed             uint64
+	BatchSize        int
+	Epochs           int
+	GradientClipping mat.Float
+	UpdateMethod     gd.MethodConfig
+	// Patience is the number of epochs without improvement of the score on the development set after
+	// which the training stops. Zero means no early stopping.
+	Patience int
+	// MaxSequenceLength is the maximum number of tokens of an input, including the special tokens.
+	// Zero means the maximum number of positions of the model. The longer texts of the sequence classification
+	// are truncated, the other longer examples are skipped.
+	MaxSequenceLength int
+	// ModelPath is the directory of the model, where the best model is saved, replacing the model file and
+	// updating the labels of the configuration file.
+	ModelPath string
+}
+
+// Evaluation contains the results of the evaluation of a FineTuner on a set of examples.
+type Evaluation struct {
+	// Loss is the average loss of the examples.
+	Loss mat.Float
+	// Metrics contains the metrics of each label. The question-answering has a single "answer" entry, where
+	// an exact match of the answer span is a true positive, and a mismatch is both a false positive and
+	// a false negative.
+	Metrics map[string]*stats.ClassMetrics
+	// Score is the macro-averaged F1 score of the labels, or the exact match ratio of the answers.
+	Score mat.Float
+	// Skipped is the number of examples which have not been evaluated (e.g. because too long).
+	Skipped int
+}
+
+// FineTuner implements the supervised fine-tuning of a BERT Model for sequence classification, token
+// classification or question-answering, with the evaluation on a development set after each epoch,
+// early stopping, and the saving of the best model.
+//
+// The word embeddings are trained only if the model has been loaded with Config.Training set; their storage
+// is updated in place, regardless of the best epoch.
+type FineTuner struct {
+	FineTuningConfig
+	randGen   *rand.LockedRand
+	optimizer *gd.GradientDescent
+	model     *Model
+	labels    map[string]int
+}
+
+// fineTuningInput is an Example converted to the input tokens of the model and to the expected outputs.
+type fineTuningInput struct {
+	tokens []string
+	// targets are the indices of the labels (the label of the text, or of each word), or the indices of
+	// the start and end tokens of the answer.
+	targets []int
+	// words are the ranges of the tokens of each word, for the token classification.
+	words []wordpiecetokenizer.TokensRange
+	// passageStart and passageEnd are the range of the tokens of the passage (end excluded),
+	// for the question-answering.
+	passageStart, passageEnd int
+}
+
+// NewFineTuner returns a new FineTuner. For the classification tasks, the Classifier of the model is replaced
+// with a new randomly initialized one, unless its labels are already the given ones, and the labels are set
+// in the configuration of the model.
+func NewFineTuner(model *Model, config FineTuningConfig, labels []string) *FineTuner {
+	randGen := rand.NewLockedRand(config.Seed)
+	labelsIndex := make(map[string]int, len(labels))
+	if config.Task != QuestionAnsweringTask {
+		if !reflect.DeepEqual(model.Classifier.Config.Labels, labels) {
+			model.Classifier = NewTokenClassifier(ClassifierConfig{
+				InputSize: model.Config.HiddenSize,
+				Labels:    labels,
+			})
+			initializers.XavierUniform(model.Classifier.W.Value(), 1.0, randGen)
+		}
+		model.Config.ID2Label = make(map[string]string, len(labels))
+		for i, label := range labels {
+			model.Config.ID2Label[strconv.Itoa(i)] = label
+			labelsIndex[label] = i
+		}
+	}
+	optimizer := gd.NewOptimizer(gdmbuilder.NewMethod(config.UpdateMethod), nn.NewDefaultParamsIterator(model))
+	if config.GradientClipping != 0.0 {
+		gd.ClipGradByNorm(config.GradientClipping, 2.0)(optimizer)
+	}
+	if config.BatchSize <= 0 {
+		config.BatchSize = 1
+	}
+	return &FineTuner{
+		FineTuningConfig: config,
+		randGen:          randGen,
+		optimizer:        optimizer,
+		model:            model,
+		labels:           labelsIndex,
+	}
+}
+
+// Train fine-tunes the model on the training examples. After each epoch, the model is evaluated on the
+// development examples, and saved if its score is the best so far; without development examples, the model
+// is saved after each epoch. It returns an error if an example has an unknown label, or the model can't
+// be saved.
+func (t *FineTuner) Train(trainSet, devSet []Example) error {
+	inputs, err := t.convertExamples(trainSet)
+	if err != nil {
+		return err
+	}
+	trainer := training.New(
+		training.Config{Epochs: t.Epochs, BatchSize: t.BatchSize, Shuffle: true, Seed: t.Seed},
+		t.optimizer,
+		t.learn,
+		t.callbacks(devSet)...,
+	)
+	return trainer.Train(training.NewExamples(inputs))
+}
+
+// callbacks returns the callbacks of the training: the evaluation on the development examples, if any,
+// the logging, the early stopping and the saving of the best model.
+func (t *FineTuner) callbacks(devSet []Example) []training.Callback {
+	save := &training.Checkpoint{Save: func(*training.State) error { return t.SaveModel() }}
+	if len(devSet) == 0 {
+		return []training.Callback{&training.Logger{}, save}
+	}
+	evaluation := &training.Evaluation{
+		Evaluate: func() (training.Metrics, error) {
+			evaluation, err := t.Evaluate(devSet)
+			if err != nil {
+				return nil, err
+			}
+			return training.Metrics{devLossMetric: evaluation.Loss, scoreMetric: evaluation.Score}, nil
+		},
+	}
+	save.Monitor = &training.Monitor{Metric: scoreMetric, Mode: training.Maximize}
+	callbacks := []training.Callback{evaluation, &training.Logger{}, save}
+	if t.Patience > 0 {
+		callbacks = append(callbacks, &training.EarlyStopping{
+			Monitor:  training.Monitor{Metric: scoreMetric, Mode: training.Maximize},
+			Patience: t.Patience,
+		})
+	}
+	return callbacks
+}
+
+// learn accumulates the gradients of the losses of the batch of inputs, averaged and multiplied by the
+// gradient scale, and returns the average loss.
+func (t *FineTuner) learn(batch []interface{}, gradScale mat.Float) mat.Float {
+	var totalLoss mat.Float
+	for _, input := range batch {
+		totalLoss += t.learnInput(input.(fineTuningInput), gradScale/mat.Float(len(batch)))
+	}
+	return totalLoss / mat.Float(len(batch))
+}
+
+// learnInput accumulates the gradients of the loss of the input, multiplied by the gradient scale, and
+// returns the loss.
+func (t *FineTuner) learnInput(input fineTuningInput, gradScale mat.Float) mat.Float {
+	g := ag.NewGraph(ag.Rand(t.randGen), ag.ConcurrentComputations(runtime.NumCPU()))
+	defer g.Clear()
+	proc := nn.Reify(nn.Context{Graph: g, Mode: nn.Training}, t.model).(*Model)
+	loss, _ := t.forward(proc, input)
+	g.Backward(g.ProdScalar(loss, g.NewScalar(gradScale)))
+	return loss.ScalarValue()
+}
+
+// Evaluate returns the Evaluation of the model on the examples. It returns an error if an example has
+// an unknown label.
+func (t *FineTuner) Evaluate(examples []Example) (Evaluation, error) {
+	inputs, err := t.convertExamples(examples)
+	if err != nil {
+		return Evaluation{}, err
+	}
+	evaluation := Evaluation{
+		Metrics: make(map[string]*stats.ClassMetrics),
+		Skipped: len(examples) - len(inputs),
+	}
+	if t.Task == QuestionAnsweringTask {
+		evaluation.Metrics[answerMetricsKey] = stats.NewMetricCounter()
+	} else {
+		for label := range t.labels {
+			evaluation.Metrics[label] = stats.NewMetricCounter()
+		}
+	}
+
+	for _, input := range inputs {
+		loss, predicted := t.predict(input)
+		evaluation.Loss += loss
+		t.updateMetrics(evaluation.Metrics, input.targets, predicted)
+	}
+	if len(inputs) > 0 {
+		evaluation.Loss /= mat.Float(len(inputs))
+	}
+	evaluation.Score = t.score(evaluation.Metrics)
+	return evaluation, nil
+}
+
+// predict returns the loss and the predictions of the model for the input, in inference mode.
+func (t *FineTuner) predict(input fineTuningInput) (mat.Float, []int) {
+	g := ag.NewGraph(ag.ConcurrentComputations(runtime.NumCPU()))
+	defer g.Clear()
+	proc := nn.Reify(nn.Context{Graph: g, Mode: nn.Inference}, t.model).(*Model)
+	loss, predicted := t.forward(proc, input)
+	return loss.ScalarValue(), predicted
+}
+
+// forward returns the loss of the input and the predictions, with the same form of the targets.
+func (t *FineTuner) forward(proc *Model, input fineTuningInput) (ag.Node, []int) {
+	g := proc.Graph()
+	encoded := proc.Encode(input.tokens)
+	switch t.Task {
+	case SequenceClassificationTask:
+		logits := proc.SequenceClassification(encoded)
+		predicted := []int{floatutils.ArgMax(logits.Value().Data())}
+		return losses.CrossEntropy(g, logits, input.targets[0]), predicted
+	case TokenClassificationTask:
+		var loss ag.Node
+		predicted := make([]int, len(input.words))
+		for i, logits := range proc.TokenClassification(averageWords(g, encoded, input.words)) {
+			loss = g.Add(loss, losses.CrossEntropy(g, logits, input.targets[i]))
+			predicted[i] = floatutils.ArgMax(logits.Value().Data())
+		}
+		return g.DivScalar(loss, g.NewScalar(mat.Float(len(input.words)))), predicted
+	case QuestionAnsweringTask:
+		startLogits, endLogits := proc.SpanClassifier.Classify(encoded)
+		loss := g.Add(
+			losses.CrossEntropy(g, g.Concat(startLogits...), input.targets[0]),
+			losses.CrossEntropy(g, g.Concat(endLogits...), input.targets[1]),
+		)
+		start, end := bestSpan(
+			extractScores(startLogits[input.passageStart:input.passageEnd]),
+			extractScores(endLogits[input.passageStart:input.passageEnd]),
+		)
+		predicted := []int{input.passageStart + start, input.passageStart + end}
+		return g.DivScalar(loss, g.NewScalar(2.0)), predicted
+	default:
+		panic(fmt.Sprintf("bert: unknown fine-tuning task `%s`", t.Task))
+	}
+}
+
+// averageWords returns, for each word, the average of the encodings of its tokens.
+func averageWords(g *ag.Graph, encoded []ag.Node, words []wordpiecetokenizer.TokensRange) []ag.Node {
+	out := make([]ag.Node, len(words))
+	for i, word := range words {
+		for j := word.Start; j <= word.End; j++ {
+			out[i] = g.Add(out[i], encoded[j])
+		}
+		if size := word.End - word.Start + 1; size > 1 {
+			out[i] = g.DivScalar(out[i], g.NewScalar(mat.Float(size)))
+		}
+	}
+	return out
+}
+
+// bestSpan returns the start and the end of the span with the highest sum of the logits, among the spans
+// not longer than the maximum length of an answer.
+func bestSpan(startLogits, endLogits []mat.Float) (start, end int) {
+	best := mat.Inf(-1)
+	for i, startLogit := range startLogits {
+		for j := i; j < len(endLogits) && j-i+1 <= defaultMaxAnswerLength; j++ {
+			if score := startLogit + endLogits[j]; score > best {
+				best, start, end = score, i, j
+			}
+		}
+	}
+	return
+}
+
+// updateMetrics updates the metrics with the comparison of the predictions against the targets.
+func (t *FineTuner) updateMetrics(metrics map[string]*stats.ClassMetrics, targets, predicted []int) {
+	if t.Task == QuestionAnsweringTask {
+		if targets[0] == predicted[0] && targets[1] == predicted[1] {
+			metrics[answerMetricsKey].IncTruePos()
+		} else {
+			metrics[answerMetricsKey].IncFalsePos()
+			metrics[answerMetricsKey].IncFalseNeg()
+		}
+		return
+	}
+	labels := t.model.Classifier.Config.Labels
+	for i, target := range targets {
+		for j, label := range labels {
+			switch {
+			case j == target && j == predicted[i]:
+				metrics[label].IncTruePos()
+			case j == target:
+				metrics[label].IncFalseNeg()
+			case j == predicted[i]:
+				metrics[label].IncFalsePos()
+			default:
+				metrics[label].IncTrueNeg()
+			}
+		}
+	}
+}
+
+// score returns the exact match ratio of the answers, or the macro-averaged F1 score of the labels which
+// are either expected or predicted at least once.
+func (t *FineTuner) score(metrics map[string]*stats.ClassMetrics) mat.Float {
+	if t.Task == QuestionAnsweringTask {
+		return metrics[answerMetricsKey].Precision()
+	}
+	var sum mat.Float
+	count := 0
+	for _, m := range metrics {
+		if m.ExpectedPos() == 0 && m.FalsePos == 0 {
+			continue
+		}
+		sum += m.F1Score()
+		count++
+	}
+	if count == 0 {
+		return 0
+	}
+	return sum / mat.Float(count)
+}
+
+// SaveModel saves the model to the model file of the model path, and the labels of the Classifier
+// (`id2label` and `label2id`) to its configuration file, leaving the other settings unchanged.
+func (t *FineTuner) SaveModel() error {
+	if err := nn.SaveModelFile(path.Join(t.ModelPath, DefaultModelFile), t.model, t.model.Config); err != nil {
+		return fmt.Errorf("bert: error during model serialization: %w", err)
+	}
+	if t.Task == QuestionAnsweringTask {
+		return nil
+	}
+	return t.saveLabels(path.Join(t.ModelPath, DefaultConfigurationFile))
+}
+
+// saveLabels sets the labels of the Classifier in the JSON configuration file, keeping all the other settings,
+// even those unknown to Config (e.g. the Hugging Face ones).
+func (t *FineTuner) saveLabels(filename string) error {
+	settings := make(map[string]interface{})
+	data, err := ioutil.ReadFile(filename)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	if err == nil {
+		if err := json.Unmarshal(data, &settings); err != nil {
+			return fmt.Errorf("bert: %s: %w", filename, err)
+		}
+	}
+	label2ID := make(map[string]int, len(t.labels))
+	for label, id := range t.labels {
+		label2ID[label] = id
+	}
+	settings["id2label"] = t.model.Config.ID2Label
+	settings["label2id"] = label2ID
+	data, err = json.MarshalIndent(settings, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filename, data, 0644)
+}
+
+// maxSequenceLength returns the maximum number of tokens of an input.
+func (t *FineTuner) maxSequenceLength() int {
+	maxLength := t.model.Embeddings.MaxPositions - t.model.Embeddings.PositionOffset
+	if t.MaxSequenceLength > 0 && t.MaxSequenceLength < maxLength {
+		return t.MaxSequenceLength
+	}
+	return maxLength
+}
+
+// convertExamples converts the examples to the inputs of the task, skipping those which can't be converted.
+// It returns an error if an example has an unknown label.
+func (t *FineTuner) convertExamples(examples []Example) ([]fineTuningInput, error) {
+	inputs := make([]fineTuningInput, 0, len(examples))
+	for i, example := range examples {
+		var input fineTuningInput
+		var ok bool
+		var err error
+		switch t.Task {
+		case SequenceClassificationTask:
+			input, err = t.convertSequenceExample(example)
+			ok = err == nil
+		case TokenClassificationTask:
+			input, ok, err = t.convertTokensExample(example)
+		case QuestionAnsweringTask:
+			input, ok = t.convertAnswerExample(example)
+		default:
+			return nil, fmt.Errorf("bert: unknown fine-tuning task `%s`", t.Task)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("bert: example %d: %w", i, err)
+		}
+		if ok {
+			inputs = append(inputs, input)
+		}
+	}
+	return inputs, nil
+}
+
+func (t *FineTuner) labelIndex(label string) (int, error) {
+	index, ok := t.labels[label]
+	if !ok {
+		return 0, fmt.Errorf("unknown label `%s`", label)
+	}
+	return index, nil
+}
+
+// convertSequenceExample converts the text, or the pair of texts, truncating the longest text until the
+// tokens fit the maximum length.
+func (t *FineTuner) convertSequenceExample(example Example) (fineTuningInput, error) {
+	target, err := t.labelIndex(example.Label)
+	if err != nil {
+		return fineTuningInput{}, err
+	}
+	first := tokenizers.GetStrings(t.model.Tokenize(example.Text))
+	if example.Text2 == "" {
+		if maxLength := t.maxSequenceLength() - 2; len(first) > maxLength {
+			first = first[:maxLength]
+		}
+		return fineTuningInput{tokens: t.model.pad(first), targets: []int{target}}, nil
+	}
+	second := tokenizers.GetStrings(t.model.Tokenize(example.Text2))
+	maxLength := t.maxSequenceLength() - len(t.model.padPair(nil, nil))
+	for len(first)+len(second) > maxLength {
+		if len(first) > len(second) {
+			first = first[:len(first)-1]
+		} else {
+			second = second[:len(second)-1]
+		}
+	}
+	return fineTuningInput{tokens: t.model.padPair(first, second), targets: []int{target}}, nil
+}
+
+// convertTokensExample converts the words, tokenizing each word on its own, so that its tokens are
+// the same as the ones grouped by the labeler of the Server. It reports false if the example is too long,
+// or a word has no tokens.
+func (t *FineTuner) convertTokensExample(example Example) (fineTuningInput, bool, error) {
+	if len(example.Tokens) != len(example.Labels) {
+		return fineTuningInput{}, false, fmt.Errorf("%d tokens with %d labels", len(example.Tokens), len(example.Labels))
+	}
+	targets := make([]int, len(example.Labels))
+	for i, label := range example.Labels {
+		index, err := t.labelIndex(label)
+		if err != nil {
+			return fineTuningInput{}, false, err
+		}
+		targets[i] = index
+	}
+	_, isBPE := t.model.Tokenizer.(bpeTokenizer)
+	tokens := make([]string, 0, len(example.Tokens))
+	words := make([]wordpiecetokenizer.TokensRange, len(example.Tokens))
+	for i, word := range example.Tokens {
+		if i > 0 && isBPE {
+			word = " " + word // the byte-level BPE encodes the space before the word
+		}
+		pieces := tokenizers.GetStrings(t.model.Tokenize(word))
+		if len(pieces) == 0 {
+			return fineTuningInput{}, false, nil
+		}
+		words[i] = wordpiecetokenizer.TokensRange{Start: len(tokens) + 1, End: len(tokens) + len(pieces)} // after [CLS]
+		tokens = append(tokens, pieces...)
+	}
+	if len(tokens) == 0 || len(tokens)+2 > t.maxSequenceLength() {
+		return fineTuningInput{}, false, nil
+	}
+	return fineTuningInput{tokens: t.model.pad(tokens), targets: targets, words: words}, true, nil
+}
+
+// convertAnswerExample converts the question and the passage, locating the tokens of the answer from
+// its offsets. It reports false if the example is too long, or the answer doesn't match any token.
+func (t *FineTuner) convertAnswerExample(example Example) (fineTuningInput, bool) {
+	question := tokenizers.GetStrings(t.model.Tokenize(example.Question))
+	passage := t.model.Tokenize(example.Text)
+	answerStart := example.AnswerStart
+	answerEnd := answerStart + utf8.RuneCountInString(example.AnswerText)
+	start, end := -1, -1
+	for i, token := range passage {
+		if start == -1 && token.Offsets.End > answerStart {
+			start = i
+		}
+		if token.Offsets.Start < answerEnd {
+			end = i
+		}
+	}
+	if start == -1 || end < start {
+		return fineTuningInput{}, false
+	}
+	tokens := t.model.padPair(question, tokenizers.GetStrings(passage))
+	if len(tokens) > t.maxSequenceLength() {
+		return fineTuningInput{}, false
+	}
+	passageEnd := len(tokens) - 1 // before the last separator
+	passageStart := passageEnd - len(passage)
+	return fineTuningInput{
+		tokens:       tokens,
+		targets:      []int{passageStart + start, passageStart + end},
+		passageStart: passageStart,
+		passageEnd:   passageEnd,
+	}, true
+}
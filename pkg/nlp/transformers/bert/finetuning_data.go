@@ -0,0 +1,129 @@
+// Copyright 2021 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package bert
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// Example is a labeled example for the fine-tuning of a BERT Model. The fields in use depend on the task:
+//   - sequence classification: Text, the optional Text2 of a pair, and Label;
+//   - token classification: Tokens and their Labels;
+//   - question-answering: the Question, the passage in Text, and the AnswerText starting at the
+//     AnswerStart rune offset of the passage.
+type Example struct {
+	Text        string   `json:"text"`
+	Text2       string   `json:"text2,omitempty"`
+	Label       string   `json:"label,omitempty"`
+	Tokens      []string `json:"tokens,omitempty"`
+	Labels      []string `json:"labels,omitempty"`
+	Question    string   `json:"question,omitempty"`
+	AnswerText  string   `json:"answer_text,omitempty"`
+	AnswerStart int      `json:"answer_start,omitempty"`
+}
+
+// ReadJSONLExamples reads the examples from a JSON Lines file, with one Example per line.
+// The empty lines are skipped.
+func ReadJSONLExamples(filename string) ([]Example, error) {
+	examples := make([]Example, 0)
+	err := readJSONLines(filename, func(line []byte) error {
+		var example Example
+		if err := json.Unmarshal(line, &example); err != nil {
+			return err
+		}
+		examples = append(examples, example)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return examples, nil
+}
+
+// readJSONLines calls the decoding function for each non-empty line of a JSON Lines file.
+func readJSONLines(filename string, decode func(line []byte) error) error {
+	f, err := os.Open(filename)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		if err := decode([]byte(line)); err != nil {
+			return fmt.Errorf("bert: %s line %d: %w", filename, lineNumber, err)
+		}
+	}
+	return scanner.Err()
+}
+
+// ReadCoNLLExamples reads the token classification examples from a file in CoNLL format: one token per line,
+// with the token in the first column and its label in the last one, separated by whitespaces, and the sentences
+// separated by an empty line. The document separators (`-DOCSTART-`) are skipped.
+func ReadCoNLLExamples(filename string) ([]Example, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	examples := make([]Example, 0)
+	var current Example
+	flush := func() {
+		if len(current.Tokens) > 0 {
+			examples = append(examples, current)
+		}
+		current = Example{}
+	}
+	scanner := bufio.NewScanner(f)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		fields := strings.Fields(scanner.Text())
+		switch {
+		case len(fields) == 0:
+			flush()
+		case fields[0] == "-DOCSTART-":
+			flush()
+		case len(fields) < 2:
+			return nil, fmt.Errorf("bert: %s line %d: expected token and label", filename, lineNumber)
+		default:
+			current.Tokens = append(current.Tokens, fields[0])
+			current.Labels = append(current.Labels, fields[len(fields)-1])
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	flush()
+	return examples, nil
+}
+
+// CollectLabels returns the sorted set of the labels of the examples: the sequence labels, and the token labels.
+func CollectLabels(examples []Example) []string {
+	set := make(map[string]struct{})
+	for _, example := range examples {
+		if example.Label != "" {
+			set[example.Label] = struct{}{}
+		}
+		for _, label := range example.Labels {
+			set[label] = struct{}{}
+		}
+	}
+	labels := make([]string, 0, len(set))
+	for label := range set {
+		labels = append(labels, label)
+	}
+	sort.Strings(labels)
+	return labels
+}
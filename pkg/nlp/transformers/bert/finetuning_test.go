@@ -0,0 +1,210 @@
+// Copyright 2021 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package bert
+
+import (
+	"encoding/json"
+	mat "github.com/nlpodyssey/spago/pkg/mat32"
+	"github.com/nlpodyssey/spago/pkg/ml/optimizers/gd/adam"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io/ioutil"
+	"path"
+	"strings"
+	"testing"
+)
+
+var testFineTuningTerms = []string{"[CLS]", "[SEP]", "[UNK]", "[PAD]", "[MASK]", "good", "bad", "movie", "##s"}
+
+func newTestFineTuningConfig(task FineTuningTask, modelPath string) FineTuningConfig {
+	return FineTuningConfig{
+		Task:         task,
+		Seed:         1,
+		BatchSize:    2,
+		Epochs:       100,
+		UpdateMethod: adam.NewConfig(0.05, 0.9, 0.999, 1.0e-8),
+		Patience:     0,
+		ModelPath:    modelPath,
+	}
+}
+
+func TestReadCoNLLExamples(t *testing.T) {
+	filename := path.Join(t.TempDir(), "train.conll")
+	data := "-DOCSTART- -X- O O\n\nRome NNP B-LOC\nis VBZ O\n\n\nPaolo B-PER\n"
+	require.NoError(t, ioutil.WriteFile(filename, []byte(data), 0644))
+
+	examples, err := ReadCoNLLExamples(filename)
+	require.NoError(t, err)
+	assert.Equal(t, []Example{
+		{Tokens: []string{"Rome", "is"}, Labels: []string{"B-LOC", "O"}},
+		{Tokens: []string{"Paolo"}, Labels: []string{"B-PER"}},
+	}, examples)
+	assert.Equal(t, []string{"B-LOC", "B-PER", "O"}, CollectLabels(examples))
+}
+
+func TestReadJSONLExamples(t *testing.T) {
+	filename := path.Join(t.TempDir(), "train.jsonl")
+	data := `{"text": "good movie", "label": "pos"}
+
+{"text": "good movie", "question": "what?", "answer_text": "movie", "answer_start": 5}`
+	require.NoError(t, ioutil.WriteFile(filename, []byte(data), 0644))
+
+	examples, err := ReadJSONLExamples(filename)
+	require.NoError(t, err)
+	assert.Equal(t, []Example{
+		{Text: "good movie", Label: "pos"},
+		{Text: "good movie", Question: "what?", AnswerText: "movie", AnswerStart: 5},
+	}, examples)
+
+	require.NoError(t, ioutil.WriteFile(filename, []byte("{"), 0644))
+	_, err = ReadJSONLExamples(filename)
+	assert.Error(t, err)
+}
+
+func TestFineTuner_SequenceClassification(t *testing.T) {
+	modelPath := t.TempDir()
+	config := Config{ModelType: "bert", TypeVocabSize: 2, Training: true}
+	model := newTestModelWithStorage(t, config, testFineTuningTerms, path.Join(modelPath, DefaultEmbeddingsStorage))
+	vocab := strings.Join(testFineTuningTerms, "\n")
+	require.NoError(t, ioutil.WriteFile(path.Join(modelPath, DefaultVocabularyFile), []byte(vocab), 0644))
+	settings := map[string]interface{}{"architectures": []string{"BertModel"}}
+	data, err := json.Marshal(model.Config)
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(data, &settings))
+	data, err = json.Marshal(settings)
+	require.NoError(t, err)
+	require.NoError(t, ioutil.WriteFile(path.Join(modelPath, DefaultConfigurationFile), data, 0644))
+
+	examples := []Example{
+		{Text: "good movie", Label: "pos"},
+		{Text: "bad movie", Label: "neg"},
+		{Text: "good movies", Text2: "bad", Label: "neg"},
+	}
+	fineTuningConfig := newTestFineTuningConfig(SequenceClassificationTask, modelPath)
+	fineTuningConfig.Patience = 30
+	tuner := NewFineTuner(model, fineTuningConfig, CollectLabels(examples))
+	assert.Equal(t, []string{"neg", "pos"}, model.Classifier.Config.Labels)
+	require.NoError(t, tuner.Train(examples, examples))
+
+	evaluation, err := tuner.Evaluate(examples)
+	require.NoError(t, err)
+	assert.Equal(t, mat.Float(1.0), evaluation.Score)
+	assert.Equal(t, 2, evaluation.Metrics["neg"].TruePos)
+	assert.Equal(t, 1, evaluation.Metrics["pos"].TruePos)
+	assert.Equal(t, 2, evaluation.Metrics["pos"].TrueNeg)
+
+	_, err = tuner.Evaluate([]Example{{Text: "good", Label: "unknown"}})
+	assert.Error(t, err)
+
+	model.Embeddings.Words.Close()
+	loaded, err := LoadModel(modelPath)
+	require.NoError(t, err)
+	defer loaded.Embeddings.Words.Close()
+	assert.Equal(t, []string{"neg", "pos"}, loaded.Classifier.Config.Labels)
+	loadedTuner := NewFineTuner(loaded, newTestFineTuningConfig(SequenceClassificationTask, modelPath), []string{"neg", "pos"})
+	evaluation, err = loadedTuner.Evaluate(examples)
+	require.NoError(t, err)
+	assert.Equal(t, mat.Float(1.0), evaluation.Score) // the best model
+
+	data, err = ioutil.ReadFile(path.Join(modelPath, DefaultConfigurationFile))
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"architectures"`)
+	assert.Contains(t, string(data), `"label2id"`)
+}
+
+func TestFineTuner_convertSequenceExample(t *testing.T) {
+	model := newTestModel(t, Config{ModelType: "bert", TypeVocabSize: 2}, testFineTuningTerms)
+	defer model.Embeddings.Words.Close()
+	tuner := NewFineTuner(model, newTestFineTuningConfig(SequenceClassificationTask, t.TempDir()), []string{"a"})
+
+	input, err := tuner.convertSequenceExample(Example{Text: "good good good movies", Text2: "bad movie", Label: "a"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"[CLS]", "good", "good", "good", "[SEP]", "bad", "movie", "[SEP]"}, input.tokens)
+
+	input, err = tuner.convertSequenceExample(Example{Text: "good good good good good good movies", Label: "a"})
+	require.NoError(t, err)
+	assert.Len(t, input.tokens, 8)
+	assert.Equal(t, "[SEP]", input.tokens[7])
+}
+
+func TestFineTuner_TokenClassification(t *testing.T) {
+	model := newTestModel(t, Config{ModelType: "bert", TypeVocabSize: 2}, testFineTuningTerms)
+	defer model.Embeddings.Words.Close()
+
+	examples := []Example{
+		{Tokens: []string{"good", "movies"}, Labels: []string{"B", "O"}},
+		{Tokens: []string{"movie", "bad"}, Labels: []string{"O", "B"}},
+	}
+	tuner := NewFineTuner(model, newTestFineTuningConfig(TokenClassificationTask, t.TempDir()), CollectLabels(examples))
+
+	input, ok, err := tuner.convertTokensExample(examples[0])
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.Equal(t, []string{"[CLS]", "good", "movie", "##s", "[SEP]"}, input.tokens)
+	assert.Equal(t, 2, input.words[1].Start)
+	assert.Equal(t, 3, input.words[1].End)
+	assert.Equal(t, []int{0, 1}, input.targets)
+
+	_, _, err = tuner.convertTokensExample(Example{Tokens: []string{"good"}})
+	assert.Error(t, err)
+
+	require.NoError(t, tuner.Train(examples, examples))
+	evaluation, err := tuner.Evaluate(examples)
+	require.NoError(t, err)
+	assert.Equal(t, mat.Float(1.0), evaluation.Score)
+}
+
+func TestFineTuner_QuestionAnswering(t *testing.T) {
+	model := newTestModel(t, Config{ModelType: "bert", TypeVocabSize: 2}, testFineTuningTerms)
+	defer model.Embeddings.Words.Close()
+
+	examples := []Example{
+		{Question: "good", Text: "bad movie good", AnswerText: "good", AnswerStart: 10},
+		{Question: "bad", Text: "bad movie good", AnswerText: "bad movie", AnswerStart: 0},
+		{Question: "bad", Text: "bad movie good movie good", AnswerText: "bad", AnswerStart: 0}, // too long
+	}
+	config := newTestFineTuningConfig(QuestionAnsweringTask, t.TempDir())
+	config.Epochs = 10
+	tuner := NewFineTuner(model, config, nil)
+
+	input, ok := tuner.convertAnswerExample(examples[1])
+	require.True(t, ok)
+	assert.Equal(t, []int{3, 4}, input.targets)
+	assert.Equal(t, 3, input.passageStart)
+	assert.Equal(t, 6, input.passageEnd)
+	_, ok = tuner.convertAnswerExample(examples[2])
+	assert.False(t, ok)
+
+	initial, err := tuner.Evaluate(examples)
+	require.NoError(t, err)
+	assert.Equal(t, 1, initial.Skipped)
+	assert.Contains(t, initial.Metrics, "answer")
+	require.NoError(t, tuner.Train(examples, nil))
+	evaluation, err := tuner.Evaluate(examples)
+	require.NoError(t, err)
+	assert.Less(t, float64(evaluation.Loss), float64(initial.Loss))
+}
+
+func TestBestSpan(t *testing.T) {
+	start, end := bestSpan([]mat.Float{0, 1, 5}, []mat.Float{4, 0, 1})
+	assert.Equal(t, 2, start)
+	assert.Equal(t, 2, end)
+}
+
+func TestEvaluateAnswers(t *testing.T) {
+	answers := []Answers{
+		{{Text: "the Eiffel Tower", Confidence: 0.9}, {Text: "Paris", Confidence: 0.2}},
+		nil,
+		{{Text: "in Paris France", Confidence: 0.5}},
+	}
+	evaluator, err := EvaluateAnswers(answers, [][]string{{"Eiffel Tower"}, {"1889"}, {"Paris"}})
+	require.NoError(t, err)
+	assert.Equal(t, 3, evaluator.Count())
+	assert.InDelta(t, 1.0/3.0, float64(evaluator.ExactMatch()), 1.0e-6)
+	assert.InDelta(t, 1.5/3.0, float64(evaluator.F1Score()), 1.0e-6)
+
+	_, err = EvaluateAnswers(answers, nil)
+	assert.Error(t, err)
+}
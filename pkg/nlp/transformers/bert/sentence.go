@@ -0,0 +1,240 @@
+// Copyright 2021 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package bert
+
+import (
+	"encoding/gob"
+	"encoding/json"
+	"fmt"
+	mat "github.com/nlpodyssey/spago/pkg/mat32"
+	"github.com/nlpodyssey/spago/pkg/ml/ag"
+	"github.com/nlpodyssey/spago/pkg/ml/nn"
+	"github.com/nlpodyssey/spago/pkg/ml/nn/activation"
+	"github.com/nlpodyssey/spago/pkg/ml/nn/linear"
+	"github.com/nlpodyssey/spago/pkg/ml/nn/stack"
+	"github.com/nlpodyssey/spago/pkg/nlp/tokenizers"
+	"io/ioutil"
+	"os"
+	"path"
+	"runtime"
+)
+
+const (
+	// DefaultSentenceTransformerConfigFile is the default filename of the JSON configuration of the sentence
+	// embedding head of a SentenceTransformer (custom for spaGO).
+	DefaultSentenceTransformerConfigFile = "sentence_transformer_config.json"
+	// DefaultSentenceTransformerModelFile is the default filename of the spaGO model of the dense projection
+	// of a SentenceTransformer.
+	DefaultSentenceTransformerModelFile = "spago_sentence_dense.bin"
+)
+
+var (
+	_ nn.Model = &SentenceTransformer{}
+)
+
+// SentenceTransformerConfig provides configuration settings for the sentence embedding head of
+// a SentenceTransformer.
+type SentenceTransformerConfig struct {
+	// PoolingStrategy reduces the encodings of the tokens to a single vector. Unlike Model.Vectorize,
+	// ClsToken takes the encoding of the class token as it is, without the Pooler.
+	PoolingStrategy PoolingStrategy `json:"pooling_strategy"`
+	// DenseOutputSize is the size of the optional dense projection of the pooled vector. Zero means
+	// no projection.
+	DenseOutputSize int `json:"dense_output_size,omitempty"`
+	// DenseActivation is the name of the activation of the dense projection (e.g. "Tanh").
+	DenseActivation string `json:"dense_activation,omitempty"`
+	// Normalize reports whether the sentence embeddings are scaled to unit length.
+	Normalize bool `json:"normalize"`
+	// MaxSequenceLength is the maximum number of tokens of a text, including the special tokens; the longer
+	// texts are truncated. Zero means the maximum number of positions of the model.
+	MaxSequenceLength int `json:"max_seq_length,omitempty"`
+}
+
+// DefaultSentenceTransformerConfig returns the configuration of a head with the mean pooling of the encodings,
+// without dense projection and normalization.
+func DefaultSentenceTransformerConfig() SentenceTransformerConfig {
+	return SentenceTransformerConfig{
+		PoolingStrategy: ReduceMean,
+	}
+}
+
+// SentenceTransformer implements a sentence embedding model (Sentence-BERT), made up of a pre-trained BERT
+// Model, the pooling of the encodings of the tokens, and an optional dense projection.
+type SentenceTransformer struct {
+	nn.BaseModel
+	Config SentenceTransformerConfig
+	BERT   *Model
+	// Dense is the optional projection of the pooled vector.
+	Dense *stack.Model
+}
+
+func init() {
+	gob.Register(&SentenceTransformer{})
+}
+
+// NewSentenceTransformer returns a new SentenceTransformer on top of the BERT model. The params of the dense
+// projection, if any, are zero-initialized.
+func NewSentenceTransformer(model *Model, config SentenceTransformerConfig) (*SentenceTransformer, error) {
+	m := &SentenceTransformer{
+		Config: config,
+		BERT:   model,
+	}
+	if config.DenseOutputSize == 0 {
+		return m, nil
+	}
+	activationName := config.DenseActivation
+	if activationName == "" {
+		activationName = "Identity"
+	}
+	act, err := ag.GetOpName(activationName)
+	if err != nil {
+		return nil, fmt.Errorf("bert: invalid dense activation: %w", err)
+	}
+	m.Dense = stack.New(
+		linear.New(m.pooledSize(), config.DenseOutputSize),
+		activation.New(act),
+	)
+	return m, nil
+}
+
+// pooledSize returns the size of the vector of the pooling strategy.
+func (m *SentenceTransformer) pooledSize() int {
+	if m.Config.PoolingStrategy == ReduceMeanMax {
+		return 2 * m.BERT.Config.HiddenSize
+	}
+	return m.BERT.Config.HiddenSize
+}
+
+// LoadSentenceTransformer loads a SentenceTransformer from the model path, that is a BERT model with the
+// sentence embedding head (see LoadSentenceHead).
+func LoadSentenceTransformer(modelPath string) (*SentenceTransformer, error) {
+	model, err := LoadModel(modelPath)
+	if err != nil {
+		return nil, err
+	}
+	return LoadSentenceHead(model, modelPath)
+}
+
+// LoadSentenceHead returns a SentenceTransformer on top of the BERT model, loading the configuration of the
+// sentence embedding head and the model file of its dense projection, if any, from the model path.
+// Without the configuration of the head, the DefaultSentenceTransformerConfig is used.
+func LoadSentenceHead(model *Model, modelPath string) (*SentenceTransformer, error) {
+	config, err := LoadSentenceTransformerConfig(path.Join(modelPath, DefaultSentenceTransformerConfigFile))
+	if os.IsNotExist(err) {
+		config = DefaultSentenceTransformerConfig()
+	} else if err != nil {
+		return nil, err
+	}
+	m, err := NewSentenceTransformer(model, config)
+	if err != nil {
+		return nil, err
+	}
+	if m.Dense == nil {
+		return m, nil
+	}
+	if err := nn.LoadFromFile(path.Join(modelPath, DefaultSentenceTransformerModelFile), m.Dense); err != nil {
+		return nil, fmt.Errorf("bert: error during model deserialization (%s)", err.Error())
+	}
+	return m, nil
+}
+
+// LoadSentenceTransformerConfig loads a SentenceTransformerConfig from file.
+func LoadSentenceTransformerConfig(filename string) (SentenceTransformerConfig, error) {
+	var config SentenceTransformerConfig
+	if err := readJSONFile(filename, &config); err != nil {
+		return SentenceTransformerConfig{}, err
+	}
+	return config, nil
+}
+
+// Save saves the BERT model, the configuration of the sentence embedding head and the dense projection, if any,
+// to the model path.
+func (m *SentenceTransformer) Save(modelPath string) error {
+	if err := nn.SaveModelFile(path.Join(modelPath, DefaultModelFile), m.BERT, m.BERT.Config); err != nil {
+		return fmt.Errorf("bert: error during model serialization: %w", err)
+	}
+	return m.saveHead(modelPath)
+}
+
+// saveHead saves the configuration of the sentence embedding head and the dense projection, if any,
+// to the model path.
+func (m *SentenceTransformer) saveHead(modelPath string) error {
+	data, err := json.MarshalIndent(m.Config, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(path.Join(modelPath, DefaultSentenceTransformerConfigFile), data, 0644); err != nil {
+		return err
+	}
+	if m.Dense == nil {
+		return nil
+	}
+	if err := nn.SaveModelFile(path.Join(modelPath, DefaultSentenceTransformerModelFile), m.Dense, m.Config); err != nil {
+		return fmt.Errorf("bert: error during model serialization: %w", err)
+	}
+	return nil
+}
+
+// Tokenize returns the tokens of the text, including the special tokens, truncated to the maximum
+// sequence length.
+func (m *SentenceTransformer) Tokenize(text string) []string {
+	tokens := tokenizers.GetStrings(m.BERT.Tokenize(text))
+	if maxLength := m.maxSequenceLength() - 2; len(tokens) > maxLength {
+		tokens = tokens[:maxLength]
+	}
+	return m.BERT.pad(tokens)
+}
+
+// maxSequenceLength returns the maximum number of tokens of a text.
+func (m *SentenceTransformer) maxSequenceLength() int {
+	maxLength := m.BERT.Embeddings.MaxPositions - m.BERT.Embeddings.PositionOffset
+	if m.Config.MaxSequenceLength > 0 && m.Config.MaxSequenceLength < maxLength {
+		return m.Config.MaxSequenceLength
+	}
+	return maxLength
+}
+
+// Embed returns the sentence embedding of the tokens (see Tokenize).
+func (m *SentenceTransformer) Embed(tokens []string) ag.Node {
+	g := m.Graph()
+	encoded := m.BERT.Encode(tokens)
+	var pooled ag.Node
+	switch m.Config.PoolingStrategy {
+	case ReduceMean:
+		pooled = g.Mean(encoded)
+	case ReduceMax:
+		pooled = max(g, encoded)
+	case ReduceMeanMax:
+		pooled = g.Concat(g.Mean(encoded), max(g, encoded))
+	case ClsToken:
+		pooled = encoded[0]
+	default:
+		panic("bert: invalid pooling strategy")
+	}
+	if m.Dense != nil {
+		pooled = nn.ToNode(m.Dense.Forward(pooled))
+	}
+	if m.Config.Normalize {
+		pooled = normalize(g, pooled)
+	}
+	return pooled
+}
+
+// normalize scales the vector to unit length.
+func normalize(g *ag.Graph, x ag.Node) ag.Node {
+	return g.DivScalar(x, g.Sqrt(g.ReduceSum(g.Square(x))))
+}
+
+// Vectorize returns the sentence embeddings of the texts.
+func (m *SentenceTransformer) Vectorize(texts ...string) []mat.Matrix {
+	g := ag.NewGraph(ag.ConcurrentComputations(runtime.NumCPU()))
+	defer g.Clear()
+	proc := nn.Reify(nn.Context{Graph: g, Mode: nn.Inference}, m).(*SentenceTransformer)
+	vectors := make([]mat.Matrix, len(texts))
+	for i, text := range texts {
+		vectors[i] = g.GetCopiedValue(proc.Embed(m.Tokenize(text)))
+	}
+	return vectors
+}
@@ -0,0 +1,203 @@
+// Copyright 2021 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package bert
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/nlpodyssey/gopickle/pytorch"
+	"github.com/nlpodyssey/gopickle/types"
+	mat "github.com/nlpodyssey/spago/pkg/mat32"
+	"github.com/nlpodyssey/spago/pkg/ml/nn"
+	"github.com/nlpodyssey/spago/pkg/ml/nn/linear"
+	"github.com/nlpodyssey/spago/pkg/utils/gopickleutils"
+	"io/ioutil"
+	"log"
+	"os"
+	"path"
+	"strings"
+)
+
+const (
+	// DefaultSentenceTransformerModulesFile is the default filename of the modules of a Hugging Face
+	// sentence-transformers checkpoint.
+	DefaultSentenceTransformerModulesFile = "modules.json"
+	// DefaultSentenceBERTConfigFile is the default filename of the settings of the transformer module of
+	// a sentence-transformers checkpoint.
+	DefaultSentenceBERTConfigFile = "sentence_bert_config.json"
+)
+
+// Types of the modules of a sentence-transformers checkpoint.
+const (
+	SentenceTransformerModuleTransformer = "sentence_transformers.models.Transformer"
+	SentenceTransformerModulePooling     = "sentence_transformers.models.Pooling"
+	SentenceTransformerModuleDense       = "sentence_transformers.models.Dense"
+	SentenceTransformerModuleNormalize   = "sentence_transformers.models.Normalize"
+)
+
+// SentenceTransformerModule is a module of a sentence-transformers checkpoint, whose files are in the
+// Path subdirectory.
+type SentenceTransformerModule struct {
+	Idx  int    `json:"idx"`
+	Name string `json:"name"`
+	Path string `json:"path"`
+	Type string `json:"type"`
+}
+
+// ReadSentenceTransformerModules reads the modules of a sentence-transformers checkpoint from file.
+func ReadSentenceTransformerModules(filename string) ([]SentenceTransformerModule, error) {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	var modules []SentenceTransformerModule
+	if err := json.Unmarshal(data, &modules); err != nil {
+		return nil, fmt.Errorf("bert: %s: %w", filename, err)
+	}
+	return modules, nil
+}
+
+// sentencePoolingConfig is the configuration of the pooling module of sentence-transformers.
+type sentencePoolingConfig struct {
+	ClsToken        bool `json:"pooling_mode_cls_token"`
+	MeanTokens      bool `json:"pooling_mode_mean_tokens"`
+	MaxTokens       bool `json:"pooling_mode_max_tokens"`
+	MeanSqrtLenToks bool `json:"pooling_mode_mean_sqrt_len_tokens"`
+}
+
+// strategy returns the PoolingStrategy corresponding to the pooling modes.
+func (c sentencePoolingConfig) strategy() (PoolingStrategy, error) {
+	switch {
+	case c.MeanSqrtLenToks:
+		return -1, fmt.Errorf("bert: unsupported pooling mode `mean_sqrt_len_tokens`")
+	case c.ClsToken && !c.MeanTokens && !c.MaxTokens:
+		return ClsToken, nil
+	case c.MeanTokens && c.MaxTokens && !c.ClsToken:
+		return ReduceMeanMax, nil
+	case c.MeanTokens && !c.MaxTokens && !c.ClsToken:
+		return ReduceMean, nil
+	case c.MaxTokens && !c.MeanTokens && !c.ClsToken:
+		return ReduceMax, nil
+	default:
+		return -1, fmt.Errorf("bert: unsupported combination of pooling modes %+v", c)
+	}
+}
+
+// sentenceDenseConfig is the configuration of the dense module of sentence-transformers.
+type sentenceDenseConfig struct {
+	InFeatures         int    `json:"in_features"`
+	OutFeatures        int    `json:"out_features"`
+	Bias               bool   `json:"bias"`
+	ActivationFunction string `json:"activation_function"` // e.g. "torch.nn.modules.activation.Tanh"
+}
+
+// convertSentenceTransformer converts the sentence embedding head of a sentence-transformers checkpoint,
+// whose transformer module is the BERT model. The pooling, the dense projection (at most one) and the
+// normalization modules are supported.
+func convertSentenceTransformer(modelPath string, model *Model, precision mat.Precision) error {
+	modules, err := ReadSentenceTransformerModules(path.Join(modelPath, DefaultSentenceTransformerModulesFile))
+	if err != nil {
+		return err
+	}
+	config := DefaultSentenceTransformerConfig()
+	if err := readJSONFile(path.Join(modelPath, DefaultSentenceBERTConfigFile), &config); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	var dense *SentenceTransformerModule
+	for i, module := range modules {
+		switch module.Type {
+		case SentenceTransformerModuleTransformer:
+			if module.Path != "" {
+				return fmt.Errorf("bert: unsupported transformer module path `%s`", module.Path)
+			}
+		case SentenceTransformerModulePooling:
+			var pooling sentencePoolingConfig
+			if err := readJSONFile(path.Join(modelPath, module.Path, DefaultConfigurationFile), &pooling); err != nil {
+				return err
+			}
+			if config.PoolingStrategy, err = pooling.strategy(); err != nil {
+				return err
+			}
+		case SentenceTransformerModuleDense:
+			if dense != nil {
+				return fmt.Errorf("bert: unsupported multiple dense modules")
+			}
+			dense = &modules[i]
+			var denseConfig sentenceDenseConfig
+			if err := readJSONFile(path.Join(modelPath, module.Path, DefaultConfigurationFile), &denseConfig); err != nil {
+				return err
+			}
+			config.DenseOutputSize = denseConfig.OutFeatures
+			activationFunction := strings.Split(denseConfig.ActivationFunction, ".")
+			config.DenseActivation = activationFunction[len(activationFunction)-1]
+		case SentenceTransformerModuleNormalize:
+			config.Normalize = true
+		default:
+			return fmt.Errorf("bert: unsupported sentence-transformers module `%s`", module.Type)
+		}
+	}
+
+	log.Printf("Convert the sentence embedding head: %+v", config)
+	m, err := NewSentenceTransformer(model, config)
+	if err != nil {
+		return err
+	}
+	if dense != nil {
+		if err := convertSentenceDense(path.Join(modelPath, dense.Path, defaultHuggingFaceModelFile), m.Dense.Layers[0].(*linear.Model)); err != nil {
+			return err
+		}
+		if precision != mat.FullPrecision {
+			nn.SetPrecision(m.Dense, precision)
+		}
+	}
+	return m.saveHead(modelPath)
+}
+
+// convertSentenceDense sets the params of the linear layer with the ones of the PyTorch dense module.
+func convertSentenceDense(pyTorchModelFilename string, dense *linear.Model) error {
+	result, err := pytorch.Load(pyTorchModelFilename)
+	if err != nil {
+		return err
+	}
+	params := map[string]nn.Param{
+		"linear.weight": dense.W,
+		"linear.bias":   dense.B,
+	}
+	od := result.(*types.OrderedDict)
+	for key, entry := range od.Map {
+		param, ok := params[key.(string)]
+		if !ok {
+			continue
+		}
+		t, ok := entry.Value.(*pytorch.Tensor)
+		if !ok {
+			return fmt.Errorf("bert: invalid dense param `%s`", key)
+		}
+		if _, ok := t.Source.(*pytorch.FloatStorage); !ok {
+			return fmt.Errorf("bert: unsupported storage of the dense param `%s`", key)
+		}
+		param.SetData(gopickleutils.GetData(t))
+		delete(params, key.(string))
+	}
+	for key := range params {
+		if key == "linear.bias" {
+			continue // the dense module may have no bias
+		}
+		return fmt.Errorf("bert: dense param `%s` not found", key)
+	}
+	return nil
+}
+
+// readJSONFile decodes the JSON file into v.
+func readJSONFile(filename string, v interface{}) error {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("bert: %s: %w", filename, err)
+	}
+	return nil
+}
@@ -0,0 +1,221 @@
+// Copyright 2021 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package bert
+
+import (
+	"encoding/json"
+	mat "github.com/nlpodyssey/spago/pkg/mat32"
+	"github.com/nlpodyssey/spago/pkg/mat32/rand"
+	"github.com/nlpodyssey/spago/pkg/ml/initializers"
+	"github.com/nlpodyssey/spago/pkg/ml/nn"
+	"github.com/nlpodyssey/spago/pkg/ml/optimizers/gd/adam"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
+	"testing"
+)
+
+var testSentenceTerms = []string{"[CLS]", "[SEP]", "[UNK]", "[PAD]", "[MASK]", "cat", "dog", "kitten", "puppy", "car", "auto"}
+
+func newTestSentenceTransformer(t *testing.T, modelPath string, config SentenceTransformerConfig) *SentenceTransformer {
+	model := newTestModelWithStorage(t, Config{ModelType: "bert", TypeVocabSize: 2}, testSentenceTerms,
+		path.Join(modelPath, DefaultEmbeddingsStorage))
+	vocab := strings.Join(testSentenceTerms, "\n")
+	require.NoError(t, ioutil.WriteFile(path.Join(modelPath, DefaultVocabularyFile), []byte(vocab), 0644))
+	data, err := json.Marshal(model.Config)
+	require.NoError(t, err)
+	require.NoError(t, ioutil.WriteFile(path.Join(modelPath, DefaultConfigurationFile), data, 0644))
+
+	m, err := NewSentenceTransformer(model, config)
+	require.NoError(t, err)
+	if m.Dense != nil {
+		nn.ForEachParam(m.Dense, func(param nn.Param) {
+			initializers.Normal(param.Value(), 0, 0.5, rand.NewLockedRand(42))
+		})
+	}
+	return m
+}
+
+func TestSentenceTransformer_SaveLoad(t *testing.T) {
+	modelPath := t.TempDir()
+	m := newTestSentenceTransformer(t, modelPath, SentenceTransformerConfig{
+		PoolingStrategy:   ReduceMeanMax,
+		DenseOutputSize:   3,
+		DenseActivation:   "Tanh",
+		Normalize:         true,
+		MaxSequenceLength: 4,
+	})
+	assert.Equal(t, []string{"[CLS]", "cat", "dog", "[SEP]"}, m.Tokenize("cat dog car"))
+
+	vectors := m.Vectorize("cat dog", "car")
+	require.Len(t, vectors, 2)
+	assert.Equal(t, 3, vectors[0].Size())
+	assert.InDelta(t, 1.0, float64(vectors[0].Norm(2)), 1.0e-5)
+	require.NoError(t, m.Save(modelPath))
+	m.BERT.Embeddings.Words.Close()
+
+	loaded, err := LoadSentenceTransformer(modelPath)
+	require.NoError(t, err)
+	defer loaded.BERT.Embeddings.Words.Close()
+	assert.Equal(t, m.Config, loaded.Config)
+	for i, vector := range loaded.Vectorize("cat dog", "car") {
+		assert.InDeltaSlice(t, vectors[i].Data(), vector.Data(), 1.0e-6)
+	}
+
+	_, err = NewSentenceTransformer(loaded.BERT, SentenceTransformerConfig{DenseOutputSize: 3, DenseActivation: "foo"})
+	assert.Error(t, err)
+}
+
+func TestLoadSentenceTransformer_DefaultConfig(t *testing.T) {
+	modelPath := t.TempDir()
+	m := newTestSentenceTransformer(t, modelPath, DefaultSentenceTransformerConfig())
+	require.NoError(t, nn.SaveModelFile(path.Join(modelPath, DefaultModelFile), m.BERT, m.BERT.Config))
+	m.BERT.Embeddings.Words.Close()
+
+	loaded, err := LoadSentenceTransformer(modelPath)
+	require.NoError(t, err)
+	defer loaded.BERT.Embeddings.Words.Close()
+	assert.Equal(t, DefaultSentenceTransformerConfig(), loaded.Config)
+	assert.Nil(t, loaded.Dense)
+	expected, err := loaded.BERT.Vectorize("cat dog", ReduceMean)
+	require.NoError(t, err)
+	assert.InDeltaSlice(t, expected.Data(), loaded.Vectorize("cat dog")[0].Data(), 1.0e-6)
+}
+
+func TestConvertSentenceTransformer(t *testing.T) {
+	modelPath := t.TempDir()
+	m := newTestSentenceTransformer(t, modelPath, DefaultSentenceTransformerConfig())
+	defer m.BERT.Embeddings.Words.Close()
+	write := func(name, data string) {
+		filename := path.Join(modelPath, name)
+		require.NoError(t, os.MkdirAll(path.Dir(filename), 0755))
+		require.NoError(t, ioutil.WriteFile(filename, []byte(data), 0644))
+	}
+	write(DefaultSentenceTransformerModulesFile, `[
+		{"idx": 0, "name": "0", "path": "", "type": "sentence_transformers.models.Transformer"},
+		{"idx": 1, "name": "1", "path": "1_Pooling", "type": "sentence_transformers.models.Pooling"},
+		{"idx": 2, "name": "2", "path": "2_Normalize", "type": "sentence_transformers.models.Normalize"}
+	]`)
+	write(DefaultSentenceBERTConfigFile, `{"max_seq_length": 128, "do_lower_case": false}`)
+	write("1_Pooling/config.json", `{"word_embedding_dimension": 4, "pooling_mode_cls_token": true,
+		"pooling_mode_mean_tokens": false, "pooling_mode_max_tokens": false, "pooling_mode_mean_sqrt_len_tokens": false}`)
+
+	require.NoError(t, convertSentenceTransformer(modelPath, m.BERT, mat.FullPrecision))
+	config, err := LoadSentenceTransformerConfig(path.Join(modelPath, DefaultSentenceTransformerConfigFile))
+	require.NoError(t, err)
+	assert.Equal(t, SentenceTransformerConfig{
+		PoolingStrategy:   ClsToken,
+		Normalize:         true,
+		MaxSequenceLength: 128,
+	}, config)
+
+	write(DefaultSentenceTransformerModulesFile, `[{"idx": 0, "name": "0", "path": "", "type": "foo"}]`)
+	assert.Error(t, convertSentenceTransformer(modelPath, m.BERT, mat.FullPrecision))
+}
+
+func TestSentencePoolingConfig_Strategy(t *testing.T) {
+	cases := []struct {
+		config   sentencePoolingConfig
+		expected PoolingStrategy
+	}{
+		{sentencePoolingConfig{ClsToken: true}, ClsToken},
+		{sentencePoolingConfig{MeanTokens: true}, ReduceMean},
+		{sentencePoolingConfig{MaxTokens: true}, ReduceMax},
+		{sentencePoolingConfig{MeanTokens: true, MaxTokens: true}, ReduceMeanMax},
+	}
+	for _, c := range cases {
+		strategy, err := c.config.strategy()
+		require.NoError(t, err)
+		assert.Equal(t, c.expected, strategy)
+	}
+	_, err := sentencePoolingConfig{MeanSqrtLenToks: true}.strategy()
+	assert.Error(t, err)
+	_, err = sentencePoolingConfig{ClsToken: true, MeanTokens: true}.strategy()
+	assert.Error(t, err)
+}
+
+func TestReadSentenceExamples(t *testing.T) {
+	filename := path.Join(t.TempDir(), "train.jsonl")
+	data := `{"anchor": "cat", "positive": "kitten", "score": 0.9}
+
+{"anchor": "dog", "positive": "puppy", "negative": "car"}`
+	require.NoError(t, ioutil.WriteFile(filename, []byte(data), 0644))
+
+	examples, err := ReadSentenceExamples(filename)
+	require.NoError(t, err)
+	assert.Equal(t, []SentenceExample{
+		{Anchor: "cat", Positive: "kitten", Score: 0.9},
+		{Anchor: "dog", Positive: "puppy", Negative: "car"},
+	}, examples)
+
+	require.NoError(t, ioutil.WriteFile(filename, []byte("{\n"), 0644))
+	_, err = ReadSentenceExamples(filename)
+	assert.Error(t, err)
+}
+
+func newTestSentenceTrainingConfig(loss SentenceLoss, modelPath string) SentenceTrainingConfig {
+	return SentenceTrainingConfig{
+		Loss:         loss,
+		Seed:         1,
+		BatchSize:    3,
+		Epochs:       30,
+		UpdateMethod: adam.NewConfig(0.01, 0.9, 0.999, 1.0e-8),
+		Margin:       1.0,
+		ModelPath:    modelPath,
+	}
+}
+
+func TestSentenceTrainer(t *testing.T) {
+	pairs := []SentenceExample{
+		{Anchor: "cat", Positive: "kitten"},
+		{Anchor: "dog", Positive: "puppy"},
+		{Anchor: "car", Positive: "auto"},
+	}
+	cases := map[SentenceLoss][]SentenceExample{
+		CosineSimilarityLoss: {
+			{Anchor: "cat", Positive: "kitten", Score: 1.0},
+			{Anchor: "dog", Positive: "puppy", Score: 1.0},
+			{Anchor: "car", Positive: "auto", Score: 1.0},
+			{Anchor: "cat", Positive: "auto", Score: 0.0},
+		},
+		TripletLoss: {
+			{Anchor: "cat", Positive: "kitten", Negative: "car"},
+			{Anchor: "dog", Positive: "puppy", Negative: "kitten"},
+			{Anchor: "car", Positive: "auto", Negative: "dog"},
+		},
+		MultipleNegativesRankingLoss: pairs,
+	}
+	for loss, examples := range cases {
+		loss, examples := loss, examples
+		t.Run(string(loss), func(t *testing.T) {
+			modelPath := t.TempDir()
+			m := newTestSentenceTransformer(t, modelPath, SentenceTransformerConfig{
+				PoolingStrategy: ReduceMean,
+				DenseOutputSize: 4,
+			})
+			defer m.BERT.Embeddings.Words.Close()
+			trainer := NewSentenceTrainer(m, newTestSentenceTrainingConfig(loss, modelPath))
+			initial, err := trainer.Evaluate(examples)
+			require.NoError(t, err)
+			require.NoError(t, trainer.Train(examples, examples))
+			evaluation, err := trainer.Evaluate(examples)
+			require.NoError(t, err)
+			assert.Less(t, float64(evaluation.Loss), float64(initial.Loss))
+			if loss != CosineSimilarityLoss {
+				assert.Equal(t, mat.Float(1.0), evaluation.Accuracy)
+			}
+			assert.FileExists(t, path.Join(modelPath, DefaultSentenceTransformerModelFile))
+		})
+	}
+
+	trainer := NewSentenceTrainer(&SentenceTransformer{}, newTestSentenceTrainingConfig(TripletLoss, t.TempDir()))
+	assert.Error(t, trainer.Train([]SentenceExample{{Anchor: "cat", Positive: "kitten"}}, nil))
+	trainer = NewSentenceTrainer(&SentenceTransformer{}, newTestSentenceTrainingConfig("foo", t.TempDir()))
+	_, err := trainer.Evaluate([]SentenceExample{{Anchor: "cat", Positive: "kitten"}})
+	assert.Error(t, err)
+}
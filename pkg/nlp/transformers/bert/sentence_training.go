@@ -0,0 +1,308 @@
+// Copyright 2021 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package bert
+
+import (
+	"encoding/json"
+	"fmt"
+	mat "github.com/nlpodyssey/spago/pkg/mat32"
+	"github.com/nlpodyssey/spago/pkg/mat32/floatutils"
+	"github.com/nlpodyssey/spago/pkg/mat32/rand"
+	"github.com/nlpodyssey/spago/pkg/ml/ag"
+	"github.com/nlpodyssey/spago/pkg/ml/losses"
+	"github.com/nlpodyssey/spago/pkg/ml/nn"
+	"github.com/nlpodyssey/spago/pkg/ml/optimizers/gd"
+	"github.com/nlpodyssey/spago/pkg/ml/optimizers/gd/gdmbuilder"
+	"github.com/nlpodyssey/spago/pkg/ml/training"
+	"runtime"
+)
+
+// SentenceLoss is the training objective of a SentenceTrainer.
+type SentenceLoss string
+
+// Supported sentence embedding losses.
+const (
+	// CosineSimilarityLoss trains a siamese network on pairs of texts, minimizing the squared error between
+	// the cosine similarity of their embeddings and the expected score.
+	CosineSimilarityLoss SentenceLoss = "cosine-similarity"
+	// TripletLoss trains on triplets of texts, making the Euclidean distance between the anchor and the
+	// positive text smaller than the distance between the anchor and the negative text, by at least a margin.
+	TripletLoss SentenceLoss = "triplet"
+	// MultipleNegativesRankingLoss trains on pairs of texts, ranking the positive text of each anchor above the
+	// other texts of the batch (in-batch negatives) and the optional hard negatives, by the cross-entropy of the
+	// scaled cosine similarities.
+	MultipleNegativesRankingLoss SentenceLoss = "multiple-negatives-ranking"
+)
+
+const (
+	defaultTripletMargin mat.Float = 5.0
+	defaultRankingScale  mat.Float = 20.0
+)
+
+// SentenceExample is a training example of a SentenceTransformer. The fields in use depend on the loss:
+//   - cosine similarity: the Anchor and the Positive texts, and their similarity Score;
+//   - triplet: the Anchor, the Positive and the Negative texts;
+//   - multiple negatives ranking: the Anchor and the Positive texts, and the optional hard Negative text.
+type SentenceExample struct {
+	Anchor   string    `json:"anchor"`
+	Positive string    `json:"positive"`
+	Negative string    `json:"negative,omitempty"`
+	Score    mat.Float `json:"score,omitempty"`
+}
+
+// ReadSentenceExamples reads the sentence examples from a JSON Lines file, with one SentenceExample per line.
+// The empty lines are skipped.
+func ReadSentenceExamples(filename string) ([]SentenceExample, error) {
+	examples := make([]SentenceExample, 0)
+	err := readJSONLines(filename, func(line []byte) error {
+		var example SentenceExample
+		if err := json.Unmarshal(line, &example); err != nil {
+			return err
+		}
+		examples = append(examples, example)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return examples, nil
+}
+
+// accuracyMetric is the name of the accuracy of the evaluation on the development set, during the training.
+const accuracyMetric = "Accuracy"
+
+// SentenceTrainingConfig provides configuration settings for a SentenceTrainer.
+type SentenceTrainingConfig struct {
+	Loss             SentenceLoss
+	Seed             uint64
+	BatchSize        int
+	Epochs           int
+	GradientClipping mat.Float
+	UpdateMethod     gd.MethodConfig
+	// Margin is the minimum difference between the distances of the triplet loss. Zero means 5.
+	Margin mat.Float
+	// Scale is the multiplier of the cosine similarities of the multiple negatives ranking loss. Zero means 20.
+	Scale mat.Float
+	// ModelPath is the directory of the model, where the model is saved after each epoch, or only when the
+	// loss on the development set improves.
+	ModelPath string
+}
+
+// SentenceEvaluation contains the results of the evaluation of a SentenceTrainer on a set of examples.
+type SentenceEvaluation struct {
+	// Loss is the average loss of the batches.
+	Loss mat.Float
+	// Accuracy is the ratio of the anchors whose positive text is closer than the negative texts: the negative
+	// of the triplet, or the other texts of the batch of the multiple negatives ranking. It is always zero for
+	// the cosine similarity loss.
+	Accuracy mat.Float
+}
+
+// SentenceTrainer implements the contrastive training of a SentenceTransformer, with the evaluation on
+// a development set after each epoch and the saving of the best model.
+//
+// The word embeddings are trained only if the BERT model has been loaded with Config.Training set; their
+// storage is updated in place, regardless of the best epoch.
+type SentenceTrainer struct {
+	SentenceTrainingConfig
+	randGen   *rand.LockedRand
+	optimizer *gd.GradientDescent
+	model     *SentenceTransformer
+}
+
+// NewSentenceTrainer returns a new SentenceTrainer.
+func NewSentenceTrainer(model *SentenceTransformer, config SentenceTrainingConfig) *SentenceTrainer {
+	if config.BatchSize <= 0 {
+		config.BatchSize = 1
+	}
+	if config.Margin == 0 {
+		config.Margin = defaultTripletMargin
+	}
+	if config.Scale == 0 {
+		config.Scale = defaultRankingScale
+	}
+	optimizer := gd.NewOptimizer(gdmbuilder.NewMethod(config.UpdateMethod), nn.NewDefaultParamsIterator(model))
+	if config.GradientClipping != 0.0 {
+		gd.ClipGradByNorm(config.GradientClipping, 2.0)(optimizer)
+	}
+	return &SentenceTrainer{
+		SentenceTrainingConfig: config,
+		randGen:                rand.NewLockedRand(config.Seed),
+		optimizer:              optimizer,
+		model:                  model,
+	}
+}
+
+// Train trains the model on the training examples. After each epoch, the model is evaluated on the
+// development examples, and saved if its loss is the lowest so far; without development examples, the model
+// is saved after each epoch. It returns an error if the loss is unknown, an example lacks a text required by
+// the loss, or the model can't be saved.
+func (t *SentenceTrainer) Train(trainSet, devSet []SentenceExample) error {
+	if err := t.checkExamples(trainSet); err != nil {
+		return err
+	}
+	trainer := training.New(
+		training.Config{Epochs: t.Epochs, BatchSize: t.BatchSize, Shuffle: true, Seed: t.Seed},
+		t.optimizer,
+		t.learn,
+		t.callbacks(devSet)...,
+	)
+	return trainer.Train(training.NewExamples(trainSet))
+}
+
+// callbacks returns the callbacks of the training: the evaluation on the development examples, if any,
+// the logging and the saving of the best model.
+func (t *SentenceTrainer) callbacks(devSet []SentenceExample) []training.Callback {
+	save := &training.Checkpoint{Save: func(*training.State) error { return t.SaveModel() }}
+	if len(devSet) == 0 {
+		return []training.Callback{&training.Logger{}, save}
+	}
+	evaluation := &training.Evaluation{
+		Evaluate: func() (training.Metrics, error) {
+			evaluation, err := t.Evaluate(devSet)
+			if err != nil {
+				return nil, err
+			}
+			return training.Metrics{devLossMetric: evaluation.Loss, accuracyMetric: evaluation.Accuracy}, nil
+		},
+	}
+	save.Monitor = &training.Monitor{Metric: devLossMetric, Mode: training.Minimize}
+	return []training.Callback{evaluation, &training.Logger{}, save}
+}
+
+// SaveModel saves the model to the model path (see SentenceTransformer.Save).
+func (t *SentenceTrainer) SaveModel() error {
+	return t.model.Save(t.ModelPath)
+}
+
+// checkExamples returns an error if the loss is unknown, or an example lacks a text required by the loss.
+func (t *SentenceTrainer) checkExamples(examples []SentenceExample) error {
+	switch t.Loss {
+	case CosineSimilarityLoss, TripletLoss, MultipleNegativesRankingLoss:
+	default:
+		return fmt.Errorf("bert: unknown sentence loss `%s`", t.Loss)
+	}
+	for i, example := range examples {
+		if example.Anchor == "" || example.Positive == "" {
+			return fmt.Errorf("bert: sentence example %d: missing anchor or positive text", i+1)
+		}
+		if t.Loss == TripletLoss && example.Negative == "" {
+			return fmt.Errorf("bert: sentence example %d: missing negative text", i+1)
+		}
+	}
+	return nil
+}
+
+// learn accumulates the gradients of the loss of the batch, multiplied by the gradient scale, and returns
+// the loss.
+func (t *SentenceTrainer) learn(batch []interface{}, gradScale mat.Float) mat.Float {
+	examples := make([]SentenceExample, len(batch))
+	for i, example := range batch {
+		examples[i] = example.(SentenceExample)
+	}
+	g := ag.NewGraph(ag.Rand(t.randGen), ag.ConcurrentComputations(runtime.NumCPU()))
+	defer g.Clear()
+	proc := nn.Reify(nn.Context{Graph: g, Mode: nn.Training}, t.model).(*SentenceTransformer)
+	loss, _ := t.forward(proc, examples)
+	g.Backward(g.ProdScalar(loss, g.NewScalar(gradScale)))
+	return loss.ScalarValue()
+}
+
+// Evaluate returns the SentenceEvaluation of the model on the examples, in batches of the configured size.
+// It returns an error if the loss is unknown, or an example lacks a text required by the loss.
+func (t *SentenceTrainer) Evaluate(examples []SentenceExample) (SentenceEvaluation, error) {
+	if err := t.checkExamples(examples); err != nil {
+		return SentenceEvaluation{}, err
+	}
+	var evaluation SentenceEvaluation
+	correct, numOfBatches := 0, 0
+	for start := 0; start < len(examples); start += t.BatchSize {
+		end := start + t.BatchSize
+		if end > len(examples) {
+			end = len(examples)
+		}
+		g := ag.NewGraph(ag.ConcurrentComputations(runtime.NumCPU()))
+		proc := nn.Reify(nn.Context{Graph: g, Mode: nn.Inference}, t.model).(*SentenceTransformer)
+		loss, batchCorrect := t.forward(proc, examples[start:end])
+		evaluation.Loss += loss.ScalarValue()
+		correct += batchCorrect
+		numOfBatches++
+		g.Clear()
+	}
+	if numOfBatches > 0 {
+		evaluation.Loss /= mat.Float(numOfBatches)
+	}
+	if len(examples) > 0 {
+		evaluation.Accuracy = mat.Float(correct) / mat.Float(len(examples))
+	}
+	return evaluation, nil
+}
+
+// forward returns the average loss of the batch, and the number of anchors whose positive text is the closest
+// one (see SentenceEvaluation).
+func (t *SentenceTrainer) forward(proc *SentenceTransformer, batch []SentenceExample) (ag.Node, int) {
+	g := proc.Graph()
+	embed := func(text string) ag.Node {
+		return proc.Embed(t.model.Tokenize(text))
+	}
+	var loss ag.Node
+	correct := 0
+	switch t.Loss {
+	case CosineSimilarityLoss:
+		for _, example := range batch {
+			similarity := cosineSimilarity(g, embed(example.Anchor), embed(example.Positive))
+			loss = g.Add(loss, g.Square(g.Sub(similarity, g.NewScalar(example.Score))))
+		}
+	case TripletLoss:
+		margin := g.NewScalar(t.Margin)
+		for _, example := range batch {
+			anchor := embed(example.Anchor)
+			positive := euclideanDistance(g, anchor, embed(example.Positive))
+			negative := euclideanDistance(g, anchor, embed(example.Negative))
+			loss = g.Add(loss, g.ReLU(g.Add(g.Sub(positive, negative), margin)))
+			if positive.ScalarValue() < negative.ScalarValue() {
+				correct++
+			}
+		}
+	case MultipleNegativesRankingLoss:
+		anchors := make([]ag.Node, len(batch))
+		candidates := make([]ag.Node, 0, 2*len(batch))
+		for i, example := range batch {
+			anchors[i] = normalize(g, embed(example.Anchor))
+			candidates = append(candidates, normalize(g, embed(example.Positive)))
+		}
+		for _, example := range batch {
+			if example.Negative != "" {
+				candidates = append(candidates, normalize(g, embed(example.Negative)))
+			}
+		}
+		scale := g.NewScalar(t.Scale)
+		for i, anchor := range anchors {
+			scores := make([]ag.Node, len(candidates))
+			for j, candidate := range candidates {
+				scores[j] = g.Dot(anchor, candidate)
+			}
+			logits := g.ProdScalar(g.Concat(scores...), scale)
+			loss = g.Add(loss, losses.CrossEntropy(g, logits, i))
+			if floatutils.ArgMax(logits.Value().Data()) == i {
+				correct++
+			}
+		}
+	default:
+		panic(fmt.Sprintf("bert: unknown sentence loss `%s`", t.Loss))
+	}
+	return g.DivScalar(loss, g.NewScalar(mat.Float(len(batch)))), correct
+}
+
+// cosineSimilarity returns the cosine similarity of the vectors.
+func cosineSimilarity(g *ag.Graph, x1, x2 ag.Node) ag.Node {
+	return g.Dot(normalize(g, x1), normalize(g, x2))
+}
+
+// euclideanDistance returns the Euclidean distance of the vectors. A small constant is added to the squared
+// distance, to keep the gradient finite when the vectors are equal.
+func euclideanDistance(g *ag.Graph, x1, x2 ag.Node) ag.Node {
+	return g.Sqrt(g.Add(g.ReduceSum(g.Square(g.Sub(x1, x2))), g.NewScalar(1.0e-12)))
+}
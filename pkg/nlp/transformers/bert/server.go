@@ -11,7 +11,6 @@ import (
 	"net/http"
 	"sort"
 
-	"github.com/nlpodyssey/spago/pkg/nlp/tokenizers/wordpiecetokenizer"
 	"github.com/nlpodyssey/spago/pkg/nlp/transformers/bert/grpcapi"
 	"github.com/nlpodyssey/spago/pkg/utils/grpcutils"
 	"github.com/nlpodyssey/spago/pkg/utils/httputils"
@@ -25,6 +24,9 @@ type Server struct {
 	model           *Model
 	TimeoutSeconds  int
 	MaxRequestBytes int
+	// SentenceTransformer, if set, computes the vectors of the encoding requests, with the pooling strategy
+	// of its configuration instead of the requested one.
+	SentenceTransformer *SentenceTransformer
 
 	// UnimplementedBERTServer must be embedded to have forward compatible implementations for gRPC.
 	grpcapi.UnimplementedBERTServer
@@ -84,12 +86,6 @@ type QABody struct {
 	Passage  string `json:"passage"`
 }
 
-func pad(words []string) []string {
-	leftPad := wordpiecetokenizer.DefaultClassToken
-	rightPad := wordpiecetokenizer.DefaultSequenceSeparator
-	return append([]string{leftPad}, append(words, rightPad)...)
-}
-
 // DefaultRealLabel is the default value for the real label used for BERT
 // "discriminate" server requests.
 const DefaultRealLabel = "REAL"
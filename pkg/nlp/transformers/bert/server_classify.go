@@ -18,7 +18,6 @@ import (
 	"github.com/nlpodyssey/spago/pkg/ml/ag"
 	"github.com/nlpodyssey/spago/pkg/ml/nn"
 	"github.com/nlpodyssey/spago/pkg/nlp/tokenizers"
-	"github.com/nlpodyssey/spago/pkg/nlp/tokenizers/wordpiecetokenizer"
 )
 
 // ClassifyHandler handles a classify request over HTTP.
@@ -88,14 +87,11 @@ func classificationFrom(resp *ClassifyResponse) *grpcapi.ClassifyReply {
 }
 
 func (s *Server) getTokenized(text, text2 string) []string {
-	cls := wordpiecetokenizer.DefaultClassToken
-	sep := wordpiecetokenizer.DefaultSequenceSeparator
-	tokenizer := wordpiecetokenizer.New(s.model.Vocabulary)
-	tokenized := append([]string{cls}, append(tokenizers.GetStrings(tokenizer.Tokenize(text)), sep)...)
-	if text2 != "" {
-		tokenized = append(tokenized, append(tokenizers.GetStrings(tokenizer.Tokenize(text2)), sep)...)
+	tokenized := tokenizers.GetStrings(s.model.Tokenize(text))
+	if text2 == "" {
+		return s.model.pad(tokenized)
 	}
-	return tokenized
+	return s.model.padPair(tokenized, tokenizers.GetStrings(s.model.Tokenize(text2)))
 }
 
 // TODO: This method is too long; it needs to be refactored.
@@ -14,7 +14,6 @@ import (
 	"github.com/nlpodyssey/spago/pkg/ml/ag"
 	"github.com/nlpodyssey/spago/pkg/ml/nn"
 	"github.com/nlpodyssey/spago/pkg/nlp/tokenizers"
-	"github.com/nlpodyssey/spago/pkg/nlp/tokenizers/wordpiecetokenizer"
 	"github.com/nlpodyssey/spago/pkg/nlp/transformers/bert/grpcapi"
 )
 
@@ -73,10 +72,9 @@ func tokensFrom(tokens []Token) []*grpcapi.Token {
 func (s *Server) discriminate(text string) *Response {
 	start := time.Now()
 
-	tokenizer := wordpiecetokenizer.New(s.model.Vocabulary)
-	origTokens := tokenizer.Tokenize(text)
-	groupedTokens := wordpiecetokenizer.GroupPieces(origTokens)
-	tokenized := pad(tokenizers.GetStrings(origTokens))
+	origTokens := s.model.Tokenize(text)
+	groupedTokens := s.model.groupPieces(origTokens)
+	tokenized := s.model.pad(tokenizers.GetStrings(origTokens))
 
 	g := ag.NewGraph(ag.ConcurrentComputations(runtime.NumCPU()))
 	defer g.Clear()
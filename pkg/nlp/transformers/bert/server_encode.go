@@ -73,6 +73,12 @@ func (s *Server) Encode(_ context.Context, req *grpcapi.EncodeRequest) (*grpcapi
 
 func (s *Server) encode(text string, poolingStrategy grpcapi.EncodeRequest_PoolingStrategy) (*EncodeResponse, error) {
 	start := time.Now()
+	if s.SentenceTransformer != nil {
+		return &EncodeResponse{
+			Data: s.SentenceTransformer.Vectorize(text)[0].Data(),
+			Took: time.Since(start).Milliseconds(),
+		}, nil
+	}
 	ps, err := getPoolingStrategyFromEncodeRequest(poolingStrategy)
 	if err != nil {
 		return nil, err
@@ -63,11 +63,10 @@ func (s *Server) LabelerHandler(w http.ResponseWriter, req *http.Request) {
 func (s *Server) label(text string, merge bool, filter bool) *Response {
 	start := time.Now()
 
-	tokenizer := wordpiecetokenizer.New(s.model.Vocabulary)
-	origTokens := tokenizer.Tokenize(text)
-	tokensRange := wordpiecetokenizer.GroupPieces(origTokens)
+	origTokens := s.model.Tokenize(text)
+	tokensRange := s.model.groupPieces(origTokens)
 	groupedTokens := wordpiecetokenizer.MakeOffsetPairsFromGroups(text, origTokens, tokensRange)
-	tokenized := pad(tokenizers.GetStrings(origTokens))
+	tokenized := s.model.pad(tokenizers.GetStrings(origTokens))
 
 	g := ag.NewGraph()
 	defer g.Clear()
@@ -0,0 +1,186 @@
+// Copyright 2021 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package bert
+
+import (
+	"fmt"
+	gotokenizersvocabulary "github.com/nlpodyssey/gotokenizers/vocabulary"
+	"github.com/nlpodyssey/spago/pkg/nlp/tokenizers"
+	"github.com/nlpodyssey/spago/pkg/nlp/tokenizers/bpetokenizer"
+	"github.com/nlpodyssey/spago/pkg/nlp/tokenizers/sentencepiece"
+	"github.com/nlpodyssey/spago/pkg/nlp/tokenizers/wordpiecetokenizer"
+	"github.com/nlpodyssey/spago/pkg/nlp/vocabulary"
+	"path"
+	"strings"
+)
+
+// SpecialTokens contains the special tokens of the vocabulary of a model.
+type SpecialTokens struct {
+	Class     string
+	Separator string
+	Unknown   string
+	Mask      string
+	Padding   string
+}
+
+var wordPieceSpecialTokens = SpecialTokens{
+	Class:     wordpiecetokenizer.DefaultClassToken,
+	Separator: wordpiecetokenizer.DefaultSequenceSeparator,
+	Unknown:   wordpiecetokenizer.DefaultUnknownToken,
+	Mask:      wordpiecetokenizer.DefaultMaskToken,
+	Padding:   "[PAD]",
+}
+
+var roBERTaSpecialTokens = SpecialTokens{
+	Class:     "<s>",
+	Separator: "</s>",
+	Unknown:   "<unk>",
+	Mask:      "<mask>",
+	Padding:   "<pad>",
+}
+
+// SpecialTokens returns the special tokens of the model type.
+func (c Config) SpecialTokens() SpecialTokens {
+	if c.IsRoBERTaLike() {
+		return roBERTaSpecialTokens
+	}
+	return wordPieceSpecialTokens
+}
+
+// LoadVocabulary loads the vocabulary of the model type from the files of the model path: the "vocab.txt"
+// of WordPiece, the "vocab.json" of the byte-level BPE of RoBERTa, or the sentence-piece model of XLM-RoBERTa.
+func LoadVocabulary(modelPath string, config Config) (*vocabulary.Vocabulary, error) {
+	switch config.ModelType {
+	case ModelTypeRoBERTa:
+		filename := path.Join(modelPath, DefaultBPEVocabularyFile)
+		vocab, err := gotokenizersvocabulary.FromJSONFile(filename)
+		if err != nil {
+			return nil, fmt.Errorf("bert: loading vocabulary from file %s: %w", filename, err)
+		}
+		terms := make([]string, vocab.Size())
+		for i := range terms {
+			terms[i], _ = vocab.GetString(i)
+		}
+		return vocabulary.New(terms), nil
+	case ModelTypeXLMRoBERTa:
+		filename := path.Join(modelPath, DefaultSentencePieceModelFile)
+		sp, err := sentencepiece.NewFromSentencePieceModel(filename, false)
+		if err != nil {
+			return nil, fmt.Errorf("bert: %w", err)
+		}
+		return vocabulary.New(xlmRoBERTaTerms(sp.Terms())), nil
+	default:
+		return vocabulary.NewFromFile(path.Join(modelPath, DefaultVocabularyFile))
+	}
+}
+
+// xlmRoBERTaTerms returns the terms of the XLM-RoBERTa vocabulary, given the pieces of the sentence-piece
+// model. As in the original fairseq implementation, the vocabulary starts with the special tokens, followed by
+// the pieces except the first three (the unknown, begin and end of sentence), and ends with the mask token.
+func xlmRoBERTaTerms(pieces []string) []string {
+	special := roBERTaSpecialTokens
+	terms := []string{special.Class, special.Padding, special.Separator, special.Unknown}
+	if len(pieces) > 3 {
+		terms = append(terms, pieces[3:]...)
+	}
+	return append(terms, special.Mask)
+}
+
+// NewTokenizer returns the tokenizer of the model type: WordPiece for BERT, ELECTRA and DistilBERT, the byte-level
+// BPE for RoBERTa, and the sentence-piece for XLM-RoBERTa.
+func NewTokenizer(modelPath string, config Config, vocab *vocabulary.Vocabulary) (tokenizers.Tokenizer, error) {
+	switch config.ModelType {
+	case ModelTypeRoBERTa:
+		tokenizer, err := bpetokenizer.NewFromModelFolder(modelPath)
+		if err != nil {
+			return nil, fmt.Errorf("bert: %w", err)
+		}
+		return bpeTokenizer{tokenizer}, nil
+	case ModelTypeXLMRoBERTa:
+		tokenizer, err := sentencepiece.NewFromSentencePieceModel(path.Join(modelPath, DefaultSentencePieceModelFile), false)
+		if err != nil {
+			return nil, fmt.Errorf("bert: %w", err)
+		}
+		return sentencePieceTokenizer{tokenizer}, nil
+	default:
+		return wordpiecetokenizer.New(vocab), nil
+	}
+}
+
+// bpeTokenizer adapts the byte-level BPE tokenizer to the tokenizers.Tokenizer interface.
+type bpeTokenizer struct {
+	*bpetokenizer.BPETokenizer
+}
+
+// Tokenize satisfies the tokenizers.Tokenizer interface. It panics if the text can't be tokenized.
+func (t bpeTokenizer) Tokenize(text string) []tokenizers.StringOffsetsPair {
+	tokens, err := t.BPETokenizer.Tokenize(text)
+	if err != nil {
+		panic(fmt.Errorf("bert: %w", err))
+	}
+	return tokens
+}
+
+// sentencePieceTokenizer adapts the sentence-piece tokenizer to the tokenizers.Tokenizer interface.
+type sentencePieceTokenizer struct {
+	*sentencepiece.Tokenizer
+}
+
+// Tokenize satisfies the tokenizers.Tokenizer interface.
+func (t sentencePieceTokenizer) Tokenize(text string) []tokenizers.StringOffsetsPair {
+	return t.Tokenizer.TokenizeWithOffsets(text)
+}
+
+// Tokenize splits the text into the tokens of the vocabulary, with the tokenizer of the model.
+func (m *Model) Tokenize(text string) []tokenizers.StringOffsetsPair {
+	if m.Tokenizer == nil {
+		return wordpiecetokenizer.New(m.Vocabulary).Tokenize(text)
+	}
+	return m.Tokenizer.Tokenize(text)
+}
+
+// pad adds the class token at the beginning of the sequence and the separator at the end.
+func (m *Model) pad(tokens []string) []string {
+	special := m.Config.SpecialTokens()
+	return append([]string{special.Class}, append(tokens, special.Separator)...)
+}
+
+// padPair joins the sequences of a pair, as `[CLS] A [SEP] B [SEP]`, or `<s> A </s></s> B </s>` for the
+// RoBERTa-like models.
+func (m *Model) padPair(first, second []string) []string {
+	special := m.Config.SpecialTokens()
+	tokens := m.pad(first)
+	if m.Config.IsRoBERTaLike() {
+		tokens = append(tokens, special.Separator)
+	}
+	return append(tokens, append(second, special.Separator)...)
+}
+
+// groupPieces returns the ranges of the tokens that form complete words. The WordPiece tokens are grouped
+// according to the split prefix of the continuation pieces; the others according to the prefix that stands
+// for the whitespace before a word.
+func (m *Model) groupPieces(tokens []tokenizers.StringOffsetsPair) []wordpiecetokenizer.TokensRange {
+	switch m.Tokenizer.(type) {
+	case bpeTokenizer:
+		return groupByWordPrefix(tokens, "Ġ") // the byte-level representation of the space
+	case sentencePieceTokenizer:
+		return groupByWordPrefix(tokens, "▁") // the sentence-piece representation of the space
+	default:
+		return wordpiecetokenizer.GroupPieces(tokens)
+	}
+}
+
+// groupByWordPrefix groups the tokens into words, each starting with a token with the given prefix.
+func groupByWordPrefix(tokens []tokenizers.StringOffsetsPair, prefix string) []wordpiecetokenizer.TokensRange {
+	groups := make([]wordpiecetokenizer.TokensRange, 0)
+	for i, token := range tokens {
+		if i > 0 && !strings.HasPrefix(token.String, prefix) {
+			groups[len(groups)-1].End = i
+		} else {
+			groups = append(groups, wordpiecetokenizer.TokensRange{Start: i, End: i})
+		}
+	}
+	return groups
+}
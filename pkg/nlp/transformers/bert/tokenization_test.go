@@ -0,0 +1,153 @@
+// Copyright 2021 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package bert
+
+import (
+	mat "github.com/nlpodyssey/spago/pkg/mat32"
+	"github.com/nlpodyssey/spago/pkg/mat32/rand"
+	"github.com/nlpodyssey/spago/pkg/ml/ag"
+	"github.com/nlpodyssey/spago/pkg/ml/initializers"
+	"github.com/nlpodyssey/spago/pkg/ml/nn"
+	"github.com/nlpodyssey/spago/pkg/nlp/tokenizers"
+	"github.com/nlpodyssey/spago/pkg/nlp/vocabulary"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io/ioutil"
+	"path"
+	"testing"
+)
+
+const testRoBERTaModelPath = "../../tokenizers/bpetokenizer/testdata/dummy-roberta-model"
+
+func newTestModel(t *testing.T, config Config, terms []string) *Model {
+	return newTestModelWithStorage(t, config, terms, t.TempDir())
+}
+
+func newTestModelWithStorage(t *testing.T, config Config, terms []string, embeddingsStoragePath string) *Model {
+	config.HiddenSize = 4
+	config.IntermediateSize = 8
+	config.NumAttentionHeads = 2
+	config.NumHiddenLayers = 1
+	config.MaxPositionEmbeddings = 8
+	config.VocabSize = len(terms)
+	config.Training = true
+	model := NewDefaultBERT(config, embeddingsStoragePath)
+	model.Vocabulary = vocabulary.New(terms)
+	rndGen := rand.NewLockedRand(42)
+	nn.ForEachParam(model, func(param nn.Param) {
+		initializers.Normal(param.Value(), 0, 0.5, rndGen)
+	})
+	for _, term := range terms {
+		data := make([]mat.Float, config.HiddenSize)
+		for j := range data {
+			data[j] = mat.Float(rndGen.Float()) - 0.5
+		}
+		model.Embeddings.Words.SetEmbeddingFromData(term, data)
+	}
+	return model
+}
+
+func TestLoadConfig_DistilBERT(t *testing.T) {
+	filename := path.Join(t.TempDir(), DefaultConfigurationFile)
+	data := `{"model_type": "distilbert", "activation": "gelu", "dim": 768, "hidden_dim": 3072,
+		"n_heads": 12, "n_layers": 6, "max_position_embeddings": 512, "vocab_size": 30522}`
+	require.NoError(t, ioutil.WriteFile(filename, []byte(data), 0644))
+
+	config, err := LoadConfig(filename)
+	require.NoError(t, err)
+	assert.Equal(t, Config{
+		ModelType:             ModelTypeDistilBERT,
+		HiddenAct:             "gelu",
+		HiddenSize:            768,
+		IntermediateSize:      3072,
+		MaxPositionEmbeddings: 512,
+		NumAttentionHeads:     12,
+		NumHiddenLayers:       6,
+		VocabSize:             30522,
+	}, config)
+	assert.Equal(t, 0, config.PositionOffset())
+}
+
+func TestConfig_RoBERTa(t *testing.T) {
+	config := Config{ModelType: ModelTypeXLMRoBERTa, PadTokenID: 1}
+	assert.True(t, config.IsRoBERTaLike())
+	assert.Equal(t, 2, config.PositionOffset())
+	assert.Equal(t, "<s>", config.SpecialTokens().Class)
+	assert.Equal(t, "[CLS]", Config{ModelType: ModelTypeELECTRA}.SpecialTokens().Class)
+}
+
+func TestModel_padPair(t *testing.T) {
+	model := &Model{Config: Config{ModelType: "bert"}}
+	assert.Equal(t,
+		[]string{"[CLS]", "a", "[SEP]", "b", "[SEP]"},
+		model.padPair([]string{"a"}, []string{"b"}))
+
+	model = &Model{Config: Config{ModelType: ModelTypeRoBERTa}}
+	assert.Equal(t,
+		[]string{"<s>", "a", "</s>", "</s>", "b", "</s>"},
+		model.padPair([]string{"a"}, []string{"b"}))
+}
+
+func TestXLMRoBERTaTerms(t *testing.T) {
+	terms := xlmRoBERTaTerms([]string{"<unk>", "<s>", "</s>", "▁a", "b"})
+	assert.Equal(t, []string{"<s>", "<pad>", "</s>", "<unk>", "▁a", "b", "<mask>"}, terms)
+}
+
+func TestRoBERTaTokenization(t *testing.T) {
+	config := Config{ModelType: ModelTypeRoBERTa, PadTokenID: 1}
+	vocab, err := LoadVocabulary(testRoBERTaModelPath, config)
+	require.NoError(t, err)
+	assert.Equal(t, "related", vocab.MustTerm(14))
+
+	tokenizer, err := NewTokenizer(testRoBERTaModelPath, config, vocab)
+	require.NoError(t, err)
+	model := &Model{Config: config, Vocabulary: vocab, Tokenizer: tokenizer}
+	assert.Equal(t, []string{"related", "unrelated"}, tokenizers.GetStrings(model.Tokenize("related unrelated")))
+
+	groups := model.groupPieces([]tokenizers.StringOffsetsPair{{String: "Ġun"}, {String: "related"}, {String: "Ġre"}})
+	assert.Len(t, groups, 2)
+	assert.Equal(t, 1, groups[0].End)
+	assert.Equal(t, 2, groups[1].Start)
+}
+
+func TestModel_Encode(t *testing.T) {
+	configs := []Config{
+		{ModelType: ModelTypeRoBERTa, PadTokenID: 1, TypeVocabSize: 1},
+		{ModelType: ModelTypeDistilBERT},
+	}
+	for _, config := range configs {
+		t.Run(config.ModelType, func(t *testing.T) {
+			terms := []string{"<s>", "</s>", "<unk>", "[UNK]", "a", "b"}
+			model := newTestModel(t, config, terms)
+			defer model.Embeddings.Words.Close()
+
+			g := ag.NewGraph()
+			defer g.Clear()
+			proc := nn.Reify(nn.Context{Graph: g, Mode: nn.Inference}, model).(*Model)
+			tokens := model.padPair([]string{"a"}, []string{"b"})
+			encoded := proc.Encode(tokens) // the positions must not exceed the maximum, despite the offset
+			assert.Len(t, encoded, len(tokens))
+			assert.Equal(t, model.Config.HiddenSize, encoded[0].Value().Size())
+		})
+	}
+}
+
+func TestModel_Serialization(t *testing.T) {
+	config := Config{ModelType: ModelTypeRoBERTa, PadTokenID: 1, TypeVocabSize: 1}
+	vocab, err := LoadVocabulary(testRoBERTaModelPath, config)
+	require.NoError(t, err)
+	model := newTestModel(t, config, vocab.Items())
+	defer model.Embeddings.Words.Close()
+	model.Tokenizer, err = NewTokenizer(testRoBERTaModelPath, config, vocab)
+	require.NoError(t, err)
+
+	filename := path.Join(t.TempDir(), DefaultModelFile)
+	require.NoError(t, nn.SaveModelFile(filename, model, model.Config))
+	loaded := NewDefaultBERT(model.Config, t.TempDir())
+	defer loaded.Embeddings.Words.Close()
+	require.NoError(t, nn.LoadFromFile(filename, loaded))
+	assert.Equal(t, vocab.Items(), loaded.Vocabulary.Items())
+	assert.Equal(t, model.Embeddings.Position[7].Value().Data(), loaded.Embeddings.Position[7].Value().Data())
+}
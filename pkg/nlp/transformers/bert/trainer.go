@@ -5,9 +5,6 @@
 package bert
 
 import (
-	"archive/tar"
-	"bufio"
-	"compress/gzip"
 	"fmt"
 	mat "github.com/nlpodyssey/spago/pkg/mat32"
 	"github.com/nlpodyssey/spago/pkg/mat32/rand"
@@ -16,12 +13,10 @@ import (
 	"github.com/nlpodyssey/spago/pkg/ml/nn"
 	"github.com/nlpodyssey/spago/pkg/ml/optimizers/gd"
 	"github.com/nlpodyssey/spago/pkg/ml/optimizers/gd/gdmbuilder"
+	"github.com/nlpodyssey/spago/pkg/ml/training"
+	"github.com/nlpodyssey/spago/pkg/nlp/corpora"
 	"github.com/nlpodyssey/spago/pkg/nlp/tokenizers"
 	"github.com/nlpodyssey/spago/pkg/nlp/tokenizers/wordpiecetokenizer"
-	"github.com/nlpodyssey/spago/pkg/utils"
-	"io"
-	"log"
-	"os"
 	"runtime"
 )
 
@@ -33,6 +28,9 @@ type TrainingConfig struct {
 	UpdateMethod     gd.MethodConfig
 	CorpusPath       string
 	ModelPath        string
+	// CheckpointPath, if not empty, is the file where the state of the training is saved along with the model,
+	// so that an interrupted training can be resumed (see Trainer.LoadCheckpoint).
+	CheckpointPath string
 }
 
 // Trainer implements the training process for a BERT Model.
@@ -60,24 +58,68 @@ func NewTrainer(model *Model, config TrainingConfig) *Trainer {
 	}
 }
 
-// Train executes the training process.
+// Train executes the training process: the model is trained on each line of the corpus, and serialized
+// every 1000 lines.
 func (t *Trainer) Train() {
-	t.forEachLine(func(i int, text string) {
-		t.trainPassage(text)
-		t.optimizer.IncBatch()
-		t.optimizer.IncExample()
-		t.optimizer.Optimize()
-
-		if i > 0 && i%1000 == 0 {
-			fmt.Println("=== MODEL SERIALIZATION")
-			err := utils.SerializeToFile(t.ModelPath, t.model)
-			if err != nil {
-				panic("bert: error during model serialization.")
-			}
+	trainer := training.New(
+		training.Config{Epochs: 1, BatchSize: 1},
+		t.optimizer,
+		t.learn,
+		&training.Logger{Interval: 1},
+		&training.Checkpoint{Save: t.serialize, Interval: 1000},
+	)
+	if t.countLine > 0 {
+		// resume the training skipping the lines already processed before the checkpoint
+		trainer.State = training.State{Epoch: 1, Step: t.countLine, Example: t.countLine}
+	}
+	corpus := corpora.NewGZipCorpusIterator(t.CorpusPath)
+	if err := trainer.Train(training.Lines(corpus.ForEachLine)); err != nil {
+		panic(fmt.Sprintf("bert: %v", err))
+	}
+}
+
+// learn trains the model on a line of the corpus.
+func (t *Trainer) learn(batch []interface{}, gradScale mat.Float) mat.Float {
+	t.countLine++
+	return t.trainPassage(batch[0].(string), gradScale)
+}
+
+// serialize saves the model, and the checkpoint if required.
+func (t *Trainer) serialize(*training.State) error {
+	if err := nn.SaveModelFile(t.ModelPath, t.model, t.model.Config); err != nil {
+		return fmt.Errorf("error during model serialization: %w", err)
+	}
+	if t.CheckpointPath != "" {
+		if err := t.SaveCheckpoint(t.CheckpointPath); err != nil {
+			return fmt.Errorf("error during checkpoint serialization: %w", err)
 		}
+	}
+	return nil
+}
+
+// SaveCheckpoint saves the state of the training to file: the params of the model, the state of the optimizer
+// and of the random generator, and the progress on the corpus.
+func (t *Trainer) SaveCheckpoint(filename string) error {
+	checkpoint, err := gd.NewCheckpoint(t.optimizer, t.randGen)
+	if err != nil {
+		return err
+	}
+	checkpoint.Example = t.countLine
+	return gd.SaveCheckpoint(filename, checkpoint)
+}
 
-		t.countLine++
-	})
+// LoadCheckpoint restores the state of the training from a file written by SaveCheckpoint.
+// The lines of the corpus processed before the checkpoint are skipped by Train.
+func (t *Trainer) LoadCheckpoint(filename string) error {
+	checkpoint, err := gd.LoadCheckpoint(filename)
+	if err != nil {
+		return err
+	}
+	if err := checkpoint.Restore(t.optimizer, t.randGen); err != nil {
+		return err
+	}
+	t.countLine = checkpoint.Example
+	return nil
 }
 
 func (t *Trainer) tokenize(text string) []string {
@@ -86,10 +128,12 @@ func (t *Trainer) tokenize(text string) []string {
 	return append([]string{wordpiecetokenizer.DefaultClassToken}, tokenized...)
 }
 
-func (t *Trainer) trainPassage(text string) {
+// trainPassage propagates the gradients of the masked language modeling loss of the text, multiplied by the
+// gradient scale, and returns the loss.
+func (t *Trainer) trainPassage(text string, gradScale mat.Float) mat.Float {
 	tokenized := t.tokenize(text)
 	if len(tokenized) > t.model.Embeddings.MaxPositions {
-		return // skip, sequence too long
+		return 0 // skip, sequence too long
 	}
 
 	g := ag.NewGraph(ag.Rand(t.randGen), ag.ConcurrentComputations(runtime.NumCPU()))
@@ -98,7 +142,7 @@ func (t *Trainer) trainPassage(text string) {
 
 	maskedTokens, maskedIds := t.applyMask(tokenized)
 	if len(maskedIds) == 0 {
-		return // skip, nothing to learn
+		return 0 // skip, nothing to learn
 	}
 
 	encoded := proc.Encode(maskedTokens)
@@ -113,9 +157,9 @@ func (t *Trainer) trainPassage(text string) {
 		panic("bert: expected loss not to be nil")
 	}
 
-	g.Backward(loss)
+	g.Backward(g.ProdScalar(loss, g.NewScalar(gradScale)))
 	t.lastBatchLoss = loss.ScalarValue()
-	fmt.Printf("Cnt: %d Loss: %.6f\n", t.countLine, t.lastBatchLoss)
+	return t.lastBatchLoss
 }
 
 func (t *Trainer) applyMask(tokens []string) (newTokens []string, maskedIds []int) {
@@ -147,36 +191,3 @@ func (t *Trainer) getMaskedForm(orig string) string {
 		return orig
 	}
 }
-
-func (t *Trainer) forEachLine(callback func(i int, line string)) {
-	f, err := os.Open(t.CorpusPath)
-	if err != nil {
-		log.Fatal(err)
-	}
-	defer f.Close()
-	uncompressedStream, err := gzip.NewReader(f)
-	if err != nil {
-		log.Fatal(err)
-	}
-	tarReader := tar.NewReader(uncompressedStream)
-	i := 0
-	for true {
-		header, err := tarReader.Next()
-		if err == io.EOF {
-			break
-		}
-		if err != nil {
-			log.Fatalf("Next() failed: %s", err.Error())
-		}
-		if header.Typeflag == tar.TypeReg {
-			scanner := bufio.NewScanner(tarReader)
-			for scanner.Scan() {
-				i++
-				callback(i, scanner.Text())
-			}
-			if err := scanner.Err(); err != nil {
-				log.Fatal(err)
-			}
-		}
-	}
-}
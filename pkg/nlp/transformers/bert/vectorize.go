@@ -10,7 +10,6 @@ import (
 	"github.com/nlpodyssey/spago/pkg/ml/ag"
 	"github.com/nlpodyssey/spago/pkg/ml/nn"
 	"github.com/nlpodyssey/spago/pkg/nlp/tokenizers"
-	"github.com/nlpodyssey/spago/pkg/nlp/tokenizers/wordpiecetokenizer"
 	"runtime"
 )
 
@@ -30,9 +29,8 @@ const (
 
 // Vectorize transforms the text into a dense vector representation.
 func (m *Model) Vectorize(text string, poolingStrategy PoolingStrategy) (mat.Matrix, error) {
-	tokenizer := wordpiecetokenizer.New(m.Vocabulary)
-	origTokens := tokenizer.Tokenize(text)
-	tokenized := pad(tokenizers.GetStrings(origTokens))
+	origTokens := m.Tokenize(text)
+	tokenized := m.pad(tokenizers.GetStrings(origTokens))
 
 	g := ag.NewGraph(ag.ConcurrentComputations(runtime.NumCPU()))
 	defer g.Clear()
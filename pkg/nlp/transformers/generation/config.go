@@ -17,12 +17,15 @@ type GeneratorConfig struct {
 	// MaxLength is the maximum length of the sequence to be generated.
 	MaxLength int
 	// IsEncoderDecoder reports whether the model is used as an encoder/decoder.
+	// Otherwise, the model is decoder-only: the generated sequence starts with
+	// the input, and MaxLength includes the length of the input.
 	IsEncoderDecoder bool
 	// BOSTokenID is the ID of the Beginning-Of-Sequence token.
 	BOSTokenID int
 	// EOSTokenID is the ID of the End-Of-Sequence token.
 	EOSTokenID int
-	// PadTokenID is the id of the padding token.
+	// PadTokenID is the id of the padding token, which is never generated.
+	// A negative value means that there is no padding token.
 	PadTokenID int
 	// VocabSize is the size of the vocabulary.
 	VocabSize int
@@ -39,9 +42,143 @@ type GeneratorConfig struct {
 	EarlyStopping bool
 	// BadWordsIDs is a list of token IDs that are not allowed to be generated.
 	BadWordsIDs [][]int
+	// RepetitionPenalty is the penalty for the tokens already present in the
+	// sequence (see RepetitionPenaltyProcessor). 0 means no penalty, as well as 1.0.
+	RepetitionPenalty mat.Float
+	// NoRepeatNGramSize is the size of the n-grams which can occur only once
+	// in the sequence. 0 means no limit.
+	NoRepeatNGramSize int
+	// ForceBOSToken reports whether to force BOSTokenID as the first generated token.
+	ForceBOSToken bool
+	// ForceEOSToken reports whether to force ForcedEOSTokenID as the last token when
+	// MaxLength is reached.
+	ForceEOSToken bool
+	// ForcedEOSTokenID is the ID of the token forced as the last one, if ForceEOSToken
+	// is true (usually EOSTokenID).
+	ForcedEOSTokenID int
+	// PrefixAllowedTokens, if not nil, constrains the generation to the tokens it
+	// returns, given the tokens generated so far.
+	PrefixAllowedTokens func(inputIDs []int) []int
+	// NumBeamGroups is the number of groups in which the beams are divided, for
+	// the diverse beam search. NumBeams must be a multiple of it. 0 means no groups,
+	// as well as 1.
+	NumBeamGroups int
+	// DiversityPenalty is subtracted from the score of a token, in a group of beams,
+	// for each time the token is selected by the previous groups at the same step.
+	// It is used only with NumBeamGroups > 1.
+	DiversityPenalty mat.Float
+	// LogitsProcessors are custom processors of the scores of the next tokens,
+	// applied after the ones of the configuration (see NewLogitsProcessors).
+	LogitsProcessors []LogitsProcessor
 	// MaxConcurrentComputations is the maximum number of concurrent computations
 	// handled by the generation search algorithm.
 	MaxConcurrentComputations int
 	// IncrementalForward indicates the graph usage mode.
 	IncrementalForward bool
+	// DoSample reports whether to sample the next tokens from the probability
+	// distribution of the model, instead of choosing the most probable ones.
+	// With NumBeams > 1, NumBeams sequences are kept at each step, sampling
+	// their next tokens among all the candidates of all the sequences.
+	DoSample bool
+	// Temperature is the value used to modulate the probability distribution
+	// when sampling: values < 1.0 make it sharper, values > 1.0 flatter.
+	// 0 means no modulation, as well as 1.0.
+	Temperature mat.Float
+	// TopK is the number of most probable tokens among which to sample
+	// the next token. 0 means no limit.
+	TopK int
+	// TopP is the minimum cumulative probability of the most probable tokens
+	// among which to sample the next token (nucleus sampling). 0 means no limit,
+	// as well as 1.0.
+	TopP mat.Float
+	// TypicalP is the minimum cumulative probability of the most typical tokens
+	// among which to sample the next token (typical sampling). 0 means no limit,
+	// as well as 1.0.
+	TypicalP mat.Float
+	// Seed is the seed of the random generator used for sampling, which makes
+	// the generation reproducible.
+	Seed uint64
+}
+
+// DecodingOption allows to configure the decoding strategy of a GeneratorConfig.
+type DecodingOption func(*GeneratorConfig)
+
+// BeamSearch sets the number of beams of the beam search.
+func BeamSearch(numBeams int) DecodingOption {
+	return func(c *GeneratorConfig) {
+		c.NumBeams = numBeams
+	}
+}
+
+// Sampling enables the sampling of the next tokens, with the given seed of the random generator.
+func Sampling(seed uint64) DecodingOption {
+	return func(c *GeneratorConfig) {
+		c.DoSample = true
+		c.Seed = seed
+	}
+}
+
+// Temperature sets the temperature used to modulate the probability distribution when sampling.
+func Temperature(temperature mat.Float) DecodingOption {
+	return func(c *GeneratorConfig) {
+		c.Temperature = temperature
+	}
+}
+
+// TopK sets the number of most probable tokens among which to sample the next token.
+func TopK(k int) DecodingOption {
+	return func(c *GeneratorConfig) {
+		c.TopK = k
+	}
+}
+
+// TopP sets the cumulative probability of the most probable tokens among which to sample the next token.
+func TopP(p mat.Float) DecodingOption {
+	return func(c *GeneratorConfig) {
+		c.TopP = p
+	}
+}
+
+// RepetitionPenalty sets the penalty for the tokens already present in the sequence.
+func RepetitionPenalty(penalty mat.Float) DecodingOption {
+	return func(c *GeneratorConfig) {
+		c.RepetitionPenalty = penalty
+	}
+}
+
+// NoRepeatNGramSize sets the size of the n-grams which can occur only once in the sequence.
+func NoRepeatNGramSize(size int) DecodingOption {
+	return func(c *GeneratorConfig) {
+		c.NoRepeatNGramSize = size
+	}
+}
+
+// DiverseBeamSearch divides the beams in groups, penalizing in each group the tokens selected by the
+// previous groups at the same step.
+func DiverseBeamSearch(numBeamGroups int, diversityPenalty mat.Float) DecodingOption {
+	return func(c *GeneratorConfig) {
+		c.NumBeamGroups = numBeamGroups
+		c.DiversityPenalty = diversityPenalty
+	}
+}
+
+// PrefixAllowedTokens constrains the generation to the tokens returned by the callback.
+func PrefixAllowedTokens(fn func(inputIDs []int) []int) DecodingOption {
+	return func(c *GeneratorConfig) {
+		c.PrefixAllowedTokens = fn
+	}
+}
+
+// WithLogitsProcessors adds custom processors of the scores of the next tokens.
+func WithLogitsProcessors(processors ...LogitsProcessor) DecodingOption {
+	return func(c *GeneratorConfig) {
+		c.LogitsProcessors = append(c.LogitsProcessors, processors...)
+	}
+}
+
+// TypicalP sets the cumulative probability of the most typical tokens among which to sample the next token.
+func TypicalP(p mat.Float) DecodingOption {
+	return func(c *GeneratorConfig) {
+		c.TypicalP = p
+	}
 }
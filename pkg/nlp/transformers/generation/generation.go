@@ -8,7 +8,9 @@
 package generation
 
 import (
+	"context"
 	mat "github.com/nlpodyssey/spago/pkg/mat32"
+	"github.com/nlpodyssey/spago/pkg/mat32/rand"
 	"github.com/nlpodyssey/spago/pkg/ml/ag"
 	"github.com/nlpodyssey/spago/pkg/utils/processingqueue"
 	"math"
@@ -19,73 +21,277 @@ import (
 // Generator is an implementation of a generation search algorithm for conditional generation.
 type Generator struct {
 	config          GeneratorConfig
-	model           EncoderDecoder
+	model           Model
 	processingQueue processingqueue.ProcessingQueue
+	processors      LogitsProcessors
 	padMask         ag.Node
-	eosMask         ag.Node
+	rndGen          *rand.LockedRand
 }
 
 // NewGenerator creates a new Generator object.
-func NewGenerator(config GeneratorConfig, model EncoderDecoder) *Generator {
+// The model must be an EncoderDecoder if config.IsEncoderDecoder is true.
+// The scores of the next tokens are processed by the LogitsProcessors corresponding to the configuration
+// (see NewLogitsProcessors).
+func NewGenerator(config GeneratorConfig, model Model) *Generator {
+	if _, ok := model.(Encoder); config.IsEncoderDecoder && !ok {
+		panic("generator: the model must be an encoder-decoder")
+	}
+	if config.NumBeamGroups > 1 && config.NumBeams%config.NumBeamGroups != 0 {
+		panic("generator: the number of beams must be a multiple of the number of beam groups")
+	}
 	return &Generator{
 		config:          config,
 		model:           model,
 		processingQueue: processingqueue.New(config.MaxConcurrentComputations),
+		processors:      NewLogitsProcessors(config),
 		padMask:         makePadMask(model.Graph(), config.PadTokenID, config.VocabSize),
-		eosMask:         makeEosMask(model.Graph(), config.EOSTokenID, config.VocabSize),
+		rndGen:          rand.NewLockedRand(config.Seed),
 	}
 }
 
+// StepFunc is called at each step of the generation with the best partial sequence generated so far,
+// which must not be modified. If it returns an error, the generation is stopped and the error is returned.
+type StepFunc func(tokenIDs []int) error
+
+// BatchStepFunc is like StepFunc, for the generation of a batch of sequences: it is called at each step
+// with the index of the input in the batch and the best partial sequence generated so far for it.
+type BatchStepFunc func(index int, tokenIDs []int) error
+
 // Generate generates sequences for models with a language modeling head, using
-// generation-search decoding.
+// either beam-search decoding or sampling (see GeneratorConfig.DoSample).
 func (b *Generator) Generate(inputIDs []int) []int {
-	if !b.config.IsEncoderDecoder {
-		panic("generator: unsupported architecture")
+	out, _ := b.GenerateContext(context.Background(), inputIDs, nil) // the errors can only come from ctx and onStep
+	return out
+}
+
+// GenerateContext is like Generate, but it stops the generation as soon as the context is done, returning
+// the error of the context, and it calls onStep (if not nil) at each step, to follow the generation while
+// it advances.
+func (b *Generator) GenerateContext(ctx context.Context, inputIDs []int, onStep StepFunc) ([]int, error) {
+	var batchOnStep BatchStepFunc
+	if onStep != nil {
+		batchOnStep = func(_ int, tokenIDs []int) error { return onStep(tokenIDs) }
+	}
+	out, err := b.GenerateBatch(ctx, [][]int{inputIDs}, batchOnStep)
+	if err != nil {
+		return nil, err
+	}
+	return out[0], nil
+}
+
+// GenerateBatch is like GenerateContext, but it generates a sequence for each input of the batch.
+// The inputs are encoded together, and each step of the decoding is performed at once for the beams of
+// all the inputs whose generation is not completed yet, while the beams, the hypotheses and the caches
+// are kept separate for each input. The result is the same as generating the sequences one at a time.
+func (b *Generator) GenerateBatch(ctx context.Context, inputs [][]int, onStep BatchStepFunc) ([][]int, error) {
+	if onStep == nil {
+		onStep = func(int, []int) error { return nil }
 	}
 
-	encodedInput := b.model.Encode(inputIDs)
+	states := b.makeDecodingStates(inputs, b.encodeBatch(inputs))
 	if !b.config.IncrementalForward {
 		b.performForward()
 	}
 
-	return b.beamSearch(NewScorer(b.config), encodedInput)
+	for {
+		active := make([]*decodingState, 0, len(states))
+		for _, s := range states {
+			if !s.done {
+				active = append(active, s)
+			}
+		}
+		if len(active) == 0 {
+			break
+		}
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		scores := b.decodeStep(active)
+		for i, s := range active {
+			if s.scorer != nil {
+				b.advanceBeams(s, scores[i])
+			} else {
+				b.advanceSampling(s, scores[i])
+			}
+			if s.done {
+				continue
+			}
+			if err := onStep(s.index, s.best()); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	outputs := make([][]int, len(states))
+	for i, s := range states {
+		outputs[i] = s.output
+	}
+	return outputs, nil
+}
+
+// encodeBatch encodes the inputs concurrently. There is no encoded input for decoder-only models.
+func (b *Generator) encodeBatch(inputs [][]int) [][]ag.Node {
+	encoded := make([][]ag.Node, len(inputs))
+	if !b.config.IsEncoderDecoder {
+		return encoded
+	}
+	encoder := b.model.(Encoder)
+	var wg sync.WaitGroup
+	wg.Add(len(inputs))
+	for i := range inputs {
+		i := i // redefine `i` in the inner scope, for using it in the goroutine
+		b.processingQueue.Go(func() {
+			defer wg.Done()
+			encoded[i] = encoder.Encode(inputs[i])
+		})
+	}
+	wg.Wait()
+	return encoded
+}
+
+// decodingState is the state of the generation of the sequence of a single input of a batch.
+type decodingState struct {
+	// index is the index of the input in the batch.
+	index        int
+	encodedInput []ag.Node
+	// scorer is nil when a single sequence is sampled.
+	scorer           *Scorer
+	decodingInputIDs [][]int
+	beamScores       []mat.Float
+	cache            []Cache
+	done             bool
+	output           []int
+}
+
+// makeDecodingStates returns the initial decoding state of each input.
+func (b *Generator) makeDecodingStates(inputs [][]int, encodedInputs [][]ag.Node) []*decodingState {
+	states := make([]*decodingState, len(inputs))
+	for i, encodedInput := range encodedInputs {
+		s := &decodingState{index: i, encodedInput: encodedInput}
+		startIDs := b.startDecodingInputIDs(inputs[i])
+		if b.config.DoSample && b.config.NumBeams <= 1 {
+			s.decodingInputIDs = [][]int{startIDs}
+		} else {
+			s.scorer = NewScorer(b.config)
+			s.decodingInputIDs = makeStartDecodingInputForBeamDecoding(startIDs, b.config.NumBeams)
+			s.beamScores = b.makeInitBeamScores()
+		}
+		s.cache = make([]Cache, len(s.decodingInputIDs))
+		if len(s.decodingInputIDs[0]) >= b.config.MaxLength {
+			b.finalize(s)
+		}
+		states[i] = s
+	}
+	return states
+}
+
+// best returns the best partial sequence generated so far.
+func (s *decodingState) best() []int {
+	if s.scorer == nil {
+		return s.decodingInputIDs[0]
+	}
+	return bestBeam(s.decodingInputIDs, s.beamScores)
+}
+
+// finalize marks the generation as completed, setting the output sequence.
+func (b *Generator) finalize(s *decodingState) {
+	s.done = true
+	if s.scorer == nil {
+		s.output = s.decodingInputIDs[0]
+		return
+	}
+	s.output = s.scorer.Finalize(s.decodingInputIDs, s.beamScores)
 }
 
-func (b *Generator) beamSearch(scorer *Scorer, encodedInput []ag.Node) []int {
+// decodeStep performs a decoding step for the beams of all the given states at once, returning the scores
+// of the next tokens of each state and updating their caches.
+func (b *Generator) decodeStep(states []*decodingState) [][]Scores {
 	var (
-		numBeams         = b.config.NumBeams
-		beamScores       = b.makeInitBeamScores()
-		decodingInputIDs = b.makeStartDecodingInputForBeamDecoding()
-		scores           = make([]Scores, numBeams)
-		cache            = make([]Cache, numBeams)
-		curLen           = len(decodingInputIDs[0])
+		encodedInputs    [][]ag.Node
+		decodingInputIDs [][]int
+		cache            []Cache
 	)
+	for _, s := range states {
+		for range s.decodingInputIDs {
+			encodedInputs = append(encodedInputs, s.encodedInput)
+		}
+		decodingInputIDs = append(decodingInputIDs, s.decodingInputIDs...)
+		cache = append(cache, s.cache...)
+	}
 
-	for curLen < b.config.MaxLength {
-		scores, cache = b.generateNext(encodedInput, decodingInputIDs, cache)
-		nextTokenScores := b.inhibitInvalidTokens(decodingInputIDs, scores)
-		updateTokensScores(nextTokenScores, beamScores)
-		scoredTokens := b.getTopKScoredTokens(nextTokenScores)
-		beamOutputs := scorer.Process(decodingInputIDs, scoredTokens)
-		beamScores = beamOutputs.nextBeamScores
-		decodingInputIDs = makeNewInputIDs(decodingInputIDs, beamOutputs)
-		cache = reorderCache(cache, beamOutputs.nextBeamIndices)
-
-		if scorer.IsDone() {
-			break
+	scores, nextCache := b.generateNext(encodedInputs, decodingInputIDs, cache)
+
+	result := make([][]Scores, len(states))
+	offset := 0
+	for i, s := range states {
+		n := len(s.decodingInputIDs)
+		result[i] = scores[offset : offset+n]
+		s.cache = nextCache[offset : offset+n]
+		offset += n
+	}
+	return result
+}
+
+// advanceBeams performs a step of the beam search. If the beams are divided in groups (diverse beam search),
+// the groups are processed in order, so that each group can depend on the tokens selected by the previous ones.
+func (b *Generator) advanceBeams(s *decodingState, scores []Scores) {
+	var (
+		numBeams      = b.config.NumBeams
+		numBeamGroups = b.numBeamGroups()
+		groupSize     = numBeams / numBeamGroups
+	)
+
+	nextBeamScores := make([]mat.Float, numBeams)
+	nextInputIDs := make([][]int, numBeams)
+	nextCache := make([]Cache, numBeams)
+	currentTokens := make([]int, 0, numBeams)
+
+	for group := 0; group < numBeamGroups; group++ {
+		start, end := group*groupSize, (group+1)*groupSize
+		groupInputIDs := s.decodingInputIDs[start:end]
+		nextTokenScores := b.processors.ProcessGroup(groupInputIDs, scores[start:end], group, currentTokens)
+		updateTokensScores(nextTokenScores, s.beamScores[start:end])
+		scoredTokens := b.selectScoredTokens(nextTokenScores, groupSize*2)
+		beamOutputs := s.scorer.Process(group, groupInputIDs, scoredTokens)
+		copy(nextBeamScores[start:end], beamOutputs.nextBeamScores)
+		copy(nextInputIDs[start:end], makeNewInputIDs(groupInputIDs, beamOutputs))
+		copy(nextCache[start:end], reorderCache(s.cache[start:end], beamOutputs.nextBeamIndices))
+		currentTokens = append(currentTokens, beamOutputs.nextBeamTokens...)
+	}
+	s.beamScores, s.decodingInputIDs, s.cache = nextBeamScores, nextInputIDs, nextCache
+
+	// the beams are finalized at the last step
+	if s.scorer.IsDone() || len(s.decodingInputIDs[0]) >= b.config.MaxLength {
+		b.finalize(s)
+	}
+}
+
+// bestBeam returns the sequence of the beam with the highest score.
+func bestBeam(inputIDs [][]int, beamScores []mat.Float) []int {
+	best := 0
+	for i, score := range beamScores {
+		if score > beamScores[best] {
+			best = i
 		}
-		curLen++
 	}
+	return inputIDs[best]
+}
 
-	return scorer.Finalize(decodingInputIDs, beamScores)
+// numBeamGroups returns the number of groups of beams, which is 1 if the diverse beam search is disabled.
+func (b *Generator) numBeamGroups() int {
+	if b.config.NumBeamGroups > 1 {
+		return b.config.NumBeamGroups
+	}
+	return 1
 }
 
+// generateNext decodes the next token of each sequence, given the encoded input it is conditioned on.
 func (b *Generator) generateNext(
-	encodedInput []ag.Node,
+	encodedInputs [][]ag.Node,
 	decodingInputIDs [][]int,
 	pastCache []Cache,
 ) ([]Scores, []Cache) {
-	numBeams := b.config.NumBeams
+	numBeams := len(decodingInputIDs)
 	logProbs := make([]ag.Node, numBeams)
 	logits := make([]ag.Node, numBeams)
 	scores := make([]Scores, numBeams)
@@ -97,8 +303,8 @@ func (b *Generator) generateNext(
 		i := i // redefine `i` in the inner scope, for using it in the goroutine
 		b.processingQueue.Go(func() {
 			defer wg.Done()
-			logits[i], nextCache[i] = b.model.Decode(encodedInput, decodingInputIDs[i], pastCache[i])
-			logits[i] = b.adjustLogitsDuringGeneration(logits[i], len(decodingInputIDs[i]))
+			logits[i], nextCache[i] = b.model.Decode(encodedInputs[i], decodingInputIDs[i], pastCache[i])
+			logits[i] = b.adjustLogitsDuringGeneration(logits[i])
 			logProbs[i] = b.model.Graph().LogSoftmax(logits[i])
 		})
 	}
@@ -121,8 +327,17 @@ func (b *Generator) performForward() {
 	g.IncTimeStep() // mark the next block to be computed from here on
 }
 
-func (b *Generator) getTopKScoredTokens(tokensScores []Scores) ScoredTokens {
-	resultSize := b.config.NumBeams * 2
+// selectScoredTokens selects n candidate tokens for the next beams: the most probable ones, or
+// sampled ones if sampling is enabled.
+func (b *Generator) selectScoredTokens(tokensScores []Scores, n int) ScoredTokens {
+	if !b.config.DoSample {
+		return getTopKScoredTokens(tokensScores, n)
+	}
+	b.warpScores(tokensScores)
+	return b.sampleScoredTokens(tokensScores, n)
+}
+
+func getTopKScoredTokens(tokensScores []Scores, resultSize int) ScoredTokens {
 	result := make(ScoredTokens, 0, resultSize+1)
 
 	var currentMinValue mat.Float = -math.MaxFloat32
@@ -183,43 +398,55 @@ func reorderCache(cache []Cache, nextBeamIndices []int) []Cache {
 	return reorderedCache
 }
 
-func (b *Generator) makeStartDecodingInputForBeamDecoding() [][]int {
-	beamInputIDs := make([][]int, b.config.NumBeams)
+// startDecodingInputIDs returns the initial sequence of the decoding: the decoder start token for
+// encoder-decoder models, otherwise the input itself (or the BOS token, if the input is empty).
+func (b *Generator) startDecodingInputIDs(inputIDs []int) []int {
+	if b.config.IsEncoderDecoder {
+		return []int{b.config.DecoderStartTokenID}
+	}
+	if len(inputIDs) == 0 {
+		return []int{b.config.BOSTokenID}
+	}
+	return append([]int(nil), inputIDs...)
+}
+
+func makeStartDecodingInputForBeamDecoding(startIDs []int, numBeams int) [][]int {
+	beamInputIDs := make([][]int, numBeams)
 	for i := range beamInputIDs {
-		beamInputIDs[i] = []int{b.config.DecoderStartTokenID}
+		beamInputIDs[i] = append([]int(nil), startIDs...)
 	}
 	return beamInputIDs
 }
 
+// makeInitBeamScores returns the initial scores of the beams, so that only the first beam of each group
+// is considered at the first step.
 func (b *Generator) makeInitBeamScores() []mat.Float {
 	numBeams := b.config.NumBeams
+	groupSize := numBeams / b.numBeamGroups()
 	beamScores := make([]mat.Float, numBeams)
-	beamScores[0] = 0
-	for i := 1; i < numBeams; i++ {
-		beamScores[i] = -1e9
+	for i := range beamScores {
+		if i%groupSize != 0 {
+			beamScores[i] = -1e9
+		}
 	}
 	return beamScores
 }
 
-func (b *Generator) adjustLogitsDuringGeneration(xs ag.Node, curLength int) ag.Node {
-	// Don't generate pad token
-	ys := b.model.Graph().Add(xs, b.padMask)
-
-	if curLength == b.config.MaxLength-1 && b.config.EOSTokenID >= 0 {
-		// Force EOS to be generated
-		ys = b.model.Graph().Add(ys, b.eosMask)
+func (b *Generator) adjustLogitsDuringGeneration(xs ag.Node) ag.Node {
+	if b.padMask == nil {
+		return xs
 	}
-	return ys
+	// Don't generate pad token
+	return b.model.Graph().Add(xs, b.padMask)
 }
 
+// makePadMask returns the mask which prevents the generation of the padding token, or nil if there
+// is no padding token.
 func makePadMask(g *ag.Graph, padTokenID int, vocabSize int) ag.Node {
+	if padTokenID < 0 {
+		return nil
+	}
 	mask := mat.NewInitVecDense(vocabSize, 0)
 	mask.SetVec(padTokenID, mat.Inf(-1))
 	return g.NewVariable(mask, false)
 }
-
-func makeEosMask(g *ag.Graph, eosTokenID int, vocabSize int) ag.Node {
-	mask := mat.NewInitVecDense(vocabSize, mat.Inf(-1))
-	mask.SetVec(eosTokenID, 0)
-	return g.NewVariable(mask, false)
-}
@@ -0,0 +1,135 @@
+// Copyright 2021 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package generation
+
+import (
+	"context"
+	"errors"
+	mat "github.com/nlpodyssey/spago/pkg/mat32"
+	"github.com/nlpodyssey/spago/pkg/ml/ag"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func newBeamSearchConfig() GeneratorConfig {
+	config := newSamplingConfig(0)
+	config.DoSample = false
+	config.NumBeams = 2
+	config.MaxLength = 6
+	return config
+}
+
+func TestGenerator_GenerateContext(t *testing.T) {
+	model := &fixedModel{g: ag.NewGraph(), logits: []mat.Float{-1, 0, 1, 2, 3, 2.5}}
+	var steps [][]int
+	out, err := NewGenerator(newBeamSearchConfig(), model).GenerateContext(context.Background(), []int{3},
+		func(tokenIDs []int) error {
+			steps = append(steps, append([]int(nil), tokenIDs...))
+			return nil
+		})
+	assert.NoError(t, err)
+	assert.Equal(t, []int{2, 4, 4, 4, 4, 0}, out)
+	assert.Equal(t, [][]int{{2, 4}, {2, 4, 4}, {2, 4, 4, 4}, {2, 4, 4, 4, 4}}, steps)
+}
+
+func TestGenerator_GenerateContext_Stop(t *testing.T) {
+	model := &fixedModel{g: ag.NewGraph(), logits: []mat.Float{-1, 0, 1, 2, 3, 2.5}}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	steps := 0
+	_, err := NewGenerator(newBeamSearchConfig(), model).GenerateContext(ctx, []int{3}, func([]int) error {
+		steps++
+		cancel()
+		return nil
+	})
+	assert.Equal(t, context.Canceled, err)
+	assert.Equal(t, 1, steps)
+
+	errStop := errors.New("stop")
+	_, err = NewGenerator(newSamplingConfig(1), model).GenerateContext(context.Background(), []int{3},
+		func([]int) error {
+			return errStop
+		})
+	assert.Equal(t, errStop, err)
+}
+
+// countingModel is an EncoderDecoder which predicts the token given as input, until the sequence has
+// as many tokens as the value of the token, and then the EOS token (0).
+type countingModel struct {
+	g *ag.Graph
+}
+
+func (m *countingModel) Graph() *ag.Graph { return m.g }
+
+func (m *countingModel) Encode(inputIDs []int) []ag.Node {
+	return []ag.Node{m.g.NewScalar(mat.Float(inputIDs[0]))}
+}
+
+func (m *countingModel) Decode(encoded []ag.Node, ids []int, _ Cache) (ag.Node, Cache) {
+	tokenID := int(encoded[0].ScalarValue())
+	logits := mat.NewInitVecDense(6, -10)
+	if len(ids) < tokenID {
+		logits.SetVec(tokenID, 10)
+	} else {
+		logits.SetVec(0, 10)
+	}
+	return m.g.NewVariable(logits, false), nil
+}
+
+func TestGenerator_GenerateBatch(t *testing.T) {
+	inputs := [][]int{{3}, {5}, {4}}
+	for _, config := range []GeneratorConfig{newBeamSearchConfig(), newSamplingConfig(1)} {
+		var steps []int
+		out, err := NewGenerator(config, &countingModel{g: ag.NewGraph()}).GenerateBatch(context.Background(), inputs,
+			func(index int, _ []int) error {
+				steps = append(steps, index)
+				return nil
+			})
+		assert.NoError(t, err)
+		assert.Equal(t, [][]int{{2, 3, 3, 0}, {2, 5, 5, 5, 5, 0}, {2, 4, 4, 4, 0}}, out)
+		if config.DoSample {
+			assert.Equal(t, []int{0, 1, 2, 0, 1, 2, 1, 2, 1}, steps) // the completed inputs are left out
+		}
+
+		for i, input := range inputs {
+			assert.Equal(t, out[i], NewGenerator(config, &countingModel{g: ag.NewGraph()}).Generate(input))
+		}
+	}
+}
+
+// nextTokenModel is a decoder-only model which predicts the token following the last one, up to the
+// last token of the vocabulary, and then the EOS token (0).
+type nextTokenModel struct {
+	g *ag.Graph
+}
+
+func (m *nextTokenModel) Graph() *ag.Graph { return m.g }
+
+func (m *nextTokenModel) Decode(encoded []ag.Node, ids []int, _ Cache) (ag.Node, Cache) {
+	if encoded != nil {
+		panic("unexpected encoded input")
+	}
+	logits := mat.NewInitVecDense(6, -10)
+	if next := ids[len(ids)-1] + 1; next < 6 {
+		logits.SetVec(next, 10)
+	} else {
+		logits.SetVec(0, 10)
+	}
+	return m.g.NewVariable(logits, false), nil
+}
+
+func TestGenerator_DecoderOnly(t *testing.T) {
+	for _, config := range []GeneratorConfig{newBeamSearchConfig(), newSamplingConfig(1)} {
+		config.IsEncoderDecoder = false
+		config.BOSTokenID = 2
+		generator := NewGenerator(config, &nextTokenModel{g: ag.NewGraph()})
+		assert.Equal(t, []int{3, 4, 5, 0}, generator.Generate([]int{3}))
+		assert.Equal(t, []int{2, 3, 4, 5, 0}, generator.Generate(nil)) // starts from BOS
+	}
+
+	assert.Panics(t, func() {
+		NewGenerator(newBeamSearchConfig(), &nextTokenModel{g: ag.NewGraph()})
+	})
+}
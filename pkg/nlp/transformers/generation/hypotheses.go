@@ -13,6 +13,7 @@ import (
 // Hypotheses provides hypotheses data for a generation Scorer.
 type Hypotheses struct {
 	config     GeneratorConfig
+	numBeams   int
 	beams      []Hypothesis
 	worstScore mat.Float
 }
@@ -26,10 +27,11 @@ type Hypothesis struct {
 
 const defaultHypothesisWorstScore mat.Float = 1e9
 
-// NewHypotheses returns a new Hypotheses.
-func NewHypotheses(config GeneratorConfig) *Hypotheses {
+// NewHypotheses returns a new Hypotheses, which keeps at most numBeams hypotheses.
+func NewHypotheses(config GeneratorConfig, numBeams int) *Hypotheses {
 	return &Hypotheses{
 		config:     config,
+		numBeams:   numBeams,
 		beams:      make([]Hypothesis, 0),
 		worstScore: 1e9,
 	}
@@ -43,12 +45,12 @@ func (h *Hypotheses) Len() int {
 // Add adds a new hypothesis to the list.
 func (h *Hypotheses) Add(hypVector []int, sumLogProbs mat.Float) {
 	score := sumLogProbs / mat.Pow(mat.Float(len(hypVector)), h.config.LengthPenalty)
-	if h.Len() == h.config.NumBeams && score <= h.worstScore {
+	if h.Len() == h.numBeams && score <= h.worstScore {
 		return
 	}
 
 	h.beams = append(h.beams, Hypothesis{TokenIDs: hypVector, Score: score})
-	if h.Len() <= h.config.NumBeams {
+	if h.Len() <= h.numBeams {
 		if score < h.worstScore {
 			h.worstScore = score
 		}
@@ -82,7 +84,7 @@ func (h *Hypotheses) findWorst() (worstScore mat.Float, worstIndex int, ok bool)
 // IsDone reports whether there are enough hypotheses and none of the hypotheses
 // being generated can become better than the worst one in the heap.
 func (h *Hypotheses) IsDone(bestSumLogProbs mat.Float, curLen int) bool {
-	if h.Len() < h.config.NumBeams {
+	if h.Len() < h.numBeams {
 		return false
 	}
 	if h.config.EarlyStopping {
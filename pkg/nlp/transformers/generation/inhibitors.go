@@ -11,20 +11,19 @@ import (
 	"github.com/nlpodyssey/spago/pkg/utils"
 )
 
-func (b *Generator) inhibitInvalidTokens(inputIDs [][]int, scores []Scores) []Scores {
-	if b.config.MinLength >= 0 && b.config.EOSTokenID >= 0 {
-		scores = b.processMinLengthScores(inputIDs, scores)
-	}
-	if len(b.config.BadWordsIDs) > 0 {
-		scores = b.processBadWordsScores(inputIDs, scores)
-	}
-	return scores
+// BadWordsProcessor prevents the generation of the given sequences of tokens.
+type BadWordsProcessor struct {
+	// BadWordsIDs is a list of token IDs sequences that are not allowed to be generated.
+	BadWordsIDs [][]int
+	// EOSTokenID is the ID of the End-Of-Sequence token, which is never banned alone.
+	EOSTokenID int
 }
 
-func (b *Generator) processBadWordsScores(inputIDs [][]int, scores []Scores) []Scores {
-	BadWordsIDs := make([][]int, 0, len(b.config.BadWordsIDs))
-	for _, v := range b.config.BadWordsIDs {
-		if len(v) == 1 && v[0] == b.config.EOSTokenID {
+// Process satisfies the LogitsProcessor interface.
+func (p BadWordsProcessor) Process(inputIDs [][]int, scores []Scores) []Scores {
+	BadWordsIDs := make([][]int, 0, len(p.BadWordsIDs))
+	for _, v := range p.BadWordsIDs {
+		if len(v) == 1 && v[0] == p.EOSTokenID {
 			continue
 		}
 		BadWordsIDs = append(BadWordsIDs, v)
@@ -65,13 +64,22 @@ func bannedTokensMatch(prevTokens []int, bannedTokens []int) bool {
 	return utils.IntSliceEqual(prevTokens[len(prevTokens)-len(bannedTokens):], bannedTokens)
 }
 
-func (b *Generator) processMinLengthScores(inputIDs [][]int, scores []Scores) []Scores {
+// MinLengthProcessor prevents the generation of the EOS token before the minimum length is reached.
+type MinLengthProcessor struct {
+	// MinLength is the minimum length of the sequences.
+	MinLength int
+	// EOSTokenID is the ID of the End-Of-Sequence token.
+	EOSTokenID int
+}
+
+// Process satisfies the LogitsProcessor interface.
+func (p MinLengthProcessor) Process(inputIDs [][]int, scores []Scores) []Scores {
 	curLen := len(inputIDs[0])
-	if curLen >= b.config.MinLength {
+	if curLen >= p.MinLength {
 		return scores
 	}
 
-	eosTokenID := b.config.EOSTokenID
+	eosTokenID := p.EOSTokenID
 	for _, n := range scores {
 		n.SetVec(eosTokenID, mat.Inf(-1))
 	}
@@ -0,0 +1,238 @@
+// Copyright 2021 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+//
+// Additional copyright notes in the package README.
+
+package generation
+
+import (
+	mat "github.com/nlpodyssey/spago/pkg/mat32"
+	"github.com/nlpodyssey/spago/pkg/utils"
+)
+
+// LogitsProcessor modifies the scores (log probabilities) of the next tokens of the sequences being
+// generated, for example to prevent the generation of some tokens.
+type LogitsProcessor interface {
+	// Process modifies the scores of the next tokens of each sequence, and returns them.
+	// The scores can be modified in place.
+	Process(inputIDs [][]int, scores []Scores) []Scores
+}
+
+// GroupLogitsProcessor is a LogitsProcessor which also depends on the tokens selected at the current
+// step by the previous groups of beams, used by the diverse beam search (see GeneratorConfig.NumBeamGroups).
+type GroupLogitsProcessor interface {
+	LogitsProcessor
+	// ProcessGroup modifies the scores of the next tokens of each sequence of the group, and returns them.
+	// The previousTokens are the tokens selected at the current step by the groups which precede groupIndex.
+	ProcessGroup(inputIDs [][]int, scores []Scores, groupIndex int, previousTokens []int) []Scores
+}
+
+// LogitsProcessorFunc is an adapter to allow the use of an ordinary function as LogitsProcessor.
+type LogitsProcessorFunc func(inputIDs [][]int, scores []Scores) []Scores
+
+// Process calls f(inputIDs, scores).
+func (f LogitsProcessorFunc) Process(inputIDs [][]int, scores []Scores) []Scores {
+	return f(inputIDs, scores)
+}
+
+// LogitsProcessors is a pipeline of LogitsProcessor, applied in order.
+type LogitsProcessors []LogitsProcessor
+
+// Process applies the processors in order.
+func (ps LogitsProcessors) Process(inputIDs [][]int, scores []Scores) []Scores {
+	return ps.ProcessGroup(inputIDs, scores, 0, nil)
+}
+
+// ProcessGroup applies the processors in order, using the group information for the GroupLogitsProcessor.
+func (ps LogitsProcessors) ProcessGroup(inputIDs [][]int, scores []Scores, groupIndex int, previousTokens []int) []Scores {
+	for _, p := range ps {
+		if gp, ok := p.(GroupLogitsProcessor); ok {
+			scores = gp.ProcessGroup(inputIDs, scores, groupIndex, previousTokens)
+			continue
+		}
+		scores = p.Process(inputIDs, scores)
+	}
+	return scores
+}
+
+// NewLogitsProcessors returns the pipeline of LogitsProcessor corresponding to the configuration, followed by
+// the custom processors of the configuration.
+func NewLogitsProcessors(config GeneratorConfig) LogitsProcessors {
+	var ps LogitsProcessors
+	if config.NumBeamGroups > 1 && config.DiversityPenalty != 0 {
+		ps = append(ps, HammingDiversityProcessor{Penalty: config.DiversityPenalty})
+	}
+	if config.RepetitionPenalty != 0 && config.RepetitionPenalty != 1 {
+		ps = append(ps, RepetitionPenaltyProcessor{Penalty: config.RepetitionPenalty})
+	}
+	if config.NoRepeatNGramSize > 0 {
+		ps = append(ps, NoRepeatNGramProcessor{Size: config.NoRepeatNGramSize})
+	}
+	if len(config.BadWordsIDs) > 0 {
+		ps = append(ps, BadWordsProcessor{BadWordsIDs: config.BadWordsIDs, EOSTokenID: config.EOSTokenID})
+	}
+	if config.MinLength >= 0 && config.EOSTokenID >= 0 {
+		ps = append(ps, MinLengthProcessor{MinLength: config.MinLength, EOSTokenID: config.EOSTokenID})
+	}
+	if config.PrefixAllowedTokens != nil {
+		ps = append(ps, PrefixConstrainedProcessor{AllowedTokens: config.PrefixAllowedTokens})
+	}
+	if config.ForceBOSToken && config.BOSTokenID >= 0 {
+		ps = append(ps, ForcedBOSTokenProcessor{BOSTokenID: config.BOSTokenID})
+	}
+	if config.ForceEOSToken && config.ForcedEOSTokenID >= 0 {
+		ps = append(ps, ForcedEOSTokenProcessor{MaxLength: config.MaxLength, EOSTokenID: config.ForcedEOSTokenID})
+	}
+	return append(ps, config.LogitsProcessors...)
+}
+
+// RepetitionPenaltyProcessor penalizes the tokens already present in the sequence: the scores lower than
+// zero are multiplied by the penalty, the others are divided (see "CTRL: A Conditional Transformer Language
+// Model for Controllable Generation", Keskar et al., 2019).
+type RepetitionPenaltyProcessor struct {
+	// Penalty is the repetition penalty: 1.0 means no penalty, values > 1.0 discourage the repetitions.
+	Penalty mat.Float
+}
+
+// Process satisfies the LogitsProcessor interface.
+func (p RepetitionPenaltyProcessor) Process(inputIDs [][]int, scores []Scores) []Scores {
+	for i, sequence := range inputIDs {
+		data := scores[i].Data()
+		penalized := make(map[int]bool, len(sequence))
+		for _, tokenID := range sequence {
+			if penalized[tokenID] {
+				continue
+			}
+			penalized[tokenID] = true
+			if data[tokenID] < 0 {
+				data[tokenID] *= p.Penalty
+			} else {
+				data[tokenID] /= p.Penalty
+			}
+		}
+	}
+	return scores
+}
+
+// NoRepeatNGramProcessor prevents the repetition of the n-grams of the given size within the sequence.
+type NoRepeatNGramProcessor struct {
+	// Size is the size of the n-grams which can occur only once.
+	Size int
+}
+
+// Process satisfies the LogitsProcessor interface.
+func (p NoRepeatNGramProcessor) Process(inputIDs [][]int, scores []Scores) []Scores {
+	n := p.Size
+	for i, sequence := range inputIDs {
+		if len(sequence)+1 < n {
+			continue
+		}
+		prefix := sequence[len(sequence)-n+1:] // the last n-1 tokens
+		for start := 0; start+n <= len(sequence); start++ {
+			if utils.IntSliceEqual(sequence[start:start+n-1], prefix) {
+				scores[i].SetVec(sequence[start+n-1], mat.Inf(-1))
+			}
+		}
+	}
+	return scores
+}
+
+// PrefixConstrainedProcessor constrains the generation to the tokens allowed by a callback, which can
+// depend on the tokens generated so far.
+type PrefixConstrainedProcessor struct {
+	// AllowedTokens returns the IDs of the tokens allowed after the given sequence.
+	AllowedTokens func(inputIDs []int) []int
+}
+
+// Process satisfies the LogitsProcessor interface.
+func (p PrefixConstrainedProcessor) Process(inputIDs [][]int, scores []Scores) []Scores {
+	for i, sequence := range inputIDs {
+		data := scores[i].Data()
+		allowed := make(map[int]bool)
+		for _, tokenID := range p.AllowedTokens(sequence) {
+			allowed[tokenID] = true
+		}
+		for tokenID := range data {
+			if !allowed[tokenID] {
+				data[tokenID] = mat.Inf(-1)
+			}
+		}
+	}
+	return scores
+}
+
+// ForcedBOSTokenProcessor forces the BOS token to be the first generated token.
+type ForcedBOSTokenProcessor struct {
+	// BOSTokenID is the ID of the token to force.
+	BOSTokenID int
+}
+
+// Process satisfies the LogitsProcessor interface.
+func (p ForcedBOSTokenProcessor) Process(inputIDs [][]int, scores []Scores) []Scores {
+	if len(inputIDs[0]) == 1 {
+		forceToken(scores, p.BOSTokenID)
+	}
+	return scores
+}
+
+// ForcedEOSTokenProcessor forces the EOS token to be the last token, when the maximum length is reached.
+type ForcedEOSTokenProcessor struct {
+	// MaxLength is the maximum length of the sequences.
+	MaxLength int
+	// EOSTokenID is the ID of the token to force.
+	EOSTokenID int
+}
+
+// Process satisfies the LogitsProcessor interface.
+func (p ForcedEOSTokenProcessor) Process(inputIDs [][]int, scores []Scores) []Scores {
+	if len(inputIDs[0]) == p.MaxLength-1 {
+		forceToken(scores, p.EOSTokenID)
+	}
+	return scores
+}
+
+// forceToken sets the score of the token to zero (probability 1) and the other scores to -Inf.
+func forceToken(scores []Scores, tokenID int) {
+	for _, s := range scores {
+		data := s.Data()
+		for i := range data {
+			data[i] = mat.Inf(-1)
+		}
+		data[tokenID] = 0
+	}
+}
+
+// HammingDiversityProcessor penalizes, in each group of beams of the diverse beam search, the tokens
+// selected at the same step by the previous groups, proportionally to the number of times they were
+// selected (see "Diverse Beam Search: Decoding Diverse Solutions from Neural Sequence Models",
+// Vijayakumar et al., 2016).
+type HammingDiversityProcessor struct {
+	// Penalty is subtracted from the score of a token for each time it was selected by the previous groups.
+	Penalty mat.Float
+}
+
+var _ GroupLogitsProcessor = HammingDiversityProcessor{}
+
+// Process satisfies the LogitsProcessor interface. It doesn't modify the scores, since there are no groups.
+func (p HammingDiversityProcessor) Process(_ [][]int, scores []Scores) []Scores {
+	return scores
+}
+
+// ProcessGroup satisfies the GroupLogitsProcessor interface.
+func (p HammingDiversityProcessor) ProcessGroup(_ [][]int, scores []Scores, groupIndex int, previousTokens []int) []Scores {
+	if groupIndex == 0 || len(previousTokens) == 0 {
+		return scores
+	}
+	frequency := make(map[int]int, len(previousTokens))
+	for _, tokenID := range previousTokens {
+		frequency[tokenID]++
+	}
+	for _, s := range scores {
+		data := s.Data()
+		for tokenID, count := range frequency {
+			data[tokenID] -= p.Penalty * mat.Float(count)
+		}
+	}
+	return scores
+}
@@ -0,0 +1,99 @@
+// Copyright 2021 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package generation
+
+import (
+	mat "github.com/nlpodyssey/spago/pkg/mat32"
+	"github.com/nlpodyssey/spago/pkg/ml/ag"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func newTestScores(values ...[]mat.Float) []Scores {
+	scores := make([]Scores, len(values))
+	for i, v := range values {
+		scores[i] = mat.NewVecDense(append([]mat.Float(nil), v...))
+	}
+	return scores
+}
+
+func TestRepetitionPenaltyProcessor(t *testing.T) {
+	scores := newTestScores([]mat.Float{-1, 2, -3, 4})
+	RepetitionPenaltyProcessor{Penalty: 2}.Process([][]int{{0, 1, 1}}, scores)
+	assert.Equal(t, []mat.Float{-2, 1, -3, 4}, scores[0].Data())
+}
+
+func TestNoRepeatNGramProcessor(t *testing.T) {
+	scores := newTestScores([]mat.Float{0, 0, 0, 0}, []mat.Float{0, 0, 0, 0})
+	NoRepeatNGramProcessor{Size: 2}.Process([][]int{{1, 2, 1}, {1, 2, 3}}, scores)
+	assert.Equal(t, []mat.Float{0, 0, mat.Inf(-1), 0}, scores[0].Data())
+	assert.Equal(t, []mat.Float{0, 0, 0, 0}, scores[1].Data())
+}
+
+func TestPrefixConstrainedProcessor(t *testing.T) {
+	scores := newTestScores([]mat.Float{-1, -2, -3, -4})
+	PrefixConstrainedProcessor{
+		AllowedTokens: func(inputIDs []int) []int {
+			return []int{inputIDs[len(inputIDs)-1] + 1}
+		},
+	}.Process([][]int{{0, 1}}, scores)
+	assert.Equal(t, []mat.Float{mat.Inf(-1), mat.Inf(-1), -3, mat.Inf(-1)}, scores[0].Data())
+}
+
+func TestForcedTokenProcessors(t *testing.T) {
+	scores := newTestScores([]mat.Float{-1, -2, -3})
+	ForcedBOSTokenProcessor{BOSTokenID: 1}.Process([][]int{{2}}, scores)
+	assert.Equal(t, []mat.Float{mat.Inf(-1), 0, mat.Inf(-1)}, scores[0].Data())
+
+	scores = newTestScores([]mat.Float{-1, -2, -3})
+	ForcedEOSTokenProcessor{MaxLength: 5, EOSTokenID: 2}.Process([][]int{{2, 1, 1}}, scores)
+	assert.Equal(t, []mat.Float{-1, -2, -3}, scores[0].Data())
+	ForcedEOSTokenProcessor{MaxLength: 5, EOSTokenID: 2}.Process([][]int{{2, 1, 1, 1}}, scores)
+	assert.Equal(t, []mat.Float{mat.Inf(-1), mat.Inf(-1), 0}, scores[0].Data())
+}
+
+func TestNewLogitsProcessors_ForcedEOSToken(t *testing.T) {
+	config := GeneratorConfig{MinLength: -1, MaxLength: 5, EOSTokenID: 2}
+	assert.Empty(t, NewLogitsProcessors(config), "the zero value forces no token")
+
+	config.ForceEOSToken, config.ForcedEOSTokenID = true, 2
+	assert.Equal(t, LogitsProcessors{ForcedEOSTokenProcessor{MaxLength: 5, EOSTokenID: 2}}, NewLogitsProcessors(config))
+}
+
+func TestLogitsProcessors_ProcessGroup(t *testing.T) {
+	ps := LogitsProcessors{
+		HammingDiversityProcessor{Penalty: 0.5},
+		LogitsProcessorFunc(func(_ [][]int, scores []Scores) []Scores {
+			scores[0].SetVec(0, mat.Inf(-1))
+			return scores
+		}),
+	}
+	scores := ps.ProcessGroup([][]int{{0}}, newTestScores([]mat.Float{-1, -1, -1}), 1, []int{1, 1, 2})
+	assert.Equal(t, []mat.Float{mat.Inf(-1), -2, -1.5}, scores[0].Data())
+
+	scores = ps.ProcessGroup([][]int{{0}}, newTestScores([]mat.Float{-1, -1, -1}), 0, nil)
+	assert.Equal(t, []mat.Float{mat.Inf(-1), -1, -1}, scores[0].Data())
+}
+
+func TestGenerator_DiverseBeamSearch(t *testing.T) {
+	config := GeneratorConfig{
+		NumBeams:                  4,
+		NumBeamGroups:             2,
+		DiversityPenalty:          10,
+		MaxLength:                 5,
+		IsEncoderDecoder:          true,
+		EOSTokenID:                0,
+		PadTokenID:                1,
+		VocabSize:                 6,
+		DecoderStartTokenID:       2,
+		LengthPenalty:             1.0,
+		MaxConcurrentComputations: 1,
+		IncrementalForward:        true,
+		NoRepeatNGramSize:         1,
+	}
+	model := &fixedModel{g: ag.NewGraph(), logits: []mat.Float{-1, 0, 1, 2, 3, 2.5}}
+	out := NewGenerator(config, model).Generate([]int{3})
+	assert.Equal(t, []int{2, 4, 5, 3, 0}, out) // no repetitions
+}
@@ -0,0 +1,209 @@
+// Copyright 2021 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+//
+// Additional copyright notes in the package README.
+
+package generation
+
+import (
+	mat "github.com/nlpodyssey/spago/pkg/mat32"
+	"sort"
+)
+
+// advanceSampling performs a step of the generation of a single sequence, sampling the next token from
+// the (warped) probability distribution.
+func (b *Generator) advanceSampling(s *decodingState, scores []Scores) {
+	nextTokenScores := b.processors.Process(s.decodingInputIDs, scores)
+	b.warpScores(nextTokenScores)
+	sampled := b.sampleScoredTokens(nextTokenScores, 1)
+	if len(sampled) == 0 {
+		b.finalize(s) // no allowed tokens
+		return
+	}
+	tokenID := sampled[0].TokenIndex
+	s.decodingInputIDs[0] = append(s.decodingInputIDs[0], tokenID)
+	if tokenID == b.config.EOSTokenID || len(s.decodingInputIDs[0]) >= b.config.MaxLength {
+		b.finalize(s)
+	}
+}
+
+// warpScores modifies the scores of the next tokens of each sequence according to the sampling settings,
+// in the order: temperature, top-k, top-p (nucleus) and typical filtering.
+func (b *Generator) warpScores(tokensScores []Scores) {
+	for _, scores := range tokensScores {
+		if t := b.config.Temperature; t > 0 && t != 1 {
+			scores.ProdScalarInPlace(1 / t)
+		}
+		if k := b.config.TopK; k > 0 {
+			filterTopK(scores.Data(), k)
+		}
+		if p := b.config.TopP; p > 0 && p < 1 {
+			filterTopP(scores.Data(), p)
+		}
+		if p := b.config.TypicalP; p > 0 && p < 1 {
+			filterTypical(scores.Data(), p)
+		}
+	}
+}
+
+// sampleScoredTokens samples n distinct tokens among all the sequences, according to the probability
+// distribution given by the softmax of their scores. The result is sorted by descending scores, and it has
+// less than n tokens if there are not enough tokens with a non-zero probability.
+func (b *Generator) sampleScoredTokens(tokensScores []Scores, n int) ScoredTokens {
+	candidates := make(ScoredTokens, 0)
+	scores := make([]mat.Float, 0)
+	for beamIndex, s := range tokensScores {
+		for tokenIndex, score := range s.Data() {
+			if mat.IsInf(score, -1) {
+				continue
+			}
+			candidates = append(candidates, &ScoredToken{
+				BeamIndex:  beamIndex,
+				TokenIndex: tokenIndex,
+				Score:      score,
+			})
+			scores = append(scores, score)
+		}
+	}
+	probs := softmax(scores)
+
+	result := make(ScoredTokens, 0, n)
+	for len(result) < n && len(result) < len(candidates) {
+		var sum mat.Float
+		for _, p := range probs {
+			sum += p
+		}
+		i := weightedChoice(probs, mat.Float(b.rndGen.Float())*sum)
+		result = append(result, candidates[i])
+		probs[i] = 0 // sampling without replacement
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Score > result[j].Score
+	})
+	return result
+}
+
+// weightedChoice returns the index of the first element for which the cumulative sum of the weights
+// exceeds r, or the index of the last non-zero weight (rounding errors).
+func weightedChoice(weights []mat.Float, r mat.Float) int {
+	var cumulative mat.Float
+	last := 0
+	for i, w := range weights {
+		if w == 0 {
+			continue
+		}
+		cumulative += w
+		if r < cumulative {
+			return i
+		}
+		last = i
+	}
+	return last
+}
+
+// filterTopK keeps the k tokens with the highest scores, setting the scores of the other tokens to -Inf.
+// The ties are broken in favor of the tokens with the lowest index, so that exactly k tokens are kept.
+func filterTopK(scores []mat.Float, k int) {
+	if k >= len(scores) {
+		return
+	}
+	indices := sortedIndices(len(scores), func(i, j int) bool {
+		return scores[i] > scores[j]
+	})
+	for _, i := range indices[k:] {
+		scores[i] = mat.Inf(-1)
+	}
+}
+
+// filterTopP keeps the smallest set of most probable tokens whose cumulative probability reaches p,
+// setting the scores of the other tokens to -Inf.
+func filterTopP(scores []mat.Float, p mat.Float) {
+	probs := softmax(scores)
+	indices := sortedIndices(len(scores), func(i, j int) bool {
+		return scores[i] > scores[j]
+	})
+	var cumulative mat.Float
+	keep := len(indices)
+	for rank, i := range indices {
+		cumulative += probs[i]
+		if cumulative >= p {
+			keep = rank + 1
+			break
+		}
+	}
+	for _, i := range indices[keep:] {
+		scores[i] = mat.Inf(-1)
+	}
+}
+
+// filterTypical keeps the smallest set of tokens whose information content is the closest to the expected
+// one (the entropy of the distribution), and whose cumulative probability reaches p, setting the scores of
+// the other tokens to -Inf (see "Locally Typical Sampling", Meister et al., 2022).
+func filterTypical(scores []mat.Float, p mat.Float) {
+	probs := softmax(scores)
+	var entropy mat.Float
+	for _, prob := range probs {
+		if prob > 0 {
+			entropy -= prob * mat.Log(prob)
+		}
+	}
+	shifted := make([]mat.Float, len(probs))
+	for i, prob := range probs {
+		shifted[i] = mat.Inf(1)
+		if prob > 0 {
+			shifted[i] = mat.Abs(-mat.Log(prob) - entropy)
+		}
+	}
+	indices := sortedIndices(len(scores), func(i, j int) bool {
+		return shifted[i] < shifted[j]
+	})
+	var cumulative mat.Float
+	keep := len(indices)
+	for rank, i := range indices {
+		cumulative += probs[i]
+		if cumulative >= p {
+			keep = rank + 1
+			break
+		}
+	}
+	for _, i := range indices[keep:] {
+		scores[i] = mat.Inf(-1)
+	}
+}
+
+// sortedIndices returns the indices from 0 to n-1, sorted according to the less function.
+func sortedIndices(n int, less func(i, j int) bool) []int {
+	indices := make([]int, n)
+	for i := range indices {
+		indices[i] = i
+	}
+	sort.SliceStable(indices, func(i, j int) bool {
+		return less(indices[i], indices[j])
+	})
+	return indices
+}
+
+// softmax returns the softmax of the scores, where the scores equal to -Inf have zero probability.
+func softmax(scores []mat.Float) []mat.Float {
+	max := mat.Inf(-1)
+	for _, score := range scores {
+		if score > max {
+			max = score
+		}
+	}
+	probs := make([]mat.Float, len(scores))
+	if mat.IsInf(max, -1) {
+		return probs
+	}
+	var sum mat.Float
+	for i, score := range scores {
+		probs[i] = mat.Exp(score - max)
+		sum += probs[i]
+	}
+	for i := range probs {
+		probs[i] /= sum
+	}
+	return probs
+}
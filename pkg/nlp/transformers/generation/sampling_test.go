@@ -0,0 +1,113 @@
+// Copyright 2021 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package generation
+
+import (
+	mat "github.com/nlpodyssey/spago/pkg/mat32"
+	"github.com/nlpodyssey/spago/pkg/ml/ag"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+// fixedModel is an EncoderDecoder which always predicts the same logits.
+type fixedModel struct {
+	g      *ag.Graph
+	logits []mat.Float
+}
+
+func (m *fixedModel) Graph() *ag.Graph { return m.g }
+
+func (m *fixedModel) Encode(inputIDs []int) []ag.Node {
+	return []ag.Node{m.g.NewScalar(0)}
+}
+
+func (m *fixedModel) Decode(_ []ag.Node, _ []int, _ Cache) (ag.Node, Cache) {
+	return m.g.NewVariable(mat.NewVecDense(m.logits), false), nil
+}
+
+func newSamplingConfig(seed uint64) GeneratorConfig {
+	return GeneratorConfig{
+		NumBeams:                  1,
+		MaxLength:                 20,
+		IsEncoderDecoder:          true,
+		EOSTokenID:                0,
+		PadTokenID:                1,
+		VocabSize:                 6,
+		DecoderStartTokenID:       2,
+		LengthPenalty:             1.0,
+		ForceEOSToken:             true,
+		ForcedEOSTokenID:          0,
+		MaxConcurrentComputations: 1,
+		IncrementalForward:        true,
+		DoSample:                  true,
+		Seed:                      seed,
+	}
+}
+
+func TestGenerator_Sampling(t *testing.T) {
+	logits := []mat.Float{-1, 0, 1, 2, 3, 2.5}
+	generate := func(config GeneratorConfig) []int {
+		return NewGenerator(config, &fixedModel{g: ag.NewGraph(), logits: logits}).Generate([]int{3})
+	}
+
+	a := generate(newSamplingConfig(42))
+	b := generate(newSamplingConfig(42))
+	assert.Equal(t, a, b) // reproducible
+	assert.Equal(t, 2, a[0])
+	for _, id := range a[1:] {
+		assert.NotEqual(t, 1, id) // pad token
+	}
+
+	config := newSamplingConfig(42)
+	config.TopK = 1
+	out := generate(config)
+	assert.Len(t, out, config.MaxLength)
+	for _, id := range out[1 : len(out)-1] {
+		assert.Equal(t, 4, id) // greedy
+	}
+	assert.Equal(t, 0, out[len(out)-1]) // forced EOS
+
+	config = newSamplingConfig(7)
+	config.NumBeams = 3
+	out = generate(config)
+	assert.Equal(t, 2, out[0])
+	assert.Equal(t, 0, out[len(out)-1])
+}
+
+func TestFilterTopK(t *testing.T) {
+	scores := []mat.Float{0.1, 0.5, 0.3, 0.9}
+	filterTopK(scores, 2)
+	assert.Equal(t, []mat.Float{mat.Inf(-1), 0.5, mat.Inf(-1), 0.9}, scores)
+}
+
+func TestFilterTopK_Ties(t *testing.T) {
+	scores := []mat.Float{0.5, 0.9, 0.5, 0.5}
+	filterTopK(scores, 2)
+	assert.Equal(t, []mat.Float{0.5, 0.9, mat.Inf(-1), mat.Inf(-1)}, scores)
+}
+
+func TestFilterTopP(t *testing.T) {
+	scores := []mat.Float{mat.Log(0.1), mat.Log(0.6), mat.Log(0.05), mat.Log(0.25)}
+	filterTopP(scores, 0.8)
+	assert.True(t, mat.IsInf(scores[0], -1))
+	assert.False(t, mat.IsInf(scores[1], -1))
+	assert.True(t, mat.IsInf(scores[2], -1))
+	assert.False(t, mat.IsInf(scores[3], -1))
+}
+
+func TestFilterTypical(t *testing.T) {
+	// the most probable token is less typical than the second one
+	scores := []mat.Float{mat.Log(0.5), mat.Log(0.3), mat.Log(0.15), mat.Log(0.05)}
+	filterTypical(scores, 0.25)
+	assert.True(t, mat.IsInf(scores[0], -1))
+	assert.False(t, mat.IsInf(scores[1], -1))
+	assert.True(t, mat.IsInf(scores[2], -1))
+	assert.True(t, mat.IsInf(scores[3], -1))
+}
+
+func TestSoftmax(t *testing.T) {
+	probs := softmax([]mat.Float{0, mat.Inf(-1), 0})
+	assert.Equal(t, []mat.Float{0.5, 0, 0.5}, probs)
+}
@@ -12,10 +12,13 @@ import (
 )
 
 // Scorer is a generation scorer implementing standard generation search decoding.
+// If the diverse beam search is enabled, each group of beams keeps its own hypotheses
+// and is done independently of the other groups.
 type Scorer struct {
 	config     GeneratorConfig
-	hypotheses *Hypotheses
-	isDone     bool
+	groupSize  int
+	hypotheses []*Hypotheses
+	isDone     []bool
 }
 
 // ScoredToken associates a score to a token identified by its
@@ -38,22 +41,38 @@ type ScorerProcessOutput struct {
 
 // NewScorer returns a new Scorer.
 func NewScorer(config GeneratorConfig) *Scorer {
+	numBeamGroups := 1
+	if config.NumBeamGroups > 1 {
+		numBeamGroups = config.NumBeamGroups
+	}
+	groupSize := config.NumBeams / numBeamGroups
+	hypotheses := make([]*Hypotheses, numBeamGroups)
+	for i := range hypotheses {
+		hypotheses[i] = NewHypotheses(config, groupSize)
+	}
 	return &Scorer{
 		config:     config,
-		hypotheses: NewHypotheses(config),
-		isDone:     false,
+		groupSize:  groupSize,
+		hypotheses: hypotheses,
+		isDone:     make([]bool, numBeamGroups),
 	}
 }
 
-// IsDone reports whether there are enough hypotheses and none of the hypotheses
-// being generated can become better than the worst one in the heap.
+// IsDone reports whether, for each group of beams, there are enough hypotheses and none of the
+// hypotheses being generated can become better than the worst one in the heap.
 func (s *Scorer) IsDone() bool {
-	return s.isDone
+	for _, done := range s.isDone {
+		if !done {
+			return false
+		}
+	}
+	return true
 }
 
-// Process processes a new set of scored tokens.
-func (s *Scorer) Process(inputIDs [][]int, scoredTokens ScoredTokens) ScorerProcessOutput {
-	numBeams := s.config.NumBeams
+// Process processes a new set of scored tokens, for the beams of the given group (all the beams, if the
+// diverse beam search is disabled, in which case the group is 0).
+func (s *Scorer) Process(group int, inputIDs [][]int, scoredTokens ScoredTokens) ScorerProcessOutput {
+	numBeams := s.groupSize
 	eosTokenID := s.config.EOSTokenID
 	padTokenID := s.config.PadTokenID
 	curLen := len(inputIDs[0])
@@ -64,7 +83,7 @@ func (s *Scorer) Process(inputIDs [][]int, scoredTokens ScoredTokens) ScorerProc
 		nextBeamIndices: make([]int, numBeams),
 	}
 
-	if s.isDone {
+	if s.isDone[group] {
 		for i := range out.nextBeamTokens {
 			out.nextBeamTokens[i] = padTokenID
 		}
@@ -83,7 +102,7 @@ func (s *Scorer) Process(inputIDs [][]int, scoredTokens ScoredTokens) ScorerProc
 			}
 			hypVec := make([]int, len(inputIDs[scoredToken.BeamIndex]))
 			copy(hypVec, inputIDs[scoredToken.BeamIndex])
-			s.hypotheses.Add(hypVec, scoredToken.Score)
+			s.hypotheses[group].Add(hypVec, scoredToken.Score)
 		} else {
 			// add next predicted token since it is not eos_token
 			out.nextBeamScores[beamIdx] = scoredToken.Score
@@ -100,7 +119,7 @@ func (s *Scorer) Process(inputIDs [][]int, scoredTokens ScoredTokens) ScorerProc
 
 	// Check if we are done so that we can save a pad step
 	// (note: scoredTokens[0] contains the max score)
-	s.isDone = s.hypotheses.IsDone(scoredTokens[0].Score, curLen)
+	s.isDone[group] = s.hypotheses[group].IsDone(scoredTokens[0].Score, curLen)
 
 	return out
 }
@@ -108,20 +127,22 @@ func (s *Scorer) Process(inputIDs [][]int, scoredTokens ScoredTokens) ScorerProc
 // Finalize finalizes the generation hypotheses and returns the best sequence.
 func (s *Scorer) Finalize(inputIDs [][]int, finalBeamScores []mat.Float) []int {
 	eosTokenID := s.config.EOSTokenID
-	numBeams := s.config.NumBeams
-
-	// Finalize all open generation hypotheses and add to generated hypotheses.
-	if !s.isDone {
-		// All open generation hypotheses are added to the generation hypothesis.
-		// Generator hypothesis class automatically keeps the best beams.
-		for beamID := 0; beamID < numBeams; beamID++ {
-			finalScore := finalBeamScores[beamID]
-			finalTokens := inputIDs[beamID]
-			s.hypotheses.Add(finalTokens, finalScore)
+
+	var beams []Hypothesis
+	for group, hypotheses := range s.hypotheses {
+		// Finalize all open generation hypotheses of the group and add to generated hypotheses.
+		if !s.isDone[group] {
+			// All open generation hypotheses are added to the generation hypothesis.
+			// Generator hypothesis class automatically keeps the best beams.
+			for beamID := group * s.groupSize; beamID < (group+1)*s.groupSize; beamID++ {
+				finalScore := finalBeamScores[beamID]
+				finalTokens := inputIDs[beamID]
+				hypotheses.Add(finalTokens, finalScore)
+			}
 		}
+		beams = append(beams, hypotheses.Beams()...)
 	}
 
-	beams := s.hypotheses.Beams()
 	sort.Slice(beams, func(i, j int) bool {
 		return beams[i].Score > beams[j].Score
 	})
@@ -0,0 +1,65 @@
+// Copyright 2021 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package generation
+
+import (
+	mat "github.com/nlpodyssey/spago/pkg/mat32"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestScorer_BeamGroups(t *testing.T) {
+	config := GeneratorConfig{
+		NumBeams:      4,
+		NumBeamGroups: 2,
+		EOSTokenID:    0,
+		PadTokenID:    1,
+		MaxLength:     10,
+		LengthPenalty: 1,
+		EarlyStopping: true,
+	}
+	scorer := NewScorer(config)
+	inputIDs := [][]int{{2, 3}, {2, 4}}
+
+	// the first group produces two EOS tokens and is done
+	out := scorer.Process(0, inputIDs, ScoredTokens{
+		{BeamIndex: 0, TokenIndex: 0, Score: -1},
+		{BeamIndex: 1, TokenIndex: 0, Score: -2},
+		{BeamIndex: 0, TokenIndex: 5, Score: -3},
+		{BeamIndex: 1, TokenIndex: 6, Score: -4},
+	})
+	assert.Equal(t, []int{5, 6}, out.nextBeamTokens)
+	assert.False(t, scorer.IsDone())
+
+	// the second group is still open
+	out = scorer.Process(1, inputIDs, ScoredTokens{
+		{BeamIndex: 0, TokenIndex: 7, Score: -1},
+		{BeamIndex: 1, TokenIndex: 8, Score: -2},
+		{BeamIndex: 0, TokenIndex: 0, Score: -3},
+		{BeamIndex: 1, TokenIndex: 0, Score: -4},
+	})
+	assert.Equal(t, []int{7, 8}, out.nextBeamTokens)
+	assert.Equal(t, []int{0, 1}, out.nextBeamIndices)
+	assert.False(t, scorer.IsDone())
+
+	// at the next step, the first group only produces padding, while the second one is done
+	inputIDs = [][]int{{2, 3, 7}, {2, 4, 8}}
+	out = scorer.Process(0, inputIDs, ScoredTokens{
+		{BeamIndex: 0, TokenIndex: 9, Score: -5},
+		{BeamIndex: 1, TokenIndex: 9, Score: -6},
+	})
+	assert.Equal(t, []int{1, 1}, out.nextBeamTokens)
+
+	scorer.Process(1, inputIDs, ScoredTokens{
+		{BeamIndex: 0, TokenIndex: 0, Score: -5},
+		{BeamIndex: 1, TokenIndex: 0, Score: -6},
+		{BeamIndex: 0, TokenIndex: 9, Score: -7},
+		{BeamIndex: 1, TokenIndex: 9, Score: -8},
+	})
+	assert.True(t, scorer.IsDone())
+
+	// the best hypothesis comes from the first group
+	assert.Equal(t, []int{2, 3, 0}, scorer.Finalize(inputIDs, make([]mat.Float, 4)))
+}
@@ -11,6 +11,13 @@ import (
 	"github.com/nlpodyssey/spago/pkg/ml/ag"
 )
 
+// Model is a model able to generate sequences: either an EncoderDecoder, or a decoder-only model
+// (see GeneratorConfig.IsEncoderDecoder), whose Decode is called without encoded input.
+type Model interface {
+	Decoder
+	Graph() *ag.Graph
+}
+
 // EncoderDecoder is a model able to perform encoder-decoder conditional generation.
 type EncoderDecoder interface {
 	Encoder
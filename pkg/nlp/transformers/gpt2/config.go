@@ -0,0 +1,79 @@
+// Copyright 2021 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gpt2
+
+import (
+	"encoding/json"
+	mat "github.com/nlpodyssey/spago/pkg/mat32"
+	"os"
+)
+
+const (
+	// DefaultConfigurationFile is the default GPT-2 JSON configuration filename.
+	DefaultConfigurationFile = "config.json"
+	// DefaultModelFile is the default GPT-2 spaGO model filename.
+	DefaultModelFile = "spago_model.bin"
+	// DefaultEmbeddingsStorage is the default directory name for GPT-2 model's embedding storage.
+	DefaultEmbeddingsStorage = "embeddings_storage"
+)
+
+// Config contains the global configuration of the GPT-2 model and of the generation.
+// The configuration coincides with that of Hugging Face to facilitate compatibility between the two architectures.
+type Config struct {
+	ActivationFunction string     `json:"activation_function"`
+	Architecture       []string   `json:"architectures"`
+	AttnPDrop          mat.Float  `json:"attn_pdrop"`
+	BosTokenID         int        `json:"bos_token_id"`
+	EmbdPDrop          mat.Float  `json:"embd_pdrop"`
+	EosTokenID         int        `json:"eos_token_id"`
+	LayerNormEpsilon   mat.Float  `json:"layer_norm_epsilon"`
+	ModelType          string     `json:"model_type"`
+	NCtx               int        `json:"n_ctx"`
+	NEmbd              int        `json:"n_embd"`
+	NHead              int        `json:"n_head"`
+	NInner             *int       `json:"n_inner"` // nil means 4 * NEmbd
+	NLayer             int        `json:"n_layer"`
+	NPositions         int        `json:"n_positions"`
+	PadTokenID         *int       `json:"pad_token_id"` // nil means no padding token
+	ResidPDrop         mat.Float  `json:"resid_pdrop"`
+	VocabSize          int        `json:"vocab_size"`
+	NumBeams           int        `json:"num_beams"`
+	MaxLength          int        `json:"max_length"`
+	MinLength          int        `json:"min_length"`
+	BadWordsIDs        [][]int    `json:"bad_words_ids"`
+	LengthPenalty      *mat.Float `json:"length_penalty"` // nil means 1.0
+	EarlyStopping      bool       `json:"early_stopping"`
+	RepetitionPenalty  mat.Float  `json:"repetition_penalty"`
+	NoRepeatNGramSize  int        `json:"no_repeat_ngram_size"`
+	DoSample           bool       `json:"do_sample"`
+	Temperature        mat.Float  `json:"temperature"`
+	TopK               int        `json:"top_k"`
+	TopP               mat.Float  `json:"top_p"`
+	TypicalP           mat.Float  `json:"typical_p"`
+	Training           bool       `json:"training"` // Custom for spaGO
+}
+
+// InnerSize returns the size of the hidden layer of the feed-forward blocks.
+func (c Config) InnerSize() int {
+	if c.NInner != nil {
+		return *c.NInner
+	}
+	return 4 * c.NEmbd
+}
+
+// LoadConfig loads a GPT-2 model Config from file.
+func LoadConfig(file string) (Config, error) {
+	var config Config
+	configFile, err := os.Open(file)
+	if err != nil {
+		return Config{}, err
+	}
+	defer configFile.Close()
+	err = json.NewDecoder(configFile).Decode(&config)
+	if err != nil {
+		return Config{}, err
+	}
+	return config, nil
+}
@@ -0,0 +1,289 @@
+// Copyright 2021 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gpt2
+
+import (
+	"fmt"
+	"github.com/nlpodyssey/gopickle/pytorch"
+	"github.com/nlpodyssey/gopickle/types"
+	mat "github.com/nlpodyssey/spago/pkg/mat32"
+	"github.com/nlpodyssey/spago/pkg/ml/nn"
+	"github.com/nlpodyssey/spago/pkg/ml/nn/linear"
+	"github.com/nlpodyssey/spago/pkg/nlp/embeddings"
+	"github.com/nlpodyssey/spago/pkg/utils/gopickleutils"
+	"log"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+)
+
+const defaultHuggingFaceModelFile = "pytorch_model.bin"
+
+// ConverterOption allows to adapt the conversion to your specific needs.
+type ConverterOption func(*huggingFacePreTrainedConverter)
+
+// ParamsPrecision sets the floating-point format used to store the params and the
+// word embeddings of the converted model (default mat.FullPrecision).
+func ParamsPrecision(precision mat.Precision) ConverterOption {
+	return func(c *huggingFacePreTrainedConverter) {
+		c.precision = precision
+	}
+}
+
+// ConvertHuggingFacePreTrained converts a HuggingFace pre-trained GPT-2
+// transformer model to a corresponding spaGO LMHeadModel.
+func ConvertHuggingFacePreTrained(modelPath string, opts ...ConverterOption) error {
+	configFilename, err := exists(path.Join(modelPath, DefaultConfigurationFile))
+	if err != nil {
+		return err
+	}
+	pyTorchModelFilename, err := exists(path.Join(modelPath, defaultHuggingFaceModelFile))
+	if err != nil {
+		return err
+	}
+	config, err := LoadConfig(configFilename)
+	if err != nil {
+		return err
+	}
+
+	// Enable training mode, so that we have writing permissions
+	// (for example, for embeddings storage files).
+	config.Training = true
+
+	model := NewLMHeadModel(config, path.Join(modelPath, DefaultEmbeddingsStorage))
+	defer model.Close()
+	handler := &huggingFacePreTrainedConverter{
+		config:               config,
+		modelPath:            modelPath,
+		configFilename:       configFilename,
+		pyTorchModelFilename: pyTorchModelFilename,
+		modelFilename:        path.Join(modelPath, DefaultModelFile),
+		model:                model,
+		modelMapping:         make(map[string]*mappedParam), // lazy initialization
+		precision:            mat.FullPrecision,
+	}
+	for _, opt := range opts {
+		opt(handler)
+	}
+	model.GPT2.Embeddings.Precision = handler.precision
+	return handler.convert()
+}
+
+type huggingFacePreTrainedConverter struct {
+	config               Config
+	modelPath            string
+	configFilename       string
+	pyTorchModelFilename string
+	modelFilename        string
+	model                *LMHeadModel
+	modelMapping         map[string]*mappedParam
+	precision            mat.Precision
+}
+
+type mappedParam struct {
+	value mat.Matrix
+	used  bool
+}
+
+func exists(filename string) (string, error) {
+	if _, err := os.Stat(filename); os.IsNotExist(err) {
+		return filename, err
+	}
+	return filename, nil
+}
+
+func (c *huggingFacePreTrainedConverter) convert() error {
+	log.Printf("Start converting `%s`\nConfiguration: %+v\n", c.pyTorchModelFilename, c.config)
+	log.Printf("Extracting Hugging Face params from the PyTorch model...")
+	pyTorchParams := c.extractHuggingFaceParams()
+
+	log.Printf("Convert embeddings... ")
+	wordEmbeddings, ok := pyTorchParams["wte.weight"]
+	if !ok {
+		return fmt.Errorf("gpt2: missing word embeddings `wte.weight`")
+	}
+	dumpWordEmbeddings(wordEmbeddings, c.model.GPT2.Embeddings, c.config.VocabSize)
+	assignToParamsList(
+		pyTorchParams["wpe.weight"],
+		c.model.GPT2.PositionalEncoder.Vectors,
+		c.config.NPositions,
+		c.config.NEmbd)
+	log.Printf("Ok\n")
+
+	c.addToModelMapping(mapGPT2(c.model.GPT2))
+	c.addToModelMapping(mapLMHead(c.model.LMHead))
+
+	log.Printf("Search for matches with the mapped model to import weights...")
+	for paramName, preTrainedWeights := range pyTorchParams {
+		if param, ok := c.modelMapping[paramName]; ok {
+			fmt.Printf("Setting %s...", paramName)
+			if param.value.Size() != len(preTrainedWeights) {
+				return fmt.Errorf("gpt2: size mismatch for `%s`", paramName)
+			}
+			param.value.SetData(preTrainedWeights)
+			param.used = true
+			fmt.Println("ok")
+		}
+	}
+
+	log.Printf("Report possible mapping anomalies...")
+	for key, value := range c.modelMapping {
+		if !value.used {
+			log.Printf("WARNING!! `%s` not initialized", key)
+		}
+	}
+
+	if c.precision != mat.FullPrecision {
+		log.Printf("Convert the params to %s precision...", c.precision)
+		nn.SetPrecision(c.model, c.precision)
+	}
+
+	fmt.Printf("Serializing model to \"%s\"... ", c.modelFilename)
+	if err := nn.SaveModelFile(c.modelFilename, c.model, c.config); err != nil {
+		return fmt.Errorf("gpt2: error during model serialization: %w", err)
+	}
+	fmt.Println("ok")
+	fmt.Printf("GPT-2 has been converted successfully!\n")
+	return nil
+}
+
+func (c *huggingFacePreTrainedConverter) extractHuggingFaceParams() map[string][]mat.Float {
+	paramsMap := make(map[string][]mat.Float)
+	result, err := pytorch.Load(c.pyTorchModelFilename)
+	if err != nil {
+		log.Fatal(err)
+	}
+	od := result.(*types.OrderedDict)
+	for key, entry := range od.Map {
+		t := entry.Value.(*pytorch.Tensor)
+		paramName := normalizeParamName(key.(string))
+		fmt.Printf("Reading %s.... ", paramName)
+		_, isFloat := t.Source.(*pytorch.FloatStorage)
+		if !isFloat || len(t.Size) == 0 || len(t.Size) > 2 {
+			fmt.Println("skip") // e.g. the attention masks
+			continue
+		}
+		data := gopickleutils.GetData(t)
+		if len(t.Size) == 2 && isConv1D(paramName) {
+			// the Conv1D layers store the weights as (input, output), transposed with respect to the linear layers
+			data = transpose(data, t.Size[0], t.Size[1])
+		}
+		paramsMap[paramName] = data
+		fmt.Println("ok")
+	}
+	c.disaggregateSelfAttentionParams(paramsMap)
+	return paramsMap
+}
+
+// isConv1D reports whether the param is the weight of a Conv1D layer of the Hugging Face implementation.
+func isConv1D(paramName string) bool {
+	for _, suffix := range []string{".c_attn.weight", ".c_proj.weight", ".c_fc.weight"} {
+		if strings.HasSuffix(paramName, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// transpose returns the transposed data of a rows x cols matrix in row-major order.
+func transpose(data []mat.Float, rows, cols int) []mat.Float {
+	out := make([]mat.Float, len(data))
+	for i := 0; i < rows; i++ {
+		for j := 0; j < cols; j++ {
+			out[j*rows+i] = data[i*cols+j]
+		}
+	}
+	return out
+}
+
+// disaggregateSelfAttentionParams splits the combined query, key and value projections of each layer
+// into the projections of each attention head.
+func (c *huggingFacePreTrainedConverter) disaggregateSelfAttentionParams(paramsMap map[string][]mat.Float) {
+	size := c.config.NEmbd
+	dim := size / c.config.NHead
+	for i := 0; i < c.config.NLayer; i++ {
+		prefix := fmt.Sprintf("h.%d.attn", i)
+		weight := paramsMap[fmt.Sprintf("%s.c_attn.weight", prefix)] // (3 * size) x size, after the transposition
+		bias := paramsMap[fmt.Sprintf("%s.c_attn.bias", prefix)]
+		if weight == nil || bias == nil {
+			continue
+		}
+		for j := 0; j < c.config.NHead; j++ {
+			newPrefix := fmt.Sprintf("h.%d.%d.attn", i, j)
+			for k, name := range []string{"q_proj", "k_proj", "v_proj"} {
+				from := k*size + j*dim
+				to := from + dim
+				paramsMap[fmt.Sprintf("%s.%s.weight", newPrefix, name)] = weight[from*size : to*size]
+				paramsMap[fmt.Sprintf("%s.%s.bias", newPrefix, name)] = bias[from:to]
+			}
+		}
+	}
+}
+
+// normalizeParamName removes the "transformer." prefix of the params of GPT2LMHeadModel, so that they
+// have the same names of the params of the base GPT2Model.
+func normalizeParamName(orig string) string {
+	return strings.TrimPrefix(orig, "transformer.")
+}
+
+func dumpWordEmbeddings(source []mat.Float, dest *embeddings.Model, vocabSize int) {
+	size := dest.Size
+	for i := 0; i < vocabSize; i++ {
+		dest.SetEmbeddingFromData(strconv.Itoa(i), source[i*size:(i+1)*size])
+	}
+}
+
+func assignToParamsList(source []mat.Float, dest []nn.Param, rows, cols int) {
+	for i := 0; i < rows; i++ {
+		dest[i].SetData(source[i*cols : (i+1)*cols])
+	}
+}
+
+func mapGPT2(model *Model) map[string]mat.Matrix {
+	paramsMap := make(map[string]mat.Matrix)
+	for i, layer := range model.Layers {
+		prefixBase := fmt.Sprintf("h.%d", i)
+		for j, attention := range layer.SelfAttention.Attention {
+			prefix := fmt.Sprintf("h.%d.%d.attn", i, j)
+			paramsMap[fmt.Sprintf("%s.q_proj.weight", prefix)] = attention.Query.W.Value()
+			paramsMap[fmt.Sprintf("%s.q_proj.bias", prefix)] = attention.Query.B.Value()
+			paramsMap[fmt.Sprintf("%s.k_proj.weight", prefix)] = attention.Key.W.Value()
+			paramsMap[fmt.Sprintf("%s.k_proj.bias", prefix)] = attention.Key.B.Value()
+			paramsMap[fmt.Sprintf("%s.v_proj.weight", prefix)] = attention.Value.W.Value()
+			paramsMap[fmt.Sprintf("%s.v_proj.bias", prefix)] = attention.Value.B.Value()
+		}
+		paramsMap[fmt.Sprintf("%s.attn.c_proj.weight", prefixBase)] = layer.SelfAttention.OutputMerge.W.Value()
+		paramsMap[fmt.Sprintf("%s.attn.c_proj.bias", prefixBase)] = layer.SelfAttention.OutputMerge.B.Value()
+		paramsMap[fmt.Sprintf("%s.ln_1.weight", prefixBase)] = layer.SelfAttentionLayerNorm.W.Value()
+		paramsMap[fmt.Sprintf("%s.ln_1.bias", prefixBase)] = layer.SelfAttentionLayerNorm.B.Value()
+		paramsMap[fmt.Sprintf("%s.mlp.c_fc.weight", prefixBase)] = layer.FFN.Layers[0].(*linear.Model).W.Value()
+		paramsMap[fmt.Sprintf("%s.mlp.c_fc.bias", prefixBase)] = layer.FFN.Layers[0].(*linear.Model).B.Value()
+		paramsMap[fmt.Sprintf("%s.mlp.c_proj.weight", prefixBase)] = layer.FFN.Layers[2].(*linear.Model).W.Value()
+		paramsMap[fmt.Sprintf("%s.mlp.c_proj.bias", prefixBase)] = layer.FFN.Layers[2].(*linear.Model).B.Value()
+		paramsMap[fmt.Sprintf("%s.ln_2.weight", prefixBase)] = layer.LayerNorm.W.Value()
+		paramsMap[fmt.Sprintf("%s.ln_2.bias", prefixBase)] = layer.LayerNorm.B.Value()
+	}
+	paramsMap["ln_f.weight"] = model.LayerNorm.W.Value()
+	paramsMap["ln_f.bias"] = model.LayerNorm.B.Value()
+	return paramsMap
+}
+
+// mapLMHead maps the weights of the language modeling head to the word embeddings, since they are tied.
+// The head has no bias.
+func mapLMHead(model *linear.Model) map[string]mat.Matrix {
+	paramsMap := make(map[string]mat.Matrix)
+	paramsMap["wte.weight"] = model.W.Value()
+	return paramsMap
+}
+
+func (c *huggingFacePreTrainedConverter) addToModelMapping(paramsMap map[string]mat.Matrix) {
+	for k, v := range paramsMap {
+		c.modelMapping[k] = &mappedParam{
+			value: v,
+			used:  false,
+		}
+	}
+}
@@ -0,0 +1,118 @@
+// Copyright 2021 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package gpt2 implements the decoder-only transformer model introduced by Radford et al., 2019.
+// "Language Models are Unsupervised Multitask Learners"
+// https://cdn.openai.com/better-language-models/language_models_are_unsupervised_multitask_learners.pdf
+package gpt2
+
+import (
+	"encoding/gob"
+	"github.com/nlpodyssey/spago/pkg/ml/ag"
+	"github.com/nlpodyssey/spago/pkg/ml/nn"
+	"github.com/nlpodyssey/spago/pkg/ml/nn/attention/multiheadattention"
+	"github.com/nlpodyssey/spago/pkg/ml/nn/normalization/layernorm"
+	"github.com/nlpodyssey/spago/pkg/nlp/embeddings"
+	"github.com/nlpodyssey/spago/pkg/nlp/transformers/bart/positionalencoder/learnedpositionalencoder"
+	"strconv"
+)
+
+var (
+	_ nn.Model = &Model{}
+)
+
+// Model implements a GPT-2 model: a stack of transformer blocks with causal self-attention over the
+// sum of the word embeddings and the learned positional embeddings.
+type Model struct {
+	nn.BaseModel
+	Config            Config
+	Embeddings        *embeddings.Model
+	PositionalEncoder *learnedpositionalencoder.LearnedPositionalEncoder
+	Layers            []*Layer
+	LayerNorm         *layernorm.Model
+}
+
+func init() {
+	gob.Register(&Model{})
+}
+
+// New returns a new GPT-2 Model.
+func New(config Config, embeddingsStoragePath string) *Model {
+	layers := make([]*Layer, config.NLayer)
+	for i := range layers {
+		layers[i] = NewLayer(config)
+	}
+	return &Model{
+		Config: config,
+		Embeddings: embeddings.New(embeddings.Config{
+			Size:       config.NEmbd,
+			DBPath:     embeddingsStoragePath,
+			ReadOnly:   !config.Training,
+			ForceNewDB: false, // TODO: from config?
+		}),
+		PositionalEncoder: learnedpositionalencoder.New(learnedpositionalencoder.Config{
+			NumEmbeddings: config.NPositions,
+			EmbeddingDim:  config.NEmbd,
+		}),
+		Layers:    layers,
+		LayerNorm: layernorm.New(config.NEmbd),
+	}
+}
+
+// Close closes the GPT-2 model's embeddings DB.
+func (m *Model) Close() {
+	m.Embeddings.Close()
+}
+
+// KeysValuesPairs contains the multiheadattention.KeysValuesPairs of the self-attention of each layer.
+type KeysValuesPairs = []multiheadattention.KeysValuesPairs
+
+// Forward performs the forward step for each input and returns the result.
+// The past keys and values (nil at the first step) are the ones returned by the previous call, so that
+// only the new inputs have to be processed; the positions of the inputs follow the past ones.
+func (m *Model) Forward(inputIDs []int, pastKeysValues KeysValuesPairs) ([]ag.Node, KeysValuesPairs) {
+	g := m.Graph()
+	wordEmbeddings := m.Embeddings.Encode(intToStringSlice(inputIDs))
+	posEmbeddings := m.PositionalEncoder.Encode(makePositions(len(inputIDs), getPastSequenceLength(pastKeysValues)))
+	ys := make([]ag.Node, len(inputIDs))
+	for i := range ys {
+		ys[i] = g.Add(wordEmbeddings[i], posEmbeddings[i])
+	}
+	// TODO: ys = m.Dropout(ys)
+
+	nextKeysValues := make(KeysValuesPairs, len(m.Layers))
+	for i, l := range m.Layers {
+		var past multiheadattention.KeysValuesPairs
+		if pastKeysValues != nil {
+			past = pastKeysValues[i]
+		}
+		ys, nextKeysValues[i] = l.Forward(ys, past)
+	}
+	return m.LayerNorm.Forward(ys...), nextKeysValues
+}
+
+func getPastSequenceLength(pkv KeysValuesPairs) int {
+	if pkv == nil {
+		return 0
+	}
+	return len(pkv[0][0].Values)
+}
+
+// makePositions returns a slice of the given size, where each element has
+// the same value of its own index position plus the offset.
+func makePositions(size, offset int) []int {
+	indices := make([]int, size)
+	for i := range indices {
+		indices[i] = i + offset
+	}
+	return indices
+}
+
+func intToStringSlice(a []int) []string {
+	out := make([]string, len(a))
+	for i, num := range a {
+		out[i] = strconv.Itoa(num)
+	}
+	return out
+}
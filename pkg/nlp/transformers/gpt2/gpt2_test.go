@@ -0,0 +1,81 @@
+// Copyright 2021 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gpt2
+
+import (
+	mat "github.com/nlpodyssey/spago/pkg/mat32"
+	"github.com/nlpodyssey/spago/pkg/mat32/rand"
+	"github.com/nlpodyssey/spago/pkg/ml/ag"
+	"github.com/nlpodyssey/spago/pkg/ml/initializers"
+	"github.com/nlpodyssey/spago/pkg/ml/nn"
+	"github.com/stretchr/testify/assert"
+	"strconv"
+	"testing"
+)
+
+func newTestModel(t *testing.T) *LMHeadModel {
+	config := Config{
+		ActivationFunction: "gelu_new",
+		BosTokenID:         0,
+		EosTokenID:         0,
+		NEmbd:              8,
+		NHead:              2,
+		NLayer:             2,
+		NPositions:         16,
+		VocabSize:          10,
+		MaxLength:          8,
+		Training:           true,
+	}
+	model := NewLMHeadModel(config, t.TempDir())
+	rndGen := rand.NewLockedRand(42)
+	nn.ForEachParam(model, func(param nn.Param) {
+		initializers.Normal(param.Value(), 0, 0.5, rndGen)
+	})
+	for i := 0; i < config.VocabSize; i++ {
+		data := make([]mat.Float, config.NEmbd)
+		for j := range data {
+			data[j] = mat.Float(rndGen.Float()) - 0.5
+		}
+		model.GPT2.Embeddings.SetEmbeddingFromData(strconv.Itoa(i), data)
+	}
+	return model
+}
+
+func TestLMHeadModel_PastKeysValues(t *testing.T) {
+	model := newTestModel(t)
+	defer model.Close()
+	inputIDs := []int{1, 2, 3, 4}
+
+	g := ag.NewGraph()
+	proc := nn.Reify(nn.Context{Graph: g, Mode: nn.Inference}, model).(*LMHeadModel)
+
+	// the whole sequence at once
+	expected, _ := proc.PredictNext(inputIDs, nil)
+
+	// one token at a time, reusing the past keys and values
+	logits, past := proc.PredictNext(inputIDs[:1], nil)
+	for i := 1; i < len(inputIDs); i++ {
+		logits, past = proc.PredictNext(inputIDs[i:i+1], past)
+	}
+
+	assert.InDeltaSlice(t, expected.Value().Data(), logits.Value().Data(), 1e-5)
+}
+
+func TestLMHeadModel_Generate(t *testing.T) {
+	model := newTestModel(t)
+	defer model.Close()
+
+	g := ag.NewGraph()
+	proc := nn.Reify(nn.Context{Graph: g, Mode: nn.Inference}, model).(*LMHeadModel)
+
+	out := proc.Generate([]int{1, 2, 3})
+	assert.Equal(t, []int{1, 2, 3}, out[:3]) // the generated sequence starts with the input
+	assert.LessOrEqual(t, len(out), model.GPT2.Config.MaxLength)
+	assert.Greater(t, len(out), 3)
+}
+
+func TestTranspose(t *testing.T) {
+	assert.Equal(t, []mat.Float{1, 4, 2, 5, 3, 6}, transpose([]mat.Float{1, 2, 3, 4, 5, 6}, 2, 3))
+}
@@ -0,0 +1,93 @@
+// Copyright 2021 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gpt2
+
+import (
+	"encoding/gob"
+	"github.com/nlpodyssey/spago/pkg/ml/ag"
+	"github.com/nlpodyssey/spago/pkg/ml/nn"
+	"github.com/nlpodyssey/spago/pkg/ml/nn/activation"
+	"github.com/nlpodyssey/spago/pkg/ml/nn/attention"
+	"github.com/nlpodyssey/spago/pkg/ml/nn/attention/multiheadattention"
+	"github.com/nlpodyssey/spago/pkg/ml/nn/linear"
+	"github.com/nlpodyssey/spago/pkg/ml/nn/normalization/layernorm"
+	"github.com/nlpodyssey/spago/pkg/ml/nn/stack"
+)
+
+var (
+	_ nn.Model = &Layer{}
+)
+
+// Layer implements a GPT-2 transformer block, where the layer normalization is applied before the
+// self-attention and the feed-forward sub-layers (pre-LayerNorm).
+type Layer struct {
+	nn.BaseModel
+	Config                 Config
+	SelfAttentionLayerNorm *layernorm.Model
+	SelfAttention          *multiheadattention.Model
+	LayerNorm              *layernorm.Model
+	FFN                    *stack.Model
+}
+
+func init() {
+	gob.Register(&Layer{})
+}
+
+// NewLayer returns a new GPT-2 Layer.
+func NewLayer(config Config) *Layer {
+	return &Layer{
+		Config:                 config,
+		SelfAttentionLayerNorm: layernorm.New(config.NEmbd),
+		SelfAttention: multiheadattention.New(
+			config.NEmbd,
+			config.NHead,
+			true, // use causal mask
+			// TODO: config.AttnPDrop
+		),
+		LayerNorm: layernorm.New(config.NEmbd),
+		FFN: stack.New(
+			linear.New(config.NEmbd, config.InnerSize()),
+			activation.New(mustGetOpName(config.ActivationFunction)),
+			linear.New(config.InnerSize(), config.NEmbd),
+			// dropout.New(config.ResidPDrop)
+		),
+	}
+}
+
+// mustGetOpName returns the operator of the activation function. The Hugging Face "gelu_new" corresponds
+// to ag.OpGELU, which uses the tanh approximation.
+func mustGetOpName(str string) ag.OpName {
+	if str == "gelu_new" {
+		return ag.OpGELU
+	}
+	value, err := ag.GetOpName(str)
+	if err != nil {
+		panic(err)
+	}
+	return value
+}
+
+// Forward performs the forward step for each input and returns the result, along with the keys and values
+// of the self-attention, to be used as past keys and values at the next step.
+func (m *Layer) Forward(
+	xs []ag.Node,
+	pastProjKeysValues multiheadattention.KeysValuesPairs,
+) ([]ag.Node, multiheadattention.KeysValuesPairs) {
+	g := m.Graph()
+	norm := m.SelfAttentionLayerNorm.Forward(xs...)
+	att := m.SelfAttention.ForwardWithPastKeysValues(attention.ToQKV(norm), pastProjKeysValues)
+	// TODO: dropout
+	hs := make([]ag.Node, len(xs))
+	for i, x := range xs {
+		hs[i] = g.Add(x, att.AttOutput[i])
+	}
+
+	ffn := m.FFN.Forward(m.LayerNorm.Forward(hs...)...)
+	ys := make([]ag.Node, len(hs))
+	for i, h := range hs {
+		ys[i] = g.Add(h, ffn[i])
+	}
+	return ys, att.ProjKeysValues
+}
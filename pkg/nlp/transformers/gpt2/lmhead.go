@@ -0,0 +1,148 @@
+// Copyright 2021 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gpt2
+
+import (
+	"context"
+	"encoding/gob"
+	"github.com/nlpodyssey/spago/pkg/ml/ag"
+	"github.com/nlpodyssey/spago/pkg/ml/nn"
+	"github.com/nlpodyssey/spago/pkg/ml/nn/linear"
+	"github.com/nlpodyssey/spago/pkg/nlp/transformers/generation"
+	"runtime"
+)
+
+var (
+	_ nn.Model         = &LMHeadModel{}
+	_ generation.Model = &LMHeadModel{}
+)
+
+// defaultMaxLength is the maximum length of the generated sequences, including the input, when
+// it is not set in the configuration (as in Hugging Face).
+const defaultMaxLength = 20
+
+// LMHeadModel implements a GPT-2 model with a language modeling head, which predicts the next token
+// of the sequence. The weights of the head are the same of the word embeddings.
+type LMHeadModel struct {
+	nn.BaseModel
+	GPT2   *Model
+	LMHead *linear.Model
+}
+
+func init() {
+	gob.Register(&LMHeadModel{})
+}
+
+// NewLMHeadModel returns a new LMHeadModel.
+func NewLMHeadModel(config Config, embeddingsPath string) *LMHeadModel {
+	return &LMHeadModel{
+		GPT2:   New(config, embeddingsPath),
+		LMHead: linear.New(config.NEmbd, config.VocabSize),
+	}
+}
+
+// Close closes the GPT-2 model's embeddings DB.
+func (m *LMHeadModel) Close() {
+	m.GPT2.Close()
+}
+
+// PredictNext returns the logits of the next token after the last input, along with the keys and values
+// to be used at the next step (see Model.Forward).
+func (m *LMHeadModel) PredictNext(inputIDs []int, pastKeysValues KeysValuesPairs) (ag.Node, KeysValuesPairs) {
+	hs, nextKeysValues := m.GPT2.Forward(inputIDs, pastKeysValues)
+	return nn.ToNode(m.LMHead.Forward(hs[len(hs)-1])), nextKeysValues
+}
+
+// Decode satisfies pkg/nlp/transformers/generation/Decoder. There is no encoded input.
+func (m *LMHeadModel) Decode(_ []ag.Node, inputIDs []int, pastCache generation.Cache) (ag.Node, generation.Cache) {
+	pastKeysValues, _ := pastCache.(KeysValuesPairs)
+	if pastKeysValues != nil {
+		// cut input ids if past is used
+		inputIDs = inputIDs[len(inputIDs)-1:]
+	}
+	return m.PredictNext(inputIDs, pastKeysValues)
+}
+
+// Generate generates the continuation of the input using either beam-search decoding or sampling, as set
+// in the configuration of the model. The decoding options override the configuration.
+// The result includes the input.
+func (m *LMHeadModel) Generate(inputIDs []int, opts ...generation.DecodingOption) []int {
+	out, _ := m.GenerateContext(context.Background(), inputIDs, nil, opts...)
+	return out
+}
+
+// GenerateContext is like Generate, but it stops as soon as the context is done, and it calls onStep
+// (if not nil) with the best partial sequence at each step (see generation.Generator.GenerateContext).
+func (m *LMHeadModel) GenerateContext(
+	ctx context.Context,
+	inputIDs []int,
+	onStep generation.StepFunc,
+	opts ...generation.DecodingOption,
+) ([]int, error) {
+	return m.newGenerator(opts...).GenerateContext(ctx, inputIDs, onStep)
+}
+
+// GenerateBatch is like GenerateContext, but it generates a sequence for each input of the batch, decoding
+// them together (see generation.Generator.GenerateBatch).
+func (m *LMHeadModel) GenerateBatch(
+	ctx context.Context,
+	inputs [][]int,
+	onStep generation.BatchStepFunc,
+	opts ...generation.DecodingOption,
+) ([][]int, error) {
+	return m.newGenerator(opts...).GenerateBatch(ctx, inputs, onStep)
+}
+
+// newGenerator returns a new generation.Generator configured according to the configuration of the model,
+// overridden by the decoding options.
+func (m *LMHeadModel) newGenerator(opts ...generation.DecodingOption) *generation.Generator {
+	config := m.GPT2.Config
+	incrementalForward := m.Graph().IncrementalForwardEnabled()
+
+	maxConcurrentComputations := runtime.NumCPU()
+	if incrementalForward && runtime.NumCPU() > 1 {
+		maxConcurrentComputations = runtime.NumCPU() / 2
+	}
+
+	generatorConfig := generation.GeneratorConfig{
+		NumBeams:                  config.NumBeams,
+		MinLength:                 config.MinLength,
+		MaxLength:                 config.MaxLength,
+		IsEncoderDecoder:          false,
+		BOSTokenID:                config.BosTokenID,
+		EOSTokenID:                config.EosTokenID,
+		PadTokenID:                -1, // no padding token
+		VocabSize:                 config.VocabSize,
+		LengthPenalty:             1.0,
+		EarlyStopping:             config.EarlyStopping,
+		BadWordsIDs:               config.BadWordsIDs,
+		RepetitionPenalty:         config.RepetitionPenalty,
+		NoRepeatNGramSize:         config.NoRepeatNGramSize,
+		MaxConcurrentComputations: maxConcurrentComputations,
+		IncrementalForward:        incrementalForward,
+		DoSample:                  config.DoSample,
+		Temperature:               config.Temperature,
+		TopK:                      config.TopK,
+		TopP:                      config.TopP,
+		TypicalP:                  config.TypicalP,
+	}
+	if generatorConfig.NumBeams < 1 {
+		generatorConfig.NumBeams = 1
+	}
+	if generatorConfig.MaxLength == 0 {
+		generatorConfig.MaxLength = defaultMaxLength
+	}
+	if config.PadTokenID != nil {
+		generatorConfig.PadTokenID = *config.PadTokenID
+	}
+	if config.LengthPenalty != nil {
+		generatorConfig.LengthPenalty = *config.LengthPenalty
+	}
+	for _, opt := range opts {
+		opt(&generatorConfig)
+	}
+
+	return generation.NewGenerator(generatorConfig, m)
+}
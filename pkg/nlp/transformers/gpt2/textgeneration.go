@@ -0,0 +1,94 @@
+// Copyright 2021 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gpt2
+
+import (
+	"context"
+	"github.com/nlpodyssey/spago/pkg/ml/ag"
+	"github.com/nlpodyssey/spago/pkg/ml/nn"
+	"github.com/nlpodyssey/spago/pkg/nlp/tokenizers/bpetokenizer"
+	"github.com/nlpodyssey/spago/pkg/nlp/transformers/generation"
+)
+
+// TextGenerator contains the LMHeadModel and the Tokenizer used for text completion.
+type TextGenerator struct {
+	*LMHeadModel
+	Tokenizer *bpetokenizer.BPETokenizer
+}
+
+// LoadTextGenerator loads a TextGenerator from the model folder, which must contain the files of the
+// tokenizer (vocab.json and merges.txt) along with the converted model.
+func LoadTextGenerator(modelPath string) (*TextGenerator, error) {
+	model, err := LoadModel(modelPath)
+	if err != nil {
+		return nil, err
+	}
+	tokenizer, err := bpetokenizer.NewFromModelFolder(modelPath)
+	if err != nil {
+		model.Close()
+		return nil, err
+	}
+	return &TextGenerator{
+		LMHeadModel: model,
+		Tokenizer:   tokenizer,
+	}, nil
+}
+
+// Generate generates the continuation of the text, which is returned without the text itself.
+// The decoding options override the decoding strategy set in the configuration of the model
+// (e.g. generation.Sampling to sample the next tokens instead of performing a beam search).
+func (t *TextGenerator) Generate(text string, opts ...generation.DecodingOption) (string, error) {
+	return t.GenerateStream(context.Background(), text, nil, opts...)
+}
+
+// GenerateStream is like Generate, but it calls onPartial (if not nil) with the text of the best partial
+// continuation at each step of the generation, and it stops as soon as the context is done, returning the
+// error of the context. If onPartial returns an error, the generation is stopped and the error is returned.
+func (t *TextGenerator) GenerateStream(
+	ctx context.Context,
+	text string,
+	onPartial func(text string) error,
+	opts ...generation.DecodingOption,
+) (string, error) {
+	g := ag.NewGraph(ag.IncrementalForward(false))
+	defer g.Clear()
+
+	proc := nn.Reify(nn.Context{Graph: g, Mode: nn.Inference}, t.LMHeadModel).(*LMHeadModel)
+	config := proc.GPT2.Config
+
+	encoded, err := t.Tokenizer.Encode(text)
+	if err != nil {
+		return "", err
+	}
+	inputIDs := encoded.IDs
+
+	var onStep generation.StepFunc
+	if onPartial != nil {
+		onStep = func(partialIDs []int) error {
+			partial, err := t.detokenize(partialIDs[len(inputIDs):], config)
+			if err != nil {
+				return err
+			}
+			return onPartial(partial)
+		}
+	}
+	generatedIDs, err := proc.GenerateContext(ctx, inputIDs, onStep, opts...)
+	if err != nil {
+		return "", err
+	}
+	return t.detokenize(generatedIDs[len(inputIDs):], config)
+}
+
+// detokenize returns the text corresponding to the generated IDs, without the special tokens.
+func (t *TextGenerator) detokenize(ids []int, config Config) (string, error) {
+	result := make([]int, 0, len(ids))
+	for _, id := range ids {
+		if id == config.EosTokenID || id == config.BosTokenID || (config.PadTokenID != nil && id == *config.PadTokenID) {
+			continue
+		}
+		result = append(result, id)
+	}
+	return t.Tokenizer.Decode(result)
+}
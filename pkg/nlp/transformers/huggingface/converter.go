@@ -6,8 +6,11 @@ package huggingface
 
 import (
 	"fmt"
+	mat "github.com/nlpodyssey/spago/pkg/mat32"
 	"github.com/nlpodyssey/spago/pkg/nlp/transformers/bart/converter"
 	"github.com/nlpodyssey/spago/pkg/nlp/transformers/bert"
+	"github.com/nlpodyssey/spago/pkg/nlp/transformers/gpt2"
+	"github.com/nlpodyssey/spago/pkg/nlp/transformers/t5"
 	"path"
 	"path/filepath"
 )
@@ -23,17 +26,35 @@ type Converter struct {
 	modelName string
 	// Full path of the model configuration file.
 	configFilename string
+	// The floating-point format used to store the params of the converted model.
+	precision mat.Precision
+}
+
+// ConverterOption allows to configure a new Converter with your specific needs.
+type ConverterOption func(*Converter)
+
+// ParamsPrecision sets the floating-point format used to store the params of
+// the converted model (default mat.FullPrecision).
+func ParamsPrecision(precision mat.Precision) ConverterOption {
+	return func(c *Converter) {
+		c.precision = precision
+	}
 }
 
 // NewConverter creates a new Converter.
-func NewConverter(modelsPath, modelName string) *Converter {
+func NewConverter(modelsPath, modelName string, opts ...ConverterOption) *Converter {
 	modelPath := filepath.Join(modelsPath, modelName)
-	return &Converter{
+	c := &Converter{
 		modelsPath:     modelsPath,
 		modelPath:      modelPath,
 		modelName:      modelName,
 		configFilename: path.Join(modelPath, ModelConfigFilename),
+		precision:      mat.FullPrecision,
+	}
+	for _, opt := range opts {
+		opt(c)
 	}
+	return c
 }
 
 // Convert converts the pickle-serialized model to spaGO.
@@ -45,12 +66,16 @@ func (c *Converter) Convert() error {
 
 	switch config.ModelType {
 	case "bart", "marian":
-		return converter.ConvertHuggingFacePreTrained(c.modelPath)
-	case "bert", "electra":
-		return bert.ConvertHuggingFacePreTrained(c.modelPath)
+		return converter.ConvertHuggingFacePreTrained(c.modelPath, converter.ParamsPrecision(c.precision))
+	case "gpt2":
+		return gpt2.ConvertHuggingFacePreTrained(c.modelPath, gpt2.ParamsPrecision(c.precision))
+	case "t5":
+		return t5.ConvertHuggingFacePreTrained(c.modelPath, t5.ParamsPrecision(c.precision))
+	case "bert", "electra", "roberta", "xlm-roberta", "distilbert":
+		return bert.ConvertHuggingFacePreTrained(c.modelPath, bert.ParamsPrecision(c.precision))
 	case "":
 		fmt.Println("model type empty; assuming it is BERT.")
-		return bert.ConvertHuggingFacePreTrained(c.modelPath)
+		return bert.ConvertHuggingFacePreTrained(c.modelPath, bert.ParamsPrecision(c.precision))
 	default:
 		return fmt.Errorf("unsupported model type: `%s`", config.ModelType)
 	}
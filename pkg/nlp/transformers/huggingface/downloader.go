@@ -6,6 +6,7 @@ package huggingface
 
 import (
 	"fmt"
+	"github.com/nlpodyssey/spago/pkg/nlp/transformers/bert"
 	"github.com/nlpodyssey/spago/pkg/utils/httputils"
 	"log"
 	"os"
@@ -75,6 +76,51 @@ func (d *Downloader) downloadModelSpecificFiles() error {
 			return err
 		}
 	}
+	if sentenceTransformersModelTypes[config.ModelType] {
+		return d.downloadSentenceTransformerFiles()
+	}
+	return nil
+}
+
+// sentenceTransformersModelTypes contains the model types whose sentence-transformers checkpoints
+// are supported (see bert.SentenceTransformer).
+var sentenceTransformersModelTypes = map[string]bool{
+	"bert":        true,
+	"roberta":     true,
+	"xlm-roberta": true,
+	"distilbert":  true,
+}
+
+// downloadSentenceTransformerFiles downloads the files of the modules of a sentence-transformers
+// checkpoint, if the model is one of them, that is if the repository has the modules file.
+func (d *Downloader) downloadSentenceTransformerFiles() error {
+	if err := d.downloadFile(bert.DefaultSentenceTransformerModulesFile); err != nil {
+		log.Printf("No sentence-transformers modules found (%v)\n", err)
+		return nil
+	}
+	modules, err := bert.ReadSentenceTransformerModules(path.Join(d.modelPath, bert.DefaultSentenceTransformerModulesFile))
+	if err != nil {
+		return err
+	}
+	if err := d.downloadFile(bert.DefaultSentenceBERTConfigFile); err != nil {
+		log.Printf("No sentence-transformers settings found (%v)\n", err)
+	}
+	for _, module := range modules {
+		if module.Path == "" || module.Type == bert.SentenceTransformerModuleNormalize {
+			continue // the transformer module is the model itself; the normalization has no files
+		}
+		if err := os.MkdirAll(path.Join(d.modelPath, module.Path), 0755); err != nil {
+			return err
+		}
+		if err := d.downloadFile(path.Join(module.Path, ModelConfigFilename)); err != nil {
+			return err
+		}
+		if module.Type == bert.SentenceTransformerModuleDense {
+			if err := d.downloadFile(path.Join(module.Path, "pytorch_model.bin")); err != nil {
+				return err
+			}
+		}
+	}
 	return nil
 }
 
@@ -89,10 +135,15 @@ const (
 // supportedModelsFiles contains the set of all supported model types as keys,
 // mapped with the set of all related files to download.
 var supportedModelsFiles = map[string][]string{
-	"bart":    {"pytorch_model.bin", "vocab.json", "merges.txt"},
-	"marian":  {"pytorch_model.bin", "vocab.json", "source.spm", "target.spm"},
-	"bert":    {"pytorch_model.bin", "vocab.txt"},
-	"electra": {"pytorch_model.bin", "vocab.txt"},
+	"bart":        {"pytorch_model.bin", "vocab.json", "merges.txt"},
+	"marian":      {"pytorch_model.bin", "vocab.json", "source.spm", "target.spm"},
+	"bert":        {"pytorch_model.bin", "vocab.txt"},
+	"electra":     {"pytorch_model.bin", "vocab.txt"},
+	"distilbert":  {"pytorch_model.bin", "vocab.txt"},
+	"roberta":     {"pytorch_model.bin", "vocab.json", "merges.txt"},
+	"xlm-roberta": {"pytorch_model.bin", "sentencepiece.bpe.model"},
+	"gpt2":        {"pytorch_model.bin", "vocab.json", "merges.txt"},
+	"t5":          {"pytorch_model.bin", "spiece.model"},
 }
 
 func (d *Downloader) downloadFile(filename string) error {
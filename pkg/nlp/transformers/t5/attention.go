@@ -0,0 +1,199 @@
+// Copyright 2021 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package t5
+
+import (
+	"encoding/gob"
+	mat "github.com/nlpodyssey/spago/pkg/mat32"
+	"github.com/nlpodyssey/spago/pkg/ml/ag"
+	"github.com/nlpodyssey/spago/pkg/ml/nn"
+	"github.com/nlpodyssey/spago/pkg/ml/nn/attention"
+	"github.com/nlpodyssey/spago/pkg/ml/nn/linear"
+)
+
+var (
+	_ nn.Model = &Attention{}
+	_ nn.Model = &RelativePositionBias{}
+)
+
+// PositionBias contains, for each query and for each attention head, the vector of the biases to be
+// added to the attention scores of the keys.
+type PositionBias = [][]ag.Node
+
+// Attention implements the T5 multi-head attention, where the attention scores are not scaled, and
+// the projections have no biases.
+type Attention struct {
+	nn.BaseModel
+	NumOfHeads    int
+	DKV           int
+	UseCausalMask bool
+	Query         *linear.Model
+	Key           *linear.Model
+	Value         *linear.Model
+	Output        *linear.Model
+}
+
+func init() {
+	gob.Register(&Attention{})
+	gob.Register(&RelativePositionBias{})
+}
+
+// NewAttention returns a new Attention.
+func NewAttention(config Config, useCausalMask bool) *Attention {
+	innerDim := config.NumHeads * config.DKV
+	return &Attention{
+		NumOfHeads:    config.NumHeads,
+		DKV:           config.DKV,
+		UseCausalMask: useCausalMask,
+		Query:         linear.New(config.DModel, innerDim, linear.BiasGrad(false)),
+		Key:           linear.New(config.DModel, innerDim, linear.BiasGrad(false)),
+		Value:         linear.New(config.DModel, innerDim, linear.BiasGrad(false)),
+		Output:        linear.New(innerDim, config.DModel, linear.BiasGrad(false)),
+	}
+}
+
+// Forward performs the attention of the queries over the keys and values, which are projected and
+// appended to the past ones. The position bias, if not nil, is added to the attention scores.
+// It returns the result along with all the projected keys and values, to be used as past keys and values
+// at the next step. If qkv.Keys is nil, only the past keys and values are attended (e.g. the projected
+// encoder hidden states of the cross-attention).
+func (m *Attention) Forward(
+	qkv attention.QKV,
+	past attention.KeysValuesPair,
+	positionBias PositionBias,
+) ([]ag.Node, attention.KeysValuesPair) {
+	g := m.Graph()
+	keys := append([]ag.Node{}, past.Keys...)
+	values := append([]ag.Node{}, past.Values...)
+	if qkv.Keys != nil {
+		keys = append(keys, m.Key.Forward(qkv.Keys...)...)
+		values = append(values, m.Value.Forward(qkv.Values...)...)
+	}
+	queries := m.Query.Forward(qkv.Queries...)
+
+	headsKeys := make([]ag.Node, m.NumOfHeads)
+	headsValues := make([]ag.Node, m.NumOfHeads)
+	for h := range headsKeys {
+		headsKeys[h] = g.Stack(m.headViews(keys, h)...)
+		headsValues[h] = g.T(g.Stack(m.headViews(values, h)...))
+	}
+
+	pastLength := len(keys) - len(queries)
+	out := make([]ag.Node, len(queries))
+	for i, q := range queries {
+		heads := make([]ag.Node, m.NumOfHeads)
+		for h := range heads {
+			scores := g.Mul(headsKeys[h], m.headView(q, h))
+			if positionBias != nil {
+				scores = g.Add(scores, positionBias[i][h])
+			}
+			if m.UseCausalMask && len(queries) > 1 {
+				causalMask := attention.MakeCausalMask(pastLength+i, len(keys))
+				scores = g.Add(scores, g.NewVariable(mat.NewVecDense(causalMask), false))
+			}
+			heads[h] = g.Mul(headsValues[h], g.Softmax(scores))
+		}
+		out[i] = g.Concat(heads...)
+	}
+	return m.Output.Forward(out...), attention.KeysValuesPair{Keys: keys, Values: values}
+}
+
+func (m *Attention) headViews(xs []ag.Node, h int) []ag.Node {
+	out := make([]ag.Node, len(xs))
+	for i, x := range xs {
+		out[i] = m.headView(x, h)
+	}
+	return out
+}
+
+func (m *Attention) headView(x ag.Node, h int) ag.Node {
+	return m.Graph().View(x, h*m.DKV, 0, m.DKV, 1)
+}
+
+// RelativePositionBias implements the learned biases of the attention scores, which depend on the
+// relative position of the keys with respect to the queries. The relative positions are mapped to a fixed
+// number of buckets: one for each small distance, and logarithmically bigger ones for the larger distances,
+// up to the maximum distance. The bias is shared by all the layers of the encoder (or the decoder).
+type RelativePositionBias struct {
+	nn.BaseModel
+	NumBuckets    int
+	MaxDistance   int
+	Bidirectional bool
+	// Vectors contains the biases of each attention head, for each bucket.
+	Vectors []nn.Param `spago:"type:weights"`
+}
+
+// NewRelativePositionBias returns a new RelativePositionBias. The bidirectional bias distinguishes the
+// keys before and after the query (encoder), otherwise only the keys before the query are considered (decoder).
+func NewRelativePositionBias(config Config, bidirectional bool) *RelativePositionBias {
+	vectors := make([]nn.Param, config.RelativeAttentionNumBuckets)
+	for i := range vectors {
+		vectors[i] = nn.NewParam(mat.NewEmptyVecDense(config.NumHeads))
+	}
+	return &RelativePositionBias{
+		NumBuckets:    config.RelativeAttentionNumBuckets,
+		MaxDistance:   config.MaxDistance(),
+		Bidirectional: bidirectional,
+		Vectors:       vectors,
+	}
+}
+
+// Forward returns the position bias of the given number of queries over the keys, where the queries
+// are the last ones of the key positions (i.e. the first queryLength-keyLength keys are past ones).
+func (m *RelativePositionBias) Forward(queryLength, keyLength int) PositionBias {
+	g := m.Graph()
+	numHeads := m.Vectors[0].Value().Size()
+	offset := keyLength - queryLength
+	bias := make(PositionBias, queryLength)
+	for i := range bias {
+		vectors := make([]ag.Node, keyLength)
+		for j := range vectors {
+			vectors[j] = m.Vectors[m.bucket(j-(i+offset))]
+		}
+		stacked := g.Stack(vectors...) // keyLength x numHeads
+		bias[i] = make([]ag.Node, numHeads)
+		for h := range bias[i] {
+			bias[i][h] = g.ColView(stacked, h)
+		}
+	}
+	return bias
+}
+
+// bucket returns the bucket of the relative position, i.e. the position of the key minus the position
+// of the query.
+func (m *RelativePositionBias) bucket(relativePosition int) int {
+	return relativePositionBucket(relativePosition, m.Bidirectional, m.NumBuckets, m.MaxDistance)
+}
+
+// relativePositionBucket maps the relative position to a bucket, as in the original implementation of T5.
+func relativePositionBucket(relativePosition int, bidirectional bool, numBuckets, maxDistance int) int {
+	bucket := 0
+	if bidirectional {
+		numBuckets /= 2
+		if relativePosition > 0 {
+			bucket += numBuckets
+		}
+		if relativePosition < 0 {
+			relativePosition = -relativePosition
+		}
+	} else {
+		if relativePosition > 0 {
+			relativePosition = 0
+		}
+		relativePosition = -relativePosition
+	}
+	maxExact := numBuckets / 2
+	if relativePosition < maxExact {
+		return bucket + relativePosition
+	}
+	large := maxExact + int(
+		mat.Log(mat.Float(relativePosition)/mat.Float(maxExact))/
+			mat.Log(mat.Float(maxDistance)/mat.Float(maxExact))*
+			mat.Float(numBuckets-maxExact))
+	if large > numBuckets-1 {
+		large = numBuckets - 1
+	}
+	return bucket + large
+}
@@ -0,0 +1,164 @@
+// Copyright 2021 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package t5
+
+import (
+	"context"
+	"encoding/gob"
+	mat "github.com/nlpodyssey/spago/pkg/mat32"
+	"github.com/nlpodyssey/spago/pkg/ml/ag"
+	"github.com/nlpodyssey/spago/pkg/ml/nn"
+	"github.com/nlpodyssey/spago/pkg/ml/nn/linear"
+	"github.com/nlpodyssey/spago/pkg/nlp/transformers/generation"
+	"runtime"
+)
+
+var (
+	_ nn.Model                  = &ConditionalGenerationModel{}
+	_ generation.EncoderDecoder = &ConditionalGenerationModel{}
+)
+
+// defaultMaxLength is the maximum length of the generated sequences when it is not set in the
+// configuration (as in Hugging Face).
+const defaultMaxLength = 20
+
+// ConditionalGenerationModel implements a T5 model with a language modeling head, for text-to-text
+// generation tasks (e.g. translation and summarization).
+type ConditionalGenerationModel struct {
+	nn.BaseModel
+	T5     *Model
+	LMHead *linear.Model
+}
+
+func init() {
+	gob.Register(&ConditionalGenerationModel{})
+}
+
+// NewConditionalGenerationModel returns a new ConditionalGenerationModel.
+func NewConditionalGenerationModel(config Config, embeddingsPath string) *ConditionalGenerationModel {
+	return &ConditionalGenerationModel{
+		T5:     New(config, embeddingsPath),
+		LMHead: linear.New(config.DModel, config.VocabSize, linear.BiasGrad(false)),
+	}
+}
+
+// Close closes the T5 model's embeddings DB.
+func (m *ConditionalGenerationModel) Close() {
+	m.T5.Close()
+}
+
+// PredictNext returns the logits of the next token after the last decoder input, along with the keys and
+// values to be used at the next step.
+func (m *ConditionalGenerationModel) PredictNext(
+	encoderHiddenStates []ag.Node,
+	decoderInputIDs []int,
+	pastKeysValues KeysValuesPairs,
+) (ag.Node, KeysValuesPairs) {
+	decoded, nextCache := m.T5.Decode(decoderInputIDs, encoderHiddenStates, pastKeysValues)
+	last := decoded[len(decoded)-1]
+	if m.T5.Config.TiedWordEmbeddings() {
+		// rescale the output before the projection on the vocabulary, since the weights are shared
+		last = m.Graph().ProdScalar(last, m.Graph().Constant(1/mat.Sqrt(mat.Float(m.T5.Config.DModel))))
+	}
+	return nn.ToNode(m.LMHead.Forward(last)), nextCache
+}
+
+// Encode satisfies pkg/nlp/transformers/generation/Encoder.
+func (m *ConditionalGenerationModel) Encode(inputIDs []int) []ag.Node {
+	return m.T5.Encode(inputIDs)
+}
+
+// Decode satisfies pkg/nlp/transformers/generation/Decoder.
+func (m *ConditionalGenerationModel) Decode(
+	encodedInput []ag.Node,
+	inputIDs []int,
+	pastCache generation.Cache,
+) (ag.Node, generation.Cache) {
+	pastKeysValues, _ := pastCache.(KeysValuesPairs)
+	if pastKeysValues != nil {
+		// cut input ids if past is used
+		inputIDs = inputIDs[len(inputIDs)-1:]
+	}
+	return m.PredictNext(encodedInput, inputIDs, pastKeysValues)
+}
+
+// Generate generates sequences using either beam-search decoding or sampling, as set in the configuration
+// of the model. The decoding options override the configuration.
+func (m *ConditionalGenerationModel) Generate(inputIDs []int, opts ...generation.DecodingOption) []int {
+	out, _ := m.GenerateContext(context.Background(), inputIDs, nil, opts...)
+	return out
+}
+
+// GenerateContext is like Generate, but it stops as soon as the context is done, and it calls onStep
+// (if not nil) with the best partial sequence at each step (see generation.Generator.GenerateContext).
+func (m *ConditionalGenerationModel) GenerateContext(
+	ctx context.Context,
+	inputIDs []int,
+	onStep generation.StepFunc,
+	opts ...generation.DecodingOption,
+) ([]int, error) {
+	return m.newGenerator(opts...).GenerateContext(ctx, inputIDs, onStep)
+}
+
+// GenerateBatch is like GenerateContext, but it generates a sequence for each input of the batch, decoding
+// them together (see generation.Generator.GenerateBatch).
+func (m *ConditionalGenerationModel) GenerateBatch(
+	ctx context.Context,
+	inputs [][]int,
+	onStep generation.BatchStepFunc,
+	opts ...generation.DecodingOption,
+) ([][]int, error) {
+	return m.newGenerator(opts...).GenerateBatch(ctx, inputs, onStep)
+}
+
+// newGenerator returns a new generation.Generator configured according to the configuration of the model,
+// overridden by the decoding options.
+func (m *ConditionalGenerationModel) newGenerator(opts ...generation.DecodingOption) *generation.Generator {
+	config := m.T5.Config
+	incrementalForward := m.Graph().IncrementalForwardEnabled()
+
+	maxConcurrentComputations := runtime.NumCPU()
+	if incrementalForward && runtime.NumCPU() > 1 {
+		maxConcurrentComputations = runtime.NumCPU() / 2
+	}
+
+	generatorConfig := generation.GeneratorConfig{
+		NumBeams:                  config.NumBeams,
+		MinLength:                 config.MinLength,
+		MaxLength:                 config.MaxLength,
+		IsEncoderDecoder:          true,
+		BOSTokenID:                -1, // no BOS token
+		EOSTokenID:                config.EosTokenID,
+		PadTokenID:                config.PadTokenID,
+		VocabSize:                 config.VocabSize,
+		DecoderStartTokenID:       config.DecoderStartTokenID,
+		LengthPenalty:             1.0,
+		EarlyStopping:             config.EarlyStopping,
+		BadWordsIDs:               config.BadWordsIDs,
+		RepetitionPenalty:         config.RepetitionPenalty,
+		NoRepeatNGramSize:         config.NoRepeatNGramSize,
+		MaxConcurrentComputations: maxConcurrentComputations,
+		IncrementalForward:        incrementalForward,
+		DoSample:                  config.DoSample,
+		Temperature:               config.Temperature,
+		TopK:                      config.TopK,
+		TopP:                      config.TopP,
+		TypicalP:                  config.TypicalP,
+	}
+	if generatorConfig.NumBeams < 1 {
+		generatorConfig.NumBeams = 1
+	}
+	if generatorConfig.MaxLength == 0 {
+		generatorConfig.MaxLength = defaultMaxLength
+	}
+	if config.LengthPenalty != nil {
+		generatorConfig.LengthPenalty = *config.LengthPenalty
+	}
+	for _, opt := range opts {
+		opt(&generatorConfig)
+	}
+
+	return generation.NewGenerator(generatorConfig, m)
+}
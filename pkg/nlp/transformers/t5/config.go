@@ -0,0 +1,165 @@
+// Copyright 2021 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package t5
+
+import (
+	"encoding/json"
+	"fmt"
+	mat "github.com/nlpodyssey/spago/pkg/mat32"
+	"github.com/nlpodyssey/spago/pkg/ml/ag"
+	"github.com/nlpodyssey/spago/pkg/nlp/transformers/generation"
+	"os"
+	"strings"
+)
+
+const (
+	// DefaultConfigurationFile is the default T5 JSON configuration filename.
+	DefaultConfigurationFile = "config.json"
+	// DefaultModelFile is the default T5 spaGO model filename.
+	DefaultModelFile = "spago_model.bin"
+	// DefaultEmbeddingsStorage is the default directory name for T5 model's embedding storage.
+	DefaultEmbeddingsStorage = "embeddings_storage"
+	// DefaultSentencePieceModelFile is the default filename of the T5 sentence-piece model.
+	DefaultSentencePieceModelFile = "spiece.model"
+)
+
+// Config contains the global configuration of the T5 model and of the generation.
+// The configuration coincides with that of Hugging Face to facilitate compatibility between the two architectures.
+type Config struct {
+	Architecture                 []string              `json:"architectures"`
+	DFF                          int                   `json:"d_ff"`
+	DKV                          int                   `json:"d_kv"`
+	DModel                       int                   `json:"d_model"`
+	DecoderStartTokenID          int                   `json:"decoder_start_token_id"`
+	DropoutRate                  mat.Float             `json:"dropout_rate"`
+	EosTokenID                   int                   `json:"eos_token_id"`
+	FeedForwardProj              string                `json:"feed_forward_proj"` // empty means "relu"
+	IsEncoderDecoder             bool                  `json:"is_encoder_decoder"`
+	LayerNormEpsilon             mat.Float             `json:"layer_norm_epsilon"`
+	ModelType                    string                `json:"model_type"`
+	NumDecoderLayers             *int                  `json:"num_decoder_layers"` // nil means NumLayers
+	NumHeads                     int                   `json:"num_heads"`
+	NumLayers                    int                   `json:"num_layers"`
+	PadTokenID                   int                   `json:"pad_token_id"`
+	RelativeAttentionNumBuckets  int                   `json:"relative_attention_num_buckets"`
+	RelativeAttentionMaxDistance int                   `json:"relative_attention_max_distance"` // zero means 128
+	TieWordEmbeddings            *bool                 `json:"tie_word_embeddings"`             // nil means true
+	VocabSize                    int                   `json:"vocab_size"`
+	TaskSpecificParams           map[string]TaskParams `json:"task_specific_params"`
+	NumBeams                     int                   `json:"num_beams"`
+	MaxLength                    int                   `json:"max_length"`
+	MinLength                    int                   `json:"min_length"`
+	BadWordsIDs                  [][]int               `json:"bad_words_ids"`
+	LengthPenalty                *mat.Float            `json:"length_penalty"` // nil means 1.0
+	EarlyStopping                bool                  `json:"early_stopping"`
+	RepetitionPenalty            mat.Float             `json:"repetition_penalty"`
+	NoRepeatNGramSize            int                   `json:"no_repeat_ngram_size"`
+	DoSample                     bool                  `json:"do_sample"`
+	Temperature                  mat.Float             `json:"temperature"`
+	TopK                         int                   `json:"top_k"`
+	TopP                         mat.Float             `json:"top_p"`
+	TypicalP                     mat.Float             `json:"typical_p"`
+	Training                     bool                  `json:"training"` // Custom for spaGO
+}
+
+// TaskParams contains the prefix of the input texts and the generation settings of a task
+// (e.g. "summarization" or "translation_en_to_de").
+type TaskParams struct {
+	Prefix            string     `json:"prefix"`
+	NumBeams          int        `json:"num_beams"`
+	MaxLength         int        `json:"max_length"`
+	MinLength         int        `json:"min_length"`
+	LengthPenalty     *mat.Float `json:"length_penalty"`
+	EarlyStopping     bool       `json:"early_stopping"`
+	NoRepeatNGramSize int        `json:"no_repeat_ngram_size"`
+}
+
+// DecoderLayers returns the number of layers of the decoder.
+func (c Config) DecoderLayers() int {
+	if c.NumDecoderLayers != nil {
+		return *c.NumDecoderLayers
+	}
+	return c.NumLayers
+}
+
+// MaxDistance returns the maximum distance of the relative positions, beyond which the positions
+// share the same bucket.
+func (c Config) MaxDistance() int {
+	if c.RelativeAttentionMaxDistance == 0 {
+		return 128
+	}
+	return c.RelativeAttentionMaxDistance
+}
+
+// TiedWordEmbeddings reports whether the language modeling head shares the weights of the word embeddings.
+func (c Config) TiedWordEmbeddings() bool {
+	return c.TieWordEmbeddings == nil || *c.TieWordEmbeddings
+}
+
+// IsGatedActivation reports whether the feed-forward blocks use a gated activation (e.g. "gated-gelu"),
+// as in T5 v1.1.
+func (c Config) IsGatedActivation() bool {
+	return strings.HasPrefix(c.FeedForwardProj, "gated-")
+}
+
+// ActivationFunction returns the operator of the activation function of the feed-forward blocks.
+// It panics if the activation function is not supported.
+func (c Config) ActivationFunction() ag.OpName {
+	switch name := strings.TrimPrefix(c.FeedForwardProj, "gated-"); name {
+	case "", "relu":
+		return ag.OpReLU
+	case "gelu", "gelu_new":
+		return ag.OpGELU // tanh approximation, as "gelu_new" in Hugging Face
+	default:
+		value, err := ag.GetOpName(name)
+		if err != nil {
+			panic(fmt.Errorf("t5: unsupported feed-forward projection `%s`", c.FeedForwardProj))
+		}
+		return value
+	}
+}
+
+// TaskSettings returns the prefix of the input texts and the decoding option of the task, which applies the
+// generation settings of the task-specific params of the configuration. It returns an error if the task is unknown.
+func (c Config) TaskSettings(task string) (string, generation.DecodingOption, error) {
+	params, ok := c.TaskSpecificParams[task]
+	if !ok {
+		return "", nil, fmt.Errorf("t5: unknown task `%s`", task)
+	}
+	opt := func(config *generation.GeneratorConfig) {
+		if params.NumBeams > 0 {
+			config.NumBeams = params.NumBeams
+		}
+		if params.MaxLength > 0 {
+			config.MaxLength = params.MaxLength
+		}
+		if params.MinLength > 0 {
+			config.MinLength = params.MinLength
+		}
+		if params.LengthPenalty != nil {
+			config.LengthPenalty = *params.LengthPenalty
+		}
+		if params.NoRepeatNGramSize > 0 {
+			config.NoRepeatNGramSize = params.NoRepeatNGramSize
+		}
+		config.EarlyStopping = config.EarlyStopping || params.EarlyStopping
+	}
+	return params.Prefix, opt, nil
+}
+
+// LoadConfig loads a T5 model Config from file.
+func LoadConfig(file string) (Config, error) {
+	var config Config
+	configFile, err := os.Open(file)
+	if err != nil {
+		return Config{}, err
+	}
+	defer configFile.Close()
+	err = json.NewDecoder(configFile).Decode(&config)
+	if err != nil {
+		return Config{}, err
+	}
+	return config, nil
+}
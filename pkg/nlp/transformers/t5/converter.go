@@ -0,0 +1,260 @@
+// Copyright 2021 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package t5
+
+import (
+	"fmt"
+	"github.com/nlpodyssey/gopickle/pytorch"
+	"github.com/nlpodyssey/gopickle/types"
+	mat "github.com/nlpodyssey/spago/pkg/mat32"
+	"github.com/nlpodyssey/spago/pkg/ml/nn"
+	"github.com/nlpodyssey/spago/pkg/ml/nn/linear"
+	"github.com/nlpodyssey/spago/pkg/nlp/embeddings"
+	"github.com/nlpodyssey/spago/pkg/utils/gopickleutils"
+	"log"
+	"os"
+	"path"
+	"strconv"
+)
+
+const defaultHuggingFaceModelFile = "pytorch_model.bin"
+
+// ConverterOption allows to adapt the conversion to your specific needs.
+type ConverterOption func(*huggingFacePreTrainedConverter)
+
+// ParamsPrecision sets the floating-point format used to store the params and the
+// word embeddings of the converted model (default mat.FullPrecision).
+func ParamsPrecision(precision mat.Precision) ConverterOption {
+	return func(c *huggingFacePreTrainedConverter) {
+		c.precision = precision
+	}
+}
+
+// ConvertHuggingFacePreTrained converts a HuggingFace pre-trained T5
+// transformer model to a corresponding spaGO ConditionalGenerationModel.
+func ConvertHuggingFacePreTrained(modelPath string, opts ...ConverterOption) error {
+	configFilename, err := exists(path.Join(modelPath, DefaultConfigurationFile))
+	if err != nil {
+		return err
+	}
+	pyTorchModelFilename, err := exists(path.Join(modelPath, defaultHuggingFaceModelFile))
+	if err != nil {
+		return err
+	}
+	config, err := LoadConfig(configFilename)
+	if err != nil {
+		return err
+	}
+
+	// Enable training mode, so that we have writing permissions
+	// (for example, for embeddings storage files).
+	config.Training = true
+
+	model := NewConditionalGenerationModel(config, path.Join(modelPath, DefaultEmbeddingsStorage))
+	defer model.Close()
+	handler := &huggingFacePreTrainedConverter{
+		config:               config,
+		modelPath:            modelPath,
+		configFilename:       configFilename,
+		pyTorchModelFilename: pyTorchModelFilename,
+		modelFilename:        path.Join(modelPath, DefaultModelFile),
+		model:                model,
+		modelMapping:         make(map[string]*mappedParam), // lazy initialization
+		precision:            mat.FullPrecision,
+	}
+	for _, opt := range opts {
+		opt(handler)
+	}
+	model.T5.Embeddings.Precision = handler.precision
+	return handler.convert()
+}
+
+type huggingFacePreTrainedConverter struct {
+	config               Config
+	modelPath            string
+	configFilename       string
+	pyTorchModelFilename string
+	modelFilename        string
+	model                *ConditionalGenerationModel
+	modelMapping         map[string]*mappedParam
+	precision            mat.Precision
+}
+
+type mappedParam struct {
+	value mat.Matrix
+	used  bool
+}
+
+func exists(filename string) (string, error) {
+	if _, err := os.Stat(filename); os.IsNotExist(err) {
+		return filename, err
+	}
+	return filename, nil
+}
+
+func (c *huggingFacePreTrainedConverter) convert() error {
+	log.Printf("Start converting `%s`\nConfiguration: %+v\n", c.pyTorchModelFilename, c.config)
+	log.Printf("Extracting Hugging Face params from the PyTorch model...")
+	pyTorchParams := c.extractHuggingFaceParams()
+
+	log.Printf("Convert embeddings... ")
+	wordEmbeddings, ok := pyTorchParams["shared.weight"]
+	if !ok {
+		return fmt.Errorf("t5: missing word embeddings `shared.weight`")
+	}
+	dumpWordEmbeddings(wordEmbeddings, c.model.T5.Embeddings, c.config.VocabSize)
+	log.Printf("Ok\n")
+
+	c.addToModelMapping(mapEncoder(c.model.T5.Encoder))
+	c.addToModelMapping(mapDecoder(c.model.T5.Decoder))
+	c.addToModelMapping(mapLMHead(c.model.LMHead, c.config.TiedWordEmbeddings()))
+
+	log.Printf("Search for matches with the mapped model to import weights...")
+	for paramName, preTrainedWeights := range pyTorchParams {
+		if param, ok := c.modelMapping[paramName]; ok {
+			fmt.Printf("Setting %s...", paramName)
+			if param.value.Size() != len(preTrainedWeights) {
+				return fmt.Errorf("t5: size mismatch for `%s`", paramName)
+			}
+			param.value.SetData(preTrainedWeights)
+			param.used = true
+			fmt.Println("ok")
+		}
+	}
+	for _, bias := range []struct {
+		name  string
+		model *RelativePositionBias
+	}{
+		{"encoder.block.0.layer.0.SelfAttention.relative_attention_bias.weight", c.model.T5.Encoder.RelativePositionBias},
+		{"decoder.block.0.layer.0.SelfAttention.relative_attention_bias.weight", c.model.T5.Decoder.RelativePositionBias},
+	} {
+		weights, ok := pyTorchParams[bias.name]
+		if !ok {
+			log.Printf("WARNING!! `%s` not initialized", bias.name)
+			continue
+		}
+		assignToParamsList(weights, bias.model.Vectors, c.config.RelativeAttentionNumBuckets, c.config.NumHeads)
+	}
+
+	log.Printf("Report possible mapping anomalies...")
+	for key, value := range c.modelMapping {
+		if !value.used {
+			log.Printf("WARNING!! `%s` not initialized", key)
+		}
+	}
+
+	if c.precision != mat.FullPrecision {
+		log.Printf("Convert the params to %s precision...", c.precision)
+		nn.SetPrecision(c.model, c.precision)
+	}
+
+	fmt.Printf("Serializing model to \"%s\"... ", c.modelFilename)
+	if err := nn.SaveModelFile(c.modelFilename, c.model, c.config); err != nil {
+		return fmt.Errorf("t5: error during model serialization: %w", err)
+	}
+	fmt.Println("ok")
+	fmt.Printf("T5 has been converted successfully!\n")
+	return nil
+}
+
+func (c *huggingFacePreTrainedConverter) extractHuggingFaceParams() map[string][]mat.Float {
+	paramsMap := make(map[string][]mat.Float)
+	result, err := pytorch.Load(c.pyTorchModelFilename)
+	if err != nil {
+		log.Fatal(err)
+	}
+	od := result.(*types.OrderedDict)
+	for key, entry := range od.Map {
+		t := entry.Value.(*pytorch.Tensor)
+		paramName := key.(string)
+		fmt.Printf("Reading %s.... ", paramName)
+		if _, isFloat := t.Source.(*pytorch.FloatStorage); !isFloat {
+			fmt.Println("skip")
+			continue
+		}
+		paramsMap[paramName] = gopickleutils.GetData(t)
+		fmt.Println("ok")
+	}
+	return paramsMap
+}
+
+func dumpWordEmbeddings(source []mat.Float, dest *embeddings.Model, vocabSize int) {
+	size := dest.Size
+	for i := 0; i < vocabSize; i++ {
+		dest.SetEmbeddingFromData(strconv.Itoa(i), source[i*size:(i+1)*size])
+	}
+}
+
+func assignToParamsList(source []mat.Float, dest []nn.Param, rows, cols int) {
+	for i := 0; i < rows; i++ {
+		dest[i].SetData(source[i*cols : (i+1)*cols])
+	}
+}
+
+func mapEncoder(model *Encoder) map[string]mat.Matrix {
+	paramsMap := make(map[string]mat.Matrix)
+	for i, layer := range model.Layers {
+		prefix := fmt.Sprintf("encoder.block.%d.layer", i)
+		mapAttention(paramsMap, fmt.Sprintf("%s.0.SelfAttention", prefix), layer.SelfAttention)
+		paramsMap[fmt.Sprintf("%s.0.layer_norm.weight", prefix)] = layer.SelfAttentionLayerNorm.W.Value()
+		mapFeedForward(paramsMap, fmt.Sprintf("%s.1.DenseReluDense", prefix), layer.FFN)
+		paramsMap[fmt.Sprintf("%s.1.layer_norm.weight", prefix)] = layer.LayerNorm.W.Value()
+	}
+	paramsMap["encoder.final_layer_norm.weight"] = model.LayerNorm.W.Value()
+	return paramsMap
+}
+
+func mapDecoder(model *Decoder) map[string]mat.Matrix {
+	paramsMap := make(map[string]mat.Matrix)
+	for i, layer := range model.Layers {
+		prefix := fmt.Sprintf("decoder.block.%d.layer", i)
+		mapAttention(paramsMap, fmt.Sprintf("%s.0.SelfAttention", prefix), layer.SelfAttention)
+		paramsMap[fmt.Sprintf("%s.0.layer_norm.weight", prefix)] = layer.SelfAttentionLayerNorm.W.Value()
+		mapAttention(paramsMap, fmt.Sprintf("%s.1.EncDecAttention", prefix), layer.EncoderAttention)
+		paramsMap[fmt.Sprintf("%s.1.layer_norm.weight", prefix)] = layer.EncoderAttentionLayerNorm.W.Value()
+		mapFeedForward(paramsMap, fmt.Sprintf("%s.2.DenseReluDense", prefix), layer.FFN)
+		paramsMap[fmt.Sprintf("%s.2.layer_norm.weight", prefix)] = layer.LayerNorm.W.Value()
+	}
+	paramsMap["decoder.final_layer_norm.weight"] = model.LayerNorm.W.Value()
+	return paramsMap
+}
+
+func mapAttention(paramsMap map[string]mat.Matrix, prefix string, model *Attention) {
+	paramsMap[fmt.Sprintf("%s.q.weight", prefix)] = model.Query.W.Value()
+	paramsMap[fmt.Sprintf("%s.k.weight", prefix)] = model.Key.W.Value()
+	paramsMap[fmt.Sprintf("%s.v.weight", prefix)] = model.Value.W.Value()
+	paramsMap[fmt.Sprintf("%s.o.weight", prefix)] = model.Output.W.Value()
+}
+
+func mapFeedForward(paramsMap map[string]mat.Matrix, prefix string, model *FeedForward) {
+	if model.WIGate != nil {
+		paramsMap[fmt.Sprintf("%s.wi_0.weight", prefix)] = model.WI.W.Value()
+		paramsMap[fmt.Sprintf("%s.wi_1.weight", prefix)] = model.WIGate.W.Value()
+	} else {
+		paramsMap[fmt.Sprintf("%s.wi.weight", prefix)] = model.WI.W.Value()
+	}
+	paramsMap[fmt.Sprintf("%s.wo.weight", prefix)] = model.WO.W.Value()
+}
+
+// mapLMHead maps the weights of the language modeling head, which are the same of the word embeddings
+// if they are tied. The head has no bias.
+func mapLMHead(model *linear.Model, tiedWordEmbeddings bool) map[string]mat.Matrix {
+	paramsMap := make(map[string]mat.Matrix)
+	if tiedWordEmbeddings {
+		paramsMap["shared.weight"] = model.W.Value()
+	} else {
+		paramsMap["lm_head.weight"] = model.W.Value()
+	}
+	return paramsMap
+}
+
+func (c *huggingFacePreTrainedConverter) addToModelMapping(paramsMap map[string]mat.Matrix) {
+	for k, v := range paramsMap {
+		c.modelMapping[k] = &mappedParam{
+			value: v,
+			used:  false,
+		}
+	}
+}
@@ -0,0 +1,141 @@
+// Copyright 2021 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package t5
+
+import (
+	"encoding/gob"
+	"github.com/nlpodyssey/spago/pkg/ml/ag"
+	"github.com/nlpodyssey/spago/pkg/ml/nn"
+	"github.com/nlpodyssey/spago/pkg/ml/nn/attention"
+	"github.com/nlpodyssey/spago/pkg/ml/nn/normalization/rmsnorm"
+)
+
+var (
+	_ nn.Model = &Decoder{}
+	_ nn.Model = &DecoderLayer{}
+)
+
+// Decoder implements the T5 decoder: a stack of layers sharing the same (unidirectional) relative
+// position bias, followed by a final layer normalization.
+type Decoder struct {
+	nn.BaseModel
+	Config               Config
+	RelativePositionBias *RelativePositionBias
+	Layers               []*DecoderLayer
+	LayerNorm            *rmsnorm.Model
+}
+
+// DecoderLayer implements a T5 decoder layer, where the layer normalization is applied before the
+// self-attention, the cross-attention and the feed-forward sub-layers.
+type DecoderLayer struct {
+	nn.BaseModel
+	SelfAttentionLayerNorm    *rmsnorm.Model
+	SelfAttention             *Attention
+	EncoderAttentionLayerNorm *rmsnorm.Model
+	EncoderAttention          *Attention
+	LayerNorm                 *rmsnorm.Model
+	FFN                       *FeedForward
+}
+
+func init() {
+	gob.Register(&Decoder{})
+	gob.Register(&DecoderLayer{})
+}
+
+// NewDecoder returns a new T5 Decoder.
+func NewDecoder(config Config) *Decoder {
+	layers := make([]*DecoderLayer, config.DecoderLayers())
+	for i := range layers {
+		layers[i] = NewDecoderLayer(config)
+	}
+	return &Decoder{
+		Config:               config,
+		RelativePositionBias: NewRelativePositionBias(config, false),
+		Layers:               layers,
+		LayerNorm:            rmsnorm.New(config.DModel),
+	}
+}
+
+// NewDecoderLayer returns a new T5 DecoderLayer.
+func NewDecoderLayer(config Config) *DecoderLayer {
+	return &DecoderLayer{
+		SelfAttentionLayerNorm:    rmsnorm.New(config.DModel),
+		SelfAttention:             NewAttention(config, true), // use causal mask
+		EncoderAttentionLayerNorm: rmsnorm.New(config.DModel),
+		EncoderAttention:          NewAttention(config, false),
+		LayerNorm:                 rmsnorm.New(config.DModel),
+		FFN:                       NewFeedForward(config),
+	}
+}
+
+// LayerKeysValuesPairs contains the keys and values used by the self-attention and cross-attention
+// of a decoder layer.
+type LayerKeysValuesPairs struct {
+	// SelfAttKeyValues contains the keys and values used by self-attention.
+	SelfAttKeyValues attention.KeysValuesPair
+	// CrossAttKeyValues contains the keys and values used by cross-attention.
+	CrossAttKeyValues attention.KeysValuesPair
+}
+
+// KeysValuesPairs contains the LayerKeysValuesPairs for each decoding layer.
+type KeysValuesPairs = []LayerKeysValuesPairs
+
+func getPastSequenceLength(pkv KeysValuesPairs) int {
+	if pkv == nil {
+		return 0
+	}
+	return len(pkv[0].SelfAttKeyValues.Values)
+}
+
+// Decode performs the forward step for each input and returns the result.
+// The past keys and values (nil at the first step) are the ones returned by the previous call, so that
+// only the new inputs have to be processed.
+func (m *Decoder) Decode(
+	xs []ag.Node,
+	encoderHiddenStates []ag.Node,
+	pastKeysValuesPairs KeysValuesPairs,
+) ([]ag.Node, KeysValuesPairs) {
+	pastLength := getPastSequenceLength(pastKeysValuesPairs)
+	positionBias := m.RelativePositionBias.Forward(len(xs), pastLength+len(xs))
+	// TODO: ys = m.Dropout(ys)
+	ys := xs
+	nextCache := make(KeysValuesPairs, len(m.Layers))
+	for i, l := range m.Layers {
+		var past LayerKeysValuesPairs
+		if pastKeysValuesPairs != nil {
+			past = pastKeysValuesPairs[i]
+		}
+		ys, nextCache[i] = l.Forward(ys, encoderHiddenStates, past, positionBias)
+	}
+	return m.LayerNorm.Forward(ys...), nextCache
+}
+
+// Forward performs the forward step for each input and returns the result.
+func (m *DecoderLayer) Forward(
+	xs []ag.Node,
+	encoderHiddenStates []ag.Node,
+	past LayerKeysValuesPairs,
+	positionBias PositionBias,
+) ([]ag.Node, LayerKeysValuesPairs) {
+	g := m.Graph()
+	norm := m.SelfAttentionLayerNorm.Forward(xs...)
+	selfAtt, selfAttKeyValues := m.SelfAttention.Forward(attention.ToQKV(norm), past.SelfAttKeyValues, positionBias)
+	hs := add(g, xs, selfAtt)
+
+	qkv := attention.QKV{Queries: m.EncoderAttentionLayerNorm.Forward(hs...)}
+	// use the past key-values if they are available otherwise use the encoder hidden states
+	if past.CrossAttKeyValues.Keys == nil {
+		qkv.Keys = encoderHiddenStates
+		qkv.Values = encoderHiddenStates
+	}
+	crossAtt, crossAttKeyValues := m.EncoderAttention.Forward(qkv, past.CrossAttKeyValues, nil)
+	hs = add(g, hs, crossAtt)
+
+	ys := add(g, hs, m.FFN.Forward(m.LayerNorm.Forward(hs...)...))
+	return ys, LayerKeysValuesPairs{
+		SelfAttKeyValues:  selfAttKeyValues,
+		CrossAttKeyValues: crossAttKeyValues,
+	}
+}
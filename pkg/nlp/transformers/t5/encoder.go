@@ -0,0 +1,94 @@
+// Copyright 2021 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package t5
+
+import (
+	"encoding/gob"
+	"github.com/nlpodyssey/spago/pkg/ml/ag"
+	"github.com/nlpodyssey/spago/pkg/ml/nn"
+	"github.com/nlpodyssey/spago/pkg/ml/nn/attention"
+	"github.com/nlpodyssey/spago/pkg/ml/nn/normalization/rmsnorm"
+)
+
+var (
+	_ nn.Model = &Encoder{}
+	_ nn.Model = &EncoderLayer{}
+)
+
+// Encoder implements the T5 encoder: a stack of layers sharing the same relative position bias,
+// followed by a final layer normalization.
+type Encoder struct {
+	nn.BaseModel
+	Config               Config
+	RelativePositionBias *RelativePositionBias
+	Layers               []*EncoderLayer
+	LayerNorm            *rmsnorm.Model
+}
+
+// EncoderLayer implements a T5 encoder layer, where the layer normalization is applied before the
+// self-attention and the feed-forward sub-layers.
+type EncoderLayer struct {
+	nn.BaseModel
+	SelfAttentionLayerNorm *rmsnorm.Model
+	SelfAttention          *Attention
+	LayerNorm              *rmsnorm.Model
+	FFN                    *FeedForward
+}
+
+func init() {
+	gob.Register(&Encoder{})
+	gob.Register(&EncoderLayer{})
+}
+
+// NewEncoder returns a new T5 Encoder.
+func NewEncoder(config Config) *Encoder {
+	layers := make([]*EncoderLayer, config.NumLayers)
+	for i := range layers {
+		layers[i] = NewEncoderLayer(config)
+	}
+	return &Encoder{
+		Config:               config,
+		RelativePositionBias: NewRelativePositionBias(config, true),
+		Layers:               layers,
+		LayerNorm:            rmsnorm.New(config.DModel),
+	}
+}
+
+// NewEncoderLayer returns a new T5 EncoderLayer.
+func NewEncoderLayer(config Config) *EncoderLayer {
+	return &EncoderLayer{
+		SelfAttentionLayerNorm: rmsnorm.New(config.DModel),
+		SelfAttention:          NewAttention(config, false),
+		LayerNorm:              rmsnorm.New(config.DModel),
+		FFN:                    NewFeedForward(config),
+	}
+}
+
+// Encode performs the forward step for each input node and returns the result.
+func (m *Encoder) Encode(xs []ag.Node) []ag.Node {
+	positionBias := m.RelativePositionBias.Forward(len(xs), len(xs))
+	// TODO: ys = m.Dropout(ys)
+	ys := xs
+	for _, l := range m.Layers {
+		ys = l.Forward(ys, positionBias)
+	}
+	return m.LayerNorm.Forward(ys...)
+}
+
+// Forward performs the forward step for each input node and returns the result.
+func (m *EncoderLayer) Forward(xs []ag.Node, positionBias PositionBias) []ag.Node {
+	norm := m.SelfAttentionLayerNorm.Forward(xs...)
+	att, _ := m.SelfAttention.Forward(attention.ToQKV(norm), attention.KeysValuesPair{}, positionBias)
+	hs := add(m.Graph(), xs, att)
+	return add(m.Graph(), hs, m.FFN.Forward(m.LayerNorm.Forward(hs...)...))
+}
+
+func add(g *ag.Graph, a []ag.Node, b []ag.Node) []ag.Node {
+	c := make([]ag.Node, len(a))
+	for i := 0; i < len(a); i++ {
+		c[i] = g.Add(a[i], b[i])
+	}
+	return c
+}
@@ -0,0 +1,60 @@
+// Copyright 2021 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package t5
+
+import (
+	"encoding/gob"
+	"github.com/nlpodyssey/spago/pkg/ml/ag"
+	"github.com/nlpodyssey/spago/pkg/ml/nn"
+	"github.com/nlpodyssey/spago/pkg/ml/nn/linear"
+)
+
+var (
+	_ nn.Model = &FeedForward{}
+)
+
+// FeedForward implements the T5 feed-forward block. With a gated activation (T5 v1.1), the activated
+// input projection is multiplied element-wise by a second linear projection of the input.
+type FeedForward struct {
+	nn.BaseModel
+	Activation ag.OpName
+	WI         *linear.Model
+	WIGate     *linear.Model // nil if the activation is not gated
+	WO         *linear.Model
+}
+
+func init() {
+	gob.Register(&FeedForward{})
+}
+
+// NewFeedForward returns a new FeedForward.
+func NewFeedForward(config Config) *FeedForward {
+	m := &FeedForward{
+		Activation: config.ActivationFunction(),
+		WI:         linear.New(config.DModel, config.DFF, linear.BiasGrad(false)),
+		WO:         linear.New(config.DFF, config.DModel, linear.BiasGrad(false)),
+	}
+	if config.IsGatedActivation() {
+		m.WIGate = linear.New(config.DModel, config.DFF, linear.BiasGrad(false))
+	}
+	return m
+}
+
+// Forward performs the forward step for each input node and returns the result.
+func (m *FeedForward) Forward(xs ...ag.Node) []ag.Node {
+	g := m.Graph()
+	hs := m.WI.Forward(xs...)
+	for i, h := range hs {
+		hs[i] = g.Invoke(m.Activation, h)
+	}
+	if m.WIGate != nil {
+		gates := m.WIGate.Forward(xs...)
+		for i, h := range hs {
+			hs[i] = g.Prod(h, gates[i])
+		}
+	}
+	// TODO: hs = m.Dropout(hs)
+	return m.WO.Forward(hs...)
+}
@@ -0,0 +1,38 @@
+// Copyright 2021 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package t5
+
+import (
+	"fmt"
+	"github.com/nlpodyssey/spago/pkg/ml/nn"
+	"path"
+)
+
+// LoadModel loads a ConditionalGenerationModel from file.
+func LoadModel(modelPath string) (*ConditionalGenerationModel, error) {
+	configFilename := path.Join(modelPath, DefaultConfigurationFile)
+	embeddingsPath := path.Join(modelPath, DefaultEmbeddingsStorage)
+	modelFilename := path.Join(modelPath, DefaultModelFile)
+
+	fmt.Printf("Start loading pre-trained model from \"%s\"\n", modelPath)
+	fmt.Printf("[1/2] Loading configuration... ")
+	config, err := LoadConfig(configFilename)
+	if err != nil {
+		return nil, err
+	}
+	fmt.Printf("ok\n")
+
+	model := NewConditionalGenerationModel(config, embeddingsPath)
+
+	fmt.Printf("[2/2] Loading model weights... ")
+	err = nn.LoadFromFile(modelFilename, model)
+	if err != nil {
+		model.Close()
+		return nil, fmt.Errorf("t5: error during model deserialization (%w)", err)
+	}
+	fmt.Println("ok")
+
+	return model, nil
+}
@@ -0,0 +1,85 @@
+// Copyright 2021 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package t5 implements the encoder-decoder transformer model introduced by Raffel et al., 2019.
+// "Exploring the Limits of Transfer Learning with a Unified Text-to-Text Transformer"
+// https://arxiv.org/abs/1910.10683
+//
+// The layers are not built on the ones of the bart package. T5 normalizes the input of each sub-layer
+// with RMSNorm, adds relative position biases to unscaled attention scores, and has no biases in the
+// projections, while the BART layers are serialized with concrete LayerNorm, multi-head attention and
+// feed-forward types: making them configurable would break the BART models already converted.
+package t5
+
+import (
+	"encoding/gob"
+	"github.com/nlpodyssey/spago/pkg/ml/ag"
+	"github.com/nlpodyssey/spago/pkg/ml/nn"
+	"github.com/nlpodyssey/spago/pkg/nlp/embeddings"
+	"strconv"
+)
+
+var (
+	_ nn.Model = &Model{}
+)
+
+// Model implements a T5 model. The encoder and the decoder share the word embeddings, and the positions
+// are encoded by the relative position biases of the self-attention.
+type Model struct {
+	nn.BaseModel
+	Config     Config
+	Embeddings *embeddings.Model
+	Encoder    *Encoder
+	Decoder    *Decoder
+}
+
+func init() {
+	gob.Register(&Model{})
+}
+
+// New returns a new T5 Model.
+func New(config Config, embeddingsStoragePath string) *Model {
+	return &Model{
+		Config: config,
+		Embeddings: embeddings.New(embeddings.Config{
+			Size:       config.DModel,
+			DBPath:     embeddingsStoragePath,
+			ReadOnly:   !config.Training,
+			ForceNewDB: false, // TODO: from config?
+		}),
+		Encoder: NewEncoder(config),
+		Decoder: NewDecoder(config),
+	}
+}
+
+// Close closes the T5 model's embeddings DB.
+func (m *Model) Close() {
+	m.Embeddings.Close()
+}
+
+// Encode performs the T5 encoding.
+func (m *Model) Encode(inputIDs []int) []ag.Node {
+	return m.Encoder.Encode(m.Embeddings.Encode(intToStringSlice(inputIDs)))
+}
+
+// Decode performs the T5 decoding.
+func (m *Model) Decode(
+	inputIDs []int,
+	encoderHiddenStates []ag.Node,
+	pastKeysValuesPairs KeysValuesPairs,
+) ([]ag.Node, KeysValuesPairs) {
+	return m.Decoder.Decode(
+		m.Embeddings.Encode(intToStringSlice(inputIDs)),
+		encoderHiddenStates,
+		pastKeysValuesPairs,
+	)
+}
+
+func intToStringSlice(a []int) []string {
+	out := make([]string, len(a))
+	for i, num := range a {
+		out[i] = strconv.Itoa(num)
+	}
+	return out
+}
@@ -0,0 +1,135 @@
+// Copyright 2021 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package t5
+
+import (
+	mat "github.com/nlpodyssey/spago/pkg/mat32"
+	"github.com/nlpodyssey/spago/pkg/mat32/rand"
+	"github.com/nlpodyssey/spago/pkg/ml/ag"
+	"github.com/nlpodyssey/spago/pkg/ml/initializers"
+	"github.com/nlpodyssey/spago/pkg/ml/nn"
+	"github.com/nlpodyssey/spago/pkg/nlp/transformers/generation"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"path"
+	"strconv"
+	"testing"
+)
+
+func newTestModel(t *testing.T, feedForwardProj string) *ConditionalGenerationModel {
+	config := Config{
+		DFF:                         12,
+		DKV:                         3,
+		DModel:                      8,
+		DecoderStartTokenID:         0,
+		EosTokenID:                  1,
+		FeedForwardProj:             feedForwardProj,
+		NumHeads:                    2,
+		NumLayers:                   2,
+		PadTokenID:                  0,
+		RelativeAttentionNumBuckets: 8,
+		VocabSize:                   10,
+		MaxLength:                   8,
+		Training:                    true,
+	}
+	model := NewConditionalGenerationModel(config, t.TempDir())
+	rndGen := rand.NewLockedRand(42)
+	nn.ForEachParam(model, func(param nn.Param) {
+		initializers.Normal(param.Value(), 0, 0.5, rndGen)
+	})
+	for i := 0; i < config.VocabSize; i++ {
+		data := make([]mat.Float, config.DModel)
+		for j := range data {
+			data[j] = mat.Float(rndGen.Float()) - 0.5
+		}
+		model.T5.Embeddings.SetEmbeddingFromData(strconv.Itoa(i), data)
+	}
+	return model
+}
+
+func TestRelativePositionBucket(t *testing.T) {
+	bidirectional := map[int]int{0: 0, -1: 1, 1: 17, -7: 7, -8: 8, -20: 10, 20: 26, -200: 15, 200: 31}
+	for relativePosition, expected := range bidirectional {
+		assert.Equal(t, expected, relativePositionBucket(relativePosition, true, 32, 128), relativePosition)
+	}
+	unidirectional := map[int]int{3: 0, 0: 0, -3: 3, -15: 15, -20: 17, -500: 31}
+	for relativePosition, expected := range unidirectional {
+		assert.Equal(t, expected, relativePositionBucket(relativePosition, false, 32, 128), relativePosition)
+	}
+}
+
+func TestConditionalGenerationModel_PastKeysValues(t *testing.T) {
+	for _, feedForwardProj := range []string{"relu", "gated-gelu"} {
+		t.Run(feedForwardProj, func(t *testing.T) {
+			model := newTestModel(t, feedForwardProj)
+			defer model.Close()
+			decoderInputIDs := []int{0, 4, 5, 6}
+
+			g := ag.NewGraph()
+			proc := nn.Reify(nn.Context{Graph: g, Mode: nn.Inference}, model).(*ConditionalGenerationModel)
+			encoded := proc.Encode([]int{2, 3, 4, 1})
+
+			// the whole sequence at once
+			expected, _ := proc.PredictNext(encoded, decoderInputIDs, nil)
+
+			// one token at a time, reusing the past keys and values
+			logits, past := proc.PredictNext(encoded, decoderInputIDs[:1], nil)
+			for i := 1; i < len(decoderInputIDs); i++ {
+				logits, past = proc.PredictNext(encoded, decoderInputIDs[i:i+1], past)
+			}
+
+			assert.InDeltaSlice(t, expected.Value().Data(), logits.Value().Data(), 1e-5)
+		})
+	}
+}
+
+func TestConditionalGenerationModel_Generate(t *testing.T) {
+	model := newTestModel(t, "gated-gelu")
+	defer model.Close()
+
+	g := ag.NewGraph()
+	proc := nn.Reify(nn.Context{Graph: g, Mode: nn.Inference}, model).(*ConditionalGenerationModel)
+
+	out := proc.Generate([]int{2, 3, 4, 1})
+	assert.Equal(t, 0, out[0]) // decoder start token
+	assert.LessOrEqual(t, len(out), model.T5.Config.MaxLength)
+	assert.Greater(t, len(out), 1)
+}
+
+func TestConditionalGenerationModel_Serialization(t *testing.T) {
+	model := newTestModel(t, "relu")
+	defer model.Close()
+	filename := path.Join(t.TempDir(), DefaultModelFile)
+	require.NoError(t, nn.SaveModelFile(filename, model, model.T5.Config))
+
+	loaded := NewConditionalGenerationModel(model.T5.Config, t.TempDir())
+	defer loaded.Close()
+	require.NoError(t, nn.LoadFromFile(filename, loaded))
+	assert.Nil(t, loaded.T5.Encoder.Layers[0].FFN.WIGate)
+	assert.Equal(t,
+		model.T5.Decoder.RelativePositionBias.Vectors[3].Value().Data(),
+		loaded.T5.Decoder.RelativePositionBias.Vectors[3].Value().Data())
+}
+
+func TestConfig_TaskSettings(t *testing.T) {
+	config := Config{
+		TaskSpecificParams: map[string]TaskParams{
+			"summarization": {Prefix: "summarize: ", NumBeams: 4, MaxLength: 200, NoRepeatNGramSize: 3},
+		},
+	}
+	prefix, opt, err := config.TaskSettings("summarization")
+	require.NoError(t, err)
+	assert.Equal(t, "summarize: ", prefix)
+
+	generatorConfig := generation.GeneratorConfig{NumBeams: 1, MaxLength: 20, EarlyStopping: true}
+	opt(&generatorConfig)
+	assert.Equal(t, 4, generatorConfig.NumBeams)
+	assert.Equal(t, 200, generatorConfig.MaxLength)
+	assert.Equal(t, 3, generatorConfig.NoRepeatNGramSize)
+	assert.True(t, generatorConfig.EarlyStopping)
+
+	_, _, err = config.TaskSettings("unknown")
+	assert.Error(t, err)
+}
@@ -0,0 +1,130 @@
+// Copyright 2021 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package t5
+
+import (
+	"context"
+	"fmt"
+	"github.com/nlpodyssey/spago/pkg/ml/ag"
+	"github.com/nlpodyssey/spago/pkg/ml/nn"
+	"github.com/nlpodyssey/spago/pkg/nlp/tokenizers/sentencepiece"
+	"github.com/nlpodyssey/spago/pkg/nlp/transformers/generation"
+	"path"
+)
+
+// numExtraIDs is the number of sentinel tokens of T5 ("<extra_id_0>", "<extra_id_1>", ...), which follow
+// the pieces of the sentence-piece model in the vocabulary, in reverse order.
+const numExtraIDs = 100
+
+// Text2TextGenerator contains the ConditionalGenerationModel and the Tokenizer used for text-to-text
+// generation tasks. The task is usually set by a prefix of the input text (see Config.TaskSettings).
+type Text2TextGenerator struct {
+	*ConditionalGenerationModel
+	Tokenizer *sentencepiece.Tokenizer
+}
+
+// LoadText2TextGenerator loads a Text2TextGenerator from the model folder, which must contain the
+// sentence-piece model of the tokenizer (spiece.model) along with the converted model.
+func LoadText2TextGenerator(modelPath string) (*Text2TextGenerator, error) {
+	model, err := LoadModel(modelPath)
+	if err != nil {
+		return nil, err
+	}
+	sentinels := make([]string, numExtraIDs)
+	for i := range sentinels {
+		sentinels[i] = fmt.Sprintf("<extra_id_%d>", numExtraIDs-1-i)
+	}
+	tokenizer, err := sentencepiece.NewFromSentencePieceModel(
+		path.Join(modelPath, DefaultSentencePieceModelFile), false, sentinels...)
+	if err != nil {
+		model.Close()
+		return nil, err
+	}
+	return &Text2TextGenerator{
+		ConditionalGenerationModel: model,
+		Tokenizer:                  tokenizer,
+	}, nil
+}
+
+// Generate generates a new text starting from the input text.
+// The decoding options override the decoding strategy set in the configuration of the model
+// (e.g. generation.Sampling to sample the next tokens instead of performing a beam search).
+func (t *Text2TextGenerator) Generate(text string, opts ...generation.DecodingOption) (string, error) {
+	return t.GenerateStream(context.Background(), text, nil, opts...)
+}
+
+// GenerateStream is like Generate, but it calls onPartial (if not nil) with the text of the best partial
+// hypothesis at each step of the generation, and it stops as soon as the context is done, returning the
+// error of the context. If onPartial returns an error, the generation is stopped and the error is returned.
+func (t *Text2TextGenerator) GenerateStream(
+	ctx context.Context,
+	text string,
+	onPartial func(text string) error,
+	opts ...generation.DecodingOption,
+) (string, error) {
+	g := ag.NewGraph(ag.IncrementalForward(false))
+	defer g.Clear()
+
+	proc := nn.Reify(nn.Context{Graph: g, Mode: nn.Inference}, t.ConditionalGenerationModel).(*ConditionalGenerationModel)
+	config := proc.T5.Config
+
+	var onStep generation.StepFunc
+	if onPartial != nil {
+		onStep = func(partialIDs []int) error {
+			return onPartial(t.detokenize(partialIDs, config))
+		}
+	}
+	generatedIDs, err := proc.GenerateContext(ctx, t.encode(text, config), onStep, opts...)
+	if err != nil {
+		return "", err
+	}
+	return t.detokenize(generatedIDs, config), nil
+}
+
+// GenerateBatch generates a new text for each input text, decoding them together.
+// It stops as soon as the context is done, returning the error of the context.
+func (t *Text2TextGenerator) GenerateBatch(
+	ctx context.Context,
+	texts []string,
+	opts ...generation.DecodingOption,
+) ([]string, error) {
+	g := ag.NewGraph(ag.IncrementalForward(false))
+	defer g.Clear()
+
+	proc := nn.Reify(nn.Context{Graph: g, Mode: nn.Inference}, t.ConditionalGenerationModel).(*ConditionalGenerationModel)
+	config := proc.T5.Config
+
+	inputs := make([][]int, len(texts))
+	for i, text := range texts {
+		inputs[i] = t.encode(text, config)
+	}
+	generatedIDs, err := proc.GenerateBatch(ctx, inputs, nil, opts...)
+	if err != nil {
+		return nil, err
+	}
+	outputs := make([]string, len(generatedIDs))
+	for i, ids := range generatedIDs {
+		outputs[i] = t.detokenize(ids, config)
+	}
+	return outputs, nil
+}
+
+// encode returns the IDs of the tokens of the text, followed by the EOS token.
+func (t *Text2TextGenerator) encode(text string, config Config) []int {
+	tokenIDs := t.Tokenizer.TokensToIDs(t.Tokenizer.Tokenize(text))
+	return append(tokenIDs, config.EosTokenID)
+}
+
+// detokenize returns the text corresponding to the generated IDs, without the special tokens.
+func (t *Text2TextGenerator) detokenize(ids []int, config Config) string {
+	result := make([]int, 0, len(ids))
+	for _, id := range ids {
+		if id == config.EosTokenID || id == config.PadTokenID || id == config.DecoderStartTokenID {
+			continue
+		}
+		result = append(result, id)
+	}
+	return t.Tokenizer.Detokenize(t.Tokenizer.IDsToTokens(result))
+}
@@ -33,6 +33,8 @@ func DownloadFile(filepath string, url string) error {
 	defer resp.Body.Close()
 
 	if resp.StatusCode != 200 {
+		out.Close()
+		os.Remove(filepath + ".tmp")
 		return fmt.Errorf(
 			"error fetching %s: found status code `%d`, expected `200`", url, resp.StatusCode)
 	}
@@ -36,8 +36,13 @@ func SerializeToFile(filename string, obj interface{}) (err error) {
 	return nil
 }
 
+// DecodingHook is a function called by DeserializeFromFile on the decoded object.
+// It allows to adapt the object before use, for example converting its data to a different format.
+type DecodingHook func(obj interface{}) error
+
 // DeserializeFromFile deserializes obj from file, using gob decoding.
-func DeserializeFromFile(filename string, obj interface{}) (err error) {
+// The optional hooks are called in order on the decoded object.
+func DeserializeFromFile(filename string, obj interface{}, hooks ...DecodingHook) (err error) {
 	f, err := os.Open(filename)
 	if err != nil {
 		return err
@@ -51,6 +56,11 @@ func DeserializeFromFile(filename string, obj interface{}) (err error) {
 	if err != nil {
 		return err
 	}
+	for _, hook := range hooks {
+		if err = hook(obj); err != nil {
+			return err
+		}
+	}
 	return
 }
 